@@ -53,13 +53,37 @@ var (
 	InvalidPropertyId             = &AppErrorType{http.StatusBadRequest, "invalid-property-id"}
 	PropertyNotFound              = &AppErrorType{http.StatusNotFound, "property-not-found"}
 	InvalidPropertyImageExtension = &AppErrorType{http.StatusBadRequest, "invalid-property-image-extensions"}
+	PropertyHistoryNotFound       = &AppErrorType{http.StatusNotFound, "property-history-not-found"}
+	InvalidPropertyHistoryVersion = &AppErrorType{http.StatusBadRequest, "invalid-property-history-version"}
+
+	// property attachment errors
+	InvalidPropertyAttachmentId          = &AppErrorType{http.StatusBadRequest, "invalid-property-attachment-id"}
+	InvalidPropertyAttachmentType        = &AppErrorType{http.StatusBadRequest, "invalid-property-attachment-type"}
+	InvalidPropertyAttachmentContentType = &AppErrorType{http.StatusBadRequest, "invalid-property-attachment-content-type"}
+	InvalidPropertyAttachmentUrl         = &AppErrorType{http.StatusBadRequest, "invalid-property-attachment-url"}
+	InvalidPropertyAttachmentOrder       = &AppErrorType{http.StatusBadRequest, "invalid-property-attachment-order"}
+	PropertyAttachmentNotFound           = &AppErrorType{http.StatusNotFound, "property-attachment-not-found"}
 
 	// appointment errors
-	InvalidAppointmentId     = &AppErrorType{http.StatusBadRequest, "invalid-appointment-id"}
-	AppointmentNotFound      = &AppErrorType{http.StatusNotFound, "appointment-not-found"}
-	DuplicateAppointment     = &AppErrorType{http.StatusBadRequest, "duplicate-appointment"}
-	InvalidAppointmentStatus = &AppErrorType{http.StatusBadRequest, "invalid-appointment-status"}
-	UserHasVerified          = &AppErrorType{http.StatusBadRequest, "user-has-verified"}
+	InvalidAppointmentId         = &AppErrorType{http.StatusBadRequest, "invalid-appointment-id"}
+	AppointmentNotFound          = &AppErrorType{http.StatusNotFound, "appointment-not-found"}
+	DuplicateAppointment         = &AppErrorType{http.StatusBadRequest, "duplicate-appointment"}
+	InvalidAppointmentStatus     = &AppErrorType{http.StatusBadRequest, "invalid-appointment-status"}
+	InvalidAppointmentTransition = &AppErrorType{http.StatusConflict, "invalid-appointment-transition"}
+	AppointmentNotReschedulable  = &AppErrorType{http.StatusConflict, "appointment-not-reschedulable"}
+	InvalidProposalId            = &AppErrorType{http.StatusBadRequest, "invalid-proposal-id"}
+	ProposalNotFound             = &AppErrorType{http.StatusNotFound, "proposal-not-found"}
+	ProposalNotPending           = &AppErrorType{http.StatusConflict, "proposal-not-pending"}
+	AppointmentSlotNotFree       = &AppErrorType{http.StatusConflict, "appointment-slot-not-free"}
+	InvalidSlotRange             = &AppErrorType{http.StatusBadRequest, "invalid-slot-range"}
+	InvalidAvailability          = &AppErrorType{http.StatusBadRequest, "invalid-availability"}
+	InvalidExceptionId           = &AppErrorType{http.StatusBadRequest, "invalid-exception-id"}
+	ExceptionNotFound            = &AppErrorType{http.StatusNotFound, "exception-not-found"}
+	CalendarFeedNotFound         = &AppErrorType{http.StatusNotFound, "calendar-feed-not-found"}
+	AppointmentNotAttendable     = &AppErrorType{http.StatusConflict, "appointment-not-attendable"}
+	AttendanceWindowClosed       = &AppErrorType{http.StatusConflict, "attendance-window-closed"}
+	AttendanceAlreadyRecorded    = &AppErrorType{http.StatusConflict, "attendance-already-recorded"}
+	UserHasVerified              = &AppErrorType{http.StatusBadRequest, "user-has-verified"}
 
 	// user errors
 	InvalidUserId                 = &AppErrorType{http.StatusBadRequest, "invalid-user-id"}
@@ -76,9 +100,80 @@ var (
 	ServiceUnavailable            = &AppErrorType{http.StatusServiceUnavailable, "service-unavailable"}
 	InvalidProfileImageExtension  = &AppErrorType{http.StatusBadRequest, "invalid-profile-image-extensions"}
 
-	InvalidAgreementId = &AppErrorType{http.StatusBadRequest, "invalid-agreement-id"}
-	AgreementNotFound  = &AppErrorType{http.StatusNotFound, "agreement-not-found"}
-	DuplicateAgreement = &AppErrorType{http.StatusBadRequest, "duplicate-agreement"}
+	InvalidAgreementId         = &AppErrorType{http.StatusBadRequest, "invalid-agreement-id"}
+	AgreementNotFound          = &AppErrorType{http.StatusNotFound, "agreement-not-found"}
+	DuplicateAgreement         = &AppErrorType{http.StatusBadRequest, "duplicate-agreement"}
+	InvalidAgreementStatus     = &AppErrorType{http.StatusBadRequest, "invalid-agreement-status"}
+	InvalidAgreementTransition = &AppErrorType{http.StatusConflict, "invalid-agreement-transition"}
+	AgreementNotPaid           = &AppErrorType{http.StatusConflict, "agreement-not-paid"}
+	InstallmentNotFound        = &AppErrorType{http.StatusNotFound, "installment-not-found"}
+	InstallmentAlreadyPaid     = &AppErrorType{http.StatusConflict, "installment-already-paid"}
+	InvalidAgreementType       = &AppErrorType{http.StatusBadRequest, "invalid-agreement-type"}
+	PropertyNotAvailable       = &AppErrorType{http.StatusConflict, "property-not-available"}
+	InvalidContractLanguage    = &AppErrorType{http.StatusBadRequest, "invalid-contract-language"}
+	ContractNotFound           = &AppErrorType{http.StatusNotFound, "contract-not-found"}
+	DuplicateContract          = &AppErrorType{http.StatusConflict, "duplicate-contract"}
+	ContractAlreadySigned      = &AppErrorType{http.StatusConflict, "contract-already-signed"}
+	ContractTampered           = &AppErrorType{http.StatusConflict, "contract-tampered"}
+	InvalidSignatureType       = &AppErrorType{http.StatusBadRequest, "invalid-signature-type"}
+	InvalidSignatureImage      = &AppErrorType{http.StatusBadRequest, "invalid-signature-image"}
+	SignatureNotFound          = &AppErrorType{http.StatusNotFound, "signature-not-found"}
+	AgreementNotSigned         = &AppErrorType{http.StatusConflict, "agreement-not-signed"}
+	AgreementNotAmendable      = &AppErrorType{http.StatusConflict, "agreement-not-amendable"}
+	AgreementNotDeletable      = &AppErrorType{http.StatusConflict, "agreement-not-deletable"}
+	InvalidAmendment           = &AppErrorType{http.StatusBadRequest, "invalid-amendment"}
+	InvalidAmendmentId         = &AppErrorType{http.StatusBadRequest, "invalid-amendment-id"}
+	AmendmentNotFound          = &AppErrorType{http.StatusNotFound, "amendment-not-found"}
+	AmendmentNotPending        = &AppErrorType{http.StatusConflict, "amendment-not-pending"}
+	DepositNotHeld             = &AppErrorType{http.StatusConflict, "deposit-not-held"}
+	DepositAlreadyRefunded     = &AppErrorType{http.StatusConflict, "deposit-already-refunded"}
+	DepositNotRefundable       = &AppErrorType{http.StatusConflict, "deposit-not-refundable"}
+	InvalidDeduction           = &AppErrorType{http.StatusBadRequest, "invalid-deduction"}
+	InvalidDeductionId         = &AppErrorType{http.StatusBadRequest, "invalid-deduction-id"}
+	DeductionNotFound          = &AppErrorType{http.StatusNotFound, "deduction-not-found"}
+	DeductionNotPending        = &AppErrorType{http.StatusConflict, "deduction-not-pending"}
+	DeductionsUnresolved       = &AppErrorType{http.StatusConflict, "deductions-unresolved"}
+	DeductionNotDisputed       = &AppErrorType{http.StatusConflict, "deduction-not-disputed"}
+	PhotoNotFound              = &AppErrorType{http.StatusNotFound, "photo-not-found"}
+	InspectionNotAllowed       = &AppErrorType{http.StatusConflict, "inspection-not-allowed"}
+	InvalidInspection          = &AppErrorType{http.StatusBadRequest, "invalid-inspection"}
+	InvalidInspectionId        = &AppErrorType{http.StatusBadRequest, "invalid-inspection-id"}
+	InvalidInspectionItemId    = &AppErrorType{http.StatusBadRequest, "invalid-inspection-item-id"}
+	InspectionNotFound         = &AppErrorType{http.StatusNotFound, "inspection-not-found"}
+	InspectionItemNotFound     = &AppErrorType{http.StatusNotFound, "inspection-item-not-found"}
+	DuplicateInspection        = &AppErrorType{http.StatusConflict, "duplicate-inspection"}
+	InspectionSigned           = &AppErrorType{http.StatusConflict, "inspection-signed"}
+	InspectionAlreadySigned    = &AppErrorType{http.StatusConflict, "inspection-already-signed"}
+	InspectionSuperseded       = &AppErrorType{http.StatusConflict, "inspection-superseded"}
+	InvalidUtilityRates        = &AppErrorType{http.StatusBadRequest, "invalid-utility-rates"}
+	UtilityRatesNotFound       = &AppErrorType{http.StatusNotFound, "utility-rates-not-found"}
+	InvalidMeterReading        = &AppErrorType{http.StatusBadRequest, "invalid-meter-reading"}
+	InvalidMeterReadingId      = &AppErrorType{http.StatusBadRequest, "invalid-meter-reading-id"}
+	MeterReadingNotFound       = &AppErrorType{http.StatusNotFound, "meter-reading-not-found"}
+	DuplicateMeterReading      = &AppErrorType{http.StatusConflict, "duplicate-meter-reading"}
+	MeterReadingNotAllowed     = &AppErrorType{http.StatusConflict, "meter-reading-not-allowed"}
+
+	// maintenance ticket errors
+	InvalidMaintenanceTicket     = &AppErrorType{http.StatusBadRequest, "invalid-maintenance-ticket"}
+	InvalidMaintenanceTicketId   = &AppErrorType{http.StatusBadRequest, "invalid-maintenance-ticket-id"}
+	MaintenanceTicketNotFound    = &AppErrorType{http.StatusNotFound, "maintenance-ticket-not-found"}
+	MaintenanceNotAllowed        = &AppErrorType{http.StatusConflict, "maintenance-not-allowed"}
+	InvalidMaintenanceStatus     = &AppErrorType{http.StatusBadRequest, "invalid-maintenance-status"}
+	InvalidMaintenanceTransition = &AppErrorType{http.StatusConflict, "invalid-maintenance-transition"}
+
+	// payment errors
+	InvalidPaymentId     = &AppErrorType{http.StatusBadRequest, "invalid-payment-id"}
+	PaymentNotFound      = &AppErrorType{http.StatusNotFound, "payment-not-found"}
+	PaymentNotRefundable = &AppErrorType{http.StatusConflict, "payment-not-refundable"}
+	InvalidWebhook       = &AppErrorType{http.StatusBadRequest, "invalid-webhook"}
+	PaymentProviderError = &AppErrorType{http.StatusBadGateway, "payment-provider-error"}
+
+	// statement errors
+	InvalidStatementPeriod = &AppErrorType{http.StatusBadRequest, "invalid-statement-period"}
+	InvalidStatementFormat = &AppErrorType{http.StatusBadRequest, "invalid-statement-format"}
+
+	// trash errors
+	ResourceNotRestorable = &AppErrorType{http.StatusConflict, "resource-not-restorable"}
 
 	WebSocketDuplicatedConnection = &AppErrorType{http.StatusBadRequest, "websocket-duplicated-connection"}
 	NotInChat                     = &AppErrorType{http.StatusBadRequest, "not-in-chat"}
@@ -86,4 +181,5 @@ var (
 	InvalidCallbackRequest = &AppErrorType{http.StatusBadRequest, "invalid-callback-request"}
 
 	Unauthorized = &AppErrorType{http.StatusUnauthorized, "unauthorized"}
+	Forbidden    = &AppErrorType{http.StatusForbidden, "forbidden"}
 )
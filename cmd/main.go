@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/brain-flowing-company/pprp-backend/config"
 	"github.com/brain-flowing-company/pprp-backend/database"
@@ -13,10 +14,14 @@ import (
 	"github.com/brain-flowing-company/pprp-backend/internal/core/emails"
 	"github.com/brain-flowing-company/pprp-backend/internal/core/google"
 	"github.com/brain-flowing-company/pprp-backend/internal/core/greetings"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/maintenance"
 	"github.com/brain-flowing-company/pprp-backend/internal/core/payments"
 	"github.com/brain-flowing-company/pprp-backend/internal/core/properties"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/statements"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/trash"
 	"github.com/brain-flowing-company/pprp-backend/internal/core/users"
 	"github.com/brain-flowing-company/pprp-backend/internal/middleware"
+	"github.com/brain-flowing-company/pprp-backend/scheduler"
 	"github.com/brain-flowing-company/pprp-backend/storage"
 	"github.com/gofiber/contrib/fiberzap"
 	"github.com/gofiber/contrib/websocket"
@@ -56,6 +61,11 @@ func main() {
 		panic(fmt.Sprintf("Could not establish connection with AWS S3 with err: %v", err.Error()))
 	}
 
+	paymentProvider, err := payments.NewPaymentProvider(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("Could not set up payment provider with err: %v", err.Error()))
+	}
+
 	app := fiber.New()
 
 	var logger *zap.Logger
@@ -88,10 +98,6 @@ func main() {
 	propertyService := properties.NewService(logger, propertyRepo, storage)
 	propertyHandler := properties.NewHandler(propertyService)
 
-	agreementsRepo := agreements.NewRepository(db)
-	agreementsService := agreements.NewService(logger, agreementsRepo)
-	agreementsHandler := agreements.NewHandler(agreementsService)
-
 	usersRepo := users.NewRepository(db)
 	usersService := users.NewService(logger, cfg, usersRepo, storage)
 	usersHandler := users.NewHandler(usersService)
@@ -110,27 +116,62 @@ func main() {
 	authHandler := auth.NewHandler(cfg, authService)
 
 	appointmentRepository := appointments.NewRepository(db)
-	appointmentService := appointments.NewService(logger, appointmentRepository)
+	appointmentService := appointments.NewService(logger, cfg, appointmentRepository, emailService)
 	appointmentHandler := appointments.NewHandler(appointmentService)
 
+	agreementsRepo := agreements.NewRepository(db)
+	agreementsService := agreements.NewService(logger, cfg, agreementsRepo, emailService, appointmentService, storage)
+	agreementsHandler := agreements.NewHandler(agreementsService)
+
 	hub := chats.NewHub()
 	chatRepository := chats.NewRepository(db)
 	chatService := chats.NewService(logger, chatRepository)
 	chatHandler := chats.NewHandler(logger, cfg, hub, chatService)
 
+	maintenanceRepository := maintenance.NewRepository(db)
+	maintenanceService := maintenance.NewService(logger, maintenanceRepository, agreementsService, hub)
+	maintenanceHandler := maintenance.NewHandler(maintenanceService)
+
 	paymentsRepository := payments.NewRepository(db)
-	paymentsService := payments.NewService(logger, paymentsRepository)
+	paymentsService := payments.NewService(logger, paymentsRepository, agreementsService, paymentProvider)
 	paymentsHandler := payments.NewHandler(paymentsService)
 
+	statementsRepository := statements.NewRepository(db)
+	statementsService := statements.NewService(logger, cfg, statementsRepository)
+	statementsHandler := statements.NewHandler(statementsService)
+
+	trashRepository := trash.NewRepository(db)
+	trashService := trash.NewService(logger, cfg, trashRepository)
+	trashHandler := trash.NewHandler(trashService)
+
+	jobs := scheduler.New(logger)
+	if cfg.TrashRetention > 0 && cfg.TrashPurgeInterval > 0 {
+		jobs.Every("purge-expired-trash", time.Duration(cfg.TrashPurgeInterval)*time.Second, trashService.PurgeExpiredTrash)
+	}
+	if len(cfg.ReminderOffsets) > 0 && cfg.ReminderInterval > 0 {
+		jobs.Every("send-appointment-reminders", time.Duration(cfg.ReminderInterval)*time.Second, appointmentService.SendAppointmentReminders)
+	}
+	if cfg.ArchiveInterval > 0 {
+		jobs.Every("archive-past-appointments", time.Duration(cfg.ArchiveInterval)*time.Second, appointmentService.ArchivePastAppointments)
+	}
+	if cfg.OverdueInterval > 0 {
+		jobs.Every("mark-overdue-agreements", time.Duration(cfg.OverdueInterval)*time.Second, agreementsService.MarkOverdueAgreements)
+	}
+	if cfg.DisputeTimeoutDays > 0 && cfg.DisputeInterval > 0 {
+		jobs.Every("withdraw-stale-disputes", time.Duration(cfg.DisputeInterval)*time.Second, agreementsService.WithdrawStaleDisputes)
+	}
+	jobs.Start()
+	defer jobs.Stop()
+
 	mw := middleware.NewMiddleware(cfg)
 
 	apiv1 := app.Group("/api/v1", mw.SessionMiddleware)
 
-	apiv2 := app.Group("/api/v2", mw.SessionMiddleware)
-	apiv2.Post("/payments", payments.Checkout)
-
-	apiv1.Post("/payments", paymentsHandler.CreatePayment)
-	apiv1.Get("/payments", paymentsHandler.GetPaymentByUserId)
+	apiv1.Post("/payments", mw.AuthMiddlewareWrapper(paymentsHandler.CreatePayment))
+	apiv1.Get("/payments", mw.AuthMiddlewareWrapper(paymentsHandler.GetPaymentByUserId))
+	apiv1.Post("/payments/webhook", paymentsHandler.HandleWebhook)
+	apiv1.Get("/payments/:paymentId", mw.AuthMiddlewareWrapper(paymentsHandler.GetPaymentById))
+	apiv1.Post("/payments/:paymentId/refund", mw.AdminMiddlewareWrapper(paymentsHandler.RefundPayment))
 
 	apiv1.Get("/greeting", hwHandler.Greeting)
 	apiv1.Get("/user/greeting", mw.AuthMiddlewareWrapper(hwHandler.UserGreeting))
@@ -145,6 +186,12 @@ func main() {
 	apiv1.Delete("/properties/favorites/:propertyId", mw.AuthMiddlewareWrapper(propertyHandler.RemoveFavoriteProperty))
 	apiv1.Get("/user/me/favorites", mw.AuthMiddlewareWrapper(propertyHandler.GetMyFavoriteProperties))
 	apiv1.Get("/top10properties", propertyHandler.GetTop10Properties)
+	apiv1.Get("/properties/:propertyId/histories", mw.AuthMiddlewareWrapper(propertyHandler.GetPropertyHistories))
+	apiv1.Post("/properties/:propertyId/histories/:version/revert", mw.AuthMiddlewareWrapper(propertyHandler.RevertPropertyById))
+	apiv1.Get("/properties/:propertyId/attachments", propertyHandler.GetPropertyAttachments)
+	apiv1.Post("/properties/:propertyId/attachments", mw.AuthMiddlewareWrapper(propertyHandler.CreatePropertyAttachment))
+	apiv1.Put("/properties/:propertyId/attachments/order", mw.AuthMiddlewareWrapper(propertyHandler.ReorderPropertyAttachments))
+	apiv1.Delete("/properties/:propertyId/attachments/:attachmentId", mw.AuthMiddlewareWrapper(propertyHandler.DeletePropertyAttachmentById))
 
 	apiv1.Get("/appointments", mw.AuthMiddlewareWrapper(appointmentHandler.GetAllAppointments))
 	apiv1.Get("/appointments/:appointmentId", mw.AuthMiddlewareWrapper(appointmentHandler.GetAppointmentById))
@@ -152,6 +199,24 @@ func main() {
 	apiv1.Post("/appointments", mw.AuthMiddlewareWrapper(appointmentHandler.CreateAppointment))
 	apiv1.Delete("/appointments", mw.AuthMiddlewareWrapper(appointmentHandler.DeleteAppointment))
 	apiv1.Patch("/appointments/:appointmentId", mw.AuthMiddlewareWrapper(appointmentHandler.UpdateAppointmentStatus))
+	apiv1.Get("/appointments/:appointmentId/histories", mw.AuthMiddlewareWrapper(appointmentHandler.GetAppointmentStatusHistories))
+	apiv1.Get("/appointments/:appointmentId/proposals", mw.AuthMiddlewareWrapper(appointmentHandler.GetAppointmentProposals))
+	apiv1.Post("/appointments/:appointmentId/proposals", mw.AuthMiddlewareWrapper(appointmentHandler.CreateAppointmentProposal))
+	apiv1.Post("/appointments/:appointmentId/proposals/:proposalId/accept", mw.AuthMiddlewareWrapper(appointmentHandler.AcceptAppointmentProposal))
+	apiv1.Post("/appointments/:appointmentId/proposals/:proposalId/decline", mw.AuthMiddlewareWrapper(appointmentHandler.DeclineAppointmentProposal))
+	apiv1.Get("/appointments/:appointmentId/ics", mw.AuthMiddlewareWrapper(appointmentHandler.GetAppointmentCalendar))
+	apiv1.Post("/appointments/:appointmentId/check-in", mw.AuthMiddlewareWrapper(appointmentHandler.CheckInAppointment))
+	apiv1.Post("/appointments/:appointmentId/no-show", mw.AuthMiddlewareWrapper(appointmentHandler.ReportNoShow))
+	apiv1.Get("/appointments/:appointmentId/attendances", mw.AuthMiddlewareWrapper(appointmentHandler.GetAppointmentAttendances))
+	apiv1.Get("/user/:userId/attendance", mw.AuthMiddlewareWrapper(appointmentHandler.GetAttendanceStatistics))
+	apiv1.Get("/user/me/calendar", mw.AuthMiddlewareWrapper(appointmentHandler.GetCalendarFeed))
+	apiv1.Post("/user/me/calendar/regenerate", mw.AuthMiddlewareWrapper(appointmentHandler.RegenerateCalendarFeed))
+	apiv1.Get("/calendar/:token", appointmentHandler.GetCalendarByToken)
+	apiv1.Get("/properties/:propertyId/availabilities", appointmentHandler.GetPropertyAvailabilities)
+	apiv1.Put("/properties/:propertyId/availabilities", mw.AuthMiddlewareWrapper(appointmentHandler.UpdatePropertyAvailabilities))
+	apiv1.Post("/properties/:propertyId/availabilities/exceptions", mw.AuthMiddlewareWrapper(appointmentHandler.CreateAvailabilityException))
+	apiv1.Delete("/properties/:propertyId/availabilities/exceptions/:exceptionId", mw.AuthMiddlewareWrapper(appointmentHandler.DeleteAvailabilityException))
+	apiv1.Get("/properties/:propertyId/slots", appointmentHandler.GetOpenSlots)
 
 	apiv1.Get("/users", usersHandler.GetAllUsers)
 	apiv1.Get("/user/me/personal-information", mw.AuthMiddlewareWrapper(usersHandler.GetCurrentUser))
@@ -161,6 +226,8 @@ func main() {
 	apiv1.Put("/user/me/personal-information", mw.AuthMiddlewareWrapper(usersHandler.UpdateUser))
 	apiv1.Put("/user/me/financial-information", mw.AuthMiddlewareWrapper(usersHandler.UpdateUserFinancialInformation))
 	apiv1.Post("/user/me/verify", mw.AuthMiddlewareWrapper(usersHandler.VerifyCitizenId))
+	apiv1.Get("/user/me/notification-preferences", mw.AuthMiddlewareWrapper(usersHandler.GetNotificationPreferences))
+	apiv1.Put("/user/me/notification-preferences", mw.AuthMiddlewareWrapper(usersHandler.UpdateNotificationPreferences))
 	apiv1.Delete("/user/:userId", mw.AuthMiddlewareWrapper(usersHandler.DeleteUser))
 
 	apiv1.Post("/register", usersHandler.Register)
@@ -173,6 +240,50 @@ func main() {
 	apiv1.Post("/agreements", mw.AuthMiddlewareWrapper(agreementsHandler.CreateAgreement))
 	apiv1.Delete("/agreements/:agreementId", mw.AuthMiddlewareWrapper(agreementsHandler.DeleteAgreement))
 	apiv1.Patch("/agreements/:agreementId", mw.AuthMiddlewareWrapper(agreementsHandler.UpdateAgreementStatus))
+	apiv1.Get("/agreements/:agreementId/installments", mw.AuthMiddlewareWrapper(agreementsHandler.GetAgreementInstallments))
+	apiv1.Post("/agreements/:agreementId/contract", mw.AuthMiddlewareWrapper(agreementsHandler.GenerateAgreementContract))
+	apiv1.Get("/agreements/:agreementId/contract", mw.AuthMiddlewareWrapper(agreementsHandler.DownloadAgreementContract))
+	apiv1.Get("/agreements/:agreementId/photos/*", mw.AuthMiddlewareWrapper(agreementsHandler.DownloadAgreementPhoto))
+	apiv1.Post("/agreements/:agreementId/signatures", mw.AuthMiddlewareWrapper(agreementsHandler.SignAgreement))
+	apiv1.Get("/agreements/:agreementId/signatures", mw.AuthMiddlewareWrapper(agreementsHandler.VerifyAgreementSignatures))
+	apiv1.Get("/agreements/:agreementId/amendments", mw.AuthMiddlewareWrapper(agreementsHandler.GetAgreementAmendments))
+	apiv1.Post("/agreements/:agreementId/amendments", mw.AuthMiddlewareWrapper(agreementsHandler.CreateAgreementAmendment))
+	apiv1.Post("/agreements/:agreementId/amendments/:amendmentId/accept", mw.AuthMiddlewareWrapper(agreementsHandler.AcceptAgreementAmendment))
+	apiv1.Post("/agreements/:agreementId/amendments/:amendmentId/decline", mw.AuthMiddlewareWrapper(agreementsHandler.DeclineAgreementAmendment))
+	apiv1.Get("/agreements/:agreementId/deposit", mw.AuthMiddlewareWrapper(agreementsHandler.GetAgreementDeposit))
+	apiv1.Post("/agreements/:agreementId/deposit/deductions", mw.AuthMiddlewareWrapper(agreementsHandler.CreateDepositDeduction))
+	apiv1.Post("/agreements/:agreementId/deposit/deductions/:deductionId/accept", mw.AuthMiddlewareWrapper(agreementsHandler.AcceptDepositDeduction))
+	apiv1.Post("/agreements/:agreementId/deposit/deductions/:deductionId/dispute", mw.AuthMiddlewareWrapper(agreementsHandler.DisputeDepositDeduction))
+	apiv1.Post("/agreements/:agreementId/deposit/deductions/:deductionId/resolve", mw.AuthMiddlewareWrapper(agreementsHandler.ResolveDepositDeduction))
+	apiv1.Delete("/agreements/:agreementId/deposit/deductions/:deductionId", mw.AuthMiddlewareWrapper(agreementsHandler.DeleteDepositDeduction))
+	apiv1.Post("/agreements/:agreementId/deposit/refund", mw.AuthMiddlewareWrapper(agreementsHandler.RefundDeposit))
+	apiv1.Get("/agreements/:agreementId/inspections", mw.AuthMiddlewareWrapper(agreementsHandler.GetAgreementInspections))
+	apiv1.Post("/agreements/:agreementId/inspections", mw.AuthMiddlewareWrapper(agreementsHandler.CreateAgreementInspection))
+	apiv1.Get("/agreements/:agreementId/inspections/comparison", mw.AuthMiddlewareWrapper(agreementsHandler.CompareAgreementInspections))
+	apiv1.Get("/agreements/:agreementId/inspections/:inspectionId", mw.AuthMiddlewareWrapper(agreementsHandler.GetAgreementInspection))
+	apiv1.Put("/agreements/:agreementId/inspections/:inspectionId", mw.AuthMiddlewareWrapper(agreementsHandler.UpdateAgreementInspection))
+	apiv1.Post("/agreements/:agreementId/inspections/:inspectionId/items/:itemId/photos", mw.AuthMiddlewareWrapper(agreementsHandler.UploadInspectionPhotos))
+	apiv1.Post("/agreements/:agreementId/inspections/:inspectionId/sign", mw.AuthMiddlewareWrapper(agreementsHandler.SignAgreementInspection))
+	apiv1.Get("/agreements/:agreementId/utility-rates", mw.AuthMiddlewareWrapper(agreementsHandler.GetUtilityRates))
+	apiv1.Put("/agreements/:agreementId/utility-rates", mw.AuthMiddlewareWrapper(agreementsHandler.UpdateUtilityRates))
+	apiv1.Get("/agreements/:agreementId/meter-readings", mw.AuthMiddlewareWrapper(agreementsHandler.GetMeterReadings))
+	apiv1.Post("/agreements/:agreementId/meter-readings", mw.AuthMiddlewareWrapper(agreementsHandler.CreateMeterReading))
+	apiv1.Delete("/agreements/:agreementId/meter-readings/:readingId", mw.AuthMiddlewareWrapper(agreementsHandler.DeleteMeterReading))
+
+	apiv1.Get("/agreements/:agreementId/maintenance-tickets", mw.AuthMiddlewareWrapper(maintenanceHandler.GetMaintenanceTickets))
+	apiv1.Post("/agreements/:agreementId/maintenance-tickets", mw.AuthMiddlewareWrapper(maintenanceHandler.CreateMaintenanceTicket))
+	apiv1.Get("/maintenance-tickets/:ticketId", mw.AuthMiddlewareWrapper(maintenanceHandler.GetMaintenanceTicketById))
+	apiv1.Patch("/maintenance-tickets/:ticketId", mw.AuthMiddlewareWrapper(maintenanceHandler.UpdateMaintenanceTicket))
+
+	apiv1.Get("/user/me/statements", mw.AuthMiddlewareWrapper(statementsHandler.GetMonthlyStatement))
+	apiv1.Get("/user/me/statements/annual", mw.AuthMiddlewareWrapper(statementsHandler.GetAnnualStatement))
+
+	apiv1.Get("/user/me/trash", mw.AuthMiddlewareWrapper(trashHandler.GetMyTrash))
+	apiv1.Get("/trash", mw.AdminMiddlewareWrapper(trashHandler.GetAllTrash))
+	apiv1.Post("/trash/properties/:propertyId/restore", mw.AuthMiddlewareWrapper(trashHandler.RestorePropertyById))
+	apiv1.Post("/trash/appointments/:appointmentId/restore", mw.AuthMiddlewareWrapper(trashHandler.RestoreAppointmentById))
+	apiv1.Post("/trash/agreements/:agreementId/restore", mw.AuthMiddlewareWrapper(trashHandler.RestoreAgreementById))
+	apiv1.Post("/trash/users/:userId/restore", mw.AdminMiddlewareWrapper(trashHandler.RestoreUserById))
 
 	apiv1.Get("/oauth/google", googleHandler.GoogleLogin)
 	apiv1.Post("/email", emailHandler.SendVerificationEmail)
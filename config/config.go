@@ -22,12 +22,42 @@ type Config struct {
 	SmtpPort               string   `mapstructure:"SMTP_PORT"`
 	AuthRedirect           string   `mapstructure:"AUTH_REDIRECT"`
 	AuthVerificationExpire int      `mapstructure:"AUTH_VERIFICATION_EXPIRE"`
+	AdminEmails            []string `mapstructure:"ADMIN_EMAILS"`
+	TrashRetention         int      `mapstructure:"TRASH_RETENTION"`
+	TrashPurgeInterval     int      `mapstructure:"TRASH_PURGE_INTERVAL"`
+	ReminderOffsets        []int    `mapstructure:"APPOINTMENT_REMINDER_OFFSETS"`
+	ReminderInterval       int      `mapstructure:"APPOINTMENT_REMINDER_INTERVAL"`
+	ArchiveGrace           int      `mapstructure:"APPOINTMENT_ARCHIVE_GRACE"`
+	PendingExpiry          int      `mapstructure:"APPOINTMENT_PENDING_EXPIRY"`
+	ArchiveInterval        int      `mapstructure:"APPOINTMENT_ARCHIVE_INTERVAL"`
+	OverdueGraceDays       int      `mapstructure:"AGREEMENT_OVERDUE_GRACE_DAYS"`
+	LateFeeType            string   `mapstructure:"AGREEMENT_LATE_FEE_TYPE"`
+	LateFeeRate            float64  `mapstructure:"AGREEMENT_LATE_FEE_RATE"`
+	LateFeeCap             float64  `mapstructure:"AGREEMENT_LATE_FEE_CAP"`
+	OverdueInterval        int      `mapstructure:"AGREEMENT_OVERDUE_INTERVAL"`
+	DisputeTimeoutDays     int      `mapstructure:"DEPOSIT_DISPUTE_TIMEOUT_DAYS"`
+	DisputeInterval        int      `mapstructure:"DEPOSIT_DISPUTE_INTERVAL"`
+	ContractFontPath       string   `mapstructure:"CONTRACT_FONT_PATH"`
+	PaymentProvider        string   `mapstructure:"PAYMENT_PROVIDER"`
+	StripeSecretKey        string   `mapstructure:"STRIPE_SECRET_KEY"`
+	PaymentWebhookSecret   string   `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	PaymentSuccessUrl      string   `mapstructure:"PAYMENT_SUCCESS_URL"`
+	PaymentCancelUrl       string   `mapstructure:"PAYMENT_CANCEL_URL"`
 }
 
 func (cfg *Config) IsDevelopment() bool {
 	return cfg.AppEnv == "development"
 }
 
+func (cfg *Config) IsAdmin(email string) bool {
+	for _, adminEmail := range cfg.AdminEmails {
+		if adminEmail == email {
+			return true
+		}
+	}
+	return false
+}
+
 func Load(config *Config) error {
 	_ = viper.BindEnv("APP_ENV")
 	_ = viper.BindEnv("APP_PORT")
@@ -48,6 +78,27 @@ func Load(config *Config) error {
 	_ = viper.BindEnv("AUTH_VERIFICATION_EXPIRE")
 	_ = viper.BindEnv("SMTP_HOST")
 	_ = viper.BindEnv("SMTP_PORT")
+	_ = viper.BindEnv("ADMIN_EMAILS")
+	_ = viper.BindEnv("TRASH_RETENTION")
+	_ = viper.BindEnv("TRASH_PURGE_INTERVAL")
+	_ = viper.BindEnv("APPOINTMENT_REMINDER_OFFSETS")
+	_ = viper.BindEnv("APPOINTMENT_REMINDER_INTERVAL")
+	_ = viper.BindEnv("APPOINTMENT_ARCHIVE_GRACE")
+	_ = viper.BindEnv("APPOINTMENT_PENDING_EXPIRY")
+	_ = viper.BindEnv("APPOINTMENT_ARCHIVE_INTERVAL")
+	_ = viper.BindEnv("AGREEMENT_OVERDUE_GRACE_DAYS")
+	_ = viper.BindEnv("AGREEMENT_LATE_FEE_TYPE")
+	_ = viper.BindEnv("AGREEMENT_LATE_FEE_RATE")
+	_ = viper.BindEnv("AGREEMENT_LATE_FEE_CAP")
+	_ = viper.BindEnv("AGREEMENT_OVERDUE_INTERVAL")
+	_ = viper.BindEnv("DEPOSIT_DISPUTE_TIMEOUT_DAYS")
+	_ = viper.BindEnv("DEPOSIT_DISPUTE_INTERVAL")
+	_ = viper.BindEnv("CONTRACT_FONT_PATH")
+	_ = viper.BindEnv("PAYMENT_PROVIDER")
+	_ = viper.BindEnv("STRIPE_SECRET_KEY")
+	_ = viper.BindEnv("PAYMENT_WEBHOOK_SECRET")
+	_ = viper.BindEnv("PAYMENT_SUCCESS_URL")
+	_ = viper.BindEnv("PAYMENT_CANCEL_URL")
 
 	viper.AutomaticEnv()
 	viper.AllowEmptyEnv(false)
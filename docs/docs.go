@@ -44,7 +44,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create an agreement by parsing the body",
+                "description": "Create an agreement for a property of the current user by parsing the body. The status always starts at AWAITING_DEPOSIT and the total payment is computed by the server: deposit plus payment per month times duration for renting, the selling price for selling.",
                 "produces": [
                     "application/json"
                 ],
@@ -70,6 +70,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.MessageResponses"
                         }
                     },
+                    "400": {
+                        "description": "Invalid agreement type, amounts or duration",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property or dweller not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Property already sold, occupied or under another agreement",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
                     "500": {
                         "description": "Could not create agreement",
                         "schema": {
@@ -81,7 +105,7 @@ const docTemplate = `{
         },
         "/api/v1/agreements/:agreementId": {
             "get": {
-                "description": "Get an agreement by its id",
+                "description": "Get an agreement by its id, with every status transition it went through (oldest first)",
                 "produces": [
                     "application/json"
                 ],
@@ -126,7 +150,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an agreement by its id",
+                "description": "Delete an agreement by its id. Only the owner or an admin can delete it, and a RENTING or OVERDUE agreement has to be archived or cancelled first",
                 "produces": [
                     "application/json"
                 ],
@@ -150,6 +174,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.MessageResponses"
                         }
                     },
+                    "400": {
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the agreement",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Agreement is renting or overdue",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
                     "500": {
                         "description": "Could not delete agreement",
                         "schema": {
@@ -159,7 +207,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update an agreement status by id with **status** and **cancelled_message**(optional)",
+                "description": "Move an agreement to **status** with **cancelled_message**(optional). Only the transitions of the agreement lifecycle are allowed, each for either the owner or the dweller, and some of them require the related payment to have succeeded. Leaving AWAITING_DEPOSIT also requires both parties to have signed the contract",
                 "produces": [
                     "application/json"
                 ],
@@ -193,7 +241,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid agreement id",
+                        "description": "Invalid agreement id or status",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to make this transition",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid agreement transition or required payment not made",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -207,28 +273,55 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/appointments": {
+        "/api/v1/agreements/:agreementId/amendments": {
             "get": {
-                "description": "Get all appointments",
+                "description": "Get every renewal and amendment requested on an agreement, oldest first. Accepted amendments carry the version of the terms they introduced, the original terms being version 1",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "appointments"
+                    "agreements"
+                ],
+                "summary": "Get the amendments of an agreement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all appointments *use cookies*",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.AppointmentLists"
+                                "$ref": "#/definitions/models.AgreementAmendments"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
                     "500": {
-                        "description": "Could not get all appointments",
+                        "description": "Could not get agreement amendments",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -236,40 +329,68 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create an appointment by parsing the body (note is optional)",
+                "description": "Ask the other party to change the term or the monthly payment of a renting agreement that is awaiting payment, renting or overdue. The term is given either as **payment_duration** in months or as **end_date**. Installments that are already due or paid keep their amount, so the term cannot end before them. A new amendment supersedes the one still waiting for an answer",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "appointments"
+                    "agreements"
                 ],
-                "summary": "Create an appointment *use cookies*",
+                "summary": "Request an amendment to an agreement *use cookies*",
                 "parameters": [
                     {
-                        "description": "Appointment details",
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New terms and message(optional)",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreatingAppointments"
+                            "$ref": "#/definitions/models.CreatingAgreementAmendments"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Appointments created",
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.AgreementAmendments"
                         }
                     },
                     "400": {
-                        "description": "Empty dates or some of appointments duplicate with existing one",
+                        "description": "Invalid agreement id or terms",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Agreement could not be amended",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not create appointments",
+                        "description": "Could not create agreement amendment",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -277,21 +398,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/appointments/:appointmentId": {
-            "get": {
-                "description": "Get the appointment and other related information by id",
+        "/api/v1/agreements/:agreementId/amendments/:amendmentId/accept": {
+            "post": {
+                "description": "Accept an amendment made by the other party. The agreement takes the new terms as its next version and the installments that are not due yet are recalculated",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "appointments"
+                    "agreements"
                 ],
-                "summary": "Get an appointment by id *use cookies*",
+                "summary": "Accept an agreement amendment *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Appointment ID",
-                        "name": "appointmentId",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amendment ID",
+                        "name": "amendmentId",
                         "in": "path",
                         "required": true
                     }
@@ -300,107 +428,101 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.AppointmentDetails"
-                            }
+                            "$ref": "#/definitions/models.AgreementAmendments"
                         }
                     },
                     "400": {
-                        "description": "Invalid appointment id",
+                        "description": "Invalid agreement id, amendment id or terms",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the other party of the amendment",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "Could not find the specified appointment",
+                        "description": "Agreement or amendment not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Amendment is not pending or the agreement could not be amended",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not get appointment by id",
+                        "description": "Could not accept agreement amendment",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an appointment by id",
+            }
+        },
+        "/api/v1/agreements/:agreementId/amendments/:amendmentId/decline": {
+            "post": {
+                "description": "Decline an amendment made by the other party. The agreement keeps its current terms",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "appointments"
+                    "agreements"
                 ],
-                "summary": "Delete an appointment by id *use cookies*",
+                "summary": "Decline an agreement amendment *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Appointment ID",
-                        "name": "appointmentId",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amendment ID",
+                        "name": "amendmentId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Appointments deleted",
+                        "description": "Amendment declined",
                         "schema": {
                             "$ref": "#/definitions/models.MessageResponses"
                         }
                     },
-                    "500": {
-                        "description": "Could not delete appointments",
+                    "400": {
+                        "description": "Invalid agreement id or amendment id",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
-                    }
-                }
-            },
-            "patch": {
-                "description": "Update an appointment status by id with **status** and **cancelled_message**(optional)",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "appointments"
-                ],
-                "summary": "Update an appointment status by id *use cookies*",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Appointment ID",
-                        "name": "appointmentId",
-                        "in": "path",
-                        "required": true
                     },
-                    {
-                        "description": "Appointment status and cancelled message(optional)",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                    "403": {
+                        "description": "Not the other party of the amendment",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdatingAppointmentStatus"
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Appointment state updated",
+                    },
+                    "404": {
+                        "description": "Agreement or amendment not found",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
-                    "400": {
-                        "description": "Invalid appointment id",
+                    "409": {
+                        "description": "Amendment is not pending",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not update appointment status",
+                        "description": "Could not decline agreement amendment",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -408,81 +530,129 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/callback": {
+        "/api/v1/agreements/:agreementId/contract": {
             "get": {
-                "description": "Callback from google / register redirect. Basically put all query strings to this request.",
+                "description": "Download the latest generated version of the contract of an agreement as a PDF. Only the owner, the dweller and admins can download it.",
+                "produces": [
+                    "application/pdf"
+                ],
                 "tags": [
-                    "auth"
+                    "agreements"
                 ],
-                "summary": "Callback",
+                "summary": "Download the contract of an agreement *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "SCK-A1b2C3",
-                        "name": "code",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "email",
-                        "in": "query"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "name": "state",
+                        "default": "EN",
+                        "description": "Contract language, EN or TH",
+                        "name": "language",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Contract document",
                         "schema": {
-                            "$ref": "#/definitions/models.CallbackResponses"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid agreement id or contract language",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Not the owner or the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "404": {
+                        "description": "Agreement or contract not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not download contract",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/chats": {
-            "get": {
-                "description": "Get current users chat",
+            },
+            "post": {
+                "description": "Render the contract of an agreement from its current terms as a PDF and store it as a new version. Every generated version is kept along with the SHA-256 hash of its document. Only the owner and the dweller can generate it, and only until someone has signed it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chats"
+                    "agreements"
                 ],
-                "summary": "Get current users chat *use cookies*",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Generate the contract of an agreement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "EN",
+                        "description": "Contract language, EN or TH",
+                        "name": "language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ChatPreviews"
-                            }
+                            "$ref": "#/definitions/models.AgreementContracts"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id or contract language",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Another version was generated at the same time",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not generate contract",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "503": {
+                        "description": "Thai contracts are not available",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -490,48 +660,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/chats/:recvUserId": {
+        "/api/v1/agreements/:agreementId/deposit": {
             "get": {
-                "description": "Get messages chatting with recvUserId. Pagination is available.",
+                "description": "Get where the deposit of an agreement stands: UNPAID until the dweller has paid it, then HELD, PARTIALLY_DEDUCTED once a deduction is accepted and REFUNDED once the owner has refunded the rest. Every deduction is listed with its evidence photos",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "chats"
+                    "agreements"
                 ],
-                "summary": "Get messages in a chat with recvUserId *use cookies*",
+                "summary": "Get the deposit of an agreement *use cookies*",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "default 50, max 50",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Messages"
-                            }
+                            "$ref": "#/definitions/models.AgreementDeposits"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not get agreement deposit",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -539,36 +713,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/email": {
+        "/api/v1/agreements/:agreementId/deposit/deductions": {
             "post": {
-                "description": "Send a verification email to the user",
+                "description": "Claim part of the held deposit of a renting agreement once the dweller has moved in. Evidence photos (.jpg / .png, up to 10) are uploaded in formData with field ` + "`" + `evidences` + "`" + `. Deductions cannot exceed the held deposit and wait for the dweller to accept or dispute them. Only the owner can deduct",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "emails"
+                    "agreements"
                 ],
-                "summary": "Send verification email",
+                "summary": "Deduct from the deposit of an agreement *use cookies*",
                 "parameters": [
                     {
-                        "description": "User email address",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SendingEmailRequests"
-                        }
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": 1200,
+                        "name": "amount",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Broken bathroom mirror",
+                        "name": "description",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.AgreementDepositDeductions"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id, amount, description or photos",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Deposit is not held or already refunded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not create deposit deduction",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -576,393 +784,287 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/greeting": {
-            "get": {
-                "description": "says hello, world",
+        "/api/v1/agreements/:agreementId/deposit/deductions/:deductionId": {
+            "delete": {
+                "description": "Withdraw a deduction that is pending or disputed, for instance to claim a revised amount instead. Accepted deductions cannot be withdrawn. Only the owner can withdraw it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "greeting"
+                    "agreements"
+                ],
+                "summary": "Withdraw a deposit deduction *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deduction ID",
+                        "name": "deductionId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Greeting",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Deduction withdrawn",
                         "schema": {
-                            "$ref": "#/definitions/models.Greetings"
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id or deduction id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement or deduction not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Deduction is not pending or disputed, or the deposit is not held",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete deposit deduction",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
             }
         },
-        "/api/v1/login": {
+        "/api/v1/agreements/:agreementId/deposit/deductions/:deductionId/accept": {
             "post": {
-                "description": "Login with email and password",
+                "description": "Accept a deduction claimed by the owner. The amount is taken out of the deposit refund. Only the dweller can accept it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "agreements"
+                ],
+                "summary": "Accept a deposit deduction *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deduction ID",
+                        "name": "deductionId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Login with email",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Deduction accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.Properties"
+                            "$ref": "#/definitions/models.MessageResponses"
                         }
                     },
                     "400": {
-                        "description": "Empty or invalid credentials",
+                        "description": "Invalid agreement id or deduction id",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
-                    "401": {
-                        "description": "Password mismatch",
+                    "403": {
+                        "description": "Not the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Agreement or deduction not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Deduction is not pending or the deposit is not held",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/logout": {
-            "post": {
-                "description": "Logout",
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Logout",
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    }
-                }
-            }
-        },
-        "/api/v1/oauth/google": {
-            "get": {
-                "description": "Redirect to this endpoint to login with Google OAuth2. When logging in is completed, the redirection to /register in client will occur.",
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Login with Google",
-                "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not respond to deposit deduction",
                         "schema": {
-                            "type": "model"
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
             }
         },
-        "/api/v1/properties": {
-            "get": {
-                "description": "Get all properties or search properties by query",
+        "/api/v1/agreements/:agreementId/deposit/deductions/:deductionId/dispute": {
+            "post": {
+                "description": "Dispute a deduction claimed by the owner with a reason. The deposit cannot be refunded until the dispute is resolved. Disputes that are not escalated within the timeout are withdrawn. Only the dweller can dispute it",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Get or search properties",
+                "summary": "Dispute a deposit deduction *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "query",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit per page, max 50, default 20",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination page index as 1-based index, default 1",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=selling_property.price:desc` + "`" + `",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "Deduction ID",
+                        "name": "deductionId",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Filter in format ` + "`" + `\u003cjson_field\u003e[\u003coperator\u003e]:\u003cvalue\u003e` + "`" + ` where operator can only be greater than or equal ` + "`" + `gte` + "`" + ` or less than or equal ` + "`" + `lte` + "`" + `. Multiple filters can be done with ` + "`" + `,` + "`" + ` separating each filters. Ex. ` + "`" + `?filter=floor_size[gte]:22,floor_size[lte]:45.5` + "`" + `",
-                        "name": "filter",
-                        "in": "query"
+                        "description": "Reason of the dispute",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DisputingDepositDeductions"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Deduction disputed",
                         "schema": {
-                            "$ref": "#/definitions/models.AllPropertiesResponses"
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id, deduction id or reason",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement or deduction not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Deduction is not pending or the deposit is not held",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not get properties",
+                        "description": "Could not respond to deposit deduction",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/agreements/:agreementId/deposit/deductions/:deductionId/resolve": {
             "post": {
-                "description": "Create a property with formData *upload property images (array of images) in formData with field ` + "`" + `property_images` + "`" + `. Available formats are .png / .jpg / .jpeg",
+                "description": "Settle a DISPUTED deduction. The dweller can move it to ACCEPTED and the owner to WITHDRAWN. Either of them can move it to ESCALATED for an admin, who then moves it to ACCEPTED or WITHDRAWN. Disputes that are not escalated within the timeout are withdrawn",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Create a property *user cookies*",
+                "summary": "Resolve a disputed deposit deduction *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "123/4",
-                        "name": "address",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Pattaya Nua 78",
-                        "name": "alley",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 2,
-                        "name": "bathrooms",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 3,
-                        "name": "bedrooms",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Thailand",
-                        "name": "country",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Bang Phli",
-                        "name": "district",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 5,
-                        "name": "floor",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "example": 123.45,
-                        "name": "floor_size",
-                        "in": "formData"
-                    },
-                    {
-                        "enum": [
-                            "SQM",
-                            "SQFT"
-                        ],
-                        "type": "string",
-                        "example": "SQM",
-                        "x-enum-varnames": [
-                            "SQM",
-                            "SQFT"
-                        ],
-                        "name": "floor_size_unit",
-                        "in": "formData"
-                    },
-                    {
-                        "enum": [
-                            "UNFURNISHED",
-                            "PARTIALLY_FURNISHED",
-                            "FULLY_FURNISHED",
-                            "READY_TO_MOVE_IN"
-                        ],
-                        "type": "string",
-                        "example": "UNFURNISHED",
-                        "x-enum-varnames": [
-                            "UNFURNISHED",
-                            "PARTIALLY_FURNISHED",
-                            "FULLY_FURNISHED",
-                            "READY_TO_MOVE_IN"
-                        ],
-                        "name": "furnishing",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "example": [
-                            "https://image_url.com/abcd",
-                            "https://image_url.com/abcd",
-                            "https://image_url.com/abcd"
-                        ],
-                        "name": "image_urls",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "name": "is_occupied",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": true,
-                        "name": "is_sold",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "123e4567-e89b-12d3-a456-426614174000",
-                        "name": "-",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "69096",
-                        "name": "postal_code",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "example": 12345.67,
-                        "name": "price",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "example": 12345.67,
-                        "name": "price_per_month",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Et sequi dolor praes",
-                        "name": "property_description",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "123e4567-e89b-12d3-a456-426614174000",
-                        "name": "-",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Supalai",
-                        "name": "property_name",
-                        "in": "formData"
-                    },
-                    {
-                        "enum": [
-                            "CONDOMINIUM",
-                            "APARTMENT",
-                            "SEMI-DETACHED_HOUSE",
-                            "HOUSE",
-                            "SERVICED_APARTMENT",
-                            "TOWNHOUSE"
-                        ],
-                        "type": "string",
-                        "example": "CONDOMINIUM",
-                        "x-enum-varnames": [
-                            "CONDOMINIUM",
-                            "APARTMENT",
-                            "SEMI_DETACHED_HOUSE",
-                            "HOUSE",
-                            "SERVICED_APARTMENT",
-                            "TOWNHOUSE"
-                        ],
-                        "name": "property_type",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Pattaya",
-                        "name": "province",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Pattaya",
-                        "name": "street",
-                        "in": "formData"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "example": "Bang Bon",
-                        "name": "sub_district",
-                        "in": "formData"
+                        "description": "Deduction ID",
+                        "name": "deductionId",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "example": 123,
-                        "name": "unit_number",
-                        "in": "formData"
+                        "description": "Status to move the deduction to",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolvingDepositDeductions"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Property created",
+                        "description": "Deduction resolved",
                         "schema": {
                             "$ref": "#/definitions/models.MessageResponses"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid agreement id, deduction id or status",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not allowed to move the deduction to the status",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "Property id not found",
+                        "description": "Agreement or deduction not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Deduction is not disputed or the deposit is not held",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not create property",
+                        "description": "Could not resolve deposit deduction",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -970,342 +1072,399 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/properties/:propertyId": {
-            "get": {
-                "description": "Get property by its id",
+        "/api/v1/agreements/:agreementId/deposit/refund": {
+            "post": {
+                "description": "Record the refund of the held deposit minus the accepted deductions once the agreement is archived or cancelled. Every deduction must be accepted or withdrawn first. The refund is a payment from the owner to the dweller and shows up in the payment history of both. Only the owner can refund",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Get property by propertyId",
+                "summary": "Refund the deposit of an agreement *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Property id",
-                        "name": "propertyId",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Properties"
+                            "$ref": "#/definitions/models.Payments"
                         }
                     },
                     "400": {
-                        "description": "Invalid property id",
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "Property id not found",
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Agreement is not over, deductions are unresolved or the deposit is not held",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not refund deposit",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a property, owned by the current user, by its id",
+            }
+        },
+        "/api/v1/agreements/:agreementId/inspections": {
+            "get": {
+                "description": "Get the move-in and move-out reports of an agreement with their items, condition ratings, notes and photos, including the reports that have been superseded",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Delete a property *use cookies*",
+                "summary": "Get inspection reports of an agreement *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Property id",
-                        "name": "propertyId",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Property deleted",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AgreementInspections"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid agreement id",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner or the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "Property id not found",
+                        "description": "Agreement not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not delete property",
+                        "description": "Could not get agreement inspections",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
             },
-            "patch": {
-                "description": "Update a property with formData *upload **NEW** property images (array of images) in formData with field ` + "`" + `property_images` + "`" + `. Available formats are .png / .jpg / .jpeg *If you want to keep the old images, you need to include them in the formData with field ` + "`" + `image_urls` + "`" + ` as an array of strings",
+            "post": {
+                "description": "Write the MOVE_IN or MOVE_OUT report of a renting agreement room by room. Each item has a condition (EXCELLENT, GOOD, FAIR, POOR or DAMAGED) and optional notes, and photos are uploaded per item afterwards. The move-in report can be written from AWAITING_PAYMENT until the agreement is archived, the move-out report from RENTING. Either the owner or the dweller can write it. Writing a report while the current one is signed off by one party only supersedes it, so a party can contest a report they disagree with",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Update a property *user cookies*",
+                "summary": "Write an inspection report *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Property id",
-                        "name": "propertyId",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "example": "123/4",
-                        "name": "address",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Pattaya Nua 78",
-                        "name": "alley",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 2,
-                        "name": "bathrooms",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 3,
-                        "name": "bedrooms",
-                        "in": "formData"
+                        "description": "Inspection report",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatingInspections"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AgreementInspections"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "Thailand",
-                        "name": "country",
-                        "in": "formData"
+                    "400": {
+                        "description": "Invalid agreement id, type or items",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "Bang Phli",
-                        "name": "district",
-                        "in": "formData"
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "example": 5,
-                        "name": "floor",
-                        "in": "formData"
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "type": "number",
-                        "example": 123.45,
-                        "name": "floor_size",
-                        "in": "formData"
+                    "409": {
+                        "description": "Report not signed off yet or final, or not allowed in the current status",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
+                    "500": {
+                        "description": "Could not create agreement inspection",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/agreements/:agreementId/inspections/:inspectionId": {
+            "get": {
+                "description": "Get an inspection report of an agreement with its items, condition ratings, notes and photos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agreements"
+                ],
+                "summary": "Get an inspection report *use cookies*",
+                "parameters": [
                     {
-                        "enum": [
-                            "SQM",
-                            "SQFT"
-                        ],
                         "type": "string",
-                        "example": "SQM",
-                        "x-enum-varnames": [
-                            "SQM",
-                            "SQFT"
-                        ],
-                        "name": "floor_size_unit",
-                        "in": "formData"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "UNFURNISHED",
-                            "PARTIALLY_FURNISHED",
-                            "FULLY_FURNISHED",
-                            "READY_TO_MOVE_IN"
-                        ],
                         "type": "string",
-                        "example": "UNFURNISHED",
-                        "x-enum-varnames": [
-                            "UNFURNISHED",
-                            "PARTIALLY_FURNISHED",
-                            "FULLY_FURNISHED",
-                            "READY_TO_MOVE_IN"
-                        ],
-                        "name": "furnishing",
-                        "in": "formData"
+                        "description": "Inspection ID",
+                        "name": "inspectionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AgreementInspections"
+                        }
                     },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "example": [
-                            "https://image_url.com/abcd",
-                            "https://image_url.com/abcd",
-                            "https://image_url.com/abcd"
-                        ],
-                        "name": "image_urls",
-                        "in": "formData"
+                    "400": {
+                        "description": "Invalid agreement id or inspection id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "name": "is_occupied",
-                        "in": "formData"
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "type": "boolean",
-                        "example": true,
-                        "name": "is_sold",
-                        "in": "formData"
+                    "404": {
+                        "description": "Agreement or inspection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
+                    "500": {
+                        "description": "Could not get agreement inspection",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the items of an inspection report nobody has signed off yet. The photos of the replaced items are dropped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agreements"
+                ],
+                "summary": "Rewrite the items of an inspection report *use cookies*",
+                "parameters": [
                     {
                         "type": "string",
-                        "example": "123e4567-e89b-12d3-a456-426614174000",
-                        "name": "-",
-                        "in": "formData"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "example": "69096",
-                        "name": "postal_code",
-                        "in": "formData"
+                        "description": "Inspection ID",
+                        "name": "inspectionId",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "number",
-                        "example": 12345.67,
-                        "name": "price",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "number",
-                        "example": 12345.67,
-                        "name": "price_per_month",
-                        "in": "formData"
+                        "description": "Inspection items",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatingInspections"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AgreementInspections"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "Et sequi dolor praes",
-                        "name": "property_description",
-                        "in": "formData"
+                    "400": {
+                        "description": "Invalid agreement id, inspection id or items",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "123e4567-e89b-12d3-a456-426614174000",
-                        "name": "-",
-                        "in": "formData"
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "Supalai",
-                        "name": "property_name",
-                        "in": "formData"
+                    "404": {
+                        "description": "Agreement or inspection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
-                    {
-                        "enum": [
-                            "CONDOMINIUM",
-                            "APARTMENT",
-                            "SEMI-DETACHED_HOUSE",
-                            "HOUSE",
-                            "SERVICED_APARTMENT",
-                            "TOWNHOUSE"
-                        ],
-                        "type": "string",
-                        "example": "CONDOMINIUM",
-                        "x-enum-varnames": [
-                            "CONDOMINIUM",
-                            "APARTMENT",
-                            "SEMI_DETACHED_HOUSE",
-                            "HOUSE",
-                            "SERVICED_APARTMENT",
-                            "TOWNHOUSE"
-                        ],
-                        "name": "property_type",
-                        "in": "formData"
+                    "409": {
+                        "description": "Report already signed off or not allowed in the current status",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
                     },
+                    "500": {
+                        "description": "Could not update agreement inspection",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/agreements/:agreementId/inspections/:inspectionId/items/:itemId/photos": {
+            "post": {
+                "description": "Upload photos (.jpg / .png) of an item of an inspection report nobody has signed off yet in formData with field ` + "`" + `photos` + "`" + `. An item can have up to 10 photos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agreements"
+                ],
+                "summary": "Upload photos of an inspection item *use cookies*",
+                "parameters": [
                     {
                         "type": "string",
-                        "example": "Pattaya",
-                        "name": "province",
-                        "in": "formData"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "example": "Pattaya",
-                        "name": "street",
-                        "in": "formData"
+                        "description": "Inspection ID",
+                        "name": "inspectionId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "example": "Bang Bon",
-                        "name": "sub_district",
-                        "in": "formData"
+                        "description": "Item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "example": 123,
-                        "name": "unit_number",
-                        "in": "formData"
+                        "type": "file",
+                        "description": "Photos of the item",
+                        "name": "photos",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Property updated",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.AgreementInspectionItems"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid ids or photos",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner or the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "Property id not found",
+                        "description": "Agreement, inspection or item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Report already signed off or not allowed in the current status",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not update property",
+                        "description": "Could not upload inspection photos",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1313,91 +1472,118 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/properties/favorites/:propertyId": {
+        "/api/v1/agreements/:agreementId/inspections/:inspectionId/sign": {
             "post": {
-                "description": "Add property to the current user favorites",
+                "description": "Record that the owner or the dweller agrees with an inspection report. The party that did not write the report signs it off first, which freezes it, and the report is final once its author has signed it off too",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Add property to favorites *use cookies*",
+                "summary": "Sign off an inspection report *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Property id",
-                        "name": "propertyId",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inspection ID",
+                        "name": "inspectionId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Property added to favorites",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.AgreementInspections"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id or inspection id, or the report is empty",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner or the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "Property id not found",
+                        "description": "Agreement or inspection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Already signed off, not signed off by the other party yet or superseded",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not add favorite property",
+                        "description": "Could not sign agreement inspection",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove property to the current user favorites",
+            }
+        },
+        "/api/v1/agreements/:agreementId/inspections/comparison": {
+            "get": {
+                "description": "Put the items of the move-out report side by side with the move-in report, matched by room and name. Items missing from one report are null on that side, and items whose condition got worse are flagged",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Remove property to favorites *use cookies*",
+                "summary": "Compare the move-out report with the move-in report *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Property id",
-                        "name": "propertyId",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Property removed from favorites",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.InspectionComparisons"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner or the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "Property id not found",
+                        "description": "Agreement or one of the reports not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not remove favorite property",
+                        "description": "Could not get agreement inspections",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1405,76 +1591,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/register": {
-            "post": {
-                "description": "Create user with formData **\\***upload profile image in formData with field ` + "`" + `profile_image` + "`" + `. Available formats are .png / .jpg / .jpeg",
+        "/api/v1/agreements/:agreementId/installments": {
+            "get": {
+                "description": "Get every monthly installment of a renting agreement with its due date, amount and status, along with the paid and outstanding totals. Utilities billed with an installment are itemized in its meter readings and included in the totals. Only the owner and the dweller can view it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "agreements"
                 ],
-                "summary": "Register *use cookies*",
+                "summary": "Get the installment schedule of an agreement *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "email@email.com",
-                        "name": "email",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "John",
-                        "name": "first_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Doe",
-                        "name": "last_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "password1234",
-                        "name": "password",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "0812345678",
-                        "name": "phone_number",
-                        "in": "formData"
-                    },
-                    {
-                        "enum": [
-                            "EMAIL",
-                            "GOOGLE"
-                        ],
-                        "type": "string",
-                        "x-enum-varnames": [
-                            "EMAIL",
-                            "GOOGLE"
-                        ],
-                        "name": "registered_type",
-                        "in": "formData"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User created",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.AgreementInstallmentSchedules"
                         }
                     },
                     "400": {
-                        "description": "Invalid user info",
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not create user",
+                        "description": "Could not get agreement installments",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1482,102 +1644,166 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/top10properties": {
+        "/api/v1/agreements/:agreementId/maintenance-tickets": {
             "get": {
-                "description": "Get top 10 properties with the most favorites, sorted by the number of favorites then by the newest properties",
+                "description": "Get the maintenance tickets of an agreement with their photos, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "maintenance"
                 ],
-                "summary": "Get top 10 properties",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Properties"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Could not get top 10 properties",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponses"
-                        }
+                "summary": "Get maintenance tickets of an agreement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     }
-                }
-            }
-        },
-        "/api/v1/user/:userId": {
-            "get": {
-                "description": "Get a user by its id",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
                 ],
-                "summary": "Get user by id",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Users"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MaintenanceTickets"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid user id",
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Agreement not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not get maintenance tickets",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a user by its id",
+            "post": {
+                "description": "Report something to repair in a rented unit. Category is one of AIR_CONDITIONING, PLUMBING, ELECTRICAL, APPLIANCE, STRUCTURAL, PEST_CONTROL or OTHER and priority one of LOW, MEDIUM, HIGH or URGENT. Photos (.jpg / .png, up to 10) are uploaded in formData with field ` + "`" + `photos` + "`" + ` and kept private, so only the parties can download them through their ` + "`" + `image_url` + "`" + `. Only the dweller can open tickets, while the agreement is RENTING. The owner receives the ticket as a ` + "`" + `TICKET` + "`" + ` event on the chat websocket",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "maintenance"
+                ],
+                "summary": "Open a maintenance ticket *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "AIR_CONDITIONING",
+                            "PLUMBING",
+                            "ELECTRICAL",
+                            "APPLIANCE",
+                            "STRUCTURAL",
+                            "PEST_CONTROL",
+                            "OTHER"
+                        ],
+                        "type": "string",
+                        "example": "AIR_CONDITIONING",
+                        "x-enum-varnames": [
+                            "AirConditioningMaintenance",
+                            "PlumbingMaintenance",
+                            "ElectricalMaintenance",
+                            "ApplianceMaintenance",
+                            "StructuralMaintenance",
+                            "PestControlMaintenance",
+                            "OtherMaintenance"
+                        ],
+                        "name": "category",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Water drips from the indoor unit whenever it runs",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "LOW",
+                            "MEDIUM",
+                            "HIGH",
+                            "URGENT"
+                        ],
+                        "type": "string",
+                        "example": "HIGH",
+                        "x-enum-varnames": [
+                            "LowPriority",
+                            "MediumPriority",
+                            "HighPriority",
+                            "UrgentPriority"
+                        ],
+                        "name": "priority",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Bedroom air-con is leaking",
+                        "name": "title",
+                        "in": "formData"
+                    }
                 ],
-                "summary": "Delete user by id  *use cookies*",
                 "responses": {
-                    "200": {
-                        "description": "User deleted",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.MaintenanceTickets"
                         }
                     },
                     "400": {
-                        "description": "Invalid user id",
+                        "description": "Invalid agreement id, ticket details or photos",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Agreement is not renting",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not create maintenance ticket",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1585,85 +1811,151 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/greeting": {
+        "/api/v1/agreements/:agreementId/meter-readings": {
             "get": {
-                "description": "says hello to current user",
+                "description": "Get every water and electricity meter reading of an agreement with the units used, the rate and the amount billed with each installment",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "greeting"
+                    "agreements"
+                ],
+                "summary": "Get the meter readings of an agreement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Greeting with auth required *use cookies*",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Greetings"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AgreementMeterReadings"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid agreement id",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/user/me": {
-            "get": {
-                "description": "Get current user info",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "Get current user info *use cookies*",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
                         "schema": {
-                            "$ref": "#/definitions/models.Users"
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not get meter readings",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user/me/agreements": {
-            "get": {
-                "description": "Get all agreements related to the user",
+            },
+            "post": {
+                "description": "Record the WATER or ELECTRICITY meter reading of a month with a photo (.jpg / .png) of the meter in formData with field ` + "`" + `photo` + "`" + `. The units used since the previous reading are charged at the current rate and added to the given installment, which has to be the earliest unpaid one and due within a month. An installment billed for utilities can no longer be dropped by shortening the term. The first reading of a meter needs the reading it started from. Only the owner can record readings, while the agreement is RENTING or OVERDUE",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "agreements"
                 ],
-                "summary": "Get my agreements *use cookies*",
+                "summary": "Record a meter reading *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order by ASC or DESC",
-                        "name": "order",
-                        "in": "query"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": 10452,
+                        "name": "current_reading",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "name": "installment_number",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "example": 10240,
+                        "name": "previous_reading",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "WATER",
+                            "ELECTRICITY"
+                        ],
+                        "type": "string",
+                        "example": "ELECTRICITY",
+                        "x-enum-varnames": [
+                            "WaterUtility",
+                            "ElectricityUtility"
+                        ],
+                        "name": "utility_type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photo of the meter",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MyAgreementResponses"
+                            "$ref": "#/definitions/models.AgreementMeterReadings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id, reading or photo",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement, installment or utility rates not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Installment already paid, not the earliest unpaid one, not due yet, meter already read or not renting",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not get my agreements",
+                        "description": "Could not create meter reading",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1671,33 +1963,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/me/appointments": {
-            "get": {
-                "description": "Get all appointments related to the user",
+        "/api/v1/agreements/:agreementId/meter-readings/:readingId": {
+            "delete": {
+                "description": "Take back the latest reading of a meter along with its charge, as long as its installment has not been paid. Only the owner can delete readings",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "appointments"
+                    "agreements"
                 ],
-                "summary": "Get my appointments *use cookies*",
+                "summary": "Delete a meter reading *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order by ASC or DESC",
-                        "name": "order",
-                        "in": "query"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reading ID",
+                        "name": "readingId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Meter reading deleted",
                         "schema": {
-                            "$ref": "#/definitions/models.MyAppointmentResponses"
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id or reading id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement or reading not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Not the latest reading or the installment has been paid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not get my appointments",
+                        "description": "Could not delete meter reading",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1705,51 +2029,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/me/favorites": {
+        "/api/v1/agreements/:agreementId/photos/:path": {
             "get": {
-                "description": "Get all properties that the current user has added to favorites",
+                "description": "Download deduction evidence, an inspection photo, a meter photo or a maintenance photo of an agreement. The photos are private and their ` + "`" + `image_url` + "`" + ` points here. Only the owner, the dweller and admins can download them.",
                 "produces": [
-                    "application/json"
+                    "image/jpeg"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Get my favorite properties *use cookies*",
+                "summary": "Download a photo attached to an agreement *use cookies*",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pagination limit per page, max 50, default 20",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination page index as 1-based index, default 1",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=selling_property.price:desc` + "`" + `",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "Path of the photo, e.g. deductions/123e4567-e89b-12d3-a456-426614174000-1.jpeg",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Photo",
                         "schema": {
-                            "$ref": "#/definitions/models.MyFavoritePropertiesResponses"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement or photo not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not get favorite properties",
+                        "description": "Could not download photo",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1757,84 +2089,143 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/me/financial-information": {
+        "/api/v1/agreements/:agreementId/signatures": {
             "get": {
-                "description": "Get current user financial information",
+                "description": "Get the signature audit trail of the signed contract of an agreement: who signed, how, when and from which IP address. The stored document is hashed again and compared with the hash frozen at the first signature, so any change to it is reported. Only the owner, the dweller and admins can view it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "agreements"
+                ],
+                "summary": "Verify the signatures of an agreement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user financial information *use cookies*",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserFinancialInformations"
+                            "$ref": "#/definitions/models.AgreementSignatureVerifications"
                         }
                     },
                     "400": {
-                        "description": "Invalid user id",
+                        "description": "Invalid agreement id",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found or not signed yet",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not verify signatures",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Update the current user financial information with data from the body",
+            "post": {
+                "description": "Sign the generated contract of an agreement awaiting deposit. A signature is either typed, or drawn and uploaded as a PNG in formData with field ` + "`" + `signature_image` + "`" + `. The first signature freezes the hash of the contract so both parties sign the same document, and ` + "`" + `language` + "`" + ` is only needed for it (defaults to EN). The signer's IP address and the time are recorded. The agreement can only progress once both the owner and the dweller have signed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "agreements"
                 ],
-                "summary": "Update the current user financial information *use cookies*",
+                "summary": "Sign the contract of an agreement *use cookies*",
                 "parameters": [
                     {
-                        "description": "User financial information",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UserFinancialInformations"
-                        }
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "EN",
+                            "TH"
+                        ],
+                        "type": "string",
+                        "example": "TH",
+                        "x-enum-varnames": [
+                            "EnglishContract",
+                            "ThaiContract"
+                        ],
+                        "name": "language",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "TYPED",
+                            "DRAWN"
+                        ],
+                        "type": "string",
+                        "example": "TYPED",
+                        "x-enum-varnames": [
+                            "TypedSignature",
+                            "DrawnSignature"
+                        ],
+                        "name": "signature_type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "John Doe",
+                        "name": "signed_name",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User financial information updated",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.AgreementSignatures"
                         }
                     },
                     "400": {
-                        "description": "Invalid user financial information",
+                        "description": "Invalid agreement id, signature or contract language",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement or contract not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Already signed, not awaiting deposit or the document has changed",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not update user financial information",
+                        "description": "Could not sign agreement",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1842,109 +2233,121 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/me/personal-information": {
-            "put": {
-                "description": "Update specifying userId with formData **\\***upload profile image in formData with field ` + "`" + `profile_image` + "`" + `. Available formats are .png / .jpg / .jpeg",
+        "/api/v1/agreements/:agreementId/utility-rates": {
+            "get": {
+                "description": "Get what the owner charges per unit of water and electricity",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "agreements"
                 ],
-                "summary": "Update current user personal information *use cookies*",
+                "summary": "Get the utility rates of an agreement *use cookies*",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "John",
-                        "name": "first_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Doe",
-                        "name": "last_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "example": "0812345678",
-                        "name": "phone_number",
-                        "in": "formData"
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User personal information updated",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponses"
+                            "$ref": "#/definitions/models.AgreementUtilityRates"
                         }
                     },
                     "400": {
-                        "description": "Invalid user info",
+                        "description": "Invalid agreement id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Agreement not found or rates not set",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Could not update user",
+                        "description": "Could not get utility rates",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user/me/properties": {
-            "get": {
-                "description": "Get all properties owned by the current user",
+            },
+            "put": {
+                "description": "Set what the owner charges per unit of water and electricity in a renting agreement. Readings already recorded keep the rate they were charged at. Only the owner can set the rates",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "property"
+                    "agreements"
                 ],
-                "summary": "Get my properties *use cookies*",
+                "summary": "Set the utility rates of an agreement *use cookies*",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Pagination limit per page, max 50, default 20",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination page index as 1-based index, default 1",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Agreement ID",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=selling_property.price:desc` + "`" + `",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "Unit rates",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatingUtilityRates"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.MyPropertiesResponses"
+                            "$ref": "#/definitions/models.AgreementUtilityRates"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id or rates",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "403": {
-                        "description": "Unauthorized",
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Not a renting agreement or the agreement is over",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not save utility rates",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
@@ -1952,952 +2355,6958 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/me/registered": {
+        "/api/v1/appointments": {
             "get": {
-                "description": "Get user registered type",
+                "description": "Get all appointments",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "appointments"
                 ],
-                "summary": "Get user registered type *use cookies*",
+                "summary": "Get all appointments *use cookies*",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Sessions"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AppointmentLists"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get all appointments",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user/me/verify": {
+            },
             "post": {
-                "description": "Verify user by citizen id and citizen id image",
+                "description": "Create an appointment by parsing the body (note is optional). The appointment date must be the start time of one of the open slots of the property",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "appointments"
                 ],
-                "summary": "Verify user *use cookies*",
+                "summary": "Create an appointment *use cookies*",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "1100111111111",
-                        "name": "citizen_id",
-                        "in": "formData"
+                        "description": "Appointment details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatingAppointments"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Verified",
+                    "201": {
+                        "description": "Appointments created",
                         "schema": {
                             "$ref": "#/definitions/models.MessageResponses"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Empty dates or some of appointments duplicate with existing one",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/users": {
-            "get": {
-                "description": "Get all users",
-                "produces": [
-                    "application/json"
-                ],
+                    },
+                    "403": {
+                        "description": "Owners cannot book their own property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Appointment date is not a free slot",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create appointments",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/appointments/:appointmentId": {
+            "get": {
+                "description": "Get the appointment and other related information by id. The attendance of the dweller is only included for the owner and admins",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "users"
+                    "appointments"
+                ],
+                "summary": "Get an appointment by id *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Users"
+                                "$ref": "#/definitions/models.AppointmentDetails"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Could not get appointment by id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an appointment by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Delete an appointment by id *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Appointments deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete appointments",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update an appointment status by id with **status** and **cancelled_message**(optional). Only the owner can confirm or reject a PENDING appointment, either party can cancel a PENDING or CONFIRMED appointment, and archiving is done by the system",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Update an appointment status by id *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Appointment status and cancelled message(optional)",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatingAppointmentStatus"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Appointment state updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not allowed to make this transition",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Invalid appointment transition",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update appointment status",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponses"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "enums.AgreementStatus": {
-            "type": "string",
-            "enum": [
-                "AWAITING_DEPOSIT",
-                "AWAITING_PAYMENT",
-                "RENTING",
-                "CANCELLED",
-                "OVERDUE",
-                "ARCHIVED"
-            ],
-            "x-enum-varnames": [
-                "AwaitingDepositAgreement",
-                "AwaitingPaymentAgreement",
-                "RentingAgreement",
-                "CancelledAgreement",
-                "OverdueAgreement",
-                "ArchivedAgreement"
-            ]
-        },
-        "enums.AgreementTypes": {
-            "type": "string",
-            "enum": [
-                "SELLING",
-                "RENTING"
-            ],
-            "x-enum-varnames": [
-                "AgreementForSell",
-                "AgreementForRent"
-            ]
         },
-        "enums.AppointmentStatus": {
-            "type": "string",
-            "enum": [
-                "PENDING",
-                "CONFIRMED",
-                "REJECTED",
-                "CANCELLED",
-                "ARCHIVED"
-            ],
-            "x-enum-varnames": [
-                "PendingAppointment",
-                "ConfirmedAppointment",
-                "RejectedAppointment",
-                "CancelledAppointment",
-                "ArchivedAppointment"
-            ]
+        "/api/v1/appointments/:appointmentId/attendances": {
+            "get": {
+                "description": "Get the check-ins and no-shows recorded for an appointment",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get appointment attendances *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AppointmentAttendances"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get appointment attendances",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
         },
-        "enums.BankNames": {
-            "type": "string",
-            "enum": [
-                "KBANK",
-                "BBL",
-                "KTB",
-                "BAY",
+        "/api/v1/appointments/:appointmentId/check-in": {
+            "post": {
+                "description": "Record that the current user showed up to a confirmed appointment. Check-in is open from 30 minutes before until 2 hours after the appointment. Checking in replaces a no-show the other party has reported",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Check in to an appointment *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AppointmentAttendances"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Appointment is not confirmed, outside the check-in window or attendance already recorded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not record attendance",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/appointments/:appointmentId/histories": {
+            "get": {
+                "description": "Get every status transition of an appointment with the actor and time, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get appointment status history *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AppointmentStatusHistories"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get appointment status histories",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/appointments/:appointmentId/ics": {
+            "get": {
+                "description": "Download a single appointment as an .ics file with the property address and the contact of the other party",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Download appointment as iCalendar *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get appointment by id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/appointments/:appointmentId/no-show": {
+            "post": {
+                "description": "Record that the other party of a confirmed appointment did not show up. A no-show can be reported from 15 minutes after the start until 48 hours after the appointment, unless the other party has checked in. The report is replaced if the other party checks in later",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Report the other party as a no-show *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AppointmentAttendances"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Appointment is not confirmed, outside the no-show window or attendance already recorded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not record attendance",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/appointments/:appointmentId/proposals": {
+            "get": {
+                "description": "Get every reschedule proposal made on an appointment, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get appointment reschedule proposals *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AppointmentProposals"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get appointment proposals",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Propose to move a PENDING or CONFIRMED appointment to another date. Dwellers can only propose open slots of the property while owners can propose any time the property is not booked. A new proposal supersedes the one still waiting for an answer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Propose a new appointment date *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proposed date and message(optional)",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatingAppointmentProposals"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AppointmentProposals"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id or body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Appointment could not be rescheduled or the date is not free",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create appointment proposal",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/appointments/:appointmentId/proposals/:proposalId/accept": {
+            "post": {
+                "description": "Accept a proposal made by the other party and move the appointment to the proposed date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Accept a reschedule proposal *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proposal ID",
+                        "name": "proposalId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Appointment rescheduled",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id or proposal id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the other party of the proposal",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Appointment or proposal not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Proposal is not pending or the date is no longer free",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not accept appointment proposal",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/appointments/:appointmentId/proposals/:proposalId/decline": {
+            "post": {
+                "description": "Decline a proposal made by the other party. The appointment keeps its current date",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Decline a reschedule proposal *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proposal ID",
+                        "name": "proposalId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Proposal declined",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id or proposal id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the other party of the proposal",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Appointment or proposal not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Proposal is not pending",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not decline appointment proposal",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/callback": {
+            "get": {
+                "description": "Callback from google / register redirect. Basically put all query strings to this request.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "SCK-A1b2C3",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CallbackResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calendar/:token": {
+            "get": {
+                "description": "Get the iCalendar (RFC 5545) feed of the user owning the token. Cancelled and rejected appointments stay in the feed as cancelled events",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Calendar feed token, optionally followed by .ics",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Could not find the specified calendar feed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get calendar feed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/chats": {
+            "get": {
+                "description": "Get current users chat",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Get current users chat *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ChatPreviews"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/chats/:recvUserId": {
+            "get": {
+                "description": "Get messages chatting with recvUserId. Pagination is available.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Get messages in a chat with recvUserId *use cookies*",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "default 50, max 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Messages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/email": {
+            "post": {
+                "description": "Send a verification email to the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emails"
+                ],
+                "summary": "Send verification email",
+                "parameters": [
+                    {
+                        "description": "User email address",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SendingEmailRequests"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/greeting": {
+            "get": {
+                "description": "says hello, world",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "greeting"
+                ],
+                "summary": "Greeting",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Greetings"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login": {
+            "post": {
+                "description": "Login with email and password",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login with email",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Properties"
+                        }
+                    },
+                    "400": {
+                        "description": "Empty or invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "401": {
+                        "description": "Password mismatch",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/logout": {
+            "post": {
+                "description": "Logout",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/api/v1/maintenance-tickets/:ticketId": {
+            "get": {
+                "description": "Get a maintenance ticket with its photos and every status update",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Get a maintenance ticket *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MaintenanceTickets"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticket id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner or the dweller",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Ticket not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get maintenance ticket",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Move a ticket from OPEN to ACKNOWLEDGED, SCHEDULED and RESOLVED, with an optional note. Scheduling needs the time of the visit and a scheduled ticket can be scheduled again. Only the owner can update tickets. The dweller receives the updated ticket as a ` + "`" + `TICKET` + "`" + ` event on the chat websocket",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Update the status of a maintenance ticket *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatingMaintenanceTickets"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MaintenanceTickets"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticket id, status or visit time",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Ticket not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Status cannot be moved to the given one",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update maintenance ticket",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/google": {
+            "get": {
+                "description": "Redirect to this endpoint to login with Google OAuth2. When logging in is completed, the redirection to /register in client will occur.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login with Google",
+                "responses": {
+                    "307": {
+                        "description": "Temporary Redirect"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "model"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payments": {
+            "post": {
+                "description": "Create a pending payment and open a checkout session for it with the payment provider. Redirect the user to ` + "`" + `checkout_url` + "`" + ` to pay. The payment succeeds once the provider confirms it through the webhook",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Create a payment *use cookies*",
+                "parameters": [
+                    {
+                        "description": "Payment to make",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Payments"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatedPayments"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Installment or agreement not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Installment already paid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create payment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "502": {
+                        "description": "Could not start the checkout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payments/:paymentId": {
+            "get": {
+                "description": "Get a payment of the current user. A pending payment is checked with the payment provider, so the success page can poll it until the payment goes through",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Get a payment *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "paymentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payments"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the payer",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get payment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "502": {
+                        "description": "Could not get the status of the checkout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payments/:paymentId/refund": {
+            "post": {
+                "description": "Refund a successful payment in full through the payment provider. The payment stops counting as paid, and the installment it settled is due again. Admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Refund a payment *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "paymentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payments"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment not refundable",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not record payment refund",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "502": {
+                        "description": "Could not refund payment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payments/webhook": {
+            "post": {
+                "description": "Called by the payment provider when a checkout session changes. The body is verified against the ` + "`" + `Stripe-Signature` + "`" + ` header before a paid session completes its payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Receive payment provider events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signature of the body",
+                        "name": "Stripe-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not verify webhook",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not complete payment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties": {
+            "get": {
+                "description": "Get all properties or search properties by query",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Get or search properties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit per page, max 50, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination page index as 1-based index, default 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=selling_property.price:desc` + "`" + `",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter in format ` + "`" + `\u003cjson_field\u003e[\u003coperator\u003e]:\u003cvalue\u003e` + "`" + ` where operator can only be greater than or equal ` + "`" + `gte` + "`" + ` or less than or equal ` + "`" + `lte` + "`" + `. Multiple filters can be done with ` + "`" + `,` + "`" + ` separating each filters. Ex. ` + "`" + `?filter=floor_size[gte]:22,floor_size[lte]:45.5` + "`" + `",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AllPropertiesResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get properties",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a property with formData *upload property images (array of images) in formData with field ` + "`" + `property_images` + "`" + `. Available formats are .png / .jpg / .jpeg",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Create a property *user cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "123/4",
+                        "name": "address",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Pattaya Nua 78",
+                        "name": "alley",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 2,
+                        "name": "bathrooms",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "name": "bedrooms",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Thailand",
+                        "name": "country",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Bang Phli",
+                        "name": "district",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "name": "floor",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "example": 123.45,
+                        "name": "floor_size",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "SQM",
+                            "SQFT"
+                        ],
+                        "type": "string",
+                        "example": "SQM",
+                        "x-enum-varnames": [
+                            "SQM",
+                            "SQFT"
+                        ],
+                        "name": "floor_size_unit",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "UNFURNISHED",
+                            "PARTIALLY_FURNISHED",
+                            "FULLY_FURNISHED",
+                            "READY_TO_MOVE_IN"
+                        ],
+                        "type": "string",
+                        "example": "UNFURNISHED",
+                        "x-enum-varnames": [
+                            "UNFURNISHED",
+                            "PARTIALLY_FURNISHED",
+                            "FULLY_FURNISHED",
+                            "READY_TO_MOVE_IN"
+                        ],
+                        "name": "furnishing",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "example": [
+                            "https://image_url.com/abcd",
+                            "https://image_url.com/abcd",
+                            "https://image_url.com/abcd"
+                        ],
+                        "name": "image_urls",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "name": "is_occupied",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "is_sold",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "123e4567-e89b-12d3-a456-426614174000",
+                        "name": "-",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "69096",
+                        "name": "postal_code",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "example": 12345.67,
+                        "name": "price",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "example": 12345.67,
+                        "name": "price_per_month",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Et sequi dolor praes",
+                        "name": "property_description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "123e4567-e89b-12d3-a456-426614174000",
+                        "name": "-",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Supalai",
+                        "name": "property_name",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "CONDOMINIUM",
+                            "APARTMENT",
+                            "SEMI-DETACHED_HOUSE",
+                            "HOUSE",
+                            "SERVICED_APARTMENT",
+                            "TOWNHOUSE"
+                        ],
+                        "type": "string",
+                        "example": "CONDOMINIUM",
+                        "x-enum-varnames": [
+                            "CONDOMINIUM",
+                            "APARTMENT",
+                            "SEMI_DETACHED_HOUSE",
+                            "HOUSE",
+                            "SERVICED_APARTMENT",
+                            "TOWNHOUSE"
+                        ],
+                        "name": "property_type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Pattaya",
+                        "name": "province",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Pattaya",
+                        "name": "street",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Bang Bon",
+                        "name": "sub_district",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 123,
+                        "name": "unit_number",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId": {
+            "get": {
+                "description": "Get property by its id, including its images and attachments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Get property by propertyId",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Properties"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a property, owned by the current user, by its id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Delete a property *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a property with formData *upload **NEW** property images (array of images) in formData with field ` + "`" + `property_images` + "`" + `. Available formats are .png / .jpg / .jpeg *If you want to keep the old images, you need to include them in the formData with field ` + "`" + `image_urls` + "`" + ` as an array of strings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Update a property *user cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "123/4",
+                        "name": "address",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Pattaya Nua 78",
+                        "name": "alley",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 2,
+                        "name": "bathrooms",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "name": "bedrooms",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Thailand",
+                        "name": "country",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Bang Phli",
+                        "name": "district",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "name": "floor",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "example": 123.45,
+                        "name": "floor_size",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "SQM",
+                            "SQFT"
+                        ],
+                        "type": "string",
+                        "example": "SQM",
+                        "x-enum-varnames": [
+                            "SQM",
+                            "SQFT"
+                        ],
+                        "name": "floor_size_unit",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "UNFURNISHED",
+                            "PARTIALLY_FURNISHED",
+                            "FULLY_FURNISHED",
+                            "READY_TO_MOVE_IN"
+                        ],
+                        "type": "string",
+                        "example": "UNFURNISHED",
+                        "x-enum-varnames": [
+                            "UNFURNISHED",
+                            "PARTIALLY_FURNISHED",
+                            "FULLY_FURNISHED",
+                            "READY_TO_MOVE_IN"
+                        ],
+                        "name": "furnishing",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "example": [
+                            "https://image_url.com/abcd",
+                            "https://image_url.com/abcd",
+                            "https://image_url.com/abcd"
+                        ],
+                        "name": "image_urls",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "name": "is_occupied",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "is_sold",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "123e4567-e89b-12d3-a456-426614174000",
+                        "name": "-",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "69096",
+                        "name": "postal_code",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "example": 12345.67,
+                        "name": "price",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "example": 12345.67,
+                        "name": "price_per_month",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Et sequi dolor praes",
+                        "name": "property_description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "123e4567-e89b-12d3-a456-426614174000",
+                        "name": "-",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Supalai",
+                        "name": "property_name",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "CONDOMINIUM",
+                            "APARTMENT",
+                            "SEMI-DETACHED_HOUSE",
+                            "HOUSE",
+                            "SERVICED_APARTMENT",
+                            "TOWNHOUSE"
+                        ],
+                        "type": "string",
+                        "example": "CONDOMINIUM",
+                        "x-enum-varnames": [
+                            "CONDOMINIUM",
+                            "APARTMENT",
+                            "SEMI_DETACHED_HOUSE",
+                            "HOUSE",
+                            "SERVICED_APARTMENT",
+                            "TOWNHOUSE"
+                        ],
+                        "name": "property_type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Pattaya",
+                        "name": "province",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Pattaya",
+                        "name": "street",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Bang Bon",
+                        "name": "sub_district",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 123,
+                        "name": "unit_number",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/attachments": {
+            "get": {
+                "description": "Get the documents, floor plans, video and tour links of a property in display order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Get property attachments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PropertyAttachments"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get property attachments",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add an attachment to a property. DOCUMENT and FLOOR_PLAN attachments are uploaded in formData with field ` + "`" + `attachment` + "`" + `. Documents must be .pdf and floor plans .pdf / .png / .jpg. VIDEO_URL and TOUR_URL attachments take a link in field ` + "`" + `url` + "`" + ` instead. Only the property owner or an admin can add attachments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Add a property attachment *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "DOCUMENT",
+                            "FLOOR_PLAN",
+                            "VIDEO_URL",
+                            "TOUR_URL"
+                        ],
+                        "type": "string",
+                        "example": "TOUR_URL",
+                        "x-enum-varnames": [
+                            "DocumentAttachment",
+                            "FloorPlanAttachment",
+                            "VideoUrlAttachment",
+                            "TourUrlAttachment"
+                        ],
+                        "name": "attachment_type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "360 tour",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "https://tour_url.com/abcd",
+                        "name": "url",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PropertyAttachments"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid attachment type, content type or url",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create property attachment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/attachments/:attachmentId": {
+            "delete": {
+                "description": "Delete an attachment of a property. Only the property owner or an admin can delete attachments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Delete a property attachment *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment id",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property attachment deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id or attachment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property or attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete property attachment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/attachments/order": {
+            "put": {
+                "description": "Set the display order of the attachments of a property. The body must list every attachment id of the property exactly once. Only the property owner or an admin can reorder attachments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Reorder property attachments *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attachment ids in the new order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReorderingPropertyAttachments"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachments in their new order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PropertyAttachments"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or attachment order",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not reorder property attachments",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/availabilities": {
+            "get": {
+                "description": "Get the weekly viewing availability of a property and its upcoming exceptions. Day of week starts from 0 (Sunday) and times are in Bangkok time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get property viewing availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PropertyAvailabilityResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get property availabilities",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the weekly viewing availability of a property. Each availability is split into slots of **slot_duration** minutes (15 - 240). Availabilities on the same day must not overlap. Only the property owner or an admin can set availability",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Set property viewing availability *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Weekly availabilities",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatingPropertyAvailabilities"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property availabilities updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid availability",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update property availabilities",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/availabilities/exceptions": {
+            "post": {
+                "description": "Block a whole day or a time range of a date by leaving **is_available** false (omit start and end time to block the whole day), or open extra slots on a date by setting **is_available** with start time, end time and slot duration. Only the property owner or an admin can add exceptions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Add an availability exception *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Exception details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatingAvailabilityExceptions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PropertyAvailabilityExceptions"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid exception",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create availability exception",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/availabilities/exceptions/:exceptionId": {
+            "delete": {
+                "description": "Delete an availability exception of a property. Only the property owner or an admin can delete exceptions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Delete an availability exception *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exception id",
+                        "name": "exceptionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Availability exception deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id or exception id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property or exception not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not delete availability exception",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/histories": {
+            "get": {
+                "description": "Get the audit trail of a property, newest version first. Only the property owner or an admin can view it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Get property version history *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit per page, max 50, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination page index as 1-based index, default 1",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PropertyHistoriesResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get property histories",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/histories/:version/revert": {
+            "post": {
+                "description": "Restore the property details, prices and images recorded at the given version. The revert is recorded as a new version. Only the property owner or an admin can revert",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Revert a property to a previous version *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to revert to",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property reverted",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id or version",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property or version not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not revert property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/:propertyId/slots": {
+            "get": {
+                "description": "Get the viewing slots of a property that can still be booked. Dates are in Bangkok time and both ends are inclusive. The range defaults to the next 7 days and spans at most 31 days",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get open viewing slots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First date of the range in YYYY-MM-DD, default today",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last date of the range in YYYY-MM-DD, default 6 days after from",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AppointmentSlots"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id or date range",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get open slots",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/favorites/:propertyId": {
+            "post": {
+                "description": "Add property to the current user favorites",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Add property to favorites *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property added to favorites",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not add favorite property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove property to the current user favorites",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Remove property to favorites *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property removed from favorites",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property id not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not remove favorite property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/register": {
+            "post": {
+                "description": "Create user with formData **\\***upload profile image in formData with field ` + "`" + `profile_image` + "`" + `. Available formats are .png / .jpg / .jpeg",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "email@email.com",
+                        "name": "email",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "John",
+                        "name": "first_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Doe",
+                        "name": "last_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "password1234",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0812345678",
+                        "name": "phone_number",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "EMAIL",
+                            "GOOGLE"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "EMAIL",
+                            "GOOGLE"
+                        ],
+                        "name": "registered_type",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User created",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user info",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/top10properties": {
+            "get": {
+                "description": "Get top 10 properties with the most favorites, sorted by the number of favorites then by the newest properties",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Get top 10 properties",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Properties"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get top 10 properties",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash": {
+            "get": {
+                "description": "Get every deleted user, property, appointment and agreement that has not been purged yet. Admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get all trash *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrashResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash/agreements/:agreementId/restore": {
+            "post": {
+                "description": "Restore a deleted agreement. Only the owner, the dweller or an admin can restore",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted agreement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agreement id",
+                        "name": "agreementId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Agreement restored",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid agreement id or duplicate agreement",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the agreement",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Agreement not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Property or user has been deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not restore agreement",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash/appointments/:appointmentId/restore": {
+            "post": {
+                "description": "Restore a deleted appointment. Only the owner, the dweller or an admin can restore",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted appointment *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment id",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Appointment restored",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid appointment id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not in the appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Appointment not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Property or user has been deleted, or the slot has been booked since",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not restore appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash/properties/:propertyId/restore": {
+            "post": {
+                "description": "Restore a deleted property together with its images, attachments, prices and appointments that were deleted with it. Only the property owner or an admin can restore",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted property *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Property id",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Property restored",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid property id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the property owner",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "Property not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Property owner has been deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not restore property",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash/users/:userId/restore": {
+            "post": {
+                "description": "Restore a deleted user together with the properties, appointments and financial information deleted with it. Favorites and verifications are not restored. Admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted user *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User restored",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "409": {
+                        "description": "Email or phone number has been taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not restore user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/:userId": {
+            "get": {
+                "description": "Get a user by its id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by id",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Users"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a user by its id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user by id  *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "User deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/:userId/attendance": {
+            "get": {
+                "description": "Get how many viewings a user checked in to and how many they missed, optionally only as an owner or as a dweller. Only the user, admins and owners the user has made an appointment with can see it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get user attendance statistics *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count attendances as ` + "`" + `OWNER` + "`" + ` or as ` + "`" + `DWELLER` + "`" + `",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AttendanceStatistics"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id or role",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "No appointment with the user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get attendance statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/greeting": {
+            "get": {
+                "description": "says hello to current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "greeting"
+                ],
+                "summary": "Greeting with auth required *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Greetings"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me": {
+            "get": {
+                "description": "Get current user info",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user info *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Users"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/agreements": {
+            "get": {
+                "description": "Get all agreements related to the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agreements"
+                ],
+                "summary": "Get my agreements *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order by created time ASC or DESC, used when sort is not given",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list agreements as ` + "`" + `OWNER` + "`" + ` or as ` + "`" + `DWELLER` + "`" + `",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit per page per role, max 50, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination page index as 1-based index, default 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=agreement_date:desc` + "`" + `",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter in format ` + "`" + `\u003cjson_field\u003e[\u003coperator\u003e]:\u003cvalue\u003e` + "`" + ` where operator can be ` + "`" + `gte` + "`" + `, ` + "`" + `lte` + "`" + ` or ` + "`" + `eql` + "`" + `. Filterable fields are ` + "`" + `status` + "`" + `, ` + "`" + `agreement_type` + "`" + `, ` + "`" + `agreement_date` + "`" + ` (RFC 3339 time or YYYY-MM-DD date) and ` + "`" + `property.property_id` + "`" + `. Ex. ` + "`" + `?filter=status[eql]:AWAITING_DEPOSIT,agreement_date[gte]:2024-02-01` + "`" + `",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MyAgreementResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role, sort or filter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get my agreements",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/appointments": {
+            "get": {
+                "description": "Get all appointments related to the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get my appointments *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order by created time ASC or DESC, used when sort is not given",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list appointments as ` + "`" + `OWNER` + "`" + ` or as ` + "`" + `DWELLER` + "`" + `",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit per page per role, max 50, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination page index as 1-based index, default 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=appointment_date:desc` + "`" + `",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter in format ` + "`" + `\u003cjson_field\u003e[\u003coperator\u003e]:\u003cvalue\u003e` + "`" + ` where operator can be ` + "`" + `gte` + "`" + `, ` + "`" + `lte` + "`" + ` or ` + "`" + `eql` + "`" + `. Filterable fields are ` + "`" + `status` + "`" + `, ` + "`" + `appointment_date` + "`" + ` (RFC 3339 time or YYYY-MM-DD date) and ` + "`" + `property.property_id` + "`" + `. Ex. ` + "`" + `?filter=status[eql]:CONFIRMED,appointment_date[gte]:2024-02-01` + "`" + `",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MyAppointmentResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role, sort or filter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get my appointments",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/calendar": {
+            "get": {
+                "description": "Get the secret iCalendar feed url listing every appointment of the current user as an owner and as a dweller. The feed is created on first use",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Get my calendar feed *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CalendarFeeds"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get calendar feed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/calendar/regenerate": {
+            "post": {
+                "description": "Replace the secret token of the calendar feed of the current user. The old feed url stops working immediately",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "appointments"
+                ],
+                "summary": "Regenerate my calendar feed *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CalendarFeeds"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not create calendar feed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/favorites": {
+            "get": {
+                "description": "Get all properties that the current user has added to favorites",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Get my favorite properties *use cookies*",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit per page, max 50, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination page index as 1-based index, default 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=selling_property.price:desc` + "`" + `",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MyFavoritePropertiesResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get favorite properties",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/financial-information": {
+            "get": {
+                "description": "Get current user financial information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user financial information *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserFinancialInformations"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the current user financial information with data from the body",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update the current user financial information *use cookies*",
+                "parameters": [
+                    {
+                        "description": "User financial information",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserFinancialInformations"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User financial information updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user financial information",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update user financial information",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/notification-preferences": {
+            "get": {
+                "description": "Get which notifications the current user receives by email",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get current user notification preferences *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserNotificationPreferences"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update which notifications the current user receives by email. Preferences left out of the body are unchanged",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update current user notification preferences *use cookies*",
+                "parameters": [
+                    {
+                        "description": "Notification preferences",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatingUserNotificationPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserNotificationPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update notification preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/personal-information": {
+            "put": {
+                "description": "Update specifying userId with formData **\\***upload profile image in formData with field ` + "`" + `profile_image` + "`" + `. Available formats are .png / .jpg / .jpeg",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update current user personal information *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "John",
+                        "name": "first_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Doe",
+                        "name": "last_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0812345678",
+                        "name": "phone_number",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User personal information updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user info",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not update user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/properties": {
+            "get": {
+                "description": "Get all properties owned by the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "property"
+                ],
+                "summary": "Get my properties *use cookies*",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit per page, max 50, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination page index as 1-based index, default 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort in format ` + "`" + `\u003cjson_field\u003e:\u003cdirection\u003e` + "`" + ` where direction can only be ` + "`" + `desc` + "`" + ` or ` + "`" + `asc` + "`" + `. Ex. ` + "`" + `?sort=selling_property.price:desc` + "`" + `",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MyPropertiesResponses"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/registered": {
+            "get": {
+                "description": "Get user registered type",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user registered type *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sessions"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/statements": {
+            "get": {
+                "description": "Get what the current user received as an owner in a month, per property: rent collected, late fees, utilities, deposits received and refunded, and the deposits held at the end of the month. A refunded payment is taken off in the month it was refunded. Months are in Thai time and default to the current one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statements"
+                ],
+                "summary": "Get my monthly statement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year, defaults to the current year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month from 1 to 12, defaults to the current month",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MonthlyStatements"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid year or month",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get statement",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/statements/annual": {
+            "get": {
+                "description": "Get a year of what the current user received as an owner, month by month and property by property, to declare rental income. Set ` + "`" + `format` + "`" + ` to CSV or PDF to download it as a file instead of JSON",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "statements"
+                ],
+                "summary": "Get my annual statement *use cookies*",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year, defaults to the current year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON, CSV or PDF, defaults to JSON",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AnnualStatements"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid year or format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not get statement",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/trash": {
+            "get": {
+                "description": "Get the properties, appointments and agreements of the current user that have been deleted but not purged yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get my trash *use cookies*",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrashResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/me/verify": {
+            "post": {
+                "description": "Verify user by citizen id and citizen id image",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Verify user *use cookies*",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "1100111111111",
+                        "name": "citizen_id",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verified",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponses"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "description": "Get all users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Users"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponses"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "enums.ActorRoles": {
+            "type": "string",
+            "enum": [
+                "OWNER",
+                "DWELLER",
+                "SYSTEM",
+                "ADMIN"
+            ],
+            "x-enum-varnames": [
+                "OwnerActor",
+                "DwellerActor",
+                "SystemActor",
+                "AdminActor"
+            ]
+        },
+        "enums.AgreementStatus": {
+            "type": "string",
+            "enum": [
+                "AWAITING_DEPOSIT",
+                "AWAITING_PAYMENT",
+                "RENTING",
+                "CANCELLED",
+                "OVERDUE",
+                "ARCHIVED"
+            ],
+            "x-enum-varnames": [
+                "AwaitingDepositAgreement",
+                "AwaitingPaymentAgreement",
+                "RentingAgreement",
+                "CancelledAgreement",
+                "OverdueAgreement",
+                "ArchivedAgreement"
+            ]
+        },
+        "enums.AgreementTypes": {
+            "type": "string",
+            "enum": [
+                "SELLING",
+                "RENTING"
+            ],
+            "x-enum-varnames": [
+                "AgreementForSell",
+                "AgreementForRent"
+            ]
+        },
+        "enums.AmendmentTypes": {
+            "type": "string",
+            "enum": [
+                "RENEWAL",
+                "AMENDMENT"
+            ],
+            "x-enum-varnames": [
+                "RenewalAmendment",
+                "TermsAmendment"
+            ]
+        },
+        "enums.AppointmentStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "CONFIRMED",
+                "REJECTED",
+                "CANCELLED",
+                "ARCHIVED",
+                "EXPIRED"
+            ],
+            "x-enum-varnames": [
+                "PendingAppointment",
+                "ConfirmedAppointment",
+                "RejectedAppointment",
+                "CancelledAppointment",
+                "ArchivedAppointment",
+                "ExpiredAppointment"
+            ]
+        },
+        "enums.AttendanceStatus": {
+            "type": "string",
+            "enum": [
+                "CHECKED_IN",
+                "NO_SHOW"
+            ],
+            "x-enum-varnames": [
+                "CheckedInAttendance",
+                "NoShowAttendance"
+            ]
+        },
+        "enums.BankNames": {
+            "type": "string",
+            "enum": [
+                "KBANK",
+                "BBL",
+                "KTB",
+                "BAY",
                 "CIMB",
                 "TTB",
                 "SCB",
                 "GSB",
                 ""
             ],
-            "x-enum-comments": {
-                "BAY": "\"BANK OF AYUDHYA\"",
-                "BBL": "\"BANGKOK BANK\"",
-                "CIMB": "\"CIMB THAI BANK\"",
-                "GSB": "\"GOVERNMENT SAVINGS BANK\"",
-                "KBANK": "\"KASIKORN BANK\"",
-                "KTB": "\"KRUNG THAI BANK\"",
-                "NULL": "\"BANK NOT SELECTED\"",
-                "SCB": "\"SIAM COMMERCIAL BANK\"",
-                "TTB": "\"TMBTHANACHART BANK\""
-            },
+            "x-enum-comments": {
+                "BAY": "\"BANK OF AYUDHYA\"",
+                "BBL": "\"BANGKOK BANK\"",
+                "CIMB": "\"CIMB THAI BANK\"",
+                "GSB": "\"GOVERNMENT SAVINGS BANK\"",
+                "KBANK": "\"KASIKORN BANK\"",
+                "KTB": "\"KRUNG THAI BANK\"",
+                "NULL": "\"BANK NOT SELECTED\"",
+                "SCB": "\"SIAM COMMERCIAL BANK\"",
+                "TTB": "\"TMBTHANACHART BANK\""
+            },
+            "x-enum-varnames": [
+                "KBANK",
+                "BBL",
+                "KTB",
+                "BAY",
+                "CIMB",
+                "TTB",
+                "SCB",
+                "GSB",
+                "NULL"
+            ]
+        },
+        "enums.CardColors": {
+            "type": "string",
+            "enum": [
+                "LIGHT_BLUE",
+                "BLUE",
+                "DARK_BLUE",
+                "VERY_DARK_BLUE"
+            ],
+            "x-enum-varnames": [
+                "LIGHT_BLUE",
+                "BLUE",
+                "DARK_BLUE",
+                "VERY_DARK_BLUE"
+            ]
+        },
+        "enums.ContractLanguages": {
+            "type": "string",
+            "enum": [
+                "EN",
+                "TH"
+            ],
+            "x-enum-varnames": [
+                "EnglishContract",
+                "ThaiContract"
+            ]
+        },
+        "enums.DeductionStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "ACCEPTED",
+                "DISPUTED",
+                "ESCALATED",
+                "WITHDRAWN"
+            ],
+            "x-enum-varnames": [
+                "PendingDeduction",
+                "AcceptedDeduction",
+                "DisputedDeduction",
+                "EscalatedDeduction",
+                "WithdrawnDeduction"
+            ]
+        },
+        "enums.DepositStatus": {
+            "type": "string",
+            "enum": [
+                "UNPAID",
+                "HELD",
+                "PARTIALLY_DEDUCTED",
+                "REFUNDED"
+            ],
+            "x-enum-varnames": [
+                "UnpaidDeposit",
+                "HeldDeposit",
+                "PartiallyDeductedDeposit",
+                "RefundedDeposit"
+            ]
+        },
+        "enums.FloorSizeUnits": {
+            "type": "string",
+            "enum": [
+                "SQM",
+                "SQFT"
+            ],
+            "x-enum-varnames": [
+                "SQM",
+                "SQFT"
+            ]
+        },
+        "enums.Furnishing": {
+            "type": "string",
+            "enum": [
+                "UNFURNISHED",
+                "PARTIALLY_FURNISHED",
+                "FULLY_FURNISHED",
+                "READY_TO_MOVE_IN"
+            ],
             "x-enum-varnames": [
-                "KBANK",
-                "BBL",
-                "KTB",
-                "BAY",
-                "CIMB",
-                "TTB",
-                "SCB",
-                "GSB",
-                "NULL"
+                "UNFURNISHED",
+                "PARTIALLY_FURNISHED",
+                "FULLY_FURNISHED",
+                "READY_TO_MOVE_IN"
+            ]
+        },
+        "enums.InspectionTypes": {
+            "type": "string",
+            "enum": [
+                "MOVE_IN",
+                "MOVE_OUT"
+            ],
+            "x-enum-varnames": [
+                "MoveInInspection",
+                "MoveOutInspection"
+            ]
+        },
+        "enums.InstallmentStatus": {
+            "type": "string",
+            "enum": [
+                "UNPAID",
+                "PAID",
+                "OVERDUE"
+            ],
+            "x-enum-varnames": [
+                "UnpaidInstallment",
+                "PaidInstallment",
+                "OverdueInstallment"
+            ]
+        },
+        "enums.ItemConditions": {
+            "type": "string",
+            "enum": [
+                "EXCELLENT",
+                "GOOD",
+                "FAIR",
+                "POOR",
+                "DAMAGED"
+            ],
+            "x-enum-varnames": [
+                "ExcellentCondition",
+                "GoodCondition",
+                "FairCondition",
+                "PoorCondition",
+                "DamagedCondition"
+            ]
+        },
+        "enums.MaintenanceCategories": {
+            "type": "string",
+            "enum": [
+                "AIR_CONDITIONING",
+                "PLUMBING",
+                "ELECTRICAL",
+                "APPLIANCE",
+                "STRUCTURAL",
+                "PEST_CONTROL",
+                "OTHER"
+            ],
+            "x-enum-varnames": [
+                "AirConditioningMaintenance",
+                "PlumbingMaintenance",
+                "ElectricalMaintenance",
+                "ApplianceMaintenance",
+                "StructuralMaintenance",
+                "PestControlMaintenance",
+                "OtherMaintenance"
+            ]
+        },
+        "enums.MaintenancePriorities": {
+            "type": "string",
+            "enum": [
+                "LOW",
+                "MEDIUM",
+                "HIGH",
+                "URGENT"
+            ],
+            "x-enum-varnames": [
+                "LowPriority",
+                "MediumPriority",
+                "HighPriority",
+                "UrgentPriority"
+            ]
+        },
+        "enums.MaintenanceStatus": {
+            "type": "string",
+            "enum": [
+                "OPEN",
+                "ACKNOWLEDGED",
+                "SCHEDULED",
+                "RESOLVED"
+            ],
+            "x-enum-varnames": [
+                "OpenMaintenance",
+                "AcknowledgedMaintenance",
+                "ScheduledMaintenance",
+                "ResolvedMaintenance"
+            ]
+        },
+        "enums.PaymentTypes": {
+            "type": "string",
+            "enum": [
+                "DEPOSIT",
+                "RENT",
+                "PURCHASE",
+                "DEPOSIT_REFUND"
+            ],
+            "x-enum-varnames": [
+                "DepositPayment",
+                "RentPayment",
+                "PurchasePayment",
+                "DepositRefundPayment"
+            ]
+        },
+        "enums.PropertyAttachmentTypes": {
+            "type": "string",
+            "enum": [
+                "DOCUMENT",
+                "FLOOR_PLAN",
+                "VIDEO_URL",
+                "TOUR_URL"
+            ],
+            "x-enum-varnames": [
+                "DocumentAttachment",
+                "FloorPlanAttachment",
+                "VideoUrlAttachment",
+                "TourUrlAttachment"
+            ]
+        },
+        "enums.PropertyHistoryActions": {
+            "type": "string",
+            "enum": [
+                "CREATE",
+                "UPDATE",
+                "DELETE",
+                "REVERT"
+            ],
+            "x-enum-varnames": [
+                "CreatePropertyHistory",
+                "UpdatePropertyHistory",
+                "DeletePropertyHistory",
+                "RevertPropertyHistory"
+            ]
+        },
+        "enums.PropertyTypes": {
+            "type": "string",
+            "enum": [
+                "CONDOMINIUM",
+                "APARTMENT",
+                "SEMI-DETACHED_HOUSE",
+                "HOUSE",
+                "SERVICED_APARTMENT",
+                "TOWNHOUSE"
+            ],
+            "x-enum-varnames": [
+                "CONDOMINIUM",
+                "APARTMENT",
+                "SEMI_DETACHED_HOUSE",
+                "HOUSE",
+                "SERVICED_APARTMENT",
+                "TOWNHOUSE"
+            ]
+        },
+        "enums.ProposalStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "ACCEPTED",
+                "DECLINED",
+                "SUPERSEDED"
+            ],
+            "x-enum-varnames": [
+                "PendingProposal",
+                "AcceptedProposal",
+                "DeclinedProposal",
+                "SupersededProposal"
+            ]
+        },
+        "enums.RegisteredTypes": {
+            "type": "string",
+            "enum": [
+                "EMAIL",
+                "GOOGLE"
+            ],
+            "x-enum-varnames": [
+                "EMAIL",
+                "GOOGLE"
+            ]
+        },
+        "enums.SessionType": {
+            "type": "string",
+            "enum": [
+                "REGISTER",
+                "LOGIN"
+            ],
+            "x-enum-varnames": [
+                "SessionRegister",
+                "SessionLogin"
+            ]
+        },
+        "enums.SignatureTypes": {
+            "type": "string",
+            "enum": [
+                "TYPED",
+                "DRAWN"
+            ],
+            "x-enum-varnames": [
+                "TypedSignature",
+                "DrawnSignature"
             ]
         },
-        "enums.CardColors": {
-            "type": "string",
-            "enum": [
-                "LIGHT_BLUE",
-                "BLUE",
-                "DARK_BLUE",
-                "VERY_DARK_BLUE"
-            ],
-            "x-enum-varnames": [
-                "LIGHT_BLUE",
-                "BLUE",
-                "DARK_BLUE",
-                "VERY_DARK_BLUE"
-            ]
+        "enums.UtilityTypes": {
+            "type": "string",
+            "enum": [
+                "WATER",
+                "ELECTRICITY"
+            ],
+            "x-enum-varnames": [
+                "WaterUtility",
+                "ElectricityUtility"
+            ]
+        },
+        "models.AgreementAmendments": {
+            "type": "object",
+            "properties": {
+                "amendment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "amendment_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AmendmentTypes"
+                        }
+                    ],
+                    "example": "RENEWAL"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2026-02-18T00:00:00+07:00"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Renewing for another year"
+                },
+                "payment_duration": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "payment_per_month": {
+                    "type": "number",
+                    "example": 16000
+                },
+                "previous_payment_duration": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "previous_payment_per_month": {
+                    "type": "number",
+                    "example": 15000
+                },
+                "proposed_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "proposer_role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ActorRoles"
+                        }
+                    ],
+                    "example": "OWNER"
+                },
+                "responded_at": {
+                    "type": "string",
+                    "example": "2024-02-17T11:00:00Z"
+                },
+                "responded_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ProposalStatus"
+                        }
+                    ],
+                    "example": "PENDING"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.AgreementContracts": {
+            "type": "object",
+            "properties": {
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "agreement_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "contract_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "created_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "document_hash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "language": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ContractLanguages"
+                        }
+                    ],
+                    "example": "TH"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.AgreementDeductionEvidences": {
+            "type": "object",
+            "properties": {
+                "image_url": {
+                    "type": "string",
+                    "example": "https://image_url.com/abcd"
+                }
+            }
+        },
+        "models.AgreementDepositDeductions": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 1200
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                },
+                "created_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "deduction_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Broken bathroom mirror"
+                },
+                "dispute_reason": {
+                    "type": "string",
+                    "example": "The mirror was already cracked when I moved in"
+                },
+                "evidences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementDeductionEvidences"
+                    }
+                },
+                "resolved_at": {
+                    "type": "string",
+                    "example": "2024-02-20T09:00:00Z"
+                },
+                "responded_at": {
+                    "type": "string",
+                    "example": "2024-02-17T11:00:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.DeductionStatus"
+                        }
+                    ],
+                    "example": "PENDING"
+                }
+            }
+        },
+        "models.AgreementDeposits": {
+            "type": "object",
+            "properties": {
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "deducted_amount": {
+                    "type": "number",
+                    "example": 4500
+                },
+                "deductions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementDepositDeductions"
+                    }
+                },
+                "deposit_amount": {
+                    "type": "number",
+                    "example": 30000
+                },
+                "held_amount": {
+                    "type": "number",
+                    "example": 30000
+                },
+                "pending_amount": {
+                    "type": "number",
+                    "example": 1200
+                },
+                "refund": {
+                    "$ref": "#/definitions/models.Payments"
+                },
+                "refundable_amount": {
+                    "type": "number",
+                    "example": 25500
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.DepositStatus"
+                        }
+                    ],
+                    "example": "PARTIALLY_DEDUCTED"
+                }
+            }
+        },
+        "models.AgreementDetails": {
+            "type": "object",
+            "properties": {
+                "agreement_date": {
+                    "type": "string",
+                    "example": "2021-01-01T00:00:00Z"
+                },
+                "agreement_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                },
+                "agreement_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementTypes"
+                        }
+                    ],
+                    "example": "SELLING"
+                },
+                "cancelled_message": {
+                    "type": "string",
+                    "example": "This is cancelled message."
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deposit_amount": {
+                    "type": "number",
+                    "example": 1000000
+                },
+                "dweller": {
+                    "$ref": "#/definitions/models.DwellerAgreementDetails"
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.OwnerAgreementDetails"
+                },
+                "payment_duration": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "payment_per_month": {
+                    "type": "number",
+                    "example": 1000000
+                },
+                "property": {
+                    "$ref": "#/definitions/models.PropertyAgreementDetails"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementStatus"
+                        }
+                    ],
+                    "example": "AWAITING_DEPOSIT"
+                },
+                "status_histories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementStatusHistories"
+                    }
+                },
+                "total_payment": {
+                    "type": "number",
+                    "example": 12000000
+                }
+            }
+        },
+        "models.AgreementInspectionItems": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ItemConditions"
+                        }
+                    ],
+                    "example": "GOOD"
+                },
+                "item": {
+                    "type": "string",
+                    "example": "Mirror"
+                },
+                "item_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Small scratch on the lower left corner"
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementInspectionPhotos"
+                    }
+                },
+                "room": {
+                    "type": "string",
+                    "example": "Bathroom"
+                }
+            }
+        },
+        "models.AgreementInspectionPhotos": {
+            "type": "object",
+            "properties": {
+                "image_url": {
+                    "type": "string",
+                    "example": "https://image_url.com/abcd"
+                }
+            }
+        },
+        "models.AgreementInspections": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                },
+                "created_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "dweller_signed_at": {
+                    "type": "string",
+                    "example": "2024-02-17T11:30:00Z"
+                },
+                "inspection_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "inspection_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.InspectionTypes"
+                        }
+                    ],
+                    "example": "MOVE_IN"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementInspectionItems"
+                    }
+                },
+                "owner_signed_at": {
+                    "type": "string",
+                    "example": "2024-02-17T11:00:00Z"
+                },
+                "superseded_at": {
+                    "type": "string",
+                    "example": "2024-02-18T09:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                }
+            }
+        },
+        "models.AgreementInstallmentSchedules": {
+            "type": "object",
+            "properties": {
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "installments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementInstallments"
+                    }
+                },
+                "outstanding_amount": {
+                    "type": "number",
+                    "example": 150000
+                },
+                "paid_amount": {
+                    "type": "number",
+                    "example": 30000
+                }
+            }
+        },
+        "models.AgreementInstallments": {
+            "type": "object",
+            "properties": {
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 15000
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2024-02-18T00:00:00Z"
+                },
+                "installment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "installment_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "late_fee": {
+                    "type": "number",
+                    "example": 750
+                },
+                "meter_readings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementMeterReadings"
+                    }
+                },
+                "paid_at": {
+                    "type": "string",
+                    "example": "2024-02-17T11:00:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.InstallmentStatus"
+                        }
+                    ],
+                    "example": "UNPAID"
+                },
+                "utility_amount": {
+                    "type": "number",
+                    "example": 2236
+                }
+            }
+        },
+        "models.AgreementLists": {
+            "type": "object",
+            "properties": {
+                "agreement_date": {
+                    "type": "string",
+                    "example": "2021-01-01T00:00:00Z"
+                },
+                "agreement_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                },
+                "agreement_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementTypes"
+                        }
+                    ],
+                    "example": "SELLING"
+                },
+                "cancelled_message": {
+                    "type": "string",
+                    "example": "This is cancelled message."
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.OwnerAgreementLists"
+                },
+                "property": {
+                    "$ref": "#/definitions/models.PropertyAgreementLists"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementStatus"
+                        }
+                    ],
+                    "example": "AWAITING_DEPOSIT"
+                }
+            }
+        },
+        "models.AgreementMeterReadings": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 1696
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                },
+                "current_reading": {
+                    "type": "number",
+                    "example": 10452
+                },
+                "image_url": {
+                    "type": "string",
+                    "example": "https://image_url.com/abcd"
+                },
+                "installment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "installment_number": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "previous_reading": {
+                    "type": "number",
+                    "example": 10240
+                },
+                "reading_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "recorded_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "unit_rate": {
+                    "type": "number",
+                    "example": 8
+                },
+                "units": {
+                    "type": "number",
+                    "example": 212
+                },
+                "utility_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.UtilityTypes"
+                        }
+                    ],
+                    "example": "ELECTRICITY"
+                }
+            }
+        },
+        "models.AgreementSignatureVerifications": {
+            "type": "object",
+            "properties": {
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "contract": {
+                    "$ref": "#/definitions/models.AgreementContracts"
+                },
+                "current_hash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "frozen_hash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "is_document_intact": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_fully_signed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "signatures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementSignatures"
+                    }
+                }
+            }
+        },
+        "models.AgreementSignatures": {
+            "type": "object",
+            "properties": {
+                "contract_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "document_hash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ActorRoles"
+                        }
+                    ],
+                    "example": "OWNER"
+                },
+                "signature_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "signature_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.SignatureTypes"
+                        }
+                    ],
+                    "example": "TYPED"
+                },
+                "signed_at": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "signed_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.AgreementStatusHistories": {
+            "type": "object",
+            "properties": {
+                "actor_role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ActorRoles"
+                        }
+                    ],
+                    "example": "OWNER"
+                },
+                "actor_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "from_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementStatus"
+                        }
+                    ],
+                    "example": "AWAITING_DEPOSIT"
+                },
+                "history_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "This is a cancelled message"
+                },
+                "to_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementStatus"
+                        }
+                    ],
+                    "example": "AWAITING_PAYMENT"
+                }
+            }
+        },
+        "models.AgreementUtilityRates": {
+            "type": "object",
+            "properties": {
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "electricity_rate": {
+                    "type": "number",
+                    "example": 8
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                },
+                "water_rate": {
+                    "type": "number",
+                    "example": 18
+                }
+            }
+        },
+        "models.Agreements": {
+            "type": "object",
+            "properties": {
+                "agreement_date": {
+                    "type": "string",
+                    "example": "2021-01-01T00:00:00Z"
+                },
+                "agreement_id": {
+                    "type": "string"
+                },
+                "agreement_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementTypes"
+                        }
+                    ],
+                    "example": "SELLING"
+                },
+                "cancelled_message": {
+                    "type": "string",
+                    "example": "This is cancelled message."
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deposit_amount": {
+                    "type": "number",
+                    "example": 1000000
+                },
+                "dweller_user_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                },
+                "owner_user_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                },
+                "payment_duration": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "payment_per_month": {
+                    "type": "number",
+                    "example": 1000000
+                },
+                "property_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementStatus"
+                        }
+                    ],
+                    "example": "AWAITING_DEPOSIT"
+                },
+                "total_payment": {
+                    "type": "number",
+                    "example": 12000000
+                }
+            }
+        },
+        "models.AllPropertiesResponses": {
+            "type": "object",
+            "properties": {
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Properties"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.AnnualStatements": {
+            "type": "object",
+            "properties": {
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MonthlyStatements"
+                    }
+                },
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyStatements"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/models.StatementAmounts"
+                },
+                "year": {
+                    "type": "integer",
+                    "example": 2024
+                }
+            }
+        },
+        "models.AppointmentAttendances": {
+            "type": "object",
+            "properties": {
+                "appointment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-18T11:05:00Z"
+                },
+                "reported_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ActorRoles"
+                        }
+                    ],
+                    "example": "DWELLER"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AttendanceStatus"
+                        }
+                    ],
+                    "example": "CHECKED_IN"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.AppointmentDetails": {
+            "type": "object",
+            "properties": {
+                "appointment_date": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "appointment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "cancelled_message": {
+                    "type": "string",
+                    "example": "This is a cancelled message"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "dweller": {
+                    "$ref": "#/definitions/models.DwellerAppointmentDetails"
+                },
+                "dweller_attendance": {
+                    "$ref": "#/definitions/models.AttendanceStatistics"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "This is a note"
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.OwnerAppointmentDetails"
+                },
+                "property": {
+                    "$ref": "#/definitions/models.PropertyAppointmentDetails"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AppointmentStatus"
+                        }
+                    ],
+                    "example": "PENDING"
+                }
+            }
+        },
+        "models.AppointmentLists": {
+            "type": "object",
+            "properties": {
+                "appointment_date": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "appointment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "cancelled_message": {
+                    "type": "string",
+                    "example": "This is a cancelled message"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "note": {
+                    "type": "string",
+                    "example": "This is a note"
+                },
+                "owner": {
+                    "$ref": "#/definitions/models.OwnerAppointmentLists"
+                },
+                "property": {
+                    "$ref": "#/definitions/models.PropertyAppointmentLists"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AppointmentStatus"
+                        }
+                    ],
+                    "example": "PENDING"
+                }
+            }
+        },
+        "models.AppointmentProposals": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Can we do 5pm instead?"
+                },
+                "proposal_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "proposed_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "proposed_date": {
+                    "type": "string",
+                    "example": "2024-02-18T17:00:00+07:00"
+                },
+                "proposer_role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ActorRoles"
+                        }
+                    ],
+                    "example": "OWNER"
+                },
+                "responded_at": {
+                    "type": "string",
+                    "example": "2024-02-17T11:00:00Z"
+                },
+                "responded_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ProposalStatus"
+                        }
+                    ],
+                    "example": "PENDING"
+                }
+            }
+        },
+        "models.AppointmentSlots": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "example": "2024-02-18T09:30:00+07:00"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "2024-02-18T09:00:00+07:00"
+                }
+            }
+        },
+        "models.AppointmentStatusHistories": {
+            "type": "object",
+            "properties": {
+                "actor_role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ActorRoles"
+                        }
+                    ],
+                    "example": "OWNER"
+                },
+                "actor_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "from_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AppointmentStatus"
+                        }
+                    ],
+                    "example": "PENDING"
+                },
+                "history_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "This is a cancelled message"
+                },
+                "to_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AppointmentStatus"
+                        }
+                    ],
+                    "example": "CONFIRMED"
+                }
+            }
+        },
+        "models.AttendanceStatistics": {
+            "type": "object",
+            "properties": {
+                "check_ins": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "no_show_rate": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "no_shows": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.CalendarFeeds": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "feed_url": {
+                    "type": "string",
+                    "example": "http://localhost:8000/api/v1/calendar/0123456789abcdef.ics"
+                }
+            }
+        },
+        "models.CallbackResponses": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "johnd@email.com"
+                },
+                "registered_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.RegisteredTypes"
+                        }
+                    ],
+                    "example": "EMAIL / GOOGLE"
+                },
+                "session_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.SessionType"
+                        }
+                    ],
+                    "example": "REGISTER / LOGIN"
+                }
+            }
+        },
+        "models.ChatPreviews": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "hello, world"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "profile_image_url": {
+                    "type": "string",
+                    "example": "www.image.com/profile"
+                },
+                "unread_messages": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.CreatedPayments": {
+            "type": "object",
+            "properties": {
+                "checkout_url": {
+                    "type": "string",
+                    "example": "https://checkout.stripe.com/c/pay/cs_test_a1b2c3"
+                },
+                "payment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.CreatingAgreementAmendments": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2026-02-18T00:00:00+07:00"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Renewing for another year"
+                },
+                "payment_duration": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "payment_per_month": {
+                    "type": "number",
+                    "example": 16000
+                }
+            }
+        },
+        "models.CreatingAgreements": {
+            "type": "object",
+            "properties": {
+                "agreement_date": {
+                    "type": "string",
+                    "example": "2021-01-01T00:00:00Z"
+                },
+                "agreement_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AgreementTypes"
+                        }
+                    ],
+                    "example": "SELLING"
+                },
+                "deposit_amount": {
+                    "type": "number",
+                    "example": 1000000
+                },
+                "dweller_user_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                },
+                "payment_duration": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "payment_per_month": {
+                    "type": "number",
+                    "example": 1000000
+                },
+                "property_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                }
+            }
+        },
+        "models.CreatingAppointmentProposals": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Can we do 5pm instead?"
+                },
+                "proposed_date": {
+                    "type": "string",
+                    "example": "2024-02-18T17:00:00+07:00"
+                }
+            }
+        },
+        "models.CreatingAppointments": {
+            "type": "object",
+            "properties": {
+                "appointment_dates": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "This is a note"
+                },
+                "property_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.CreatingAvailabilityExceptions": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "example": "13:00"
+                },
+                "exception_date": {
+                    "type": "string",
+                    "example": "2024-02-18"
+                },
+                "is_available": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Lunch break"
+                },
+                "slot_duration": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "12:00"
+                }
+            }
+        },
+        "models.CreatingInspectionItems": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.ItemConditions"
+                        }
+                    ],
+                    "example": "GOOD"
+                },
+                "item": {
+                    "type": "string",
+                    "example": "Mirror"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Small scratch on the lower left corner"
+                },
+                "room": {
+                    "type": "string",
+                    "example": "Bathroom"
+                }
+            }
+        },
+        "models.CreatingInspections": {
+            "type": "object",
+            "properties": {
+                "inspection_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.InspectionTypes"
+                        }
+                    ],
+                    "example": "MOVE_IN"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatingInspectionItems"
+                    }
+                }
+            }
+        },
+        "models.CreditCards": {
+            "type": "object",
+            "properties": {
+                "card_color": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.CardColors"
+                        }
+                    ],
+                    "example": "BLUE"
+                },
+                "card_nickname": {
+                    "type": "string",
+                    "example": "John's Card"
+                },
+                "card_number": {
+                    "type": "string",
+                    "example": "1234567890123456"
+                },
+                "cardholder_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "cvv": {
+                    "type": "string",
+                    "example": "123"
+                },
+                "expire_month": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "expire_year": {
+                    "type": "string",
+                    "example": "2023"
+                },
+                "tag_number": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.DisputingDepositDeductions": {
+            "type": "object",
+            "properties": {
+                "dispute_reason": {
+                    "type": "string",
+                    "example": "The mirror was already cracked when I moved in"
+                }
+            }
+        },
+        "models.DwellerAgreementDetails": {
+            "type": "object",
+            "properties": {
+                "dweller_first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "dweller_last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "dweller_phone_number": {
+                    "type": "string",
+                    "example": "0812345678"
+                },
+                "dweller_profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
+                },
+                "dweller_user_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
+                }
+            }
+        },
+        "models.DwellerAppointmentDetails": {
+            "type": "object",
+            "properties": {
+                "dweller_first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "dweller_last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "dweller_phone_number": {
+                    "type": "string",
+                    "example": "0812345678"
+                },
+                "dweller_profile_image_url": {
+                    "type": "string",
+                    "example": "https://image_url.com/abcd"
+                },
+                "dweller_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.ErrorResponses": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "message": {
+                    "type": "string",
+                    "example": "internal server error"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "internal-server-error"
+                }
+            }
+        },
+        "models.FieldChangeItems": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {}
+            }
         },
-        "enums.FloorSizeUnits": {
-            "type": "string",
-            "enum": [
-                "SQM",
-                "SQFT"
-            ],
-            "x-enum-varnames": [
-                "SQM",
-                "SQFT"
-            ]
+        "models.FieldChanges": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/models.FieldChangeItems"
+            }
         },
-        "enums.Furnishing": {
-            "type": "string",
-            "enum": [
-                "UNFURNISHED",
-                "PARTIALLY_FURNISHED",
-                "FULLY_FURNISHED",
-                "READY_TO_MOVE_IN"
-            ],
-            "x-enum-varnames": [
-                "UNFURNISHED",
-                "PARTIALLY_FURNISHED",
-                "FULLY_FURNISHED",
-                "READY_TO_MOVE_IN"
-            ]
+        "models.Greetings": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Hello, World"
+                }
+            }
         },
-        "enums.PropertyTypes": {
-            "type": "string",
-            "enum": [
-                "CONDOMINIUM",
-                "APARTMENT",
-                "SEMI-DETACHED_HOUSE",
-                "HOUSE",
-                "SERVICED_APARTMENT",
-                "TOWNHOUSE"
-            ],
-            "x-enum-varnames": [
-                "CONDOMINIUM",
-                "APARTMENT",
-                "SEMI_DETACHED_HOUSE",
-                "HOUSE",
-                "SERVICED_APARTMENT",
-                "TOWNHOUSE"
-            ]
+        "models.InspectionComparisonItems": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "item": {
+                    "type": "string",
+                    "example": "Mirror"
+                },
+                "move_in": {
+                    "$ref": "#/definitions/models.AgreementInspectionItems"
+                },
+                "move_out": {
+                    "$ref": "#/definitions/models.AgreementInspectionItems"
+                },
+                "room": {
+                    "type": "string",
+                    "example": "Bathroom"
+                },
+                "worsened": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
         },
-        "enums.RegisteredTypes": {
-            "type": "string",
-            "enum": [
-                "EMAIL",
-                "GOOGLE"
-            ],
-            "x-enum-varnames": [
-                "EMAIL",
-                "GOOGLE"
-            ]
+        "models.InspectionComparisons": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InspectionComparisonItems"
+                    }
+                },
+                "move_in_inspection_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "move_out_inspection_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
         },
-        "enums.SessionType": {
-            "type": "string",
-            "enum": [
-                "REGISTER",
-                "LOGIN"
-            ],
-            "x-enum-varnames": [
-                "SessionRegister",
-                "SessionLogin"
-            ]
+        "models.MaintenanceTicketPhotos": {
+            "type": "object",
+            "properties": {
+                "image_url": {
+                    "type": "string",
+                    "example": "https://image_url.com/abcd"
+                }
+            }
         },
-        "models.AgreementDetails": {
+        "models.MaintenanceTicketUpdates": {
             "type": "object",
             "properties": {
-                "agreement_date": {
+                "created_at": {
                     "type": "string",
-                    "example": "2021-01-01T00:00:00Z"
+                    "example": "2024-02-18T10:00:00Z"
                 },
-                "agreement_id": {
+                "from_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.MaintenanceStatus"
+                        }
+                    ],
+                    "example": "ACKNOWLEDGED"
+                },
+                "note": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "The technician will come on Tuesday morning"
                 },
-                "agreement_type": {
+                "scheduled_at": {
+                    "type": "string",
+                    "example": "2024-02-20T09:00:00Z"
+                },
+                "to_status": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.AgreementTypes"
+                            "$ref": "#/definitions/enums.MaintenanceStatus"
                         }
                     ],
-                    "example": "SELLING"
+                    "example": "SCHEDULED"
                 },
-                "cancelled_message": {
+                "update_id": {
                     "type": "string",
-                    "example": "This is cancelled message."
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "updated_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.MaintenanceTickets": {
+            "type": "object",
+            "properties": {
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "category": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.MaintenanceCategories"
+                        }
+                    ],
+                    "example": "AIR_CONDITIONING"
                 },
                 "created_at": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "2024-02-17T10:00:00Z"
+                },
+                "created_by_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Water drips from the indoor unit whenever it runs"
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MaintenanceTicketPhotos"
+                    }
+                },
+                "priority": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.MaintenancePriorities"
+                        }
+                    ],
+                    "example": "HIGH"
+                },
+                "resolved_at": {
+                    "type": "string",
+                    "example": "2024-02-20T11:00:00Z"
+                },
+                "scheduled_at": {
+                    "type": "string",
+                    "example": "2024-02-20T09:00:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.MaintenanceStatus"
+                        }
+                    ],
+                    "example": "SCHEDULED"
+                },
+                "ticket_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bedroom air-con is leaking"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-02-18T10:00:00Z"
+                },
+                "updates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MaintenanceTicketUpdates"
+                    }
+                }
+            }
+        },
+        "models.MessageResponses": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Message sent successfully"
+                }
+            }
+        },
+        "models.Messages": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "chat_id": {
+                    "type": "string",
+                    "example": "27b79b15-a56f-464a-90f7-bab515ba4c02"
+                },
+                "content": {
+                    "type": "string",
+                    "example": "hello, world"
+                },
+                "message_id": {
+                    "type": "string",
+                    "example": "27b79b15-a56f-464a-90f7-bab515ba4c02"
+                },
+                "read_at": {
+                    "type": "string",
+                    "example": "2024-02-22T03:06:53.313735Z"
+                },
+                "sent_at": {
+                    "type": "string",
+                    "example": "2024-02-22T03:06:53.313735Z"
+                }
+            }
+        },
+        "models.MonthlyStatements": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyStatements"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/models.StatementAmounts"
                 },
-                "deposit_amount": {
-                    "type": "number",
-                    "example": 1000000
+                "year": {
+                    "type": "integer",
+                    "example": 2024
+                }
+            }
+        },
+        "models.MyAgreementResponses": {
+            "type": "object",
+            "properties": {
+                "dweller_agreements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementLists"
+                    }
                 },
-                "dweller": {
-                    "$ref": "#/definitions/models.DwellerAgreementDetails"
+                "dweller_total": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "owner": {
-                    "$ref": "#/definitions/models.OwnerAgreementDetails"
+                "owner_agreements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgreementLists"
+                    }
                 },
-                "payment_duration": {
+                "owner_total": {
                     "type": "integer",
-                    "example": 12
+                    "example": 2
+                }
+            }
+        },
+        "models.MyAppointmentResponses": {
+            "type": "object",
+            "properties": {
+                "dweller_appointments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AppointmentLists"
+                    }
                 },
-                "payment_per_month": {
-                    "type": "number",
-                    "example": 1000000
+                "dweller_total": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "property": {
-                    "$ref": "#/definitions/models.PropertyAgreementDetails"
+                "owner_appointments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AppointmentLists"
+                    }
                 },
-                "status": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.AgreementStatus"
-                        }
-                    ],
-                    "example": "AWAITING_DEPOSIT"
+                "owner_total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.MyFavoritePropertiesResponses": {
+            "type": "object",
+            "properties": {
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Properties"
+                    }
                 },
-                "total_payment": {
-                    "type": "number",
-                    "example": 12000000
+                "total": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "models.AgreementLists": {
+        "models.MyPropertiesResponses": {
             "type": "object",
             "properties": {
-                "agreement_date": {
-                    "type": "string",
-                    "example": "2021-01-01T00:00:00Z"
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Properties"
+                    }
                 },
-                "agreement_id": {
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.OwnerAgreementDetails": {
+            "type": "object",
+            "properties": {
+                "owner_first_name": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
-                },
-                "agreement_type": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.AgreementTypes"
-                        }
-                    ],
-                    "example": "SELLING"
+                    "example": "John"
                 },
-                "cancelled_message": {
+                "owner_last_name": {
                     "type": "string",
-                    "example": "This is cancelled message."
-                },
-                "created_at": {
-                    "type": "string"
+                    "example": "Doe"
                 },
-                "owner": {
-                    "$ref": "#/definitions/models.OwnerAgreementLists"
+                "owner_phone_number": {
+                    "type": "string",
+                    "example": "0812345678"
                 },
-                "property": {
-                    "$ref": "#/definitions/models.PropertyAgreementLists"
+                "owner_profile_image_url": {
+                    "type": "string",
+                    "example": "https://www.example.com/image.jpg"
                 },
-                "status": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.AgreementStatus"
-                        }
-                    ],
-                    "example": "AWAITING_DEPOSIT"
+                "owner_user_id": {
+                    "type": "string",
+                    "example": "00000000-0000-0000-0000-000000000000"
                 }
             }
         },
-        "models.Agreements": {
+        "models.OwnerAgreementLists": {
             "type": "object",
             "properties": {
-                "agreement_date": {
+                "owner_first_name": {
                     "type": "string",
-                    "example": "2021-01-01T00:00:00Z"
-                },
-                "agreement_id": {
-                    "type": "string"
-                },
-                "agreement_type": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.AgreementTypes"
-                        }
-                    ],
-                    "example": "SELLING"
+                    "example": "John"
                 },
-                "cancelled_message": {
+                "owner_last_name": {
                     "type": "string",
-                    "example": "This is cancelled message."
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "deposit_amount": {
-                    "type": "number",
-                    "example": 1000000
+                    "example": "Doe"
                 },
-                "dweller_user_id": {
+                "owner_profile_image_url": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "https://www.example.com/image.jpg"
                 },
                 "owner_user_id": {
                     "type": "string",
                     "example": "00000000-0000-0000-0000-000000000000"
+                }
+            }
+        },
+        "models.OwnerAppointmentDetails": {
+            "type": "object",
+            "properties": {
+                "owner_first_name": {
+                    "type": "string",
+                    "example": "John"
                 },
-                "payment_duration": {
-                    "type": "integer",
-                    "example": 12
-                },
-                "payment_per_month": {
-                    "type": "number",
-                    "example": 1000000
+                "owner_last_name": {
+                    "type": "string",
+                    "example": "Doe"
                 },
-                "property_id": {
+                "owner_phone_number": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "0812345678"
                 },
-                "status": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.AgreementStatus"
-                        }
-                    ],
-                    "example": "AWAITING_DEPOSIT"
+                "owner_profile_image_url": {
+                    "type": "string",
+                    "example": "https://image_url.com/abcd"
                 },
-                "total_payment": {
-                    "type": "number",
-                    "example": 12000000
+                "owner_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 }
             }
         },
-        "models.AllPropertiesResponses": {
+        "models.OwnerAppointmentLists": {
             "type": "object",
             "properties": {
-                "properties": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Properties"
-                    }
+                "owner_first_name": {
+                    "type": "string",
+                    "example": "John"
                 },
-                "total": {
-                    "type": "integer",
-                    "example": 2
+                "owner_last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "owner_profile_image_url": {
+                    "type": "string",
+                    "example": "https://image_url.com/abcd"
+                },
+                "owner_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 }
             }
         },
-        "models.AppointmentDetails": {
+        "models.Payments": {
             "type": "object",
             "properties": {
-                "appointment_date": {
+                "agreement_id": {
                     "type": "string",
-                    "example": "2024-02-18T11:00:00Z"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "appointment_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "installment_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "cancelled_message": {
-                    "type": "string",
-                    "example": "This is a cancelled message"
+                "is_success": {
+                    "type": "boolean"
                 },
-                "created_at": {
+                "name": {
                     "type": "string"
                 },
-                "dweller": {
-                    "$ref": "#/definitions/models.DwellerAppointmentDetails"
-                },
-                "note": {
+                "paid_at": {
                     "type": "string",
-                    "example": "This is a note"
-                },
-                "owner": {
-                    "$ref": "#/definitions/models.OwnerAppointmentDetails"
+                    "example": "2024-02-22T03:06:53.313735Z"
                 },
-                "property": {
-                    "$ref": "#/definitions/models.PropertyAppointmentDetails"
+                "payment_id": {
+                    "type": "string"
                 },
-                "status": {
+                "payment_type": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.AppointmentStatus"
+                            "$ref": "#/definitions/enums.PaymentTypes"
                         }
                     ],
-                    "example": "PENDING"
+                    "example": "DEPOSIT"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "recipient_user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "refunded_at": {
+                    "type": "string",
+                    "example": "2024-02-22T03:06:53.313735Z"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "models.AppointmentLists": {
+        "models.Properties": {
             "type": "object",
             "properties": {
-                "appointment_date": {
+                "address": {
                     "type": "string",
-                    "example": "2024-02-18T11:00:00Z"
+                    "example": "123/4"
                 },
-                "appointment_id": {
+                "alley": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "Pattaya Nua 78"
                 },
-                "cancelled_message": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyAttachments"
+                    }
+                },
+                "bathrooms": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "bedrooms": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "country": {
                     "type": "string",
-                    "example": "This is a cancelled message"
+                    "example": "Thailand"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "note": {
+                "district": {
                     "type": "string",
-                    "example": "This is a note"
+                    "example": "Bang Phli"
                 },
-                "owner": {
-                    "$ref": "#/definitions/models.OwnerAppointmentLists"
+                "floor": {
+                    "type": "integer",
+                    "example": 5
                 },
-                "property": {
-                    "$ref": "#/definitions/models.PropertyAppointmentLists"
+                "floor_size": {
+                    "type": "number",
+                    "example": 123.45
                 },
-                "status": {
+                "floor_size_unit": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.AppointmentStatus"
+                            "$ref": "#/definitions/enums.FloorSizeUnits"
                         }
                     ],
-                    "example": "PENDING"
-                }
-            }
-        },
-        "models.CallbackResponses": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "johnd@email.com"
+                    "example": "SQM"
                 },
-                "registered_type": {
+                "furnishing": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.RegisteredTypes"
+                            "$ref": "#/definitions/enums.Furnishing"
                         }
                     ],
-                    "example": "EMAIL / GOOGLE"
+                    "example": "UNFURNISHED"
                 },
-                "session_type": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.SessionType"
-                        }
-                    ],
-                    "example": "REGISTER / LOGIN"
-                }
-            }
-        },
-        "models.ChatPreviews": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "type": "string",
-                    "example": "hello, world"
+                "is_favorite": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "first_name": {
+                "owner_id": {
                     "type": "string",
-                    "example": "John"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "last_name": {
+                "postal_code": {
                     "type": "string",
-                    "example": "Doe"
+                    "example": "69096"
                 },
-                "profile_image_url": {
+                "property_description": {
                     "type": "string",
-                    "example": "www.image.com/profile"
-                },
-                "unread_messages": {
-                    "type": "integer",
-                    "example": 9
+                    "example": "Et sequi dolor praes"
                 },
-                "user_id": {
+                "property_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
-                }
-            }
-        },
-        "models.CreatingAgreements": {
-            "type": "object",
-            "properties": {
-                "agreement_date": {
+                },
+                "property_images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyImages"
+                    }
+                },
+                "property_name": {
                     "type": "string",
-                    "example": "2021-01-01T00:00:00Z"
+                    "example": "Supalai"
                 },
-                "agreement_type": {
+                "property_type": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.AgreementTypes"
+                            "$ref": "#/definitions/enums.PropertyTypes"
                         }
                     ],
-                    "example": "SELLING"
-                },
-                "deposit_amount": {
-                    "type": "number",
-                    "example": 1000000
+                    "example": "CONDOMINIUM"
                 },
-                "dweller_user_id": {
+                "province": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "Pattaya"
                 },
-                "payment_duration": {
-                    "type": "integer",
-                    "example": 12
+                "renting_property": {
+                    "$ref": "#/definitions/models.RentingProperties"
                 },
-                "payment_per_month": {
-                    "type": "number",
-                    "example": 1000000
+                "selling_property": {
+                    "$ref": "#/definitions/models.SellingProperties"
                 },
-                "property_id": {
+                "street": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "Pattaya"
                 },
-                "status": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.AgreementStatus"
-                        }
-                    ],
-                    "example": "AWAITING_DEPOSIT"
+                "sub_district": {
+                    "type": "string",
+                    "example": "Bang Bon"
                 },
-                "total_payment": {
-                    "type": "number",
-                    "example": 12000000
+                "unit_number": {
+                    "type": "integer",
+                    "example": 123
                 }
             }
         },
-        "models.CreatingAppointments": {
+        "models.PropertyAgreementDetails": {
             "type": "object",
             "properties": {
-                "appointment_dates": {
-                    "type": "string",
-                    "example": "2024-02-18T11:00:00Z"
-                },
-                "note": {
+                "address": {
                     "type": "string",
-                    "example": "This is a note"
+                    "example": "123/456 The Base Sukhumvit 77"
                 },
-                "owner_user_id": {
+                "alley": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "77"
                 },
-                "property_id": {
+                "country": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
-                }
-            }
-        },
-        "models.CreditCards": {
-            "type": "object",
-            "properties": {
-                "card_color": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.CardColors"
-                        }
-                    ],
-                    "example": "BLUE"
+                    "example": "Thailand"
                 },
-                "card_nickname": {
+                "district": {
                     "type": "string",
-                    "example": "John's Card"
+                    "example": "Bangkok"
                 },
-                "card_number": {
+                "postal_code": {
                     "type": "string",
-                    "example": "1234567890123456"
+                    "example": "10110"
                 },
-                "cardholder_name": {
-                    "type": "string",
-                    "example": "John Doe"
+                "price": {
+                    "type": "number",
+                    "example": 1000000
                 },
-                "cvv": {
-                    "type": "string",
-                    "example": "123"
+                "price_per_month": {
+                    "type": "number",
+                    "example": 1000000
                 },
-                "expire_month": {
+                "property_id": {
                     "type": "string",
-                    "example": "12"
+                    "example": "00000000-0000-0000-0000-000000000000"
                 },
-                "expire_year": {
-                    "type": "string",
-                    "example": "2023"
+                "property_images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyImageAgreements"
+                    }
                 },
-                "tag_number": {
-                    "type": "integer",
-                    "example": 1
-                }
-            }
-        },
-        "models.DwellerAgreementDetails": {
-            "type": "object",
-            "properties": {
-                "dweller_first_name": {
+                "property_name": {
                     "type": "string",
-                    "example": "John"
+                    "example": "The Base Sukhumvit 77"
                 },
-                "dweller_last_name": {
-                    "type": "string",
-                    "example": "Doe"
+                "property_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.PropertyTypes"
+                        }
+                    ],
+                    "example": "CONDO"
                 },
-                "dweller_phone_number": {
+                "province": {
                     "type": "string",
-                    "example": "0812345678"
+                    "example": "Bangkok"
                 },
-                "dweller_profile_image_url": {
+                "street": {
                     "type": "string",
-                    "example": "https://www.example.com/image.jpg"
+                    "example": "Sukhumvit"
                 },
-                "dweller_user_id": {
+                "sub_district": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "Phra Khanong"
                 }
             }
         },
-        "models.DwellerAppointmentDetails": {
+        "models.PropertyAgreementLists": {
             "type": "object",
             "properties": {
-                "dweller_first_name": {
-                    "type": "string",
-                    "example": "John"
-                },
-                "dweller_last_name": {
+                "property_id": {
                     "type": "string",
-                    "example": "Doe"
+                    "example": "00000000-0000-0000-0000-000000000000"
                 },
-                "dweller_phone_number": {
-                    "type": "string",
-                    "example": "0812345678"
+                "property_images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyImageAgreements"
+                    }
                 },
-                "dweller_profile_image_url": {
+                "property_name": {
                     "type": "string",
-                    "example": "https://image_url.com/abcd"
+                    "example": "The Base Sukhumvit 77"
                 },
-                "dweller_user_id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                "property_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.PropertyTypes"
+                        }
+                    ],
+                    "example": "CONDO"
                 }
             }
         },
-        "models.ErrorResponses": {
+        "models.PropertyAppointmentDetails": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 500
+                "address": {
+                    "type": "string",
+                    "example": "123/4"
                 },
-                "message": {
+                "alley": {
                     "type": "string",
-                    "example": "internal server error"
+                    "example": "Pattaya Nua 78"
                 },
-                "name": {
+                "country": {
                     "type": "string",
-                    "example": "internal-server-error"
-                }
-            }
-        },
-        "models.Greetings": {
-            "type": "object",
-            "properties": {
-                "message": {
+                    "example": "Thailand"
+                },
+                "district": {
                     "type": "string",
-                    "example": "Hello, World"
-                }
-            }
-        },
-        "models.MessageResponses": {
-            "type": "object",
-            "properties": {
-                "message": {
+                    "example": "Bang Phli"
+                },
+                "postal_code": {
                     "type": "string",
-                    "example": "Message sent successfully"
-                }
-            }
-        },
-        "models.Messages": {
-            "type": "object",
-            "properties": {
-                "author": {
-                    "type": "boolean",
-                    "example": true
+                    "example": "69096"
                 },
-                "chat_id": {
+                "price": {
+                    "type": "number",
+                    "example": 12345.67
+                },
+                "price_per_month": {
+                    "type": "number",
+                    "example": 12345.67
+                },
+                "property_id": {
                     "type": "string",
-                    "example": "27b79b15-a56f-464a-90f7-bab515ba4c02"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "content": {
+                "property_images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyImageAppointments"
+                    }
+                },
+                "property_name": {
                     "type": "string",
-                    "example": "hello, world"
+                    "example": "The Base Sukhumvit 77"
                 },
-                "message_id": {
+                "property_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.PropertyTypes"
+                        }
+                    ],
+                    "example": "CONDO"
+                },
+                "province": {
                     "type": "string",
-                    "example": "27b79b15-a56f-464a-90f7-bab515ba4c02"
+                    "example": "Pattaya"
                 },
-                "read_at": {
+                "street": {
                     "type": "string",
-                    "example": "2024-02-22T03:06:53.313735Z"
+                    "example": "Pattaya"
                 },
-                "sent_at": {
+                "sub_district": {
                     "type": "string",
-                    "example": "2024-02-22T03:06:53.313735Z"
+                    "example": "Bang Bon"
                 }
             }
         },
-        "models.MyAgreementResponses": {
+        "models.PropertyAppointmentLists": {
             "type": "object",
             "properties": {
-                "dweller_agreements": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.AgreementLists"
-                    }
+                "property_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "owner_agreements": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.AgreementLists"
-                    }
-                }
-            }
-        },
-        "models.MyAppointmentResponses": {
-            "type": "object",
-            "properties": {
-                "dweller_appointments": {
+                "property_images": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.AppointmentLists"
+                        "$ref": "#/definitions/models.PropertyImageAppointments"
                     }
                 },
-                "owner_appointments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.AppointmentLists"
-                    }
+                "property_name": {
+                    "type": "string",
+                    "example": "The Base Sukhumvit 77"
+                },
+                "property_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.PropertyTypes"
+                        }
+                    ],
+                    "example": "CONDO"
                 }
             }
         },
-        "models.MyFavoritePropertiesResponses": {
+        "models.PropertyAttachments": {
             "type": "object",
             "properties": {
-                "properties": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Properties"
-                    }
+                "attachment_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "total": {
+                "attachment_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.PropertyAttachmentTypes"
+                        }
+                    ],
+                    "example": "FLOOR_PLAN"
+                },
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_order": {
                     "type": "integer",
-                    "example": 2
+                    "example": 1
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Floor plan"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://attachment_url.com/abcd.pdf"
                 }
             }
         },
-        "models.MyPropertiesResponses": {
+        "models.PropertyAvailabilities": {
             "type": "object",
             "properties": {
-                "properties": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Properties"
-                    }
+                "availability_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "total": {
+                "day_of_week": {
                     "type": "integer",
-                    "example": 2
+                    "example": 1
+                },
+                "end_time": {
+                    "type": "string",
+                    "example": "17:00"
+                },
+                "slot_duration": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "09:00"
                 }
             }
         },
-        "models.OwnerAgreementDetails": {
+        "models.PropertyAvailabilityExceptions": {
             "type": "object",
             "properties": {
-                "owner_first_name": {
+                "end_time": {
                     "type": "string",
-                    "example": "John"
+                    "example": "13:00"
                 },
-                "owner_last_name": {
+                "exception_date": {
                     "type": "string",
-                    "example": "Doe"
+                    "example": "2024-02-18T00:00:00Z"
                 },
-                "owner_phone_number": {
+                "exception_id": {
                     "type": "string",
-                    "example": "0812345678"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "owner_profile_image_url": {
+                "is_available": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "note": {
                     "type": "string",
-                    "example": "https://www.example.com/image.jpg"
+                    "example": "Lunch break"
                 },
-                "owner_user_id": {
+                "slot_duration": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "start_time": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "12:00"
                 }
             }
         },
-        "models.OwnerAgreementLists": {
+        "models.PropertyAvailabilityResponses": {
             "type": "object",
             "properties": {
-                "owner_first_name": {
-                    "type": "string",
-                    "example": "John"
-                },
-                "owner_last_name": {
-                    "type": "string",
-                    "example": "Doe"
-                },
-                "owner_profile_image_url": {
-                    "type": "string",
-                    "example": "https://www.example.com/image.jpg"
+                "availabilities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyAvailabilities"
+                    }
                 },
-                "owner_user_id": {
-                    "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                "exceptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyAvailabilityExceptions"
+                    }
                 }
             }
         },
-        "models.OwnerAppointmentDetails": {
+        "models.PropertyHistories": {
             "type": "object",
             "properties": {
-                "owner_first_name": {
+                "action": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.PropertyHistoryActions"
+                        }
+                    ],
+                    "example": "UPDATE"
+                },
+                "actor_first_name": {
                     "type": "string",
                     "example": "John"
                 },
-                "owner_last_name": {
+                "actor_last_name": {
                     "type": "string",
                     "example": "Doe"
                 },
-                "owner_phone_number": {
+                "actor_user_id": {
                     "type": "string",
-                    "example": "0812345678"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "owner_profile_image_url": {
+                "changes": {
+                    "$ref": "#/definitions/models.FieldChanges"
+                },
+                "created_at": {
                     "type": "string",
-                    "example": "https://image_url.com/abcd"
+                    "example": "2024-02-18T11:00:00Z"
                 },
-                "owner_user_id": {
+                "history_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "property_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "reverted_from_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.PropertySnapshots"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "models.OwnerAppointmentLists": {
+        "models.PropertyHistoriesResponses": {
             "type": "object",
             "properties": {
-                "owner_first_name": {
-                    "type": "string",
-                    "example": "John"
+                "histories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyHistories"
+                    }
                 },
-                "owner_last_name": {
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.PropertyImageAgreements": {
+            "type": "object",
+            "properties": {
+                "image_url": {
                     "type": "string",
-                    "example": "Doe"
-                },
-                "owner_profile_image_url": {
+                    "example": "https://www.example.com/image.jpg"
+                }
+            }
+        },
+        "models.PropertyImageAppointments": {
+            "type": "object",
+            "properties": {
+                "image_url": {
                     "type": "string",
                     "example": "https://image_url.com/abcd"
+                }
+            }
+        },
+        "models.PropertyImages": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "owner_user_id": {
+                "image_url": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "https://image_url.com/abcd"
                 }
             }
         },
-        "models.Properties": {
+        "models.PropertySnapshots": {
             "type": "object",
             "properties": {
                 "address": {
@@ -2920,9 +9329,6 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Thailand"
                 },
-                "created_at": {
-                    "type": "string"
-                },
                 "district": {
                     "type": "string",
                     "example": "Bang Phli"
@@ -2951,7 +9357,22 @@ const docTemplate = `{
                     ],
                     "example": "UNFURNISHED"
                 },
-                "is_favorite": {
+                "image_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "https://image_url.com/abcd",
+                        "https://image_url.com/abcd",
+                        "https://image_url.com/abcd"
+                    ]
+                },
+                "is_occupied": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "is_sold": {
                     "type": "boolean",
                     "example": true
                 },
@@ -2963,6 +9384,14 @@ const docTemplate = `{
                     "type": "string",
                     "example": "69096"
                 },
+                "price": {
+                    "type": "number",
+                    "example": 12345.67
+                },
+                "price_per_month": {
+                    "type": "number",
+                    "example": 12345.67
+                },
                 "property_description": {
                     "type": "string",
                     "example": "Et sequi dolor praes"
@@ -2971,12 +9400,6 @@ const docTemplate = `{
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "property_images": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PropertyImages"
-                    }
-                },
                 "property_name": {
                     "type": "string",
                     "example": "Supalai"
@@ -2993,12 +9416,6 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Pattaya"
                 },
-                "renting_property": {
-                    "$ref": "#/definitions/models.RentingProperties"
-                },
-                "selling_property": {
-                    "$ref": "#/definitions/models.SellingProperties"
-                },
                 "street": {
                     "type": "string",
                     "example": "Pattaya"
@@ -3013,183 +9430,317 @@ const docTemplate = `{
                 }
             }
         },
-        "models.PropertyAgreementDetails": {
+        "models.PropertyStatements": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string",
-                    "example": "123/456 The Base Sukhumvit 77"
+                "deposits_held": {
+                    "type": "number",
+                    "example": 4500
                 },
-                "alley": {
-                    "type": "string",
-                    "example": "77"
+                "deposits_received": {
+                    "type": "number",
+                    "example": 30000
                 },
-                "country": {
-                    "type": "string",
-                    "example": "Thailand"
+                "deposits_refunded": {
+                    "type": "number",
+                    "example": 25500
                 },
-                "district": {
+                "late_fees": {
+                    "type": "number",
+                    "example": 750
+                },
+                "property_id": {
                     "type": "string",
-                    "example": "Bangkok"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "postal_code": {
+                "property_name": {
                     "type": "string",
-                    "example": "10110"
+                    "example": "Supalai Place"
                 },
-                "price": {
+                "rent_collected": {
                     "type": "number",
-                    "example": 1000000
+                    "example": 45000
+                },
+                "rental_income": {
+                    "type": "number",
+                    "example": 48870.5
+                },
+                "utilities": {
+                    "type": "number",
+                    "example": 3120.5
+                }
+            }
+        },
+        "models.RentingProperties": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "is_occupied": {
+                    "type": "boolean",
+                    "example": true
                 },
                 "price_per_month": {
                     "type": "number",
-                    "example": 1000000
+                    "example": 12345.67
+                }
+            }
+        },
+        "models.ReorderingPropertyAttachments": {
+            "type": "object",
+            "properties": {
+                "attachment_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "123e4567-e89b-12d3-a456-426614174000",
+                        "123e4567-e89b-12d3-a456-426614174001"
+                    ]
+                }
+            }
+        },
+        "models.ResolvingDepositDeductions": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.DeductionStatus"
+                        }
+                    ],
+                    "example": "ESCALATED"
+                }
+            }
+        },
+        "models.SellingProperties": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "property_id": {
+                "is_sold": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "price": {
+                    "type": "number",
+                    "example": 12345.67
+                }
+            }
+        },
+        "models.SendingEmailRequests": {
+            "type": "object",
+            "properties": {
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Sessions": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
+                    "example": "admim@email.com"
                 },
-                "property_images": {
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "models.StatementAmounts": {
+            "type": "object",
+            "properties": {
+                "deposits_held": {
+                    "type": "number",
+                    "example": 4500
+                },
+                "deposits_received": {
+                    "type": "number",
+                    "example": 30000
+                },
+                "deposits_refunded": {
+                    "type": "number",
+                    "example": 25500
+                },
+                "late_fees": {
+                    "type": "number",
+                    "example": 750
+                },
+                "rent_collected": {
+                    "type": "number",
+                    "example": 45000
+                },
+                "rental_income": {
+                    "type": "number",
+                    "example": 48870.5
+                },
+                "utilities": {
+                    "type": "number",
+                    "example": 3120.5
+                }
+            }
+        },
+        "models.TrashResponses": {
+            "type": "object",
+            "properties": {
+                "agreements": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.PropertyImageAgreements"
+                        "$ref": "#/definitions/models.TrashedAgreements"
                     }
                 },
-                "property_name": {
+                "appointments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrashedAppointments"
+                    }
+                },
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrashedProperties"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrashedUsers"
+                    }
+                }
+            }
+        },
+        "models.TrashedAgreements": {
+            "type": "object",
+            "properties": {
+                "agreement_date": {
                     "type": "string",
-                    "example": "The Base Sukhumvit 77"
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "agreement_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "property_type": {
+                "agreement_type": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.PropertyTypes"
+                            "$ref": "#/definitions/enums.AgreementTypes"
                         }
                     ],
-                    "example": "CONDO"
+                    "example": "SELLING"
                 },
-                "province": {
+                "deleted_at": {
                     "type": "string",
-                    "example": "Bangkok"
+                    "example": "2024-02-18T11:00:00Z"
                 },
-                "street": {
+                "dweller_user_id": {
                     "type": "string",
-                    "example": "Sukhumvit"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "sub_district": {
+                "owner_user_id": {
                     "type": "string",
-                    "example": "Phra Khanong"
-                }
-            }
-        },
-        "models.PropertyAgreementLists": {
-            "type": "object",
-            "properties": {
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
                 "property_id": {
                     "type": "string",
-                    "example": "00000000-0000-0000-0000-000000000000"
-                },
-                "property_images": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PropertyImageAgreements"
-                    }
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
                 "property_name": {
                     "type": "string",
-                    "example": "The Base Sukhumvit 77"
+                    "example": "Supalai"
                 },
-                "property_type": {
+                "purge_at": {
+                    "type": "string",
+                    "example": "2024-03-19T11:00:00Z"
+                },
+                "restorable": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.PropertyTypes"
+                            "$ref": "#/definitions/enums.AgreementStatus"
                         }
                     ],
-                    "example": "CONDO"
+                    "example": "AWAITING_DEPOSIT"
                 }
             }
         },
-        "models.PropertyAppointmentDetails": {
+        "models.TrashedAppointments": {
             "type": "object",
             "properties": {
-                "address": {
+                "appointment_date": {
                     "type": "string",
-                    "example": "123/4"
+                    "example": "2024-02-18T11:00:00Z"
                 },
-                "alley": {
+                "appointment_id": {
                     "type": "string",
-                    "example": "Pattaya Nua 78"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "country": {
+                "deleted_at": {
                     "type": "string",
-                    "example": "Thailand"
+                    "example": "2024-02-18T11:00:00Z"
                 },
-                "district": {
+                "dweller_user_id": {
                     "type": "string",
-                    "example": "Bang Phli"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "postal_code": {
+                "owner_user_id": {
                     "type": "string",
-                    "example": "69096"
-                },
-                "price": {
-                    "type": "number",
-                    "example": 12345.67
-                },
-                "price_per_month": {
-                    "type": "number",
-                    "example": 12345.67
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
                 "property_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "property_images": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PropertyImageAppointments"
-                    }
-                },
                 "property_name": {
                     "type": "string",
-                    "example": "The Base Sukhumvit 77"
+                    "example": "Supalai"
                 },
-                "property_type": {
+                "purge_at": {
+                    "type": "string",
+                    "example": "2024-03-19T11:00:00Z"
+                },
+                "restorable": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/enums.PropertyTypes"
+                            "$ref": "#/definitions/enums.AppointmentStatus"
                         }
                     ],
-                    "example": "CONDO"
-                },
-                "province": {
-                    "type": "string",
-                    "example": "Pattaya"
-                },
-                "street": {
-                    "type": "string",
-                    "example": "Pattaya"
-                },
-                "sub_district": {
-                    "type": "string",
-                    "example": "Bang Bon"
+                    "example": "PENDING"
                 }
             }
         },
-        "models.PropertyAppointmentLists": {
+        "models.TrashedProperties": {
             "type": "object",
             "properties": {
-                "property_id": {
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2024-02-18T11:00:00Z"
+                },
+                "owner_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "property_images": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.PropertyImageAppointments"
-                    }
+                "property_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
                 "property_name": {
                     "type": "string",
-                    "example": "The Base Sukhumvit 77"
+                    "example": "Supalai"
                 },
                 "property_type": {
                     "allOf": [
@@ -3197,121 +9748,145 @@ const docTemplate = `{
                             "$ref": "#/definitions/enums.PropertyTypes"
                         }
                     ],
-                    "example": "CONDO"
-                }
-            }
-        },
-        "models.PropertyImageAgreements": {
-            "type": "object",
-            "properties": {
-                "image_url": {
+                    "example": "CONDOMINIUM"
+                },
+                "purge_at": {
                     "type": "string",
-                    "example": "https://www.example.com/image.jpg"
+                    "example": "2024-03-19T11:00:00Z"
+                },
+                "restorable": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "models.PropertyImageAppointments": {
+        "models.TrashedUsers": {
             "type": "object",
             "properties": {
-                "image_url": {
+                "deleted_at": {
                     "type": "string",
-                    "example": "https://image_url.com/abcd"
-                }
-            }
-        },
-        "models.PropertyImages": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
+                    "example": "2024-02-18T11:00:00Z"
                 },
-                "image_url": {
+                "email": {
                     "type": "string",
-                    "example": "https://image_url.com/abcd"
+                    "example": "admin@email.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "purge_at": {
+                    "type": "string",
+                    "example": "2024-03-19T11:00:00Z"
+                },
+                "restorable": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 }
             }
         },
-        "models.RentingProperties": {
+        "models.UpdatingAgreementStatus": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "cancelled_message": {
                     "type": "string"
                 },
-                "is_occupied": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "price_per_month": {
-                    "type": "number",
-                    "example": 12345.67
+                "status": {
+                    "$ref": "#/definitions/enums.AgreementStatus"
                 }
             }
         },
-        "models.SellingProperties": {
+        "models.UpdatingAppointmentStatus": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "is_sold": {
-                    "type": "boolean",
-                    "example": true
+                "cancelled_message": {
+                    "type": "string",
+                    "example": "This is a cancelled message"
                 },
-                "price": {
-                    "type": "number",
-                    "example": 12345.67
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.AppointmentStatus"
+                        }
+                    ],
+                    "example": "CANCELLED"
                 }
             }
         },
-        "models.SendingEmailRequests": {
+        "models.UpdatingInspections": {
             "type": "object",
             "properties": {
-                "emails": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.CreatingInspectionItems"
                     }
                 }
             }
         },
-        "models.Sessions": {
+        "models.UpdatingMaintenanceTickets": {
             "type": "object",
             "properties": {
-                "email": {
+                "note": {
                     "type": "string",
-                    "example": "admim@email.com"
+                    "example": "The technician will come on Tuesday morning"
                 },
-                "user_id": {
+                "scheduled_at": {
                     "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                    "example": "2024-02-20T09:00:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/enums.MaintenanceStatus"
+                        }
+                    ],
+                    "example": "SCHEDULED"
                 }
             }
         },
-        "models.UpdatingAgreementStatus": {
+        "models.UpdatingPropertyAvailabilities": {
             "type": "object",
             "properties": {
-                "cancelled_message": {
-                    "type": "string"
+                "availabilities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyAvailabilities"
+                    }
+                }
+            }
+        },
+        "models.UpdatingUserNotificationPreferences": {
+            "type": "object",
+            "properties": {
+                "appointment_reminders": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "status": {
-                    "$ref": "#/definitions/enums.AgreementStatus"
+                "appointment_updates": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "models.UpdatingAppointmentStatus": {
+        "models.UpdatingUtilityRates": {
             "type": "object",
             "properties": {
-                "cancelled_message": {
-                    "type": "string",
-                    "example": "This is a cancelled message"
+                "electricity_rate": {
+                    "type": "number",
+                    "example": 8
                 },
-                "status": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/enums.AppointmentStatus"
-                        }
-                    ],
-                    "example": "CANCELLED"
+                "water_rate": {
+                    "type": "number",
+                    "example": 18
                 }
             }
         },
@@ -3341,6 +9916,19 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UserNotificationPreferences": {
+            "type": "object",
+            "properties": {
+                "appointment_reminders": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "appointment_updates": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
         "models.Users": {
             "type": "object",
             "properties": {
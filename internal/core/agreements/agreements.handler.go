@@ -1,10 +1,14 @@
 package agreements
 
 import (
+	"bytes"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	"strings"
 
 	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/brain-flowing-company/pprp-backend/internal/utils"
 	"github.com/gofiber/fiber/v2"
@@ -17,6 +21,35 @@ type Handler interface {
 	CreateAgreement(c *fiber.Ctx) error
 	DeleteAgreement(c *fiber.Ctx) error
 	UpdateAgreementStatus(c *fiber.Ctx) error
+	GetAgreementInstallments(c *fiber.Ctx) error
+	GenerateAgreementContract(c *fiber.Ctx) error
+	DownloadAgreementContract(c *fiber.Ctx) error
+	DownloadAgreementPhoto(c *fiber.Ctx) error
+	SignAgreement(c *fiber.Ctx) error
+	VerifyAgreementSignatures(c *fiber.Ctx) error
+	GetAgreementAmendments(c *fiber.Ctx) error
+	CreateAgreementAmendment(c *fiber.Ctx) error
+	AcceptAgreementAmendment(c *fiber.Ctx) error
+	DeclineAgreementAmendment(c *fiber.Ctx) error
+	GetAgreementDeposit(c *fiber.Ctx) error
+	CreateDepositDeduction(c *fiber.Ctx) error
+	AcceptDepositDeduction(c *fiber.Ctx) error
+	DisputeDepositDeduction(c *fiber.Ctx) error
+	DeleteDepositDeduction(c *fiber.Ctx) error
+	ResolveDepositDeduction(c *fiber.Ctx) error
+	RefundDeposit(c *fiber.Ctx) error
+	GetAgreementInspections(c *fiber.Ctx) error
+	GetAgreementInspection(c *fiber.Ctx) error
+	CreateAgreementInspection(c *fiber.Ctx) error
+	UpdateAgreementInspection(c *fiber.Ctx) error
+	UploadInspectionPhotos(c *fiber.Ctx) error
+	SignAgreementInspection(c *fiber.Ctx) error
+	CompareAgreementInspections(c *fiber.Ctx) error
+	GetUtilityRates(c *fiber.Ctx) error
+	UpdateUtilityRates(c *fiber.Ctx) error
+	GetMeterReadings(c *fiber.Ctx) error
+	CreateMeterReading(c *fiber.Ctx) error
+	DeleteMeterReading(c *fiber.Ctx) error
 }
 type handlerImpl struct {
 	service Service
@@ -48,7 +81,7 @@ func (h *handlerImpl) GetAllAgreements(c *fiber.Ctx) error {
 
 // @router  /api/v1/agreements/:agreementId [get]
 // @summary  Get agreement by id *use cookies*
-// @description  Get an agreement by its id
+// @description  Get an agreement by its id, with every status transition it went through (oldest first)
 // @tags agreements
 // @produce json
 // @param agreementId path string true "Agreement ID"
@@ -73,17 +106,43 @@ func (h *handlerImpl) GetAgreementById(c *fiber.Ctx) error {
 // @description Get all agreements related to the user
 // @tags        agreements
 // @produce     json
-// @param       order query string false "Order by ASC or DESC"
+// @param       order query string false "Order by created time ASC or DESC, used when sort is not given"
+// @param       role query string false "Only list agreements as `OWNER` or as `DWELLER`"
+// @param       limit query int false "Pagination limit per page per role, max 50, default 20"
+// @param       page  query int false "Pagination page index as 1-based index, default 1"
+// @param       sort query string false "Sort in format `<json_field>:<direction>` where direction can only be `desc` or `asc`. Ex. `?sort=agreement_date:desc`"
+// @param       filter query string false "Filter in format `<json_field>[<operator>]:<value>` where operator can be `gte`, `lte` or `eql`. Filterable fields are `status`, `agreement_type`, `agreement_date` (RFC 3339 time or YYYY-MM-DD date) and `property.property_id`. Ex. `?filter=status[eql]:AWAITING_DEPOSIT,agreement_date[gte]:2024-02-01`"
 // @success     200	{object} models.MyAgreementResponses
+// @failure     400 {object} models.ErrorResponses "Invalid role, sort or filter"
 // @failure     500 {object} models.ErrorResponses "Could not get my agreements"
 func (h *handlerImpl) GetMyAgreements(c *fiber.Ctx) error {
 	agreementRequest := models.MyAgreementRequests{
 		UserId: c.Locals("session").(models.Sessions).UserId,
 		Order: c.Query("order"),
+		Role: enums.ActorRoles(c.Query("role")),
 	}
 
+	sorted := utils.NewSortedQuery(models.AgreementLists{})
+	if err := sorted.ParseQuery(c.Query("sort")); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(err.Error()))
+	}
+
+	filtered := utils.NewFilteredQuery(models.AgreementLists{})
+	if err := filtered.ParseQuery(c.Query("filter")); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(err.Error()))
+	}
+
+	limit := utils.Clamp(c.QueryInt("limit", 20), 1, 50)
+	page := utils.Max(c.QueryInt("page", 1), 1)
+
+	paginated := utils.NewPaginatedQuery(page, limit)
+
 	var agreements models.MyAgreementResponses
-	err := h.service.GetMyAgreements(&agreements, &agreementRequest)
+	err := h.service.GetMyAgreements(&agreements, &agreementRequest, paginated, sorted, filtered)
 	if err != nil {
 		return utils.ResponseError(c, err)
 	}
@@ -93,11 +152,15 @@ func (h *handlerImpl) GetMyAgreements(c *fiber.Ctx) error {
 
 // @router  /api/v1/agreements [post]
 // @summary  Create an agreement *use cookies*
-// @description  Create an agreement by parsing the body
+// @description  Create an agreement for a property of the current user by parsing the body. The status always starts at AWAITING_DEPOSIT and the total payment is computed by the server: deposit plus payment per month times duration for renting, the selling price for selling.
 // @tags agreements
 // @produce json
 // @param body body models.CreatingAgreements true "Agreement to create"
 // @success 201 {object} models.MessageResponses "Agreement created successfully"
+// @failure 400 {object} models.ErrorResponses "Invalid agreement type, amounts or duration"
+// @failure 403 {object} models.ErrorResponses "Not the owner of the property"
+// @failure 404 {object} models.ErrorResponses "Property or dweller not found"
+// @failure 409 {object} models.ErrorResponses "Property already sold, occupied or under another agreement"
 // @failure 500 {object} models.ErrorResponses "Could not create agreement"
 func (h *handlerImpl) CreateAgreement(c *fiber.Ctx) error {
 	agreement := &models.CreatingAgreements{
@@ -121,16 +184,21 @@ func (h *handlerImpl) CreateAgreement(c *fiber.Ctx) error {
 
 // @router  /api/v1/agreements/:agreementId [delete]
 // @summary  Delete an agreement by id *use cookies*
-// @description  Delete an agreement by its id
+// @description  Delete an agreement by its id. Only the owner or an admin can delete it, and a RENTING or OVERDUE agreement has to be archived or cancelled first
 // @tags agreements
 // @produce json
 // @param agreementId path string true "Agreement ID"
 // @success 200 {object} models.MessageResponses "Agreement deleted"
+// @failure 400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure 403 {object} models.ErrorResponses "Not the owner of the agreement"
+// @failure 404 {object} models.ErrorResponses "Agreement not found"
+// @failure 409 {object} models.ErrorResponses "Agreement is renting or overdue"
 // @failure 500 {object} models.ErrorResponses "Could not delete agreement"
 func (h *handlerImpl) DeleteAgreement(c *fiber.Ctx) error {
 	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
 
-	err := h.service.DeleteAgreement(agreementId)
+	err := h.service.DeleteAgreement(agreementId, &session)
 	if err != nil {
 		return utils.ResponseError(c, err)
 	}
@@ -140,13 +208,16 @@ func (h *handlerImpl) DeleteAgreement(c *fiber.Ctx) error {
 
 // @router      /api/v1/agreements/:agreementId [patch]
 // @summary     Update an agreement status by id *use cookies*
-// @description Update an agreement status by id with **status** and **cancelled_message**(optional)
+// @description Move an agreement to **status** with **cancelled_message**(optional). Only the transitions of the agreement lifecycle are allowed, each for either the owner or the dweller, and some of them require the related payment to have succeeded. Leaving AWAITING_DEPOSIT also requires both parties to have signed the contract
 // @tags        agreements
 // @produce     json
 // @param       agreementId path string true "Agreement ID"
 // @param       body body models.UpdatingAgreementStatus true "Agreement status and cancelled message(optional)"
 // @success     200	{object} models.MessageResponses "Agreement state updated"
-// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or status"
+// @failure     403 {object} models.ErrorResponses "Not allowed to make this transition"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Invalid agreement transition or required payment not made"
 // @failure     500 {object} models.ErrorResponses "Could not update agreement status"
 func (h *handlerImpl) UpdateAgreementStatus(c *fiber.Ctx) error {
 	updatingAgreement := models.UpdatingAgreementStatus{}
@@ -159,10 +230,865 @@ func (h *handlerImpl) UpdateAgreementStatus(c *fiber.Ctx) error {
 
 	agreementId := c.Params("agreementId")
 
-	apperr := h.service.UpdateAgreementStatus(&updatingAgreement, agreementId)
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.UpdateAgreementStatus(&updatingAgreement, agreementId, &session)
 	if apperr != nil {
 		return utils.ResponseError(c, apperr)
 	}
 
 	return utils.ResponseMessage(c, http.StatusOK, "Agreement state updated")
 }
+
+// @router      /api/v1/agreements/:agreementId/installments [get]
+// @summary     Get the installment schedule of an agreement *use cookies*
+// @description Get every monthly installment of a renting agreement with its due date, amount and status, along with the paid and outstanding totals. Utilities billed with an installment are itemized in its meter readings and included in the totals. Only the owner and the dweller can view it.
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} models.AgreementInstallmentSchedules
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     500 {object} models.ErrorResponses "Could not get agreement installments"
+func (h *handlerImpl) GetAgreementInstallments(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+
+	session := c.Locals("session").(models.Sessions)
+
+	schedule := models.AgreementInstallmentSchedules{}
+	apperr := h.service.GetAgreementInstallments(&schedule, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(schedule)
+}
+
+// @router      /api/v1/agreements/:agreementId/contract [post]
+// @summary     Generate the contract of an agreement *use cookies*
+// @description Render the contract of an agreement from its current terms as a PDF and store it as a new version. Every generated version is kept along with the SHA-256 hash of its document. Only the owner and the dweller can generate it, and only until someone has signed it.
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       language query string false "Contract language, EN or TH" default(EN)
+// @success     201	{object} models.AgreementContracts
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or contract language"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Another version was generated at the same time"
+// @failure     500 {object} models.ErrorResponses "Could not generate contract"
+// @failure     503 {object} models.ErrorResponses "Thai contracts are not available"
+func (h *handlerImpl) GenerateAgreementContract(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	language := enums.ContractLanguages(strings.ToUpper(c.Query("language", string(enums.EnglishContract))))
+
+	session := c.Locals("session").(models.Sessions)
+
+	contract := models.AgreementContracts{}
+	apperr := h.service.GenerateAgreementContract(&contract, agreementId, language, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(contract)
+}
+
+// @router      /api/v1/agreements/:agreementId/contract [get]
+// @summary     Download the contract of an agreement *use cookies*
+// @description Download the latest generated version of the contract of an agreement as a PDF. Only the owner, the dweller and admins can download it.
+// @tags        agreements
+// @produce     application/pdf
+// @param       agreementId path string true "Agreement ID"
+// @param       language query string false "Contract language, EN or TH" default(EN)
+// @success     200	{file} file "Contract document"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or contract language"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or contract not found"
+// @failure     500 {object} models.ErrorResponses "Could not download contract"
+func (h *handlerImpl) DownloadAgreementContract(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	language := enums.ContractLanguages(strings.ToUpper(c.Query("language", string(enums.EnglishContract))))
+
+	session := c.Locals("session").(models.Sessions)
+
+	var document bytes.Buffer
+	contract := models.AgreementContracts{}
+	apperr := h.service.DownloadAgreementContract(&document, &contract, agreementId, language, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	c.Attachment(fmt.Sprintf("agreement-%v-v%v-%v.pdf", agreementId, contract.Version, strings.ToLower(string(contract.Language))))
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	return c.Send(document.Bytes())
+}
+
+// @router      /api/v1/agreements/:agreementId/photos/:path [get]
+// @summary     Download a photo attached to an agreement *use cookies*
+// @description Download deduction evidence, an inspection photo, a meter photo or a maintenance photo of an agreement. The photos are private and their `image_url` points here. Only the owner, the dweller and admins can download them.
+// @tags        agreements
+// @produce     image/jpeg
+// @param       agreementId path string true "Agreement ID"
+// @param       path path string true "Path of the photo, e.g. deductions/123e4567-e89b-12d3-a456-426614174000-1.jpeg"
+// @success     200	{file} file "Photo"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or photo not found"
+// @failure     500 {object} models.ErrorResponses "Could not download photo"
+func (h *handlerImpl) DownloadAgreementPhoto(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	photoPath := c.Params("*")
+	session := c.Locals("session").(models.Sessions)
+
+	var photo bytes.Buffer
+	apperr := h.service.DownloadAgreementPhoto(&photo, agreementId, photoPath, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	c.Set(fiber.HeaderContentType, "image/jpeg")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=86400")
+	return c.Send(photo.Bytes())
+}
+
+// @router      /api/v1/agreements/:agreementId/signatures [post]
+// @summary     Sign the contract of an agreement *use cookies*
+// @description Sign the generated contract of an agreement awaiting deposit. A signature is either typed, or drawn and uploaded as a PNG in formData with field `signature_image`. The first signature freezes the hash of the contract so both parties sign the same document, and `language` is only needed for it (defaults to EN). The signer's IP address and the time are recorded. The agreement can only progress once both the owner and the dweller have signed
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       formData formData models.SigningAgreements true "Signature details"
+// @success     201	{object} models.AgreementSignatures
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, signature or contract language"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or contract not found"
+// @failure     409 {object} models.ErrorResponses "Already signed, not awaiting deposit or the document has changed"
+// @failure     500 {object} models.ErrorResponses "Could not sign agreement"
+func (h *handlerImpl) SignAgreement(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+
+	session := c.Locals("session").(models.Sessions)
+
+	var signing models.SigningAgreements
+	if err := c.BodyParser(&signing); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidBody).
+			Describe("Invalid request body"))
+	}
+	signing.Language = enums.ContractLanguages(strings.ToUpper(string(signing.Language)))
+	signing.SignatureType = enums.SignatureTypes(strings.ToUpper(string(signing.SignatureType)))
+	signing.IpAddress = c.IP()
+	signing.UserAgent = c.Get(fiber.HeaderUserAgent)
+
+	signatureImage, _ := c.FormFile("signature_image")
+
+	signature := models.AgreementSignatures{}
+	apperr := h.service.SignAgreement(&signature, agreementId, &signing, signatureImage, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(signature)
+}
+
+// @router      /api/v1/agreements/:agreementId/signatures [get]
+// @summary     Verify the signatures of an agreement *use cookies*
+// @description Get the signature audit trail of the signed contract of an agreement: who signed, how, when and from which IP address. The stored document is hashed again and compared with the hash frozen at the first signature, so any change to it is reported. Only the owner, the dweller and admins can view it
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} models.AgreementSignatureVerifications
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found or not signed yet"
+// @failure     500 {object} models.ErrorResponses "Could not verify signatures"
+func (h *handlerImpl) VerifyAgreementSignatures(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+
+	session := c.Locals("session").(models.Sessions)
+
+	verification := models.AgreementSignatureVerifications{}
+	apperr := h.service.VerifyAgreementSignatures(&verification, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(verification)
+}
+
+// @router      /api/v1/agreements/:agreementId/amendments [get]
+// @summary     Get the amendments of an agreement *use cookies*
+// @description Get every renewal and amendment requested on an agreement, oldest first. Accepted amendments carry the version of the terms they introduced, the original terms being version 1
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{array} models.AgreementAmendments
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     500 {object} models.ErrorResponses "Could not get agreement amendments"
+func (h *handlerImpl) GetAgreementAmendments(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	amendments := []models.AgreementAmendments{}
+	apperr := h.service.GetAgreementAmendments(&amendments, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(amendments)
+}
+
+// @router      /api/v1/agreements/:agreementId/amendments [post]
+// @summary     Request an amendment to an agreement *use cookies*
+// @description Ask the other party to change the term or the monthly payment of a renting agreement that is awaiting payment, renting or overdue. The term is given either as **payment_duration** in months or as **end_date**. Installments that are already due or paid keep their amount, so the term cannot end before them. A new amendment supersedes the one still waiting for an answer
+// @tags        agreements
+// @accept      json
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       body body models.CreatingAgreementAmendments true "New terms and message(optional)"
+// @success     201	{object} models.AgreementAmendments
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or terms"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Agreement could not be amended"
+// @failure     500 {object} models.ErrorResponses "Could not create agreement amendment"
+func (h *handlerImpl) CreateAgreementAmendment(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	creating := models.CreatingAgreementAmendments{}
+	err := c.BodyParser(&creating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	amendment := models.AgreementAmendments{}
+	apperr := h.service.CreateAgreementAmendment(&amendment, agreementId, &creating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(amendment)
+}
+
+// @router      /api/v1/agreements/:agreementId/amendments/:amendmentId/accept [post]
+// @summary     Accept an agreement amendment *use cookies*
+// @description Accept an amendment made by the other party. The agreement takes the new terms as its next version and the installments that are not due yet are recalculated
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       amendmentId path string true "Amendment ID"
+// @success     200	{object} models.AgreementAmendments
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, amendment id or terms"
+// @failure     403 {object} models.ErrorResponses "Not the other party of the amendment"
+// @failure     404 {object} models.ErrorResponses "Agreement or amendment not found"
+// @failure     409 {object} models.ErrorResponses "Amendment is not pending or the agreement could not be amended"
+// @failure     500 {object} models.ErrorResponses "Could not accept agreement amendment"
+func (h *handlerImpl) AcceptAgreementAmendment(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	amendmentId := c.Params("amendmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	amendment := models.AgreementAmendments{}
+	apperr := h.service.AcceptAgreementAmendment(&amendment, agreementId, amendmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(amendment)
+}
+
+// @router      /api/v1/agreements/:agreementId/amendments/:amendmentId/decline [post]
+// @summary     Decline an agreement amendment *use cookies*
+// @description Decline an amendment made by the other party. The agreement keeps its current terms
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       amendmentId path string true "Amendment ID"
+// @success     200	{object} models.MessageResponses "Amendment declined"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or amendment id"
+// @failure     403 {object} models.ErrorResponses "Not the other party of the amendment"
+// @failure     404 {object} models.ErrorResponses "Agreement or amendment not found"
+// @failure     409 {object} models.ErrorResponses "Amendment is not pending"
+// @failure     500 {object} models.ErrorResponses "Could not decline agreement amendment"
+func (h *handlerImpl) DeclineAgreementAmendment(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	amendmentId := c.Params("amendmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.DeclineAgreementAmendment(agreementId, amendmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Amendment declined")
+}
+
+// @router      /api/v1/agreements/:agreementId/deposit [get]
+// @summary     Get the deposit of an agreement *use cookies*
+// @description Get where the deposit of an agreement stands: UNPAID until the dweller has paid it, then HELD, PARTIALLY_DEDUCTED once a deduction is accepted and REFUNDED once the owner has refunded the rest. Every deduction is listed with its evidence photos
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} models.AgreementDeposits
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     500 {object} models.ErrorResponses "Could not get agreement deposit"
+func (h *handlerImpl) GetAgreementDeposit(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	deposit := models.AgreementDeposits{}
+	apperr := h.service.GetAgreementDeposit(&deposit, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(deposit)
+}
+
+// @router      /api/v1/agreements/:agreementId/deposit/deductions [post]
+// @summary     Deduct from the deposit of an agreement *use cookies*
+// @description Claim part of the held deposit of a renting agreement once the dweller has moved in. Evidence photos (.jpg / .png, up to 10) are uploaded in formData with field `evidences`. Deductions cannot exceed the held deposit and wait for the dweller to accept or dispute them. Only the owner can deduct
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       formData formData models.CreatingDepositDeductions true "Deduction details"
+// @success     201	{object} models.AgreementDepositDeductions
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, amount, description or photos"
+// @failure     403 {object} models.ErrorResponses "Not the owner"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Deposit is not held or already refunded"
+// @failure     500 {object} models.ErrorResponses "Could not create deposit deduction"
+func (h *handlerImpl) CreateDepositDeduction(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	var creating models.CreatingDepositDeductions
+	if err := c.BodyParser(&creating); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidBody).
+			Describe("Invalid request body"))
+	}
+
+	var evidences []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		evidences = form.File["evidences"]
+	}
+
+	deduction := models.AgreementDepositDeductions{}
+	apperr := h.service.CreateDepositDeduction(&deduction, agreementId, &creating, evidences, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(deduction)
+}
+
+// @router      /api/v1/agreements/:agreementId/deposit/deductions/:deductionId/accept [post]
+// @summary     Accept a deposit deduction *use cookies*
+// @description Accept a deduction claimed by the owner. The amount is taken out of the deposit refund. Only the dweller can accept it
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       deductionId path string true "Deduction ID"
+// @success     200	{object} models.MessageResponses "Deduction accepted"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or deduction id"
+// @failure     403 {object} models.ErrorResponses "Not the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or deduction not found"
+// @failure     409 {object} models.ErrorResponses "Deduction is not pending or the deposit is not held"
+// @failure     500 {object} models.ErrorResponses "Could not respond to deposit deduction"
+func (h *handlerImpl) AcceptDepositDeduction(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	deductionId := c.Params("deductionId")
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.AcceptDepositDeduction(agreementId, deductionId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Deduction accepted")
+}
+
+// @router      /api/v1/agreements/:agreementId/deposit/deductions/:deductionId/dispute [post]
+// @summary     Dispute a deposit deduction *use cookies*
+// @description Dispute a deduction claimed by the owner with a reason. The deposit cannot be refunded until the dispute is resolved. Disputes that are not escalated within the timeout are withdrawn. Only the dweller can dispute it
+// @tags        agreements
+// @accept      json
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       deductionId path string true "Deduction ID"
+// @param       body body models.DisputingDepositDeductions true "Reason of the dispute"
+// @success     200	{object} models.MessageResponses "Deduction disputed"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, deduction id or reason"
+// @failure     403 {object} models.ErrorResponses "Not the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or deduction not found"
+// @failure     409 {object} models.ErrorResponses "Deduction is not pending or the deposit is not held"
+// @failure     500 {object} models.ErrorResponses "Could not respond to deposit deduction"
+func (h *handlerImpl) DisputeDepositDeduction(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	deductionId := c.Params("deductionId")
+	session := c.Locals("session").(models.Sessions)
+
+	disputing := models.DisputingDepositDeductions{}
+	err := c.BodyParser(&disputing)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	apperr := h.service.DisputeDepositDeduction(agreementId, deductionId, &disputing, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Deduction disputed")
+}
+
+// @router      /api/v1/agreements/:agreementId/deposit/deductions/:deductionId [delete]
+// @summary     Withdraw a deposit deduction *use cookies*
+// @description Withdraw a deduction that is pending or disputed, for instance to claim a revised amount instead. Accepted deductions cannot be withdrawn. Only the owner can withdraw it
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       deductionId path string true "Deduction ID"
+// @success     200	{object} models.MessageResponses "Deduction withdrawn"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or deduction id"
+// @failure     403 {object} models.ErrorResponses "Not the owner"
+// @failure     404 {object} models.ErrorResponses "Agreement or deduction not found"
+// @failure     409 {object} models.ErrorResponses "Deduction is not pending or disputed, or the deposit is not held"
+// @failure     500 {object} models.ErrorResponses "Could not delete deposit deduction"
+func (h *handlerImpl) DeleteDepositDeduction(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	deductionId := c.Params("deductionId")
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.DeleteDepositDeduction(agreementId, deductionId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Deduction withdrawn")
+}
+
+// @router      /api/v1/agreements/:agreementId/deposit/deductions/:deductionId/resolve [post]
+// @summary     Resolve a disputed deposit deduction *use cookies*
+// @description Settle a DISPUTED deduction. The dweller can move it to ACCEPTED and the owner to WITHDRAWN. Either of them can move it to ESCALATED for an admin, who then moves it to ACCEPTED or WITHDRAWN. Disputes that are not escalated within the timeout are withdrawn
+// @tags        agreements
+// @accept      json
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       deductionId path string true "Deduction ID"
+// @param       body body models.ResolvingDepositDeductions true "Status to move the deduction to"
+// @success     200	{object} models.MessageResponses "Deduction resolved"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, deduction id or status"
+// @failure     403 {object} models.ErrorResponses "Not allowed to move the deduction to the status"
+// @failure     404 {object} models.ErrorResponses "Agreement or deduction not found"
+// @failure     409 {object} models.ErrorResponses "Deduction is not disputed or the deposit is not held"
+// @failure     500 {object} models.ErrorResponses "Could not resolve deposit deduction"
+func (h *handlerImpl) ResolveDepositDeduction(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	deductionId := c.Params("deductionId")
+	session := c.Locals("session").(models.Sessions)
+
+	resolving := models.ResolvingDepositDeductions{}
+	err := c.BodyParser(&resolving)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	apperr := h.service.ResolveDepositDeduction(agreementId, deductionId, &resolving, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Deduction resolved")
+}
+
+// @router      /api/v1/agreements/:agreementId/deposit/refund [post]
+// @summary     Refund the deposit of an agreement *use cookies*
+// @description Record the refund of the held deposit minus the accepted deductions once the agreement is archived or cancelled. Every deduction must be accepted or withdrawn first. The refund is a payment from the owner to the dweller and shows up in the payment history of both. Only the owner can refund
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     201	{object} models.Payments
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Agreement is not over, deductions are unresolved or the deposit is not held"
+// @failure     500 {object} models.ErrorResponses "Could not refund deposit"
+func (h *handlerImpl) RefundDeposit(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	refund := models.Payments{}
+	apperr := h.service.RefundDeposit(&refund, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(refund)
+}
+
+// @router      /api/v1/agreements/:agreementId/inspections [get]
+// @summary     Get inspection reports of an agreement *use cookies*
+// @description Get the move-in and move-out reports of an agreement with their items, condition ratings, notes and photos, including the reports that have been superseded
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} []models.AgreementInspections
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     500 {object} models.ErrorResponses "Could not get agreement inspections"
+func (h *handlerImpl) GetAgreementInspections(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	inspections := []models.AgreementInspections{}
+	apperr := h.service.GetAgreementInspections(&inspections, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(inspections)
+}
+
+// @router      /api/v1/agreements/:agreementId/inspections/:inspectionId [get]
+// @summary     Get an inspection report *use cookies*
+// @description Get an inspection report of an agreement with its items, condition ratings, notes and photos
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       inspectionId path string true "Inspection ID"
+// @success     200	{object} models.AgreementInspections
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or inspection id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or inspection not found"
+// @failure     500 {object} models.ErrorResponses "Could not get agreement inspection"
+func (h *handlerImpl) GetAgreementInspection(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	inspectionId := c.Params("inspectionId")
+	session := c.Locals("session").(models.Sessions)
+
+	inspection := models.AgreementInspections{}
+	apperr := h.service.GetAgreementInspection(&inspection, agreementId, inspectionId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(inspection)
+}
+
+// @router      /api/v1/agreements/:agreementId/inspections [post]
+// @summary     Write an inspection report *use cookies*
+// @description Write the MOVE_IN or MOVE_OUT report of a renting agreement room by room. Each item has a condition (EXCELLENT, GOOD, FAIR, POOR or DAMAGED) and optional notes, and photos are uploaded per item afterwards. The move-in report can be written from AWAITING_PAYMENT until the agreement is archived, the move-out report from RENTING. Either the owner or the dweller can write it. Writing a report while the current one is signed off by one party only supersedes it, so a party can contest a report they disagree with
+// @tags        agreements
+// @accept      json
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       body body models.CreatingInspections true "Inspection report"
+// @success     201	{object} models.AgreementInspections
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, type or items"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Report not signed off yet or final, or not allowed in the current status"
+// @failure     500 {object} models.ErrorResponses "Could not create agreement inspection"
+func (h *handlerImpl) CreateAgreementInspection(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	creating := models.CreatingInspections{}
+	err := c.BodyParser(&creating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	inspection := models.AgreementInspections{}
+	apperr := h.service.CreateAgreementInspection(&inspection, agreementId, &creating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(inspection)
+}
+
+// @router      /api/v1/agreements/:agreementId/inspections/:inspectionId [put]
+// @summary     Rewrite the items of an inspection report *use cookies*
+// @description Replace the items of an inspection report nobody has signed off yet. The photos of the replaced items are dropped
+// @tags        agreements
+// @accept      json
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       inspectionId path string true "Inspection ID"
+// @param       body body models.UpdatingInspections true "Inspection items"
+// @success     200	{object} models.AgreementInspections
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, inspection id or items"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or inspection not found"
+// @failure     409 {object} models.ErrorResponses "Report already signed off or not allowed in the current status"
+// @failure     500 {object} models.ErrorResponses "Could not update agreement inspection"
+func (h *handlerImpl) UpdateAgreementInspection(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	inspectionId := c.Params("inspectionId")
+	session := c.Locals("session").(models.Sessions)
+
+	updating := models.UpdatingInspections{}
+	err := c.BodyParser(&updating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	inspection := models.AgreementInspections{}
+	apperr := h.service.UpdateAgreementInspection(&inspection, agreementId, inspectionId, &updating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(inspection)
+}
+
+// @router      /api/v1/agreements/:agreementId/inspections/:inspectionId/items/:itemId/photos [post]
+// @summary     Upload photos of an inspection item *use cookies*
+// @description Upload photos (.jpg / .png) of an item of an inspection report nobody has signed off yet in formData with field `photos`. An item can have up to 10 photos
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       inspectionId path string true "Inspection ID"
+// @param       itemId path string true "Item ID"
+// @param       photos formData file true "Photos of the item"
+// @success     201	{object} models.AgreementInspectionItems
+// @failure     400 {object} models.ErrorResponses "Invalid ids or photos"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement, inspection or item not found"
+// @failure     409 {object} models.ErrorResponses "Report already signed off or not allowed in the current status"
+// @failure     500 {object} models.ErrorResponses "Could not upload inspection photos"
+func (h *handlerImpl) UploadInspectionPhotos(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	inspectionId := c.Params("inspectionId")
+	itemId := c.Params("itemId")
+	session := c.Locals("session").(models.Sessions)
+
+	var photos []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		photos = form.File["photos"]
+	}
+
+	item := models.AgreementInspectionItems{}
+	apperr := h.service.UploadInspectionPhotos(&item, agreementId, inspectionId, itemId, photos, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(item)
+}
+
+// @router      /api/v1/agreements/:agreementId/inspections/:inspectionId/sign [post]
+// @summary     Sign off an inspection report *use cookies*
+// @description Record that the owner or the dweller agrees with an inspection report. The party that did not write the report signs it off first, which freezes it, and the report is final once its author has signed it off too
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       inspectionId path string true "Inspection ID"
+// @success     200	{object} models.AgreementInspections
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or inspection id, or the report is empty"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or inspection not found"
+// @failure     409 {object} models.ErrorResponses "Already signed off, not signed off by the other party yet or superseded"
+// @failure     500 {object} models.ErrorResponses "Could not sign agreement inspection"
+func (h *handlerImpl) SignAgreementInspection(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	inspectionId := c.Params("inspectionId")
+	session := c.Locals("session").(models.Sessions)
+
+	inspection := models.AgreementInspections{}
+	apperr := h.service.SignAgreementInspection(&inspection, agreementId, inspectionId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(inspection)
+}
+
+// @router      /api/v1/agreements/:agreementId/inspections/comparison [get]
+// @summary     Compare the move-out report with the move-in report *use cookies*
+// @description Put the items of the move-out report side by side with the move-in report, matched by room and name. Items missing from one report are null on that side, and items whose condition got worse are flagged
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} models.InspectionComparisons
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement or one of the reports not found"
+// @failure     500 {object} models.ErrorResponses "Could not get agreement inspections"
+func (h *handlerImpl) CompareAgreementInspections(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	comparison := models.InspectionComparisons{}
+	apperr := h.service.CompareAgreementInspections(&comparison, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(comparison)
+}
+
+// @router      /api/v1/agreements/:agreementId/utility-rates [get]
+// @summary     Get the utility rates of an agreement *use cookies*
+// @description Get what the owner charges per unit of water and electricity
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} models.AgreementUtilityRates
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found or rates not set"
+// @failure     500 {object} models.ErrorResponses "Could not get utility rates"
+func (h *handlerImpl) GetUtilityRates(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	rates := models.AgreementUtilityRates{}
+	apperr := h.service.GetUtilityRates(&rates, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(rates)
+}
+
+// @router      /api/v1/agreements/:agreementId/utility-rates [put]
+// @summary     Set the utility rates of an agreement *use cookies*
+// @description Set what the owner charges per unit of water and electricity in a renting agreement. Readings already recorded keep the rate they were charged at. Only the owner can set the rates
+// @tags        agreements
+// @accept      json
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       body body models.UpdatingUtilityRates true "Unit rates"
+// @success     200	{object} models.AgreementUtilityRates
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or rates"
+// @failure     403 {object} models.ErrorResponses "Not the owner"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Not a renting agreement or the agreement is over"
+// @failure     500 {object} models.ErrorResponses "Could not save utility rates"
+func (h *handlerImpl) UpdateUtilityRates(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	updating := models.UpdatingUtilityRates{}
+	err := c.BodyParser(&updating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	rates := models.AgreementUtilityRates{}
+	apperr := h.service.UpdateUtilityRates(&rates, agreementId, &updating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(rates)
+}
+
+// @router      /api/v1/agreements/:agreementId/meter-readings [get]
+// @summary     Get the meter readings of an agreement *use cookies*
+// @description Get every water and electricity meter reading of an agreement with the units used, the rate and the amount billed with each installment
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} []models.AgreementMeterReadings
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     500 {object} models.ErrorResponses "Could not get meter readings"
+func (h *handlerImpl) GetMeterReadings(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	readings := []models.AgreementMeterReadings{}
+	apperr := h.service.GetMeterReadings(&readings, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(readings)
+}
+
+// @router      /api/v1/agreements/:agreementId/meter-readings [post]
+// @summary     Record a meter reading *use cookies*
+// @description Record the WATER or ELECTRICITY meter reading of a month with a photo (.jpg / .png) of the meter in formData with field `photo`. The units used since the previous reading are charged at the current rate and added to the given installment, which has to be the earliest unpaid one and due within a month. An installment billed for utilities can no longer be dropped by shortening the term. The first reading of a meter needs the reading it started from. Only the owner can record readings, while the agreement is RENTING or OVERDUE
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       formData formData models.CreatingMeterReadings true "Meter reading"
+// @param       photo formData file true "Photo of the meter"
+// @success     201	{object} models.AgreementMeterReadings
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, reading or photo"
+// @failure     403 {object} models.ErrorResponses "Not the owner"
+// @failure     404 {object} models.ErrorResponses "Agreement, installment or utility rates not found"
+// @failure     409 {object} models.ErrorResponses "Installment already paid, not the earliest unpaid one, not due yet, meter already read or not renting"
+// @failure     500 {object} models.ErrorResponses "Could not create meter reading"
+func (h *handlerImpl) CreateMeterReading(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	var creating models.CreatingMeterReadings
+	if err := c.BodyParser(&creating); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidBody).
+			Describe("Invalid request body"))
+	}
+
+	photo, _ := c.FormFile("photo")
+
+	reading := models.AgreementMeterReadings{}
+	apperr := h.service.CreateMeterReading(&reading, agreementId, &creating, photo, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(reading)
+}
+
+// @router      /api/v1/agreements/:agreementId/meter-readings/:readingId [delete]
+// @summary     Delete a meter reading *use cookies*
+// @description Take back the latest reading of a meter along with its charge, as long as its installment has not been paid. Only the owner can delete readings
+// @tags        agreements
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       readingId path string true "Reading ID"
+// @success     200	{object} models.MessageResponses "Meter reading deleted"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or reading id"
+// @failure     403 {object} models.ErrorResponses "Not the owner"
+// @failure     404 {object} models.ErrorResponses "Agreement or reading not found"
+// @failure     409 {object} models.ErrorResponses "Not the latest reading or the installment has been paid"
+// @failure     500 {object} models.ErrorResponses "Could not delete meter reading"
+func (h *handlerImpl) DeleteMeterReading(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	readingId := c.Params("readingId")
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.DeleteMeterReading(agreementId, readingId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Meter reading deleted")
+}
@@ -2,18 +2,88 @@ package agreements
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errAgreementStatusChanged  = errors.New("agreement status has been changed concurrently")
+	errAmendmentNotPending     = errors.New("amendment is no longer pending")
+	errAgreementTermsChanged   = errors.New("agreement terms have been changed concurrently")
+	errDeductionNotPending     = errors.New("deduction is no longer pending")
+	errDeductionsUnresolved    = errors.New("deposit deductions are still unresolved")
+	errDeductionNotDisputed    = errors.New("deduction is no longer disputed")
+	errDepositRefunded         = errors.New("deposit has already been refunded")
+	errInspectionSigned        = errors.New("inspection has been signed off")
+	errInspectionAlreadySigned = errors.New("inspection has already been signed off by this party")
+	errInspectionUnsigned      = errors.New("inspection has not been signed off yet")
+	errInspectionFinalised     = errors.New("inspection has been signed off by both parties")
+	errTooManyPhotos           = errors.New("too many photos")
+	errInstallmentSettled      = errors.New("installment has already been paid")
+	errAgreementOccupying      = errors.New("agreement is occupying the property")
+	errDeductionExceedsDeposit = errors.New("deductions exceed the held deposit")
 )
 
 type Repository interface {
 	GetAllAgreements(*[]models.AgreementLists) error
 	GetAgreementById(*models.AgreementDetails, string) error
-	GetAgreementByUserId(*models.MyAgreementResponses, *models.MyAgreementRequests) error
-	CreateAgreement(*models.CreatingAgreements) error
+	GetAgreementByUserId(*models.MyAgreementResponses, *models.MyAgreementRequests, *utils.PaginatedQuery, *utils.SortedQuery, *utils.FilteredQuery) error
+	CreateAgreement(*models.CreatingAgreements, []models.AgreementInstallments) error
 	DeleteAgreement(string) error
-	UpdateAgreementStatus(*models.UpdatingAgreementStatus, string) error
+	GetAgreement(*models.Agreements, string) error
+	UpdateAgreementStatus(*models.UpdatingAgreementStatus, string, *models.AgreementStatusHistories, *models.AgreementPropertyFlags) error
+	GetAgreementStatusHistories(*[]models.AgreementStatusHistories, string) error
+	GetPaidAmount(*float64, string, ...enums.PaymentTypes) error
+	GetAgreementInstallments(*[]models.AgreementInstallments, string) error
+	GetLateInstallments(*[]models.AgreementInstallments, time.Time) error
+	UpdateInstallmentLateFee(*models.AgreementInstallments) error
+	CountOverdueInstallments(*int64, string) error
+	GetNotificationRecipient(*models.NotificationRecipients, uuid.UUID) error
+	GetAgreementProperty(*models.AgreementProperties, uuid.UUID) error
+	CountActiveAgreements(*int64, uuid.UUID) error
+	GetLatestAgreementContract(*models.AgreementContracts, string, enums.ContractLanguages) error
+	CreateAgreementContract(*models.AgreementContracts) error
+	DeleteAgreementContract(uuid.UUID) error
+	GetSignedAgreementContract(*models.AgreementContracts, string) error
+	GetContractSignatures(*[]models.AgreementSignatures, uuid.UUID) error
+	CountAgreementSignatures(*int64, string, int) error
+	GetAgreementVersion(*int, string) error
+	CreateAgreementSignature(*models.AgreementSignatures) error
+	GetAgreementAmendments(*[]models.AgreementAmendments, string) error
+	GetAgreementAmendment(*models.AgreementAmendments, string, string) error
+	CreateAgreementAmendment(*models.AgreementAmendments) error
+	AcceptAgreementAmendment(*models.AgreementAmendments, uuid.UUID, *models.AgreementScheduleChanges) error
+	DeclineAgreementAmendment(*models.AgreementAmendments, uuid.UUID) error
+	GetDepositDeductions(*[]models.AgreementDepositDeductions, string) error
+	GetDepositDeduction(*models.AgreementDepositDeductions, string, string) error
+	CreateDepositDeduction(*models.AgreementDepositDeductions) error
+	RespondToDepositDeduction(*models.AgreementDepositDeductions) error
+	DeleteDepositDeduction(*models.AgreementDepositDeductions) error
+	ResolveDepositDeduction(*models.AgreementDepositDeductions, enums.DeductionStatus) error
+	WithdrawStaleDisputes(*int64, time.Time) error
+	GetDepositRefund(*models.Payments, string) error
+	CreateDepositRefund(*models.Payments) error
+	GetAgreementInspections(*[]models.AgreementInspections, string) error
+	GetAgreementInspection(*models.AgreementInspections, string, string) error
+	CreateAgreementInspection(*models.AgreementInspections) error
+	UpdateInspectionItems(*models.AgreementInspections) error
+	CreateInspectionPhotos(uuid.UUID, []models.AgreementInspectionPhotos, int) error
+	SignAgreementInspection(*models.AgreementInspections, enums.ActorRoles, time.Time) error
+	GetUtilityRates(*models.AgreementUtilityRates, string) error
+	SaveUtilityRates(*models.AgreementUtilityRates) error
+	GetMeterReadings(*[]models.AgreementMeterReadings, string) error
+	GetMeterReading(*models.AgreementMeterReadings, string, string) error
+	GetLatestMeterReading(*models.AgreementMeterReadings, string, enums.UtilityTypes) error
+	CreateMeterReading(*models.AgreementMeterReadings) error
+	DeleteMeterReading(*models.AgreementMeterReadings) error
 }
 
 type repositoryImpl struct {
@@ -62,7 +132,7 @@ func (repo *repositoryImpl) GetAllAgreements(agreements *[]models.AgreementLists
 				Raw(`
 					SELECT image_url
 					FROM property_images
-					WHERE property_id = @property_id
+					WHERE property_id = @property_id AND deleted_at IS NULL
 					`, sql.Named("property_id", agreement.Property.PropertyId)).
 				Pluck("image_url", &(*agreements)[i].Property.PropertyImages).Error; err != nil {
 				return err
@@ -78,8 +148,8 @@ func (repo *repositoryImpl) GetAgreementById(agreement *models.AgreementDetails,
 							p.alley, p.street, p.sub_district, p.district, p.province, 
 							p.country, p.postal_code, s.price, r.price_per_month 
 						FROM properties p
-						LEFT JOIN selling_properties s ON p.property_id = s.property_id
-						LEFT JOIN renting_properties r ON p.property_id = r.property_id
+						LEFT JOIN selling_properties s ON (p.property_id = s.property_id AND s.deleted_at IS NULL)
+						LEFT JOIN renting_properties r ON (p.property_id = r.property_id AND r.deleted_at IS NULL)
 						WHERE p.property_id = @property_id`
 
 	ownerQuery := `SELECT user_id AS owner_user_id,
@@ -134,7 +204,7 @@ func (repo *repositoryImpl) GetAgreementById(agreement *models.AgreementDetails,
 			Raw(`
 				SELECT image_url
 				FROM property_images
-				WHERE property_id = @property_id
+				WHERE property_id = @property_id AND deleted_at IS NULL
 				`, sql.Named("property_id", agreement.Property.PropertyId)).
 			Pluck("image_url", &agreement.Property.PropertyImages).Error; err != nil {
 			return err
@@ -144,7 +214,7 @@ func (repo *repositoryImpl) GetAgreementById(agreement *models.AgreementDetails,
 	})
 }
 
-func (repo *repositoryImpl) GetAgreementByUserId(agreementResponse *models.MyAgreementResponses, agreementRequest *models.MyAgreementRequests) error {
+func (repo *repositoryImpl) GetAgreementByUserId(agreementResponse *models.MyAgreementResponses, agreementRequest *models.MyAgreementRequests, paginated *utils.PaginatedQuery, sorted *utils.SortedQuery, filtered *utils.FilteredQuery) error {
 	propertiesQuery := `SELECT property_id, property_name, property_type FROM properties`
 
 	ownersQuery := `SELECT user_id AS owner_user_id,
@@ -169,45 +239,54 @@ func (repo *repositoryImpl) GetAgreementByUserId(agreementResponse *models.MyAgr
 							JOIN (` + propertiesQuery + `) AS p ON a.property_id = p.property_id
 							JOIN (` + ownersQuery + `) AS o ON a.owner_user_id = o.owner_user_id`
 	
-	return repo.db.Transaction(func(tx *gorm.DB) error {
+	orderSQL := sorted.SortedSQL()
+	if orderSQL == "" {
+		orderSQL = `ORDER BY a.created_at ` + agreementRequest.Order
+	}
+
+	getAgreementLists := func(tx *gorm.DB, userColumn string, agreements *[]models.AgreementLists, total *int64) error {
+		whereQuery := fmt.Sprintf(`WHERE %s = @user_id AND (%s)`, userColumn, filtered.FilteredSQL())
+		args := append([]interface{}{sql.Named("user_id", agreementRequest.UserId)}, filtered.FilteredArgs()...)
+
 		if err := tx.Model(&models.Agreements{}).
-			Raw(agreementListsQuery+`
-				WHERE a.owner_user_id = @user_id
-				ORDER BY a.created_at `+agreementRequest.Order+`
-				`, sql.Named("user_id", agreementRequest.UserId)).
-			Scan(&agreementResponse.OwnerAgreements).Error; err != nil {
+			Raw(`SELECT COUNT(*) FROM (`+agreementListsQuery+` `+whereQuery+`) AS lists`, args...).
+			Scan(total).Error; err != nil {
 			return err
 		}
 
 		if err := tx.Model(&models.Agreements{}).
 			Raw(agreementListsQuery+`
-				WHERE a.dweller_user_id = @user_id
-				ORDER BY a.created_at `+agreementRequest.Order+`
-				`, sql.Named("user_id", agreementRequest.UserId)).
-			Scan(&agreementResponse.DwellerAgreements).Error; err != nil {
+				`+whereQuery+`
+				`+orderSQL+`
+				`+paginated.PaginatedSQL(), args...).
+			Scan(agreements).Error; err != nil {
 			return err
 		}
 
-		for i, agreement := range agreementResponse.OwnerAgreements {
+		for i, agreement := range *agreements {
 			if err := tx.Model(&models.PropertyImages{}).
 				Raw(`
 					SELECT image_url
 					FROM property_images
-					WHERE property_id = @property_id
+					WHERE property_id = @property_id AND deleted_at IS NULL
 					`, sql.Named("property_id", agreement.Property.PropertyId)).
-				Pluck("image_url", &agreementResponse.OwnerAgreements[i].Property.PropertyImages).Error; err != nil {
+				Pluck("image_url", &(*agreements)[i].Property.PropertyImages).Error; err != nil {
 				return err
 			}
 		}
 
-		for i, agreement := range agreementResponse.DwellerAgreements {
-			if err := tx.Model(&models.PropertyImages{}).
-				Raw(`
-					SELECT image_url
-					FROM property_images
-					WHERE property_id = @property_id
-					`, sql.Named("property_id", agreement.Property.PropertyId)).
-				Pluck("image_url", &agreementResponse.DwellerAgreements[i].Property.PropertyImages).Error; err != nil {
+		return nil
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if agreementRequest.Role != enums.DwellerActor {
+			if err := getAgreementLists(tx, "a.owner_user_id", &agreementResponse.OwnerAgreements, &agreementResponse.OwnerTotal); err != nil {
+				return err
+			}
+		}
+
+		if agreementRequest.Role != enums.OwnerActor {
+			if err := getAgreementLists(tx, "a.dweller_user_id", &agreementResponse.DwellerAgreements, &agreementResponse.DwellerTotal); err != nil {
 				return err
 			}
 		}
@@ -216,25 +295,787 @@ func (repo *repositoryImpl) GetAgreementByUserId(agreementResponse *models.MyAgr
 	})
 }
 
-func (repo *repositoryImpl) CreateAgreement(agreement *models.CreatingAgreements) error {
-	return repo.db.Exec(`INSERT INTO agreements (agreement_type, property_id, owner_user_id, dweller_user_id, agreement_date, 
-		status, deposit_amount, payment_per_month, payment_duration, total_payment) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		agreement.AgreementType, agreement.PropertyId, agreement.OwnerUserId, agreement.DwellerUserId, agreement.AgreementDate, 
-		agreement.Status, agreement.DepositAmount, agreement.PaymentPerMonth, agreement.PaymentDuration, agreement.TotalPayment ).Error
+func (repo *repositoryImpl) CreateAgreement(agreement *models.CreatingAgreements, installments []models.AgreementInstallments) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`INSERT INTO agreements (agreement_type, property_id, owner_user_id, dweller_user_id, agreement_date, 
+			status, deposit_amount, payment_per_month, payment_duration, total_payment) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			RETURNING agreement_id`,
+			agreement.AgreementType, agreement.PropertyId, agreement.OwnerUserId, agreement.DwellerUserId, agreement.AgreementDate, 
+			agreement.Status, agreement.DepositAmount, agreement.PaymentPerMonth, agreement.PaymentDuration, agreement.TotalPayment ).
+			Scan(&agreement.AgreementId).Error; err != nil {
+			return err
+		}
+
+		if len(installments) == 0 {
+			return nil
+		}
+
+		for i := range installments {
+			installments[i].AgreementId = agreement.AgreementId
+		}
+
+		return tx.Create(&installments).Error
+	})
 }
 
+// DeleteAgreement moves an agreement to the trash, unless it has started renting
+// since it was read. Such an agreement occupies the property and has to be
+// archived or cancelled first.
 func (repo *repositoryImpl) DeleteAgreement(agreementId string) error {
-	if err := repo.db.Model(&models.Agreements{}).First(&models.Agreements{}, "agreement_id = ?", agreementId).Error; err != nil {
-		return err
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockAgreement(tx, uuid.MustParse(agreementId)); err != nil {
+			return err
+		}
+
+		var agreement models.Agreements
+		if err := tx.Model(&models.Agreements{}).First(&agreement, "agreement_id = ?", agreementId).Error; err != nil {
+			return err
+		}
+
+		if agreement.Status == enums.RentingAgreement || agreement.Status == enums.OverdueAgreement {
+			return errAgreementOccupying
+		}
+
+		return tx.Where("agreement_id = ?", agreementId).Delete(&models.Agreements{}).Error
+	})
+}
+
+func (repo *repositoryImpl) GetAgreement(agreement *models.Agreements, agreementId string) error {
+	return repo.db.Model(&models.Agreements{}).First(agreement, "agreement_id = ?", agreementId).Error
+}
+
+func (repo *repositoryImpl) UpdateAgreementStatus(updatingAgreement *models.UpdatingAgreementStatus, agreementId string, history *models.AgreementStatusHistories, flags *models.AgreementPropertyFlags) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// only move the agreement if nobody else has moved it since it was read
+		result := tx.Model(&models.Agreements{}).
+			Where("agreement_id = ? AND status = ?", agreementId, history.FromStatus).
+			Updates(map[string]interface{}{
+				"status":            updatingAgreement.Status,
+				"cancelled_message": gorm.Expr("COALESCE(NULLIF(?, ''), cancelled_message)", updatingAgreement.CancelledMessage),
+				"updated_at":        gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errAgreementStatusChanged
+		}
+
+		history.HistoryId = uuid.New()
+		history.AgreementId = uuid.MustParse(agreementId)
+		history.ToStatus = updatingAgreement.Status
+		history.Message = updatingAgreement.CancelledMessage
+
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+
+		if flags == nil {
+			return nil
+		}
+
+		if flags.IsSold != nil {
+			sellingQuery := `UPDATE selling_properties SET is_sold = ?, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?`
+			if err := tx.Exec(sellingQuery, *flags.IsSold, flags.PropertyId).Error; err != nil {
+				return err
+			}
+		}
+
+		if flags.IsOccupied != nil {
+			rentingQuery := `UPDATE renting_properties SET is_occupied = ?, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?`
+			if err := tx.Exec(rentingQuery, *flags.IsOccupied, flags.PropertyId).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (repo *repositoryImpl) GetAgreementStatusHistories(histories *[]models.AgreementStatusHistories, agreementId string) error {
+	return repo.db.Model(&models.AgreementStatusHistories{}).
+		Where("agreement_id = ?", agreementId).
+		Order("created_at ASC").
+		Find(histories).Error
+}
+
+// GetPaidAmount sums the successful payments of the given types made towards an agreement.
+func (repo *repositoryImpl) GetPaidAmount(amount *float64, agreementId string, paymentTypes ...enums.PaymentTypes) error {
+	return repo.db.Raw(`
+		SELECT COALESCE(SUM(price), 0)
+		FROM payments
+		WHERE agreement_id = ? AND issuccess AND payment_type IN ? AND deleted_at IS NULL
+		`, agreementId, paymentTypes).
+		Scan(amount).Error
+}
+
+func (repo *repositoryImpl) GetAgreementInstallments(installments *[]models.AgreementInstallments, agreementId string) error {
+	return repo.db.Model(&models.AgreementInstallments{}).
+		Preload("MeterReadings", func(db *gorm.DB) *gorm.DB { return db.Order("utility_type ASC") }).
+		Where("agreement_id = ?", agreementId).
+		Order("installment_number ASC").
+		Find(installments).Error
+}
+
+// GetLateInstallments lists the unpaid installments that were due before
+// dueBefore in agreements that are still running, oldest first.
+func (repo *repositoryImpl) GetLateInstallments(installments *[]models.AgreementInstallments, dueBefore time.Time) error {
+	return repo.db.Model(&models.AgreementInstallments{}).
+		Joins("JOIN agreements a ON a.agreement_id = agreement_installments.agreement_id").
+		Where("agreement_installments.status IN ? AND agreement_installments.due_date < ?",
+			[]enums.InstallmentStatus{enums.UnpaidInstallment, enums.OverdueInstallment}, dueBefore.Format(time.DateOnly)).
+		Where("a.status IN ?", []enums.AgreementStatus{enums.AwaitingPaymentAgreement, enums.RentingAgreement, enums.OverdueAgreement}).
+		Order("agreement_installments.due_date ASC").
+		Find(installments).Error
+}
+
+func (repo *repositoryImpl) UpdateInstallmentLateFee(installment *models.AgreementInstallments) error {
+	// a payment may have settled the installment since it was read
+	return repo.db.Model(&models.AgreementInstallments{}).
+		Where("installment_id = ? AND status <> ?", installment.InstallmentId, enums.PaidInstallment).
+		Updates(map[string]interface{}{
+			"status":     installment.Status,
+			"late_fee":   installment.LateFee,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+}
+
+func (repo *repositoryImpl) CountOverdueInstallments(count *int64, agreementId string) error {
+	return repo.db.Model(&models.AgreementInstallments{}).
+		Where("agreement_id = ? AND status = ?", agreementId, enums.OverdueInstallment).
+		Count(count).Error
+}
+
+func (repo *repositoryImpl) GetNotificationRecipient(recipient *models.NotificationRecipients, userId uuid.UUID) error {
+	result := repo.db.Raw(`SELECT user_id, email, first_name FROM users WHERE user_id = ?`, userId).
+		Scan(recipient)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
 	}
 
-	return repo.db.Where("agreement_id = ?", agreementId).Delete(&models.Agreements{}).Error
+	return nil
 }
 
-func (repo *repositoryImpl) UpdateAgreementStatus(updatingAgreement *models.UpdatingAgreementStatus, agreementId string) error {
-	if err := repo.db.Model(&models.Agreements{}).First(&models.Agreements{}, "agreement_id = ?", agreementId).Error; err != nil {
-		return err
+func (repo *repositoryImpl) GetAgreementProperty(property *models.AgreementProperties, propertyId uuid.UUID) error {
+	result := repo.db.Raw(`
+		SELECT p.property_id,
+			   p.owner_id,
+			   s.price AS selling_price,
+			   s.is_sold,
+			   r.price_per_month,
+			   r.is_occupied
+		FROM properties p
+		LEFT JOIN selling_properties s ON (p.property_id = s.property_id AND s.deleted_at IS NULL)
+		LEFT JOIN renting_properties r ON (p.property_id = r.property_id AND r.deleted_at IS NULL)
+		WHERE p.property_id = ?
+		`, propertyId).
+		Scan(property)
+	if result.Error != nil {
+		return result.Error
 	}
-	
-	return repo.db.Model(&models.Agreements{}).Where("agreement_id = ?", agreementId).Updates(updatingAgreement).Error
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CountActiveAgreements counts the agreements of a property that are not over yet.
+func (repo *repositoryImpl) CountActiveAgreements(count *int64, propertyId uuid.UUID) error {
+	return repo.db.Model(&models.Agreements{}).
+		Where("property_id = ? AND status IN ?", propertyId, []enums.AgreementStatus{
+			enums.AwaitingDepositAgreement, enums.AwaitingPaymentAgreement, enums.RentingAgreement, enums.OverdueAgreement,
+		}).
+		Count(count).Error
+}
+
+func (repo *repositoryImpl) GetLatestAgreementContract(contract *models.AgreementContracts, agreementId string, language enums.ContractLanguages) error {
+	return repo.db.Model(&models.AgreementContracts{}).
+		Where("agreement_id = ? AND language = ?", agreementId, language).
+		Order("version DESC").
+		First(contract).Error
+}
+
+func (repo *repositoryImpl) CreateAgreementContract(contract *models.AgreementContracts) error {
+	return repo.db.Create(contract).Error
+}
+
+// DeleteAgreementContract removes a contract whose document could not be
+// stored.
+func (repo *repositoryImpl) DeleteAgreementContract(contractId uuid.UUID) error {
+	return repo.db.Where("contract_id = ?", contractId).Delete(&models.AgreementContracts{}).Error
+}
+
+// GetSignedAgreementContract gets the contract the parties of an agreement are
+// signing, which is the latest contract that has been signed by anyone.
+func (repo *repositoryImpl) GetSignedAgreementContract(contract *models.AgreementContracts, agreementId string) error {
+	return repo.db.Model(&models.AgreementContracts{}).
+		Where("agreement_id = ? AND contract_id IN (SELECT contract_id FROM agreement_signatures WHERE agreement_id = ?)", agreementId, agreementId).
+		Order("created_at DESC").
+		First(contract).Error
+}
+
+func (repo *repositoryImpl) GetContractSignatures(signatures *[]models.AgreementSignatures, contractId uuid.UUID) error {
+	return repo.db.Model(&models.AgreementSignatures{}).
+		Where("contract_id = ?", contractId).
+		Order("signed_at ASC").
+		Find(signatures).Error
+}
+
+// CountAgreementSignatures counts the signatures made on the contracts of the
+// given version of the terms of an agreement.
+func (repo *repositoryImpl) CountAgreementSignatures(count *int64, agreementId string, agreementVersion int) error {
+	return repo.db.Model(&models.AgreementSignatures{}).
+		Joins("JOIN agreement_contracts c ON c.contract_id = agreement_signatures.contract_id").
+		Where("agreement_signatures.agreement_id = ? AND c.agreement_version = ?", agreementId, agreementVersion).
+		Count(count).Error
+}
+
+// GetAgreementVersion gets the version of the current terms of an agreement,
+// which is that of its latest accepted amendment. The original terms are
+// version 1.
+func (repo *repositoryImpl) GetAgreementVersion(version *int, agreementId string) error {
+	return repo.db.Raw(`SELECT COALESCE(MAX(version), 1) FROM agreement_amendments WHERE agreement_id = ?`, agreementId).
+		Scan(version).Error
+}
+
+func (repo *repositoryImpl) CreateAgreementSignature(signature *models.AgreementSignatures) error {
+	return repo.db.Create(signature).Error
+}
+
+func (repo *repositoryImpl) GetAgreementAmendments(amendments *[]models.AgreementAmendments, agreementId string) error {
+	return repo.db.Model(&models.AgreementAmendments{}).
+		Where("agreement_id = ?", agreementId).
+		Order("created_at ASC").
+		Find(amendments).Error
+}
+
+func (repo *repositoryImpl) GetAgreementAmendment(amendment *models.AgreementAmendments, agreementId string, amendmentId string) error {
+	return repo.db.Model(&models.AgreementAmendments{}).
+		First(amendment, "agreement_id = ? AND amendment_id = ?", agreementId, amendmentId).Error
+}
+
+func (repo *repositoryImpl) CreateAgreementAmendment(amendment *models.AgreementAmendments) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// a new amendment is a counter-offer to whatever is still open
+		if err := tx.Model(&models.AgreementAmendments{}).
+			Where("agreement_id = ? AND status = ?", amendment.AgreementId, enums.PendingProposal).
+			Update("status", enums.SupersededProposal).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(amendment).Error
+	})
+}
+
+func (repo *repositoryImpl) AcceptAgreementAmendment(amendment *models.AgreementAmendments, respondedByUserId uuid.UUID, changes *models.AgreementScheduleChanges) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := respondToAmendment(tx, amendment, enums.AcceptedProposal, respondedByUserId); err != nil {
+			return err
+		}
+
+		// the original terms are version 1
+		var version int
+		if err := tx.Raw(`SELECT COALESCE(MAX(version), 1) + 1 FROM agreement_amendments WHERE agreement_id = ?`, amendment.AgreementId).
+			Scan(&version).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.AgreementAmendments{}).
+			Where("amendment_id = ?", amendment.AmendmentId).
+			Update("version", version).Error; err != nil {
+			return err
+		}
+		amendment.Version = &version
+
+		// only amend the terms the amendment was made against
+		result := tx.Model(&models.Agreements{}).
+			Where("agreement_id = ? AND payment_duration = ? AND payment_per_month = ?",
+				amendment.AgreementId, amendment.PreviousPaymentDuration, amendment.PreviousPaymentPerMonth).
+			Updates(map[string]interface{}{
+				"payment_duration":  amendment.PaymentDuration,
+				"payment_per_month": amendment.PaymentPerMonth,
+				"total_payment":     changes.TotalPayment,
+				"updated_at":        gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errAgreementTermsChanged
+		}
+
+		// a payment or a meter reading may have settled any of the installments
+		// since they were read, in which case the schedule no longer adds up
+		if len(changes.RemovedNumbers) > 0 {
+			result := tx.Where("agreement_id = ? AND installment_number IN ? AND status = ?", amendment.AgreementId, changes.RemovedNumbers, enums.UnpaidInstallment).
+				Where("NOT EXISTS (SELECT 1 FROM agreement_meter_readings r WHERE r.installment_id = agreement_installments.installment_id)").
+				Delete(&models.AgreementInstallments{})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected != int64(len(changes.RemovedNumbers)) {
+				return errInstallmentSettled
+			}
+		}
+
+		for _, installment := range changes.Saved {
+			if installment.InstallmentId == uuid.Nil {
+				if err := tx.Create(&installment).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			result := tx.Model(&models.AgreementInstallments{}).
+				Where("installment_id = ? AND status = ?", installment.InstallmentId, enums.UnpaidInstallment).
+				Updates(map[string]interface{}{
+					"amount":     installment.Amount,
+					"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected != 1 {
+				return errInstallmentSettled
+			}
+		}
+
+		return nil
+	})
+}
+
+func (repo *repositoryImpl) DeclineAgreementAmendment(amendment *models.AgreementAmendments, respondedByUserId uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		return respondToAmendment(tx, amendment, enums.DeclinedProposal, respondedByUserId)
+	})
+}
+
+func respondToAmendment(tx *gorm.DB, amendment *models.AgreementAmendments, status enums.ProposalStatus, respondedByUserId uuid.UUID) error {
+	now := time.Now()
+	result := tx.Model(&models.AgreementAmendments{}).
+		Where("amendment_id = ? AND status = ?", amendment.AmendmentId, enums.PendingProposal).
+		Updates(map[string]interface{}{
+			"status":               status,
+			"responded_by_user_id": respondedByUserId,
+			"responded_at":         now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errAmendmentNotPending
+	}
+
+	amendment.Status = status
+	amendment.RespondedByUserId = &respondedByUserId
+	amendment.RespondedAt = &now
+	return nil
+}
+
+func (repo *repositoryImpl) GetDepositDeductions(deductions *[]models.AgreementDepositDeductions, agreementId string) error {
+	return repo.db.Model(&models.AgreementDepositDeductions{}).
+		Preload("Evidences", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Where("agreement_id = ?", agreementId).
+		Order("created_at ASC").
+		Find(deductions).Error
+}
+
+func (repo *repositoryImpl) GetDepositDeduction(deduction *models.AgreementDepositDeductions, agreementId string, deductionId string) error {
+	return repo.db.Model(&models.AgreementDepositDeductions{}).
+		Preload("Evidences").
+		First(deduction, "agreement_id = ? AND deduction_id = ?", agreementId, deductionId).Error
+}
+
+func (repo *repositoryImpl) CreateDepositDeduction(deduction *models.AgreementDepositDeductions) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// the refund takes the same lock, so it either sees this deduction or
+		// is seen by it
+		if err := lockAgreement(tx, deduction.AgreementId); err != nil {
+			return err
+		}
+
+		var refunds int64
+		if err := tx.Raw(`SELECT COUNT(*) FROM payments WHERE agreement_id = ? AND payment_type = ? AND deleted_at IS NULL`,
+			deduction.AgreementId, enums.DepositRefundPayment).
+			Scan(&refunds).Error; err != nil {
+			return err
+		}
+
+		if refunds > 0 {
+			return errDepositRefunded
+		}
+
+		// deductions made meanwhile are only seen under the lock, so the held
+		// deposit is checked again against everything that is not withdrawn
+		var remaining float64
+		if err := tx.Raw(`
+			SELECT (SELECT COALESCE(SUM(price), 0)
+					FROM payments
+					WHERE agreement_id = @agreement_id AND issuccess AND payment_type = @deposit AND deleted_at IS NULL)
+				 - (SELECT COALESCE(SUM(amount), 0)
+					FROM agreement_deposit_deductions
+					WHERE agreement_id = @agreement_id AND status <> @withdrawn)
+			`, sql.Named("agreement_id", deduction.AgreementId),
+			sql.Named("deposit", enums.DepositPayment),
+			sql.Named("withdrawn", enums.WithdrawnDeduction)).
+			Scan(&remaining).Error; err != nil {
+			return err
+		}
+
+		if deduction.Amount > remaining {
+			return errDeductionExceedsDeposit
+		}
+
+		// the evidences are created along with the deduction
+		return tx.Create(deduction).Error
+	})
+}
+
+// RespondToDepositDeduction saves the answer of the dweller to a deduction that
+// is still pending.
+func (repo *repositoryImpl) RespondToDepositDeduction(deduction *models.AgreementDepositDeductions) error {
+	result := repo.db.Model(&models.AgreementDepositDeductions{}).
+		Where("deduction_id = ? AND status = ?", deduction.DeductionId, enums.PendingDeduction).
+		Updates(map[string]interface{}{
+			"status":         deduction.Status,
+			"dispute_reason": gorm.Expr("NULLIF(?, '')", deduction.DisputeReason),
+			"responded_at":   deduction.RespondedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errDeductionNotPending
+	}
+
+	return nil
+}
+
+func (repo *repositoryImpl) DeleteDepositDeduction(deduction *models.AgreementDepositDeductions) error {
+	result := repo.db.
+		Where("deduction_id = ? AND status IN ?", deduction.DeductionId, []enums.DeductionStatus{enums.PendingDeduction, enums.DisputedDeduction}).
+		Delete(&models.AgreementDepositDeductions{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errDeductionNotPending
+	}
+
+	return nil
+}
+
+// ResolveDepositDeduction moves a deduction on from the given status, as long
+// as nobody else has moved it in the meantime.
+func (repo *repositoryImpl) ResolveDepositDeduction(deduction *models.AgreementDepositDeductions, from enums.DeductionStatus) error {
+	result := repo.db.Model(&models.AgreementDepositDeductions{}).
+		Where("deduction_id = ? AND status = ?", deduction.DeductionId, from).
+		Updates(map[string]interface{}{
+			"status":      deduction.Status,
+			"resolved_at": deduction.ResolvedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errDeductionNotDisputed
+	}
+
+	return nil
+}
+
+// WithdrawStaleDisputes withdraws the deductions that were disputed before the
+// given time and never escalated.
+func (repo *repositoryImpl) WithdrawStaleDisputes(withdrawn *int64, disputedBefore time.Time) error {
+	result := repo.db.Model(&models.AgreementDepositDeductions{}).
+		Where("status = ? AND responded_at < ?", enums.DisputedDeduction, disputedBefore).
+		Updates(map[string]interface{}{
+			"status":      enums.WithdrawnDeduction,
+			"resolved_at": time.Now(),
+		})
+
+	*withdrawn = result.RowsAffected
+	return result.Error
+}
+
+func (repo *repositoryImpl) GetDepositRefund(refund *models.Payments, agreementId string) error {
+	result := repo.db.Raw(`SELECT *, issuccess AS is_success FROM payments WHERE agreement_id = ? AND payment_type = ? AND deleted_at IS NULL`,
+		agreementId, enums.DepositRefundPayment).
+		Scan(refund)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CreateDepositRefund refunds what is left of the deposit under a lock on the
+// agreement, so no deduction can be claimed or settled between checking the
+// deductions and working out the refund.
+func (repo *repositoryImpl) CreateDepositRefund(refund *models.Payments) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockAgreement(tx, *refund.AgreementId); err != nil {
+			return err
+		}
+
+		var unresolved int64
+		if err := tx.Model(&models.AgreementDepositDeductions{}).
+			Where("agreement_id = ? AND status IN ?", refund.AgreementId, []enums.DeductionStatus{enums.PendingDeduction, enums.DisputedDeduction, enums.EscalatedDeduction}).
+			Count(&unresolved).Error; err != nil {
+			return err
+		}
+
+		if unresolved > 0 {
+			return errDeductionsUnresolved
+		}
+
+		priceQuery := `
+			SELECT GREATEST(
+				(SELECT COALESCE(SUM(price), 0) FROM payments WHERE agreement_id = @agreement_id AND issuccess AND payment_type = @deposit AND deleted_at IS NULL) -
+				(SELECT COALESCE(SUM(amount), 0) FROM agreement_deposit_deductions WHERE agreement_id = @agreement_id AND status = @accepted),
+				0)
+			`
+		if err := tx.Raw(priceQuery, map[string]interface{}{
+			"agreement_id": refund.AgreementId,
+			"deposit":      enums.DepositPayment,
+			"accepted":     enums.AcceptedDeduction,
+		}).Scan(&refund.Price).Error; err != nil {
+			return err
+		}
+
+		paidAt := time.Now()
+		refund.PaidAt = &paidAt
+
+		refundQuery := `INSERT INTO payments (payment_id, user_id, price, IsSuccess, name, agreement_id, payment_type, recipient_user_id, paid_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		return tx.Exec(refundQuery, refund.PaymentId, refund.UserId, refund.Price, refund.IsSuccess, refund.Name, refund.AgreementId, refund.PaymentType, refund.RecipientUserId, refund.PaidAt).Error
+	})
+}
+
+func (repo *repositoryImpl) GetAgreementInspections(inspections *[]models.AgreementInspections, agreementId string) error {
+	return preloadInspectionItems(repo.db.Model(&models.AgreementInspections{})).
+		Where("agreement_id = ?", agreementId).
+		Order("created_at ASC").
+		Find(inspections).Error
+}
+
+func (repo *repositoryImpl) GetAgreementInspection(inspection *models.AgreementInspections, agreementId string, inspectionId string) error {
+	return preloadInspectionItems(repo.db.Model(&models.AgreementInspections{})).
+		First(inspection, "agreement_id = ? AND inspection_id = ?", agreementId, inspectionId).Error
+}
+
+// CreateAgreementInspection writes a report. A current report of the same type
+// that one party has signed off, but not both, is superseded by it.
+func (repo *repositoryImpl) CreateAgreementInspection(inspection *models.AgreementInspections) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var current models.AgreementInspections
+		err := tx.Model(&models.AgreementInspections{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("agreement_id = ? AND inspection_type = ? AND superseded_at IS NULL", inspection.AgreementId, inspection.InspectionType).
+			Take(&current).Error
+		if err == nil {
+			switch {
+			case current.OwnerSignedAt == nil && current.DwellerSignedAt == nil:
+				return errInspectionUnsigned
+			case current.OwnerSignedAt != nil && current.DwellerSignedAt != nil:
+				return errInspectionFinalised
+			}
+
+			if err := tx.Model(&models.AgreementInspections{}).
+				Where("inspection_id = ?", current.InspectionId).
+				Update("superseded_at", time.Now()).Error; err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// the items are created along with the inspection
+		return tx.Create(inspection).Error
+	})
+}
+
+// UpdateInspectionItems replaces the items of an inspection, along with their
+// photos, as long as nobody has signed it off.
+func (repo *repositoryImpl) UpdateInspectionItems(inspection *models.AgreementInspections) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUnsignedInspection(tx, inspection.InspectionId); err != nil {
+			return err
+		}
+
+		if err := tx.Where("inspection_id = ?", inspection.InspectionId).
+			Delete(&models.AgreementInspectionItems{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&inspection.Items).Error
+	})
+}
+
+// CreateInspectionPhotos adds photos to an item of an inspection nobody has
+// signed off yet, numbering them after the photos the item already has. The
+// lock on the inspection keeps concurrent uploads from taking the same numbers
+// or going over maxPhotos together.
+func (repo *repositoryImpl) CreateInspectionPhotos(inspectionId uuid.UUID, photos []models.AgreementInspectionPhotos, maxPhotos int) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUnsignedInspection(tx, inspectionId); err != nil {
+			return err
+		}
+
+		var existing struct {
+			Count     int
+			LastOrder int
+		}
+		if err := tx.Model(&models.AgreementInspectionPhotos{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(photo_order), 0) AS last_order").
+			Where("item_id = ?", photos[0].ItemId).
+			Scan(&existing).Error; err != nil {
+			return err
+		}
+
+		if existing.Count+len(photos) > maxPhotos {
+			return errTooManyPhotos
+		}
+
+		for i := range photos {
+			photos[i].PhotoOrder = existing.LastOrder + i + 1
+		}
+
+		return tx.Create(&photos).Error
+	})
+}
+
+func (repo *repositoryImpl) SignAgreementInspection(inspection *models.AgreementInspections, role enums.ActorRoles, signedAt time.Time) error {
+	column := "owner_signed_at"
+	if role == enums.DwellerActor {
+		column = "dweller_signed_at"
+	}
+
+	result := repo.db.Model(&models.AgreementInspections{}).
+		Where(fmt.Sprintf("inspection_id = ? AND superseded_at IS NULL AND %v IS NULL", column), inspection.InspectionId).
+		Update(column, signedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errInspectionAlreadySigned
+	}
+
+	return nil
+}
+
+func preloadInspectionItems(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("item_order ASC") }).
+		Preload("Items.Photos", func(db *gorm.DB) *gorm.DB { return db.Order("photo_order ASC") })
+}
+
+// lockUnsignedInspection touches a current inspection that nobody has signed
+// off yet, which keeps it from being signed until the transaction is over.
+func lockUnsignedInspection(tx *gorm.DB, inspectionId uuid.UUID) error {
+	result := tx.Model(&models.AgreementInspections{}).
+		Where("inspection_id = ? AND superseded_at IS NULL AND owner_signed_at IS NULL AND dweller_signed_at IS NULL", inspectionId).
+		Update("updated_at", gorm.Expr("CURRENT_TIMESTAMP"))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errInspectionSigned
+	}
+
+	return nil
+}
+
+func (repo *repositoryImpl) GetUtilityRates(rates *models.AgreementUtilityRates, agreementId string) error {
+	return repo.db.Model(&models.AgreementUtilityRates{}).First(rates, "agreement_id = ?", agreementId).Error
+}
+
+func (repo *repositoryImpl) SaveUtilityRates(rates *models.AgreementUtilityRates) error {
+	return repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "agreement_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"water_rate", "electricity_rate", "updated_at"}),
+	}).Create(rates).Error
+}
+
+func (repo *repositoryImpl) GetMeterReadings(readings *[]models.AgreementMeterReadings, agreementId string) error {
+	return repo.db.Model(&models.AgreementMeterReadings{}).
+		Where("agreement_id = ?", agreementId).
+		Order("installment_number ASC, utility_type ASC").
+		Find(readings).Error
+}
+
+func (repo *repositoryImpl) GetMeterReading(reading *models.AgreementMeterReadings, agreementId string, readingId string) error {
+	return repo.db.Model(&models.AgreementMeterReadings{}).
+		First(reading, "agreement_id = ? AND reading_id = ?", agreementId, readingId).Error
+}
+
+func (repo *repositoryImpl) GetLatestMeterReading(reading *models.AgreementMeterReadings, agreementId string, utilityType enums.UtilityTypes) error {
+	return repo.db.Model(&models.AgreementMeterReadings{}).
+		Where("agreement_id = ? AND utility_type = ?", agreementId, utilityType).
+		Order("installment_number DESC").
+		First(reading).Error
+}
+
+// CreateMeterReading records a reading and adds its charge to the installment
+// it is billed with, as long as that installment has not been paid yet.
+func (repo *repositoryImpl) CreateMeterReading(reading *models.AgreementMeterReadings) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reading).Error; err != nil {
+			return err
+		}
+
+		return addUtilityAmount(tx, reading.InstallmentId, reading.Amount)
+	})
+}
+
+func (repo *repositoryImpl) DeleteMeterReading(reading *models.AgreementMeterReadings) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := addUtilityAmount(tx, reading.InstallmentId, -reading.Amount); err != nil {
+			return err
+		}
+
+		return tx.Where("reading_id = ?", reading.ReadingId).Delete(&models.AgreementMeterReadings{}).Error
+	})
+}
+
+func addUtilityAmount(tx *gorm.DB, installmentId uuid.UUID, amount float64) error {
+	result := tx.Model(&models.AgreementInstallments{}).
+		Where("installment_id = ? AND status <> ?", installmentId, enums.PaidInstallment).
+		Updates(map[string]interface{}{
+			"utility_amount": gorm.Expr("utility_amount + ?", amount),
+			"updated_at":     gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errInstallmentSettled
+	}
+
+	return nil
+}
+
+// lockAgreement locks the row of an agreement until the end of the transaction.
+func lockAgreement(tx *gorm.DB, agreementId uuid.UUID) error {
+	return tx.Exec(`SELECT 1 FROM _agreements WHERE agreement_id = ? FOR UPDATE`, agreementId).Error
 }
@@ -1,11 +1,33 @@
 package agreements
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"math"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/appointments"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/emails"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/brain-flowing-company/pprp-backend/storage"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -13,21 +35,131 @@ import (
 type Service interface {
 	GetAllAgreements(*[]models.AgreementLists) *apperror.AppError
 	GetAgreementById(*models.AgreementDetails, string) *apperror.AppError
-	GetMyAgreements(*models.MyAgreementResponses, *models.MyAgreementRequests) *apperror.AppError
+	GetMyAgreements(*models.MyAgreementResponses, *models.MyAgreementRequests, *utils.PaginatedQuery, *utils.SortedQuery, *utils.FilteredQuery) *apperror.AppError
 	CreateAgreement(*models.CreatingAgreements) *apperror.AppError
-	DeleteAgreement(string) *apperror.AppError
-	UpdateAgreementStatus(*models.UpdatingAgreementStatus, string) *apperror.AppError
+	DeleteAgreement(string, *models.Sessions) *apperror.AppError
+	UpdateAgreementStatus(*models.UpdatingAgreementStatus, string, *models.Sessions) *apperror.AppError
+	GetAgreementInstallments(*models.AgreementInstallmentSchedules, string, *models.Sessions) *apperror.AppError
+	ResumeOverdueAgreement(string) *apperror.AppError
+	CheckPaymentRefundable(*models.Payments) *apperror.AppError
+	MarkOverdueAgreements()
+	WithdrawStaleDisputes()
+	GenerateAgreementContract(*models.AgreementContracts, string, enums.ContractLanguages, *models.Sessions) *apperror.AppError
+	DownloadAgreementPhoto(*bytes.Buffer, string, string, *models.Sessions) *apperror.AppError
+	GetAgreementRole(*models.Agreements, string, *models.Sessions) (enums.ActorRoles, *apperror.AppError)
+	UploadAgreementPhotos(string, string, []*multipart.FileHeader, *apperror.AppErrorType) ([]string, *apperror.AppError)
+	DownloadAgreementContract(*bytes.Buffer, *models.AgreementContracts, string, enums.ContractLanguages, *models.Sessions) *apperror.AppError
+	SignAgreement(*models.AgreementSignatures, string, *models.SigningAgreements, *multipart.FileHeader, *models.Sessions) *apperror.AppError
+	VerifyAgreementSignatures(*models.AgreementSignatureVerifications, string, *models.Sessions) *apperror.AppError
+	GetAgreementAmendments(*[]models.AgreementAmendments, string, *models.Sessions) *apperror.AppError
+	CreateAgreementAmendment(*models.AgreementAmendments, string, *models.CreatingAgreementAmendments, *models.Sessions) *apperror.AppError
+	AcceptAgreementAmendment(*models.AgreementAmendments, string, string, *models.Sessions) *apperror.AppError
+	DeclineAgreementAmendment(string, string, *models.Sessions) *apperror.AppError
+	GetAgreementDeposit(*models.AgreementDeposits, string, *models.Sessions) *apperror.AppError
+	CreateDepositDeduction(*models.AgreementDepositDeductions, string, *models.CreatingDepositDeductions, []*multipart.FileHeader, *models.Sessions) *apperror.AppError
+	AcceptDepositDeduction(string, string, *models.Sessions) *apperror.AppError
+	DisputeDepositDeduction(string, string, *models.DisputingDepositDeductions, *models.Sessions) *apperror.AppError
+	DeleteDepositDeduction(string, string, *models.Sessions) *apperror.AppError
+	ResolveDepositDeduction(string, string, *models.ResolvingDepositDeductions, *models.Sessions) *apperror.AppError
+	RefundDeposit(*models.Payments, string, *models.Sessions) *apperror.AppError
+	GetAgreementInspections(*[]models.AgreementInspections, string, *models.Sessions) *apperror.AppError
+	GetAgreementInspection(*models.AgreementInspections, string, string, *models.Sessions) *apperror.AppError
+	CreateAgreementInspection(*models.AgreementInspections, string, *models.CreatingInspections, *models.Sessions) *apperror.AppError
+	UpdateAgreementInspection(*models.AgreementInspections, string, string, *models.UpdatingInspections, *models.Sessions) *apperror.AppError
+	UploadInspectionPhotos(*models.AgreementInspectionItems, string, string, string, []*multipart.FileHeader, *models.Sessions) *apperror.AppError
+	SignAgreementInspection(*models.AgreementInspections, string, string, *models.Sessions) *apperror.AppError
+	CompareAgreementInspections(*models.InspectionComparisons, string, *models.Sessions) *apperror.AppError
+	GetUtilityRates(*models.AgreementUtilityRates, string, *models.Sessions) *apperror.AppError
+	UpdateUtilityRates(*models.AgreementUtilityRates, string, *models.UpdatingUtilityRates, *models.Sessions) *apperror.AppError
+	GetMeterReadings(*[]models.AgreementMeterReadings, string, *models.Sessions) *apperror.AppError
+	CreateMeterReading(*models.AgreementMeterReadings, string, *models.CreatingMeterReadings, *multipart.FileHeader, *models.Sessions) *apperror.AppError
+	DeleteMeterReading(string, string, *models.Sessions) *apperror.AppError
+}
+
+// agreementTransitions lists, for every status, the statuses an agreement
+// may move to and who is allowed to make each move.
+var agreementTransitions = map[enums.AgreementStatus]map[enums.AgreementStatus][]enums.ActorRoles{
+	enums.AwaitingDepositAgreement: {
+		enums.AwaitingPaymentAgreement: {enums.OwnerActor, enums.SystemActor},
+		enums.CancelledAgreement:       {enums.OwnerActor, enums.DwellerActor},
+	},
+	enums.AwaitingPaymentAgreement: {
+		enums.RentingAgreement:   {enums.OwnerActor, enums.SystemActor},
+		enums.ArchivedAgreement:  {enums.OwnerActor, enums.SystemActor},
+		enums.CancelledAgreement: {enums.OwnerActor, enums.DwellerActor},
+		enums.OverdueAgreement:   {enums.SystemActor},
+	},
+	enums.RentingAgreement: {
+		enums.OverdueAgreement:  {enums.SystemActor},
+		enums.ArchivedAgreement: {enums.OwnerActor, enums.SystemActor},
+	},
+	enums.OverdueAgreement: {
+		enums.RentingAgreement:   {enums.OwnerActor, enums.SystemActor},
+		enums.CancelledAgreement: {enums.OwnerActor},
+	},
+	enums.CancelledAgreement: {
+		enums.ArchivedAgreement: {enums.SystemActor},
+	},
+}
+
+// deductionTransitions lists how a disputed deduction can be resolved and who
+// is allowed to resolve it each way. Disputes nobody escalates are withdrawn by
+// the system once they time out.
+var deductionTransitions = map[enums.DeductionStatus]map[enums.DeductionStatus][]enums.ActorRoles{
+	enums.DisputedDeduction: {
+		enums.AcceptedDeduction:  {enums.DwellerActor},
+		enums.WithdrawnDeduction: {enums.OwnerActor, enums.SystemActor},
+		enums.EscalatedDeduction: {enums.OwnerActor, enums.DwellerActor},
+	},
+	enums.EscalatedDeduction: {
+		enums.AcceptedDeduction:  {enums.DwellerActor, enums.AdminActor},
+		enums.WithdrawnDeduction: {enums.OwnerActor, enums.AdminActor},
+	},
+}
+
+const emailDateLayout = "Monday 2 January 2006"
+
+const maxSignatureImageSize = 1 << 20
+
+// agreementPhotoFolders are where the photos attached to an agreement are kept.
+// Contracts and signatures are kept next to them but have endpoints of their
+// own.
+var agreementPhotoFolders = []string{"deductions", "inspections", "meters", "maintenance"}
+
+const maxDeductionEvidences = 10
+
+const maxInspectionPhotos = 10
+
+// inspectionStatuses lists the statuses in which each kind of inspection report
+// can be written: the move-in report around the handover of the unit and the
+// move-out report until the agreement is archived.
+var inspectionStatuses = map[enums.InspectionTypes][]enums.AgreementStatus{
+	enums.MoveInInspection:  {enums.AwaitingPaymentAgreement, enums.RentingAgreement, enums.OverdueAgreement},
+	enums.MoveOutInspection: {enums.RentingAgreement, enums.OverdueAgreement, enums.ArchivedAgreement},
+}
+
+var thaiMonths = [...]string{
+	"มกราคม", "กุมภาพันธ์", "มีนาคม", "เมษายน", "พฤษภาคม", "มิถุนายน",
+	"กรกฎาคม", "สิงหาคม", "กันยายน", "ตุลาคม", "พฤศจิกายน", "ธันวาคม",
 }
 
 type serviceImpl struct {
-	repo   Repository
-	logger *zap.Logger
+	repo               Repository
+	logger             *zap.Logger
+	cfg                *config.Config
+	emailService       emails.Service
+	appointmentService appointments.Service
+	storage            storage.Storage
 }
 
-func NewService(logger *zap.Logger, repo Repository) Service {
+func NewService(logger *zap.Logger, cfg *config.Config, repo Repository, emailService emails.Service, appointmentService appointments.Service, storage storage.Storage) Service {
 	return &serviceImpl{
 		repo,
 		logger,
+		cfg,
+		emailService,
+		appointmentService,
+		storage,
 	}
 }
 func (s *serviceImpl) GetAllAgreements(agreements *[]models.AgreementLists) *apperror.AppError {
@@ -60,15 +192,33 @@ func (s *serviceImpl) GetAgreementById(agreement *models.AgreementDetails, agree
 			Describe("Could not get agreement by id")
 	}
 
+	agreement.StatusHistories = []models.AgreementStatusHistories{}
+	err = s.repo.GetAgreementStatusHistories(&agreement.StatusHistories, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement status histories", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement status histories")
+	}
+
 	return nil
 }
 
-func (s *serviceImpl) GetMyAgreements(agreements *models.MyAgreementResponses, agreementRequest *models.MyAgreementRequests) *apperror.AppError {
+func (s *serviceImpl) GetMyAgreements(agreements *models.MyAgreementResponses, agreementRequest *models.MyAgreementRequests, paginated *utils.PaginatedQuery, sorted *utils.SortedQuery, filtered *utils.FilteredQuery) *apperror.AppError {
 	if agreementRequest.Order != "ASC" && agreementRequest.Order != "DESC" {
 		agreementRequest.Order = "ASC"
 	}
 
-	err := s.repo.GetAgreementByUserId(agreements, agreementRequest)
+	if agreementRequest.Role != "" && agreementRequest.Role != enums.OwnerActor && agreementRequest.Role != enums.DwellerActor {
+		return apperror.
+			New(apperror.BadRequest).
+			Describe("Role can only be OWNER or DWELLER")
+	}
+
+	agreements.OwnerAgreements = []models.AgreementLists{}
+	agreements.DwellerAgreements = []models.AgreementLists{}
+
+	err := s.repo.GetAgreementByUserId(agreements, agreementRequest, paginated, sorted, filtered)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return apperror.
 			New(apperror.AppointmentNotFound).
@@ -84,8 +234,23 @@ func (s *serviceImpl) GetMyAgreements(agreements *models.MyAgreementResponses, a
 }
 
 func (s *serviceImpl) CreateAgreement(creatingAgreement *models.CreatingAgreements) *apperror.AppError {
-	err := s.repo.CreateAgreement(creatingAgreement)
-	if err != nil {
+	if apperr := s.prepareAgreement(creatingAgreement); apperr != nil {
+		return apperr
+	}
+
+	installments := []models.AgreementInstallments{}
+	if creatingAgreement.AgreementType == enums.AgreementForRent {
+		installments = installmentSchedule(creatingAgreement)
+	}
+
+	err := s.repo.CreateAgreement(creatingAgreement, installments)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return s.duplicatedAgreement(creatingAgreement.PropertyId)
+	} else if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return apperror.
+			New(apperror.UserNotFound).
+			Describe("Could not find the specified dweller")
+	} else if err != nil {
 		s.logger.Error("Could not create agreement", zap.Error(err))
 		return apperror.
 			New(apperror.InternalServerError).
@@ -95,15 +260,55 @@ func (s *serviceImpl) CreateAgreement(creatingAgreement *models.CreatingAgreemen
 	return nil
 }
 
-func (s *serviceImpl) DeleteAgreement(agreementId string) *apperror.AppError {
-	if !utils.IsValidUUID(agreementId) {
+// duplicatedAgreement tells apart the two ways a new agreement can clash with
+// another one of the same property: an agreement made since that is still
+// ongoing, or one made on the same date.
+func (s *serviceImpl) duplicatedAgreement(propertyId uuid.UUID) *apperror.AppError {
+	var active int64
+	if err := s.repo.CountActiveAgreements(&active, propertyId); err != nil {
+		s.logger.Error("Could not count active agreements", zap.String("id", propertyId.String()), zap.Error(err))
 		return apperror.
-			New(apperror.InvalidAgreementId).
-			Describe("Invalid agreement id")
+			New(apperror.InternalServerError).
+			Describe("Could not create agreement")
+	} else if active > 0 {
+		return apperror.
+			New(apperror.PropertyNotAvailable).
+			Describe("The property already has an ongoing agreement")
+	}
+
+	return apperror.
+		New(apperror.DuplicateAgreement).
+		Describe("The property already has an agreement on this date")
+}
+
+// DeleteAgreement moves an agreement to the trash for its owner or an admin. A
+// renting or overdue agreement occupies the property, so it has to be archived
+// or cancelled first to free the property.
+func (s *serviceImpl) DeleteAgreement(agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	role, apperr := s.GetAgreementRole(&agreement, agreementId, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	if role != enums.OwnerActor && role != enums.AdminActor {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner can delete this agreement")
+	}
+
+	if agreement.Status == enums.RentingAgreement || agreement.Status == enums.OverdueAgreement {
+		return apperror.
+			New(apperror.AgreementNotDeletable).
+			Describe("A renting agreement has to be archived or cancelled before it can be deleted")
 	}
 
 	err := s.repo.DeleteAgreement(agreementId)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
+	if errors.Is(err, errAgreementOccupying) {
+		return apperror.
+			New(apperror.AgreementNotDeletable).
+			Describe("A renting agreement has to be archived or cancelled before it can be deleted")
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
 		return apperror.
 			New(apperror.AgreementNotFound).
 			Describe("Could not find the specified agreement")
@@ -117,24 +322,2858 @@ func (s *serviceImpl) DeleteAgreement(agreementId string) *apperror.AppError {
 	return nil
 }
 
-func (s *serviceImpl) UpdateAgreementStatus(updatingAgreement *models.UpdatingAgreementStatus, agreementId string) *apperror.AppError {
-	if !utils.IsValidUUID(agreementId) {
+func (s *serviceImpl) UpdateAgreementStatus(updatingAgreement *models.UpdatingAgreementStatus, agreementId string, session *models.Sessions) *apperror.AppError {
+	_, ok := enums.AgreementStatusMap[string(updatingAgreement.Status)]
+	if !ok {
 		return apperror.
-			New(apperror.InvalidAgreementId).
-			Describe("Invalid agreement id")
+			New(apperror.InvalidAgreementStatus).
+			Describe("Invalid agreement status")
+	}
+
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := agreementRole(&agreement, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	return s.transitionAgreement(&agreement, updatingAgreement, role, &session.UserId)
+}
+
+func (s *serviceImpl) GetAgreementInstallments(schedule *models.AgreementInstallmentSchedules, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	schedule.AgreementId = agreement.AgreementId
+	schedule.Installments = []models.AgreementInstallments{}
+	err := s.repo.GetAgreementInstallments(&schedule.Installments, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement installments", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement installments")
+	}
+
+	for _, installment := range schedule.Installments {
+		if installment.Status == enums.PaidInstallment {
+			schedule.PaidAmount += installment.Amount + installment.LateFee + installment.UtilityAmount
+		} else {
+			schedule.OutstandingAmount += installment.Amount + installment.LateFee + installment.UtilityAmount
+		}
+	}
+
+	return nil
+}
+
+// ResumeOverdueAgreement moves an overdue agreement back to RENTING once none
+// of its installments is overdue anymore. Agreements that are not overdue, or
+// still have arrears, are left as they are.
+func (s *serviceImpl) ResumeOverdueAgreement(agreementId string) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if agreement.Status != enums.OverdueAgreement {
+		return nil
+	}
+
+	overdue, apperr := s.countOverdueInstallments(agreementId)
+	if apperr != nil {
+		return apperr
+	} else if overdue > 0 {
+		return nil
+	}
+
+	updatingAgreement := models.UpdatingAgreementStatus{
+		Status: enums.RentingAgreement,
+	}
+
+	return s.transitionAgreement(&agreement, &updatingAgreement, enums.SystemActor, nil)
+}
+
+// CheckPaymentRefundable makes sure a payment can be given back without undoing
+// what its agreement has moved on with. The money an agreement needed to get
+// past AWAITING_DEPOSIT or AWAITING_PAYMENT has to stay paid, and a deposit
+// that has deductions or has been refunded is settled through the deposit.
+func (s *serviceImpl) CheckPaymentRefundable(payment *models.Payments) *apperror.AppError {
+	if payment.AgreementId == nil {
+		return nil
 	}
 
-	err := s.repo.UpdateAgreementStatus(updatingAgreement, agreementId)
+	agreementId := payment.AgreementId.String()
+
+	var agreement models.Agreements
+	err := s.repo.GetAgreement(&agreement, agreementId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// a deleted agreement has nothing left to undo
+		return nil
+	} else if err != nil {
+		s.logger.Error("Could not get agreement", zap.String("id", agreementId), zap.Error(err))
 		return apperror.
-			New(apperror.AgreementNotFound).
-			Describe("Could not find the specified agreement")
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement")
+	}
+
+	if payment.PaymentType == enums.DepositPayment && agreement.AgreementType == enums.AgreementForRent {
+		var deposit models.AgreementDeposits
+		if apperr := s.getAgreementDeposit(&deposit, &agreement); apperr != nil {
+			return apperr
+		}
+
+		if deposit.Refund != nil || deposit.DeductedAmount+deposit.PendingAmount > 0 {
+			return apperror.
+				New(apperror.PaymentNotRefundable).
+				Describe("The deposit has deductions or has been refunded, so it is settled through the deposit")
+		}
+	}
+
+	amount, description, paymentTypes := requiredPayment(&agreement, payment.PaymentType)
+	if len(paymentTypes) == 0 {
+		return nil
+	}
+
+	var paid float64
+	err = s.repo.GetPaidAmount(&paid, agreementId, paymentTypes...)
+	if err != nil {
+		s.logger.Error("Could not get paid amount", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get paid amount")
+	}
+
+	if paid-payment.Price < amount {
+		return apperror.
+			New(apperror.PaymentNotRefundable).
+			Describe(fmt.Sprintf("The agreement is %v, so the %v has to stay paid", agreement.Status, description))
+	}
+
+	return nil
+}
+
+// MarkOverdueAgreements charges late fees on the installments that are still
+// unpaid after the grace period and moves their agreements to OVERDUE, letting
+// both parties know the first time it happens.
+func (s *serviceImpl) MarkOverdueAgreements() {
+	year, month, day := time.Now().In(utils.LocalTimezone).Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	var late []models.AgreementInstallments
+	err := s.repo.GetLateInstallments(&late, today.AddDate(0, 0, -s.cfg.OverdueGraceDays))
+	if err != nil {
+		s.logger.Error("Could not get late installments", zap.Error(err))
+		return
+	}
+
+	overdue := map[uuid.UUID][]models.AgreementInstallments{}
+	for _, installment := range late {
+		year, month, day := installment.DueDate.Date()
+		dueDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		chargedDays := int(today.Sub(dueDate).Hours()/24) - s.cfg.OverdueGraceDays
+
+		installment.Status = enums.OverdueInstallment
+		installment.LateFee = s.lateFee(installment.Amount, chargedDays)
+		if err := s.repo.UpdateInstallmentLateFee(&installment); err != nil {
+			s.logger.Error("Could not update installment late fee", zap.String("id", installment.InstallmentId.String()), zap.Error(err))
+			continue
+		}
+
+		overdue[installment.AgreementId] = append(overdue[installment.AgreementId], installment)
+	}
+
+	for agreementId, installments := range overdue {
+		s.markAgreementOverdue(agreementId.String(), installments)
+	}
+}
+
+// GenerateAgreementContract renders the contract of an agreement as a new
+// version in the given language and stores it privately. Earlier versions are
+// kept so that what was signed can always be retrieved.
+func (s *serviceImpl) GenerateAgreementContract(contract *models.AgreementContracts, agreementId string, language enums.ContractLanguages, session *models.Sessions) *apperror.AppError {
+	if _, ok := enums.ContractLanguagesMap[string(language)]; !ok {
+		return apperror.
+			New(apperror.InvalidContractLanguage).
+			Describe("Contract language must be either EN or TH")
+	}
+
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil {
+		return apperr
+	}
+
+	// every accepted amendment starts a new signing round with its own contract
+	agreementVersion, apperr := s.getAgreementVersion(agreementId)
+	if apperr != nil {
+		return apperr
+	}
+
+	var signatures int64
+	err := s.repo.CountAgreementSignatures(&signatures, agreementId, agreementVersion)
+	if err != nil {
+		s.logger.Error("Could not count agreement signatures", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not generate contract")
+	} else if signatures > 0 {
+		return apperror.
+			New(apperror.ContractAlreadySigned).
+			Describe("The contract of the current terms has already been signed and can no longer be regenerated")
+	}
+
+	var details models.AgreementDetails
+	err = s.repo.GetAgreementById(&details, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement by id", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not generate contract")
+	}
+
+	var latest models.AgreementContracts
+	err = s.repo.GetLatestAgreementContract(&latest, agreementId, language)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Could not get latest agreement contract", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not generate contract")
+	}
+
+	version := latest.Version + 1
+	document, apperr := s.renderAgreementContract(&details, language, version)
+	if apperr != nil {
+		return apperr
+	}
+
+	// the version is claimed before uploading and every contract has its own
+	// key, so a concurrent generation can never overwrite a stored contract
+	hash := sha256.Sum256(document)
+	contract.ContractId = uuid.New()
+	contract.AgreementId = agreement.AgreementId
+	contract.Language = language
+	contract.Version = version
+	contract.AgreementVersion = agreementVersion
+	contract.FileKey = fmt.Sprintf("agreements/%v/contracts/%v.pdf", agreementId, contract.ContractId)
+	contract.DocumentHash = hex.EncodeToString(hash[:])
+	contract.CreatedByUserId = &session.UserId
+
+	err = s.repo.CreateAgreementContract(contract)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.DuplicateContract).
+			Describe("Another version of this contract was just generated. Please try again")
 	} else if err != nil {
-		s.logger.Error("Could not update agreement status", zap.Error(err))
+		s.logger.Error("Could not create agreement contract", zap.String("id", agreementId), zap.Error(err))
 		return apperror.
 			New(apperror.InternalServerError).
-			Describe("Could not update agreement status")
+			Describe("Could not save contract")
+	}
+
+	_, err = s.storage.Upload(contract.FileKey, bytes.NewReader(document), types.ObjectCannedACLPrivate)
+	if err != nil {
+		s.logger.Error("Could not upload agreement contract", zap.String("id", agreementId), zap.Error(err))
+		if err := s.repo.DeleteAgreementContract(contract.ContractId); err != nil {
+			s.logger.Error("Could not delete unuploaded agreement contract", zap.String("id", contract.ContractId.String()), zap.Error(err))
+		}
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not upload contract")
+	}
+
+	return nil
+}
+
+// DownloadAgreementContract reads back the latest version of the contract of
+// an agreement in the given language.
+func (s *serviceImpl) DownloadAgreementContract(document *bytes.Buffer, contract *models.AgreementContracts, agreementId string, language enums.ContractLanguages, session *models.Sessions) *apperror.AppError {
+	if _, ok := enums.ContractLanguagesMap[string(language)]; !ok {
+		return apperror.
+			New(apperror.InvalidContractLanguage).
+			Describe("Contract language must be either EN or TH")
+	}
+
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	err := s.repo.GetLatestAgreementContract(contract, agreementId, language)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.ContractNotFound).
+			Describe("No contract has been generated for this agreement yet")
+	} else if err != nil {
+		s.logger.Error("Could not get latest agreement contract", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get contract")
+	}
+
+	file, err := s.storage.Download(contract.FileKey)
+	if err != nil {
+		s.logger.Error("Could not download agreement contract", zap.String("key", contract.FileKey), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not download contract")
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(document, file); err != nil {
+		s.logger.Error("Could not read agreement contract", zap.String("key", contract.FileKey), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not download contract")
+	}
+
+	return nil
+}
+
+// GetAgreementRole loads an agreement along with the role the session plays in
+// it, for the features built on top of agreements. Admins who are not a party
+// to the agreement get the ADMIN role.
+func (s *serviceImpl) GetAgreementRole(agreement *models.Agreements, agreementId string, session *models.Sessions) (enums.ActorRoles, *apperror.AppError) {
+	if apperr := s.getAgreement(agreement, agreementId); apperr != nil {
+		return "", apperr
+	}
+
+	role, apperr := agreementRole(agreement, session)
+	if apperr != nil && !session.IsAdmin {
+		return "", apperr
+	} else if apperr != nil {
+		return enums.AdminActor, nil
+	}
+
+	return role, nil
+}
+
+// UploadAgreementPhotos uploads photos attached to an agreement by the features
+// built on top of it, so they are kept private and downloaded through
+// DownloadAgreementPhoto like the photos of the agreement itself.
+func (s *serviceImpl) UploadAgreementPhotos(agreementId string, name string, photos []*multipart.FileHeader, invalid *apperror.AppErrorType) ([]string, *apperror.AppError) {
+	return s.uploadPhotos(agreementId, name, 1, photos, invalid)
+}
+
+// DownloadAgreementPhoto reads back a photo attached to an agreement, such as
+// deduction evidence or an inspection or meter photo. The photos are private,
+// so only the parties and admins can download them.
+func (s *serviceImpl) DownloadAgreementPhoto(photo *bytes.Buffer, agreementId string, photoPath string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	folder, _, _ := strings.Cut(photoPath, "/")
+	if !slices.Contains(agreementPhotoFolders, folder) || path.Clean(photoPath) != photoPath || path.Ext(photoPath) != ".jpeg" {
+		return apperror.
+			New(apperror.PhotoNotFound).
+			Describe("Could not find the specified photo")
+	}
+
+	key := agreementPhotoKey(agreementId, photoPath)
+	file, err := s.storage.Download(key)
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return apperror.
+			New(apperror.PhotoNotFound).
+			Describe("Could not find the specified photo")
+	} else if err != nil {
+		s.logger.Error("Could not download agreement photo", zap.String("key", key), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not download photo")
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(photo, file); err != nil {
+		s.logger.Error("Could not read agreement photo", zap.String("key", key), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not download photo")
+	}
+
+	return nil
+}
+
+// SignAgreement signs the contract of an agreement on behalf of one of its
+// parties. The first signature picks the contract and freezes its hash, so the
+// other party has to sign the very same document.
+func (s *serviceImpl) SignAgreement(signature *models.AgreementSignatures, agreementId string, signing *models.SigningAgreements, signatureImage *multipart.FileHeader, session *models.Sessions) *apperror.AppError {
+	if _, ok := enums.SignatureTypesMap[string(signing.SignatureType)]; !ok {
+		return apperror.
+			New(apperror.InvalidSignatureType).
+			Describe("Signature type must be either TYPED or DRAWN")
+	}
+
+	signing.SignedName = strings.TrimSpace(signing.SignedName)
+	if signing.SignedName == "" || len(signing.SignedName) > 100 {
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe("Signed name must be between 1 and 100 characters")
+	}
+
+	if signing.SignatureType == enums.DrawnSignature && signatureImage == nil {
+		return apperror.
+			New(apperror.InvalidSignatureImage).
+			Describe("A drawn signature needs a signature image")
+	}
+
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := agreementRole(&agreement, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	agreementVersion, apperr := s.getAgreementVersion(agreementId)
+	if apperr != nil {
+		return apperr
+	}
+
+	// amended terms are signed again while the agreement runs
+	if agreement.Status != enums.AwaitingDepositAgreement && !(agreementVersion > 1 && isAmendable(&agreement)) {
+		return apperror.
+			New(apperror.InvalidAgreementTransition).
+			Describe("Only agreements awaiting deposit or with amended terms can be signed")
+	}
+
+	var contract models.AgreementContracts
+	var signatures []models.AgreementSignatures
+	apperr = s.getSignedContract(&contract, &signatures, agreementId)
+	if apperr != nil && apperr.Name() != apperror.SignatureNotFound.Name {
+		return apperr
+	}
+
+	if apperr != nil || contract.AgreementVersion != agreementVersion {
+		// nobody has signed the current terms yet, so the latest contract in
+		// the chosen language is signed
+		contract, signatures = models.AgreementContracts{}, nil
+		if signing.Language == "" {
+			signing.Language = enums.EnglishContract
+		}
+
+		if _, ok := enums.ContractLanguagesMap[string(signing.Language)]; !ok {
+			return apperror.
+				New(apperror.InvalidContractLanguage).
+				Describe("Contract language must be either EN or TH")
+		}
+
+		err := s.repo.GetLatestAgreementContract(&contract, agreementId, signing.Language)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && contract.AgreementVersion != agreementVersion) {
+			return apperror.
+				New(apperror.ContractNotFound).
+				Describe("The contract of the current terms must be generated before it can be signed")
+		} else if err != nil {
+			s.logger.Error("Could not get latest agreement contract", zap.String("id", agreementId), zap.Error(err))
+			return apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not sign agreement")
+		}
+	} else if signing.Language != "" && signing.Language != contract.Language {
+		return apperror.
+			New(apperror.InvalidContractLanguage).
+			Describe(fmt.Sprintf("The agreement is being signed in %v", contract.Language))
+	}
+
+	for _, signed := range signatures {
+		if signed.UserId == session.UserId {
+			return apperror.
+				New(apperror.ContractAlreadySigned).
+				Describe("You have already signed this contract")
+		}
+	}
+
+	frozenHash := contract.DocumentHash
+	if len(signatures) > 0 {
+		frozenHash = signatures[0].DocumentHash
+	}
+
+	currentHash, apperr := s.contractHash(&contract)
+	if apperr != nil {
+		return apperr
+	}
+
+	if currentHash != frozenHash {
+		s.logger.Error("Agreement contract does not match its frozen hash", zap.String("id", agreementId), zap.String("contract", contract.ContractId.String()))
+		return apperror.
+			New(apperror.ContractTampered).
+			Describe("The contract document has changed since it was generated")
+	}
+
+	signature.AgreementId = agreement.AgreementId
+	signature.ContractId = contract.ContractId
+	signature.UserId = session.UserId
+	signature.Role = role
+	signature.SignatureType = signing.SignatureType
+	signature.SignedName = signing.SignedName
+	signature.DocumentHash = frozenHash
+	signature.IpAddress = signing.IpAddress
+	signature.UserAgent = signing.UserAgent
+
+	if signing.SignatureType == enums.DrawnSignature {
+		key, apperr := s.uploadSignatureImage(&contract, session.UserId, signatureImage)
+		if apperr != nil {
+			return apperr
+		}
+		signature.SignatureImageKey = &key
+	}
+
+	err := s.repo.CreateAgreementSignature(signature)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.ContractAlreadySigned).
+			Describe("You have already signed this contract")
+	} else if err != nil {
+		s.logger.Error("Could not create agreement signature", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not sign agreement")
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// VerifyAgreementSignatures gets the signature audit trail of an agreement and
+// checks that the signed document has not changed since the first signature.
+func (s *serviceImpl) VerifyAgreementSignatures(verification *models.AgreementSignatureVerifications, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	verification.AgreementId = agreement.AgreementId
+	verification.Signatures = []models.AgreementSignatures{}
+	if apperr := s.getSignedContract(&verification.Contract, &verification.Signatures, agreementId); apperr != nil {
+		return apperr
+	}
+
+	currentHash, apperr := s.contractHash(&verification.Contract)
+	if apperr != nil {
+		return apperr
+	}
+
+	verification.FrozenHash = verification.Signatures[0].DocumentHash
+	verification.CurrentHash = currentHash
+	verification.IsDocumentIntact = currentHash == verification.FrozenHash
+	for _, signature := range verification.Signatures {
+		if signature.DocumentHash != verification.FrozenHash {
+			verification.IsDocumentIntact = false
+		}
+	}
+	verification.IsFullySigned = isFullySigned(verification.Signatures)
+
+	return nil
+}
+
+func (s *serviceImpl) GetAgreementAmendments(amendments *[]models.AgreementAmendments, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	err := s.repo.GetAgreementAmendments(amendments, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement amendments", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement amendments")
+	}
+
+	return nil
+}
+
+// CreateAgreementAmendment asks the other party to change the term or the rent
+// of a renting agreement. It replaces any amendment still waiting for an answer.
+func (s *serviceImpl) CreateAgreementAmendment(amendment *models.AgreementAmendments, agreementId string, creating *models.CreatingAgreementAmendments, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := agreementRole(&agreement, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !isAmendable(&agreement) {
+		return apperror.
+			New(apperror.AgreementNotAmendable).
+			Describe("Only renting agreements that are awaiting payment, renting or overdue can be amended")
+	}
+
+	duration := agreement.PaymentDuration
+	if creating.EndDate != nil {
+		months, ok := agreementMonths(agreement.AgreementDate, *creating.EndDate)
+		if !ok {
+			return apperror.
+				New(apperror.InvalidAmendment).
+				Describe(fmt.Sprintf("End date must be a later month on day %v, the day the agreement started", agreement.AgreementDate.In(utils.LocalTimezone).Day()))
+		}
+
+		if creating.PaymentDuration != nil && *creating.PaymentDuration != months {
+			return apperror.
+				New(apperror.InvalidAmendment).
+				Describe("Payment duration does not match the end date")
+		}
+		duration = months
+	} else if creating.PaymentDuration != nil {
+		duration = *creating.PaymentDuration
+	}
+
+	paymentPerMonth := agreement.PaymentPerMonth
+	if creating.PaymentPerMonth != nil {
+		paymentPerMonth = *creating.PaymentPerMonth
+	}
+
+	if duration < 1 || paymentPerMonth <= 0 {
+		return apperror.
+			New(apperror.InvalidAmendment).
+			Describe("Payment duration and payment per month must be positive")
+	}
+
+	if duration == agreement.PaymentDuration && paymentPerMonth == agreement.PaymentPerMonth {
+		return apperror.
+			New(apperror.InvalidAmendment).
+			Describe("The amendment does not change the agreement")
+	}
+
+	// the schedule is worked out again on acceptance, this only checks it can be
+	if _, apperr := s.amendedSchedule(&agreement, duration, paymentPerMonth); apperr != nil {
+		return apperr
+	}
+
+	amendmentType := enums.TermsAmendment
+	if duration > agreement.PaymentDuration {
+		amendmentType = enums.RenewalAmendment
+	}
+
+	*amendment = models.AgreementAmendments{
+		AmendmentId:             uuid.New(),
+		AgreementId:             agreement.AgreementId,
+		AmendmentType:           amendmentType,
+		ProposedByUserId:        session.UserId,
+		ProposerRole:            role,
+		PreviousPaymentDuration: agreement.PaymentDuration,
+		PreviousPaymentPerMonth: agreement.PaymentPerMonth,
+		PaymentDuration:         duration,
+		PaymentPerMonth:         paymentPerMonth,
+		EndDate:                 agreement.AgreementDate.AddDate(0, duration, 0),
+		Status:                  enums.PendingProposal,
+		Message:                 creating.Message,
+	}
+
+	err := s.repo.CreateAgreementAmendment(amendment)
+	if err != nil {
+		s.logger.Error("Could not create agreement amendment", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create agreement amendment")
+	}
+
+	return nil
+}
+
+// AcceptAgreementAmendment applies an amendment to its agreement as a new
+// version and recalculates the installments that are not due yet.
+func (s *serviceImpl) AcceptAgreementAmendment(amendment *models.AgreementAmendments, agreementId string, amendmentId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getRespondableAmendment(&agreement, amendment, agreementId, amendmentId, session); apperr != nil {
+		return apperr
+	}
+
+	if agreement.PaymentDuration != amendment.PreviousPaymentDuration || agreement.PaymentPerMonth != amendment.PreviousPaymentPerMonth {
+		return apperror.
+			New(apperror.AmendmentNotPending).
+			Describe("The agreement has been amended since this amendment was made")
+	}
+
+	changes, apperr := s.amendedSchedule(&agreement, amendment.PaymentDuration, amendment.PaymentPerMonth)
+	if apperr != nil {
+		return apperr
+	}
+
+	err := s.repo.AcceptAgreementAmendment(amendment, session.UserId, changes)
+	if errors.Is(err, errAmendmentNotPending) {
+		return apperror.
+			New(apperror.AmendmentNotPending).
+			Describe("Amendment has already been answered or superseded")
+	} else if errors.Is(err, errAgreementTermsChanged) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.AmendmentNotPending).
+			Describe("The agreement has been amended since this amendment was made")
+	} else if errors.Is(err, errInstallmentSettled) {
+		return apperror.
+			New(apperror.InstallmentAlreadyPaid).
+			Describe("An installment has just been paid. Please try again")
+	} else if err != nil {
+		s.logger.Error("Could not accept agreement amendment", zap.String("id", amendmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not accept agreement amendment")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) DeclineAgreementAmendment(agreementId string, amendmentId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	var amendment models.AgreementAmendments
+	if apperr := s.getRespondableAmendment(&agreement, &amendment, agreementId, amendmentId, session); apperr != nil {
+		return apperr
+	}
+
+	err := s.repo.DeclineAgreementAmendment(&amendment, session.UserId)
+	if errors.Is(err, errAmendmentNotPending) {
+		return apperror.
+			New(apperror.AmendmentNotPending).
+			Describe("Amendment has already been answered or superseded")
+	} else if err != nil {
+		s.logger.Error("Could not decline agreement amendment", zap.String("id", amendmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not decline agreement amendment")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetAgreementDeposit(deposit *models.AgreementDeposits, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	return s.getAgreementDeposit(deposit, &agreement)
+}
+
+// CreateDepositDeduction lets the owner of a renting agreement claim part of
+// the held deposit, with photos as evidence. The dweller then accepts or
+// disputes it.
+func (s *serviceImpl) CreateDepositDeduction(deduction *models.AgreementDepositDeductions, agreementId string, creating *models.CreatingDepositDeductions, evidences []*multipart.FileHeader, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	var deposit models.AgreementDeposits
+	if apperr := s.getOwnedDeposit(&agreement, &deposit, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	if !slices.Contains([]enums.AgreementStatus{
+		enums.RentingAgreement, enums.OverdueAgreement, enums.ArchivedAgreement, enums.CancelledAgreement,
+	}, agreement.Status) {
+		return apperror.
+			New(apperror.DepositNotHeld).
+			Describe("Deductions can only be made once the dweller has moved in")
+	}
+
+	creating.Description = strings.TrimSpace(creating.Description)
+	if creating.Description == "" || creating.Amount <= 0 {
+		return apperror.
+			New(apperror.InvalidDeduction).
+			Describe("A deduction needs a description and a positive amount")
+	}
+
+	if len(evidences) == 0 || len(evidences) > maxDeductionEvidences {
+		return apperror.
+			New(apperror.InvalidDeduction).
+			Describe(fmt.Sprintf("A deduction needs between 1 and %v evidence photos", maxDeductionEvidences))
+	}
+
+	if creating.Amount > deposit.HeldAmount-deposit.DeductedAmount-deposit.PendingAmount {
+		return apperror.
+			New(apperror.InvalidDeduction).
+			Describe(fmt.Sprintf("Deductions cannot exceed the held deposit of %v", deposit.HeldAmount))
+	}
+
+	*deduction = models.AgreementDepositDeductions{
+		DeductionId:     uuid.New(),
+		AgreementId:     agreement.AgreementId,
+		Description:     creating.Description,
+		Amount:          creating.Amount,
+		Status:          enums.PendingDeduction,
+		CreatedByUserId: session.UserId,
+	}
+
+	urls, apperr := s.uploadPhotos(agreementId, fmt.Sprintf("deductions/%v", deduction.DeductionId), 1, evidences, apperror.InvalidDeduction)
+	if apperr != nil {
+		return apperr
+	}
+
+	for _, url := range urls {
+		deduction.Evidences = append(deduction.Evidences, models.AgreementDeductionEvidences{
+			DeductionId: deduction.DeductionId,
+			ImageUrl:    url,
+		})
+	}
+
+	err := s.repo.CreateDepositDeduction(deduction)
+	if errors.Is(err, errDepositRefunded) {
+		return apperror.
+			New(apperror.DepositAlreadyRefunded).
+			Describe("The deposit has already been refunded")
+	} else if errors.Is(err, errDeductionExceedsDeposit) {
+		return apperror.
+			New(apperror.InvalidDeduction).
+			Describe(fmt.Sprintf("Deductions cannot exceed the held deposit of %v", deposit.HeldAmount))
+	} else if err != nil {
+		s.logger.Error("Could not create deposit deduction", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create deposit deduction")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) AcceptDepositDeduction(agreementId string, deductionId string, session *models.Sessions) *apperror.AppError {
+	var deduction models.AgreementDepositDeductions
+	if apperr := s.getRespondableDeduction(&deduction, agreementId, deductionId, session); apperr != nil {
+		return apperr
+	}
+
+	return s.respondToDeduction(&deduction, enums.AcceptedDeduction, "")
+}
+
+func (s *serviceImpl) DisputeDepositDeduction(agreementId string, deductionId string, disputing *models.DisputingDepositDeductions, session *models.Sessions) *apperror.AppError {
+	disputing.DisputeReason = strings.TrimSpace(disputing.DisputeReason)
+	if disputing.DisputeReason == "" {
+		return apperror.
+			New(apperror.InvalidDeduction).
+			Describe("A dispute needs a reason")
+	}
+
+	var deduction models.AgreementDepositDeductions
+	if apperr := s.getRespondableDeduction(&deduction, agreementId, deductionId, session); apperr != nil {
+		return apperr
+	}
+
+	return s.respondToDeduction(&deduction, enums.DisputedDeduction, disputing.DisputeReason)
+}
+
+// DeleteDepositDeduction withdraws a deduction the dweller has not accepted,
+// typically to replace a disputed one with a revised claim.
+func (s *serviceImpl) DeleteDepositDeduction(agreementId string, deductionId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	var deposit models.AgreementDeposits
+	if apperr := s.getOwnedDeposit(&agreement, &deposit, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	var deduction models.AgreementDepositDeductions
+	if apperr := s.getDeduction(&deduction, agreementId, deductionId); apperr != nil {
+		return apperr
+	}
+
+	err := s.repo.DeleteDepositDeduction(&deduction)
+	if errors.Is(err, errDeductionNotPending) {
+		return apperror.
+			New(apperror.DeductionNotPending).
+			Describe("Only pending or disputed deductions can be withdrawn")
+	} else if err != nil {
+		s.logger.Error("Could not delete deposit deduction", zap.String("id", deductionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not delete deposit deduction")
+	}
+
+	return nil
+}
+
+// ResolveDepositDeduction settles a disputed deduction. The dweller can accept
+// it after all and the owner can withdraw it. When they cannot agree, either of
+// them escalates it to an admin, who then accepts or withdraws it.
+func (s *serviceImpl) ResolveDepositDeduction(agreementId string, deductionId string, resolving *models.ResolvingDepositDeductions, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	role, apperr := s.GetAgreementRole(&agreement, agreementId, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	var deposit models.AgreementDeposits
+	if apperr := s.checkDepositHeld(&agreement, &deposit); apperr != nil {
+		return apperr
+	}
+
+	var deduction models.AgreementDepositDeductions
+	if apperr := s.getDeduction(&deduction, agreementId, deductionId); apperr != nil {
+		return apperr
+	}
+
+	from := deduction.Status
+	if apperr := checkDeductionTransition(from, resolving.Status, role); apperr != nil {
+		return apperr
+	}
+
+	deduction.Status = resolving.Status
+	if resolving.Status != enums.EscalatedDeduction {
+		now := time.Now()
+		deduction.ResolvedAt = &now
+	}
+
+	err := s.repo.ResolveDepositDeduction(&deduction, from)
+	if errors.Is(err, errDeductionNotDisputed) {
+		return apperror.
+			New(apperror.DeductionNotDisputed).
+			Describe("Deduction has already been resolved")
+	} else if err != nil {
+		s.logger.Error("Could not resolve deposit deduction", zap.String("id", deductionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not resolve deposit deduction")
+	}
+
+	return nil
+}
+
+// WithdrawStaleDisputes withdraws the disputed deductions nobody has escalated
+// within the timeout, so a dispute cannot hold back the deposit forever.
+func (s *serviceImpl) WithdrawStaleDisputes() {
+	var withdrawn int64
+	err := s.repo.WithdrawStaleDisputes(&withdrawn, time.Now().AddDate(0, 0, -s.cfg.DisputeTimeoutDays))
+	if err != nil {
+		s.logger.Error("Could not withdraw stale disputes", zap.Error(err))
+		return
+	}
+
+	if withdrawn > 0 {
+		s.logger.Info("Withdrew stale disputes", zap.Int64("count", withdrawn))
+	}
+}
+
+// RefundDeposit records the refund of what is left of the deposit once the
+// agreement is over and every deduction has been accepted or withdrawn. The
+// refund is a payment from the owner to the dweller, so it shows up in the
+// payment history of both.
+func (s *serviceImpl) RefundDeposit(refund *models.Payments, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	var deposit models.AgreementDeposits
+	if apperr := s.getOwnedDeposit(&agreement, &deposit, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	if agreement.Status != enums.ArchivedAgreement && agreement.Status != enums.CancelledAgreement {
+		return apperror.
+			New(apperror.DepositNotRefundable).
+			Describe("The deposit can only be refunded once the agreement is archived or cancelled")
+	}
+
+	if deposit.PendingAmount > 0 {
+		return apperror.
+			New(apperror.DeductionsUnresolved).
+			Describe("Every deduction must be accepted or withdrawn before refunding the deposit")
+	}
+
+	*refund = models.Payments{
+		PaymentId:       uuid.New(),
+		UserId:          agreement.OwnerUserId,
+		Price:           deposit.RefundableAmount,
+		IsSuccess:       true,
+		Name:            "Deposit refund",
+		AgreementId:     &agreement.AgreementId,
+		PaymentType:     enums.DepositRefundPayment,
+		RecipientUserId: &agreement.DwellerUserId,
+	}
+
+	err := s.repo.CreateDepositRefund(refund)
+	if errors.Is(err, errDeductionsUnresolved) {
+		return apperror.
+			New(apperror.DeductionsUnresolved).
+			Describe("Every deduction must be accepted or withdrawn before refunding the deposit")
+	} else if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.DepositAlreadyRefunded).
+			Describe("The deposit has already been refunded")
+	} else if err != nil {
+		s.logger.Error("Could not create deposit refund", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not refund deposit")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetAgreementInspections(inspections *[]models.AgreementInspections, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	err := s.repo.GetAgreementInspections(inspections, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement inspections", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement inspections")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetAgreementInspection(inspection *models.AgreementInspections, agreementId string, inspectionId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	return s.getInspection(inspection, agreementId, inspectionId)
+}
+
+// CreateAgreementInspection writes the move-in or move-out report of a renting
+// agreement. Either party can write it; both then sign it off. A party that
+// disagrees with a report the other has signed off writes a new one instead,
+// which supersedes it.
+func (s *serviceImpl) CreateAgreementInspection(inspection *models.AgreementInspections, agreementId string, creating *models.CreatingInspections, session *models.Sessions) *apperror.AppError {
+	if _, ok := enums.InspectionTypesMap[string(creating.InspectionType)]; !ok {
+		return apperror.
+			New(apperror.InvalidInspection).
+			Describe("Inspection type must be MOVE_IN or MOVE_OUT")
+	}
+
+	var agreement models.Agreements
+	if apperr := s.getInspectableAgreement(&agreement, agreementId, creating.InspectionType, session); apperr != nil {
+		return apperr
+	}
+
+	*inspection = models.AgreementInspections{
+		InspectionId:    uuid.New(),
+		AgreementId:     agreement.AgreementId,
+		InspectionType:  creating.InspectionType,
+		CreatedByUserId: session.UserId,
+	}
+
+	items, apperr := inspectionItems(inspection.InspectionId, creating.Items)
+	if apperr != nil {
+		return apperr
+	}
+	inspection.Items = items
+
+	err := s.repo.CreateAgreementInspection(inspection)
+	if errors.Is(err, errInspectionUnsigned) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.DuplicateInspection).
+			Describe(fmt.Sprintf("The %v report of this agreement has already been written and can still be changed", inspection.InspectionType))
+	} else if errors.Is(err, errInspectionFinalised) {
+		return apperror.
+			New(apperror.InspectionSigned).
+			Describe(fmt.Sprintf("The %v report of this agreement has been signed off by both parties", inspection.InspectionType))
+	} else if err != nil {
+		s.logger.Error("Could not create agreement inspection", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create agreement inspection")
+	}
+
+	return nil
+}
+
+// UpdateAgreementInspection replaces the items of a report nobody has signed off
+// yet. The photos of the previous items are dropped along with them.
+func (s *serviceImpl) UpdateAgreementInspection(inspection *models.AgreementInspections, agreementId string, inspectionId string, updating *models.UpdatingInspections, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getEditableInspection(inspection, agreementId, inspectionId, session); apperr != nil {
+		return apperr
+	}
+
+	items, apperr := inspectionItems(inspection.InspectionId, updating.Items)
+	if apperr != nil {
+		return apperr
+	}
+	inspection.Items = items
+
+	err := s.repo.UpdateInspectionItems(inspection)
+	if errors.Is(err, errInspectionSigned) {
+		return apperror.
+			New(apperror.InspectionSigned).
+			Describe("Signed off reports cannot be changed")
+	} else if err != nil {
+		s.logger.Error("Could not update agreement inspection", zap.String("id", inspectionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not update agreement inspection")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) UploadInspectionPhotos(item *models.AgreementInspectionItems, agreementId string, inspectionId string, itemId string, photos []*multipart.FileHeader, session *models.Sessions) *apperror.AppError {
+	var inspection models.AgreementInspections
+	if apperr := s.getEditableInspection(&inspection, agreementId, inspectionId, session); apperr != nil {
+		return apperr
+	}
+
+	if !utils.IsValidUUID(itemId) {
+		return apperror.
+			New(apperror.InvalidInspectionItemId).
+			Describe("Invalid inspection item id")
+	}
+
+	i := slices.IndexFunc(inspection.Items, func(item models.AgreementInspectionItems) bool {
+		return item.ItemId.String() == itemId
+	})
+	if i < 0 {
+		return apperror.
+			New(apperror.InspectionItemNotFound).
+			Describe("Could not find the specified inspection item")
+	}
+	*item = inspection.Items[i]
+
+	if len(photos) == 0 || len(item.Photos)+len(photos) > maxInspectionPhotos {
+		return apperror.
+			New(apperror.InvalidInspection).
+			Describe(fmt.Sprintf("An item can have between 1 and %v photos", maxInspectionPhotos))
+	}
+
+	// the photos are numbered when they are saved, so concurrent uploads are
+	// told apart by a batch id instead
+	name := fmt.Sprintf("inspections/%v/%v-%v", inspection.InspectionId, item.ItemId, uuid.New())
+	urls, apperr := s.uploadPhotos(agreementId, name, 1, photos, apperror.InvalidInspection)
+	if apperr != nil {
+		return apperr
+	}
+
+	uploaded := []models.AgreementInspectionPhotos{}
+	for _, url := range urls {
+		uploaded = append(uploaded, models.AgreementInspectionPhotos{
+			ItemId:   item.ItemId,
+			ImageUrl: url,
+		})
+	}
+
+	err := s.repo.CreateInspectionPhotos(inspection.InspectionId, uploaded, maxInspectionPhotos)
+	if errors.Is(err, errInspectionSigned) {
+		return apperror.
+			New(apperror.InspectionSigned).
+			Describe("Signed off reports cannot be changed")
+	} else if errors.Is(err, errTooManyPhotos) {
+		return apperror.
+			New(apperror.InvalidInspection).
+			Describe(fmt.Sprintf("An item can have between 1 and %v photos", maxInspectionPhotos))
+	} else if err != nil {
+		s.logger.Error("Could not create inspection photos", zap.String("id", itemId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not upload inspection photos")
+	}
+
+	item.Photos = append(item.Photos, uploaded...)
+	return nil
+}
+
+// SignAgreementInspection records that a party agrees with a report. The other
+// party signs off first, which freezes the report, and its author then signs
+// it off too to make it final.
+func (s *serviceImpl) SignAgreementInspection(inspection *models.AgreementInspections, agreementId string, inspectionId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := agreementRole(&agreement, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	if apperr := s.getInspection(inspection, agreementId, inspectionId); apperr != nil {
+		return apperr
+	}
+
+	if apperr := checkInspectionSignable(inspection, role, session); apperr != nil {
+		return apperr
+	}
+
+	now := time.Now()
+	err := s.repo.SignAgreementInspection(inspection, role, now)
+	if errors.Is(err, errInspectionAlreadySigned) {
+		return apperror.
+			New(apperror.InspectionAlreadySigned).
+			Describe("You have already signed off this report")
+	} else if err != nil {
+		s.logger.Error("Could not sign agreement inspection", zap.String("id", inspectionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not sign agreement inspection")
+	}
+
+	if role == enums.OwnerActor {
+		inspection.OwnerSignedAt = &now
+	} else {
+		inspection.DwellerSignedAt = &now
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) CompareAgreementInspections(comparison *models.InspectionComparisons, agreementId string, session *models.Sessions) *apperror.AppError {
+	var inspections []models.AgreementInspections
+	if apperr := s.GetAgreementInspections(&inspections, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	var moveIn, moveOut *models.AgreementInspections
+	for i := range inspections {
+		if inspections[i].SupersededAt != nil {
+			continue
+		}
+
+		switch inspections[i].InspectionType {
+		case enums.MoveInInspection:
+			moveIn = &inspections[i]
+		case enums.MoveOutInspection:
+			moveOut = &inspections[i]
+		}
+	}
+
+	if moveIn == nil || moveOut == nil {
+		return apperror.
+			New(apperror.InspectionNotFound).
+			Describe("Both the move-in and the move-out reports are needed for a comparison")
+	}
+
+	*comparison = models.InspectionComparisons{
+		MoveInInspectionId:  moveIn.InspectionId,
+		MoveOutInspectionId: moveOut.InspectionId,
+		Items:               compareInspectionItems(moveIn.Items, moveOut.Items),
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetUtilityRates(rates *models.AgreementUtilityRates, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	return s.getUtilityRates(rates, agreementId)
+}
+
+// UpdateUtilityRates sets what the owner charges per unit of water and
+// electricity. Readings already recorded keep the rate they were charged at.
+func (s *serviceImpl) UpdateUtilityRates(rates *models.AgreementUtilityRates, agreementId string, updating *models.UpdatingUtilityRates, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getMeteredAgreement(&agreement, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	if slices.Contains([]enums.AgreementStatus{enums.ArchivedAgreement, enums.CancelledAgreement}, agreement.Status) {
+		return apperror.
+			New(apperror.MeterReadingNotAllowed).
+			Describe(fmt.Sprintf("Utility rates cannot be changed once the agreement is %v", agreement.Status))
+	}
+
+	if updating.WaterRate < 0 || updating.ElectricityRate < 0 {
+		return apperror.
+			New(apperror.InvalidUtilityRates).
+			Describe("Utility rates cannot be negative")
+	}
+
+	*rates = models.AgreementUtilityRates{
+		AgreementId:     agreement.AgreementId,
+		WaterRate:       updating.WaterRate,
+		ElectricityRate: updating.ElectricityRate,
+	}
+
+	err := s.repo.SaveUtilityRates(rates)
+	if err != nil {
+		s.logger.Error("Could not save utility rates", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not save utility rates")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetMeterReadings(readings *[]models.AgreementMeterReadings, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(&agreement, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	err := s.repo.GetMeterReadings(readings, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get meter readings", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get meter readings")
+	}
+
+	return nil
+}
+
+// CreateMeterReading records the monthly reading of a meter with a photo of it
+// and bills the units used since the previous reading with the installment of
+// that month. Readings of a meter are recorded month after month.
+func (s *serviceImpl) CreateMeterReading(reading *models.AgreementMeterReadings, agreementId string, creating *models.CreatingMeterReadings, photo *multipart.FileHeader, session *models.Sessions) *apperror.AppError {
+	if _, ok := enums.UtilityTypesMap[string(creating.UtilityType)]; !ok {
+		return apperror.
+			New(apperror.InvalidMeterReading).
+			Describe("Utility type must be WATER or ELECTRICITY")
+	}
+
+	if photo == nil {
+		return apperror.
+			New(apperror.InvalidMeterReading).
+			Describe("A reading needs a photo of the meter")
+	}
+
+	var agreement models.Agreements
+	if apperr := s.getMeteredAgreement(&agreement, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	if agreement.Status != enums.RentingAgreement && agreement.Status != enums.OverdueAgreement {
+		return apperror.
+			New(apperror.MeterReadingNotAllowed).
+			Describe("Meters can only be read while renting")
+	}
+
+	var rates models.AgreementUtilityRates
+	if apperr := s.getUtilityRates(&rates, agreementId); apperr != nil {
+		return apperr
+	}
+
+	var installments []models.AgreementInstallments
+	err := s.repo.GetAgreementInstallments(&installments, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement installments", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement installments")
+	}
+
+	i := slices.IndexFunc(installments, func(installment models.AgreementInstallments) bool {
+		return installment.InstallmentNumber == creating.InstallmentNumber
+	})
+	if i < 0 {
+		return apperror.
+			New(apperror.InstallmentNotFound).
+			Describe(fmt.Sprintf("Could not find installment %v", creating.InstallmentNumber))
+	}
+	installment := installments[i]
+
+	if installment.Status == enums.PaidInstallment {
+		return apperror.
+			New(apperror.InstallmentAlreadyPaid).
+			Describe("Utilities cannot be added to an installment that has been paid")
+	}
+
+	// utilities are billed with the earliest unpaid installment, once it is
+	// due or falls due within the month
+	earliest := slices.IndexFunc(installments, func(installment models.AgreementInstallments) bool {
+		return installment.Status != enums.PaidInstallment
+	})
+	if i != earliest {
+		return apperror.
+			New(apperror.MeterReadingNotAllowed).
+			Describe(fmt.Sprintf("Utilities can only be added to installment %v, the earliest one that is unpaid", installments[earliest].InstallmentNumber))
+	}
+
+	nextMonth := utils.StartOfDay(time.Now()).AddDate(0, 1, 0).Format(time.DateOnly)
+	if installment.DueDate.Format(time.DateOnly) > nextMonth {
+		return apperror.
+			New(apperror.MeterReadingNotAllowed).
+			Describe(fmt.Sprintf("Installment %v is not due until %v", installment.InstallmentNumber, installment.DueDate.Format(time.DateOnly)))
+	}
+
+	var previous models.AgreementMeterReadings
+	err = s.repo.GetLatestMeterReading(&previous, agreementId, creating.UtilityType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if creating.PreviousReading == nil || *creating.PreviousReading < 0 {
+			return apperror.
+				New(apperror.InvalidMeterReading).
+				Describe("The first reading of a meter needs the reading it started from")
+		}
+		previous.CurrentReading = *creating.PreviousReading
+	} else if err != nil {
+		s.logger.Error("Could not get latest meter reading", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get latest meter reading")
+	} else if installment.InstallmentNumber <= previous.InstallmentNumber {
+		return apperror.
+			New(apperror.MeterReadingNotAllowed).
+			Describe(fmt.Sprintf("The %v meter has already been read for installment %v", creating.UtilityType, previous.InstallmentNumber))
+	}
+
+	if creating.CurrentReading < previous.CurrentReading {
+		return apperror.
+			New(apperror.InvalidMeterReading).
+			Describe(fmt.Sprintf("The reading cannot be below the previous one of %v", previous.CurrentReading))
+	}
+
+	unitRate := rates.WaterRate
+	if creating.UtilityType == enums.ElectricityUtility {
+		unitRate = rates.ElectricityRate
+	}
+
+	units := creating.CurrentReading - previous.CurrentReading
+	*reading = models.AgreementMeterReadings{
+		ReadingId:         uuid.New(),
+		AgreementId:       agreement.AgreementId,
+		InstallmentId:     installment.InstallmentId,
+		InstallmentNumber: installment.InstallmentNumber,
+		UtilityType:       creating.UtilityType,
+		PreviousReading:   previous.CurrentReading,
+		CurrentReading:    creating.CurrentReading,
+		Units:             units,
+		UnitRate:          unitRate,
+		Amount:            math.Round(units*unitRate*100) / 100,
+		RecordedByUserId:  session.UserId,
+	}
+
+	urls, apperr := s.uploadPhotos(agreementId, fmt.Sprintf("meters/%v", reading.ReadingId), 1, []*multipart.FileHeader{photo}, apperror.InvalidMeterReading)
+	if apperr != nil {
+		return apperr
+	}
+	reading.ImageUrl = urls[0]
+
+	err = s.repo.CreateMeterReading(reading)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.DuplicateMeterReading).
+			Describe(fmt.Sprintf("The %v meter has already been read for installment %v", reading.UtilityType, reading.InstallmentNumber))
+	} else if errors.Is(err, errInstallmentSettled) {
+		return apperror.
+			New(apperror.InstallmentAlreadyPaid).
+			Describe("Utilities cannot be added to an installment that has been paid")
+	} else if err != nil {
+		s.logger.Error("Could not create meter reading", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create meter reading")
+	}
+
+	return nil
+}
+
+// DeleteMeterReading takes back the latest reading of a meter, along with its
+// charge, as long as its installment has not been paid.
+func (s *serviceImpl) DeleteMeterReading(agreementId string, readingId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getMeteredAgreement(&agreement, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	if !utils.IsValidUUID(readingId) {
+		return apperror.
+			New(apperror.InvalidMeterReadingId).
+			Describe("Invalid meter reading id")
+	}
+
+	var reading models.AgreementMeterReadings
+	err := s.repo.GetMeterReading(&reading, agreementId, readingId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.MeterReadingNotFound).
+			Describe("Could not find the specified meter reading")
+	} else if err != nil {
+		s.logger.Error("Could not get meter reading", zap.String("id", readingId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get meter reading")
+	}
+
+	var latest models.AgreementMeterReadings
+	err = s.repo.GetLatestMeterReading(&latest, agreementId, reading.UtilityType)
+	if err != nil {
+		s.logger.Error("Could not get latest meter reading", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get latest meter reading")
+	}
+
+	if latest.ReadingId != reading.ReadingId {
+		return apperror.
+			New(apperror.MeterReadingNotAllowed).
+			Describe("Only the latest reading of a meter can be deleted")
+	}
+
+	err = s.repo.DeleteMeterReading(&reading)
+	if errors.Is(err, errInstallmentSettled) {
+		return apperror.
+			New(apperror.InstallmentAlreadyPaid).
+			Describe("Readings billed with an installment that has been paid cannot be deleted")
+	} else if err != nil {
+		s.logger.Error("Could not delete meter reading", zap.String("id", readingId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not delete meter reading")
+	}
+
+	return nil
+}
+
+// lateFee charges the configured rate for every day past the grace period, up
+// to the cap when there is one.
+func (s *serviceImpl) lateFee(amount float64, chargedDays int) float64 {
+	var fee float64
+	switch enums.LateFeeTypes(s.cfg.LateFeeType) {
+	case enums.FlatLateFee:
+		fee = s.cfg.LateFeeRate * float64(chargedDays)
+	case enums.PercentageLateFee:
+		fee = amount * s.cfg.LateFeeRate / 100 * float64(chargedDays)
+	default:
+		s.logger.Warn("Unknown late fee type, no late fee is charged", zap.String("type", s.cfg.LateFeeType))
+	}
+
+	if s.cfg.LateFeeCap > 0 {
+		fee = min(fee, s.cfg.LateFeeCap)
+	}
+
+	return math.Round(max(fee, 0)*100) / 100
+}
+
+func (s *serviceImpl) markAgreementOverdue(agreementId string, installments []models.AgreementInstallments) {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		s.logger.Warn("Could not get overdue agreement", zap.String("id", agreementId), zap.Error(apperr))
+		return
+	}
+
+	// the parties have already been told when the agreement became overdue
+	if agreement.Status == enums.OverdueAgreement {
+		return
+	}
+
+	updatingAgreement := models.UpdatingAgreementStatus{
+		Status: enums.OverdueAgreement,
+	}
+
+	if apperr := s.transitionAgreement(&agreement, &updatingAgreement, enums.SystemActor, nil); apperr != nil {
+		s.logger.Warn("Could not mark agreement overdue", zap.String("id", agreementId), zap.Error(apperr))
+		return
+	}
+
+	var details models.AgreementDetails
+	err := s.repo.GetAgreementById(&details, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement by id", zap.String("id", agreementId), zap.Error(err))
+		return
+	}
+
+	var overdueAmount float64
+	for _, installment := range installments {
+		overdueAmount += installment.Amount + installment.LateFee + installment.UtilityAmount
+	}
+
+	parties := []struct {
+		userId          uuid.UUID
+		isOwner         bool
+		counterpartName string
+	}{
+		{agreement.OwnerUserId, true, fmt.Sprintf("%v %v", details.Dweller.DwellerFirstName, details.Dweller.DwellerLastName)},
+		{agreement.DwellerUserId, false, fmt.Sprintf("%v %v", details.Owner.OwnerFirstName, details.Owner.OwnerLastName)},
+	}
+
+	for _, party := range parties {
+		var recipient models.NotificationRecipients
+		err := s.repo.GetNotificationRecipient(&recipient, party.userId)
+		if err != nil {
+			s.logger.Error("Could not get party of overdue agreement", zap.String("id", agreementId), zap.Error(err))
+			continue
+		}
+
+		email := models.AgreementOverdueEmails{
+			FirstName:       recipient.FirstName,
+			PropertyName:    details.Property.PropertyName,
+			IsOwner:         party.isOwner,
+			CounterpartName: party.counterpartName,
+			DueDate:         installments[0].DueDate.Format(emailDateLayout),
+			OverdueAmount:   fmt.Sprintf("%.2f", overdueAmount),
+		}
+
+		// the email service logs its own failures and the agreement stays overdue either way
+		_ = s.emailService.SendAgreementOverdueEmail(recipient.Email, &email)
+	}
+}
+
+func (s *serviceImpl) countOverdueInstallments(agreementId string) (int64, *apperror.AppError) {
+	var count int64
+	err := s.repo.CountOverdueInstallments(&count, agreementId)
+	if err != nil {
+		s.logger.Error("Could not count overdue installments", zap.String("id", agreementId), zap.Error(err))
+		return 0, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not count overdue installments")
+	}
+
+	return count, nil
+}
+
+func (s *serviceImpl) getAgreement(agreement *models.Agreements, agreementId string) *apperror.AppError {
+	if !utils.IsValidUUID(agreementId) {
+		return apperror.
+			New(apperror.InvalidAgreementId).
+			Describe("Invalid agreement id")
+	}
+
+	err := s.repo.GetAgreement(agreement, agreementId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.AgreementNotFound).
+			Describe("Could not find the specified agreement")
+	} else if err != nil {
+		s.logger.Error("Could not get agreement", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) transitionAgreement(agreement *models.Agreements, updatingAgreement *models.UpdatingAgreementStatus, role enums.ActorRoles, actorUserId *uuid.UUID) *apperror.AppError {
+	roles, ok := agreementTransitions[agreement.Status][updatingAgreement.Status]
+	if !ok {
+		return apperror.
+			New(apperror.InvalidAgreementTransition).
+			Describe(fmt.Sprintf("Agreement could not be moved from %v to %v", agreement.Status, updatingAgreement.Status))
+	}
+
+	if !slices.Contains(roles, role) {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe(fmt.Sprintf("The %v is not allowed to move an agreement to %v", strings.ToLower(string(role)), updatingAgreement.Status))
+	}
+
+	if apperr := s.checkTransitionPreconditions(agreement, updatingAgreement.Status); apperr != nil {
+		return apperr
+	}
+
+	// only cancellations carry a message
+	if updatingAgreement.Status != enums.CancelledAgreement {
+		updatingAgreement.CancelledMessage = ""
+	}
+
+	history := models.AgreementStatusHistories{
+		FromStatus:  agreement.Status,
+		ActorUserId: actorUserId,
+		ActorRole:   role,
+	}
+
+	flags := propertyFlags(agreement, updatingAgreement.Status)
+
+	agreementId := agreement.AgreementId.String()
+	err := s.repo.UpdateAgreementStatus(updatingAgreement, agreementId, &history, flags)
+	if errors.Is(err, errAgreementStatusChanged) {
+		return apperror.
+			New(apperror.InvalidAgreementTransition).
+			Describe("Agreement status has just been changed. Please try again.")
+	} else if err != nil {
+		s.logger.Error("Could not update agreement status", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not update agreement status")
+	}
+
+	// nobody can view a property that has just been sold or rented out
+	if flags != nil && flags.IsSold != nil && *flags.IsSold {
+		s.appointmentService.CancelUpcomingAppointments(agreement.PropertyId.String(), "The property has been sold")
+	} else if flags != nil && flags.IsOccupied != nil && *flags.IsOccupied {
+		s.appointmentService.CancelUpcomingAppointments(agreement.PropertyId.String(), "The property has been rented out")
+	}
+
+	return nil
+}
+
+// checkTransitionPreconditions makes sure that whatever the next status stands
+// for has actually happened, e.g. that the deposit has been paid before the
+// agreement stops awaiting it.
+func (s *serviceImpl) checkTransitionPreconditions(agreement *models.Agreements, status enums.AgreementStatus) *apperror.AppError {
+	switch {
+	case agreement.Status == enums.AwaitingDepositAgreement && status == enums.AwaitingPaymentAgreement:
+		if apperr := s.checkSigned(agreement); apperr != nil {
+			return apperr
+		}
+
+		return s.checkPaidAmount(agreement, agreement.DepositAmount, "deposit", enums.DepositPayment)
+
+	case agreement.Status == enums.AwaitingPaymentAgreement && status == enums.RentingAgreement:
+		if agreement.AgreementType != enums.AgreementForRent {
+			return apperror.
+				New(apperror.InvalidAgreementTransition).
+				Describe("Only renting agreements can be moved to RENTING")
+		}
+
+		return s.checkPaidAmount(agreement, agreement.PaymentPerMonth, "first month's rent", enums.RentPayment)
+
+	case agreement.Status == enums.AwaitingPaymentAgreement && status == enums.ArchivedAgreement:
+		if agreement.AgreementType != enums.AgreementForSell {
+			return apperror.
+				New(apperror.InvalidAgreementTransition).
+				Describe("Only selling agreements can be completed without renting")
+		}
+
+		return s.checkPaidAmount(agreement, agreement.TotalPayment, "full price", enums.DepositPayment, enums.PurchasePayment)
+
+	case agreement.Status == enums.OverdueAgreement && status == enums.RentingAgreement:
+		overdue, apperr := s.countOverdueInstallments(agreement.AgreementId.String())
+		if apperr != nil {
+			return apperr
+		} else if overdue > 0 {
+			return apperror.
+				New(apperror.AgreementNotPaid).
+				Describe(fmt.Sprintf("%v overdue installment(s) have not been paid yet", overdue))
+		}
+
+	case agreement.Status == enums.RentingAgreement && status == enums.ArchivedAgreement:
+		endDate := agreement.AgreementDate.AddDate(0, agreement.PaymentDuration, 0)
+		if time.Now().Before(endDate) {
+			return apperror.
+				New(apperror.InvalidAgreementTransition).
+				Describe(fmt.Sprintf("The renting period does not end until %v", endDate.In(utils.LocalTimezone).Format(time.DateOnly)))
+		}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) checkSigned(agreement *models.Agreements) *apperror.AppError {
+	var contract models.AgreementContracts
+	var signatures []models.AgreementSignatures
+	apperr := s.getSignedContract(&contract, &signatures, agreement.AgreementId.String())
+	if apperr != nil && apperr.Name() != apperror.SignatureNotFound.Name {
+		return apperr
+	}
+
+	if !isFullySigned(signatures) {
+		return apperror.
+			New(apperror.AgreementNotSigned).
+			Describe("Both the owner and the dweller must sign the contract first")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) getAgreementVersion(agreementId string) (int, *apperror.AppError) {
+	var version int
+	if err := s.repo.GetAgreementVersion(&version, agreementId); err != nil {
+		s.logger.Error("Could not get agreement version", zap.String("id", agreementId), zap.Error(err))
+		return 0, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement version")
+	}
+
+	return version, nil
+}
+
+// getSignedContract gets the contract being signed along with its signatures,
+// oldest first. SignatureNotFound means nobody has signed yet.
+func (s *serviceImpl) getSignedContract(contract *models.AgreementContracts, signatures *[]models.AgreementSignatures, agreementId string) *apperror.AppError {
+	err := s.repo.GetSignedAgreementContract(contract, agreementId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.SignatureNotFound).
+			Describe("The contract of this agreement has not been signed yet")
+	} else if err != nil {
+		s.logger.Error("Could not get signed agreement contract", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement signatures")
+	}
+
+	err = s.repo.GetContractSignatures(signatures, contract.ContractId)
+	if err != nil {
+		s.logger.Error("Could not get contract signatures", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement signatures")
+	}
+
+	return nil
+}
+
+// contractHash hashes the contract document as it is stored now.
+func (s *serviceImpl) contractHash(contract *models.AgreementContracts) (string, *apperror.AppError) {
+	file, err := s.storage.Download(contract.FileKey)
+	if err != nil {
+		s.logger.Error("Could not download agreement contract", zap.String("key", contract.FileKey), zap.Error(err))
+		return "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not read contract")
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		s.logger.Error("Could not read agreement contract", zap.String("key", contract.FileKey), zap.Error(err))
+		return "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not read contract")
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (s *serviceImpl) uploadSignatureImage(contract *models.AgreementContracts, userId uuid.UUID, signatureImage *multipart.FileHeader) (string, *apperror.AppError) {
+	if strings.ToLower(filepath.Ext(signatureImage.Filename)) != ".png" || signatureImage.Size > maxSignatureImageSize {
+		return "", apperror.
+			New(apperror.InvalidSignatureImage).
+			Describe("Signature image must be a PNG of at most 1 MB")
+	}
+
+	file, err := signatureImage.Open()
+	if err != nil {
+		s.logger.Error("Could not open signature image", zap.Error(err))
+		return "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not upload signature image")
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		s.logger.Error("Could not read signature image", zap.Error(err))
+		return "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not upload signature image")
+	}
+
+	if _, err := png.DecodeConfig(bytes.NewReader(content)); err != nil {
+		return "", apperror.
+			New(apperror.InvalidSignatureImage).
+			Describe("Signature image is not a valid PNG")
+	}
+
+	filename := fmt.Sprintf("agreements/%v/signatures/%v-%v.png", contract.AgreementId, contract.ContractId, userId)
+	_, err = s.storage.Upload(filename, bytes.NewReader(content), types.ObjectCannedACLPrivate)
+	if err != nil {
+		s.logger.Error("Could not upload signature image", zap.String("key", filename), zap.Error(err))
+		return "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not upload signature image")
+	}
+
+	return filename, nil
+}
+
+// getRespondableAmendment loads a pending amendment that the current user may
+// answer, which is any amendment made by the other party of the agreement.
+func (s *serviceImpl) getRespondableAmendment(agreement *models.Agreements, amendment *models.AgreementAmendments, agreementId string, amendmentId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getAgreement(agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := agreementRole(agreement, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !utils.IsValidUUID(amendmentId) {
+		return apperror.
+			New(apperror.InvalidAmendmentId).
+			Describe("Invalid amendment id")
+	}
+
+	err := s.repo.GetAgreementAmendment(amendment, agreementId, amendmentId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.AmendmentNotFound).
+			Describe("Could not find the specified amendment")
+	} else if err != nil {
+		s.logger.Error("Could not get agreement amendment", zap.String("id", amendmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement amendment")
+	}
+
+	if amendment.Status != enums.PendingProposal {
+		return apperror.
+			New(apperror.AmendmentNotPending).
+			Describe("Amendment has already been answered or superseded")
+	}
+
+	if amendment.ProposerRole == role {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the other party can answer this amendment")
+	}
+
+	if !isAmendable(agreement) {
+		return apperror.
+			New(apperror.AgreementNotAmendable).
+			Describe("Only renting agreements that are awaiting payment, renting or overdue can be amended")
+	}
+
+	return nil
+}
+
+// amendedSchedule works out how the installments of an agreement change under
+// new terms. Installments that are already due, paid or billed for utilities
+// are left as they were, so the term cannot be shortened past them, and the new
+// rent applies from the next installment on.
+func (s *serviceImpl) amendedSchedule(agreement *models.Agreements, duration int, paymentPerMonth float64) (*models.AgreementScheduleChanges, *apperror.AppError) {
+	agreementId := agreement.AgreementId.String()
+
+	var installments []models.AgreementInstallments
+	err := s.repo.GetAgreementInstallments(&installments, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get agreement installments", zap.String("id", agreementId), zap.Error(err))
+		return nil, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement installments")
+	}
+
+	today := utils.StartOfDay(time.Now()).Format(time.DateOnly)
+	changes := models.AgreementScheduleChanges{TotalPayment: agreement.DepositAmount}
+
+	last := 0
+	for _, installment := range installments {
+		last = max(last, installment.InstallmentNumber)
+		settled := installment.Status != enums.UnpaidInstallment || installment.DueDate.Format(time.DateOnly) <= today ||
+			len(installment.MeterReadings) > 0
+
+		if installment.InstallmentNumber > duration {
+			if settled {
+				return nil, apperror.
+					New(apperror.InvalidAmendment).
+					Describe(fmt.Sprintf("The term cannot end before installment %v, which is already due, paid or billed for utilities", installment.InstallmentNumber))
+			}
+
+			changes.RemovedNumbers = append(changes.RemovedNumbers, installment.InstallmentNumber)
+			continue
+		}
+
+		if !settled && installment.Amount != paymentPerMonth {
+			installment.Amount = paymentPerMonth
+			changes.Saved = append(changes.Saved, installment)
+		}
+
+		changes.TotalPayment += installment.Amount
+	}
+
+	for number := last + 1; number <= duration; number++ {
+		changes.Saved = append(changes.Saved, models.AgreementInstallments{
+			AgreementId:       agreement.AgreementId,
+			InstallmentNumber: number,
+			DueDate:           installmentDueDate(agreement.AgreementDate, number),
+			Amount:            paymentPerMonth,
+			Status:            enums.UnpaidInstallment,
+		})
+		changes.TotalPayment += paymentPerMonth
+	}
+
+	return &changes, nil
+}
+
+// getAgreementDeposit works out where the deposit of an agreement stands from
+// the deposit payments, the deductions and the refund.
+func (s *serviceImpl) getAgreementDeposit(deposit *models.AgreementDeposits, agreement *models.Agreements) *apperror.AppError {
+	agreementId := agreement.AgreementId.String()
+
+	deposit.AgreementId = agreement.AgreementId
+	deposit.DepositAmount = agreement.DepositAmount
+	err := s.repo.GetPaidAmount(&deposit.HeldAmount, agreementId, enums.DepositPayment)
+	if err != nil {
+		s.logger.Error("Could not get paid deposit", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement deposit")
+	}
+
+	deposit.Deductions = []models.AgreementDepositDeductions{}
+	err = s.repo.GetDepositDeductions(&deposit.Deductions, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get deposit deductions", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement deposit")
+	}
+
+	var refund models.Payments
+	err = s.repo.GetDepositRefund(&refund, agreementId)
+	if err == nil {
+		deposit.Refund = &refund
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Could not get deposit refund", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement deposit")
+	}
+
+	for _, deduction := range deposit.Deductions {
+		switch deduction.Status {
+		case enums.AcceptedDeduction:
+			deposit.DeductedAmount += deduction.Amount
+		case enums.WithdrawnDeduction:
+		default:
+			deposit.PendingAmount += deduction.Amount
+		}
+	}
+	deposit.RefundableAmount = math.Max(deposit.HeldAmount-deposit.DeductedAmount, 0)
+
+	switch {
+	case deposit.Refund != nil:
+		deposit.Status = enums.RefundedDeposit
+	case deposit.HeldAmount == 0 || deposit.HeldAmount < deposit.DepositAmount:
+		deposit.Status = enums.UnpaidDeposit
+	case deposit.DeductedAmount > 0:
+		deposit.Status = enums.PartiallyDeductedDeposit
+	default:
+		deposit.Status = enums.HeldDeposit
+	}
+
+	return nil
+}
+
+// getOwnedDeposit loads the deposit of a renting agreement for its owner, as
+// long as the deposit is held and not refunded yet.
+func (s *serviceImpl) getOwnedDeposit(agreement *models.Agreements, deposit *models.AgreementDeposits, agreementId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getAgreement(agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if role, apperr := agreementRole(agreement, session); apperr != nil {
+		return apperr
+	} else if role != enums.OwnerActor {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner can manage the deposit")
+	}
+
+	return s.checkDepositHeld(agreement, deposit)
+}
+
+func (s *serviceImpl) checkDepositHeld(agreement *models.Agreements, deposit *models.AgreementDeposits) *apperror.AppError {
+	if agreement.AgreementType != enums.AgreementForRent {
+		return apperror.
+			New(apperror.DepositNotHeld).
+			Describe("The deposit of a selling agreement is part of the price")
+	}
+
+	if apperr := s.getAgreementDeposit(deposit, agreement); apperr != nil {
+		return apperr
+	}
+
+	switch deposit.Status {
+	case enums.UnpaidDeposit:
+		return apperror.
+			New(apperror.DepositNotHeld).
+			Describe("The deposit has not been paid yet")
+	case enums.RefundedDeposit:
+		return apperror.
+			New(apperror.DepositAlreadyRefunded).
+			Describe("The deposit has already been refunded")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) getDeduction(deduction *models.AgreementDepositDeductions, agreementId string, deductionId string) *apperror.AppError {
+	if !utils.IsValidUUID(deductionId) {
+		return apperror.
+			New(apperror.InvalidDeductionId).
+			Describe("Invalid deduction id")
+	}
+
+	err := s.repo.GetDepositDeduction(deduction, agreementId, deductionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.DeductionNotFound).
+			Describe("Could not find the specified deduction")
+	} else if err != nil {
+		s.logger.Error("Could not get deposit deduction", zap.String("id", deductionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get deposit deduction")
+	}
+
+	return nil
+}
+
+// getRespondableDeduction loads a pending deduction of a held deposit for the
+// dweller, who is the only one to answer deductions.
+func (s *serviceImpl) getRespondableDeduction(deduction *models.AgreementDepositDeductions, agreementId string, deductionId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if apperr := s.getAgreement(&agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if role, apperr := agreementRole(&agreement, session); apperr != nil {
+		return apperr
+	} else if role != enums.DwellerActor {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the dweller can answer deductions")
+	}
+
+	var deposit models.AgreementDeposits
+	if apperr := s.checkDepositHeld(&agreement, &deposit); apperr != nil {
+		return apperr
+	}
+
+	if apperr := s.getDeduction(deduction, agreementId, deductionId); apperr != nil {
+		return apperr
+	}
+
+	if deduction.Status != enums.PendingDeduction {
+		return apperror.
+			New(apperror.DeductionNotPending).
+			Describe("Deduction has already been answered")
+	}
+
+	return nil
+}
+
+func checkDeductionTransition(from enums.DeductionStatus, to enums.DeductionStatus, role enums.ActorRoles) *apperror.AppError {
+	transitions, ok := deductionTransitions[from]
+	if !ok {
+		return apperror.
+			New(apperror.DeductionNotDisputed).
+			Describe("Only disputed or escalated deductions can be resolved")
+	}
+
+	roles, ok := transitions[to]
+	if !ok {
+		return apperror.
+			New(apperror.InvalidDeduction).
+			Describe(fmt.Sprintf("Deduction could not be moved from %v to %v", from, to))
+	}
+
+	if !slices.Contains(roles, role) {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe(fmt.Sprintf("The %v is not allowed to move a deduction to %v", strings.ToLower(string(role)), to))
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) respondToDeduction(deduction *models.AgreementDepositDeductions, status enums.DeductionStatus, disputeReason string) *apperror.AppError {
+	now := time.Now()
+	deduction.Status = status
+	deduction.DisputeReason = disputeReason
+	deduction.RespondedAt = &now
+
+	err := s.repo.RespondToDepositDeduction(deduction)
+	if errors.Is(err, errDeductionNotPending) {
+		return apperror.
+			New(apperror.DeductionNotPending).
+			Describe("Deduction has already been answered")
+	} else if err != nil {
+		s.logger.Error("Could not respond to deposit deduction", zap.String("id", deduction.DeductionId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not respond to deposit deduction")
+	}
+
+	return nil
+}
+
+// getInspectableAgreement loads a renting agreement whose status allows the
+// given kind of inspection report to be written by one of its parties.
+func (s *serviceImpl) getInspectableAgreement(agreement *models.Agreements, agreementId string, inspectionType enums.InspectionTypes, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getAgreement(agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := agreementRole(agreement, session); apperr != nil {
+		return apperr
+	}
+
+	if agreement.AgreementType != enums.AgreementForRent {
+		return apperror.
+			New(apperror.InspectionNotAllowed).
+			Describe("Inspections are only made for renting agreements")
+	}
+
+	if !slices.Contains(inspectionStatuses[inspectionType], agreement.Status) {
+		return apperror.
+			New(apperror.InspectionNotAllowed).
+			Describe(fmt.Sprintf("The %v report cannot be written while the agreement is %v", inspectionType, agreement.Status))
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) getInspection(inspection *models.AgreementInspections, agreementId string, inspectionId string) *apperror.AppError {
+	if !utils.IsValidUUID(inspectionId) {
+		return apperror.
+			New(apperror.InvalidInspectionId).
+			Describe("Invalid inspection id")
+	}
+
+	err := s.repo.GetAgreementInspection(inspection, agreementId, inspectionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.InspectionNotFound).
+			Describe("Could not find the specified inspection")
+	} else if err != nil {
+		s.logger.Error("Could not get agreement inspection", zap.String("id", inspectionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement inspection")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) getEditableInspection(inspection *models.AgreementInspections, agreementId string, inspectionId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getInspection(inspection, agreementId, inspectionId); apperr != nil {
+		return apperr
+	}
+
+	var agreement models.Agreements
+	if apperr := s.getInspectableAgreement(&agreement, agreementId, inspection.InspectionType, session); apperr != nil {
+		return apperr
+	}
+
+	if inspection.SupersededAt != nil {
+		return apperror.
+			New(apperror.InspectionSuperseded).
+			Describe("Superseded reports cannot be changed")
+	}
+
+	if inspection.OwnerSignedAt != nil || inspection.DwellerSignedAt != nil {
+		return apperror.
+			New(apperror.InspectionSigned).
+			Describe("Signed off reports cannot be changed")
+	}
+
+	return nil
+}
+
+// checkInspectionSignable makes sure a party can sign off a report: it must be
+// the current report, and its author cannot be the only one to sign it off.
+func checkInspectionSignable(inspection *models.AgreementInspections, role enums.ActorRoles, session *models.Sessions) *apperror.AppError {
+	if inspection.SupersededAt != nil {
+		return apperror.
+			New(apperror.InspectionSuperseded).
+			Describe("Superseded reports cannot be signed off")
+	}
+
+	if len(inspection.Items) == 0 {
+		return apperror.
+			New(apperror.InvalidInspection).
+			Describe("Empty reports cannot be signed off")
+	}
+
+	signedAt, counterSignedAt := inspection.OwnerSignedAt, inspection.DwellerSignedAt
+	if role == enums.DwellerActor {
+		signedAt, counterSignedAt = counterSignedAt, signedAt
+	}
+
+	if signedAt != nil {
+		return apperror.
+			New(apperror.InspectionAlreadySigned).
+			Describe("You have already signed off this report")
+	}
+
+	if inspection.CreatedByUserId == session.UserId && counterSignedAt == nil {
+		return apperror.
+			New(apperror.InspectionNotAllowed).
+			Describe("The other party must sign off the report before its author")
+	}
+
+	return nil
+}
+
+// getMeteredAgreement loads a renting agreement for its owner, who is the one
+// billing the utilities.
+func (s *serviceImpl) getMeteredAgreement(agreement *models.Agreements, agreementId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getAgreement(agreement, agreementId); apperr != nil {
+		return apperr
+	}
+
+	if role, apperr := agreementRole(agreement, session); apperr != nil {
+		return apperr
+	} else if role != enums.OwnerActor {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner can bill utilities")
+	}
+
+	if agreement.AgreementType != enums.AgreementForRent {
+		return apperror.
+			New(apperror.MeterReadingNotAllowed).
+			Describe("Utilities are only billed for renting agreements")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) getUtilityRates(rates *models.AgreementUtilityRates, agreementId string) *apperror.AppError {
+	err := s.repo.GetUtilityRates(rates, agreementId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.UtilityRatesNotFound).
+			Describe("The owner has not set utility rates for this agreement")
+	} else if err != nil {
+		s.logger.Error("Could not get utility rates", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get utility rates")
+	}
+
+	return nil
+}
+
+// uploadPhotos processes and uploads photos attached to an agreement as private
+// files, numbering them from firstNumber after the given name. It returns the
+// urls the parties download them from through DownloadAgreementPhoto.
+func (s *serviceImpl) uploadPhotos(agreementId string, name string, firstNumber int, photos []*multipart.FileHeader, invalid *apperror.AppErrorType) ([]string, *apperror.AppError) {
+	urls := []string{}
+	for i, photo := range photos {
+		file, err := photo.Open()
+		if err != nil {
+			return nil, apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not upload photo")
+		}
+
+		ext := filepath.Ext(photo.Filename)
+		ip := utils.NewImageProcessor()
+
+		switch strings.ToLower(ext) {
+		case ".jpg", ".jpeg":
+			err = ip.LoadJPEG(file)
+		case ".png":
+			err = ip.LoadPNG(file)
+		default:
+			file.Close()
+			return nil, apperror.
+				New(invalid).
+				Describe(fmt.Sprintf("App does not support %v extension", ext))
+		}
+		file.Close()
+
+		if err != nil {
+			s.logger.Error("Could not load image", zap.Error(err))
+			return nil, apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not process image")
+		}
+
+		processedFile, err := ip.Save()
+		if err != nil {
+			s.logger.Error("Could not create new image", zap.Error(err))
+			return nil, apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not process image")
+		}
+
+		photoPath := fmt.Sprintf("%v-%v.jpeg", name, firstNumber+i)
+		filename := agreementPhotoKey(agreementId, photoPath)
+		_, err = s.storage.Upload(filename, processedFile, types.ObjectCannedACLPrivate)
+		if err != nil {
+			s.logger.Error("Could not upload photo", zap.String("key", filename), zap.Error(err))
+			return nil, apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not upload photo")
+		}
+
+		urls = append(urls, fmt.Sprintf("/api/v1/agreements/%v/photos/%v", agreementId, photoPath))
+	}
+
+	return urls, nil
+}
+
+func (s *serviceImpl) checkPaidAmount(agreement *models.Agreements, amount float64, description string, paymentTypes ...enums.PaymentTypes) *apperror.AppError {
+	agreementId := agreement.AgreementId.String()
+
+	var paid float64
+	err := s.repo.GetPaidAmount(&paid, agreementId, paymentTypes...)
+	if err != nil {
+		s.logger.Error("Could not get paid amount", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get paid amount")
+	}
+
+	if paid < amount {
+		return apperror.
+			New(apperror.AgreementNotPaid).
+			Describe(fmt.Sprintf("The %v has not been paid yet (%.2f of %.2f)", description, paid, amount))
+	}
+
+	return nil
+}
+
+// requiredPayment is what has to stay paid, and by which payment types, for an
+// agreement to keep its status, as checked by checkTransitionPreconditions when
+// it got there. No payment types means a payment of the given type is not
+// needed by the status.
+func requiredPayment(agreement *models.Agreements, paymentType enums.PaymentTypes) (float64, string, []enums.PaymentTypes) {
+	switch agreement.AgreementType {
+	case enums.AgreementForRent:
+		switch {
+		case paymentType == enums.DepositPayment && slices.Contains([]enums.AgreementStatus{
+			enums.AwaitingPaymentAgreement, enums.RentingAgreement, enums.OverdueAgreement, enums.ArchivedAgreement,
+		}, agreement.Status):
+			return agreement.DepositAmount, "deposit", []enums.PaymentTypes{enums.DepositPayment}
+		case paymentType == enums.RentPayment && slices.Contains([]enums.AgreementStatus{
+			enums.RentingAgreement, enums.OverdueAgreement, enums.ArchivedAgreement,
+		}, agreement.Status):
+			return agreement.PaymentPerMonth, "first month's rent", []enums.PaymentTypes{enums.RentPayment}
+		}
+
+	case enums.AgreementForSell:
+		switch {
+		case paymentType == enums.DepositPayment && agreement.Status == enums.AwaitingPaymentAgreement:
+			return agreement.DepositAmount, "deposit", []enums.PaymentTypes{enums.DepositPayment}
+		case (paymentType == enums.DepositPayment || paymentType == enums.PurchasePayment) && agreement.Status == enums.ArchivedAgreement:
+			return agreement.TotalPayment, "full price", []enums.PaymentTypes{enums.DepositPayment, enums.PurchasePayment}
+		}
+	}
+
+	return 0, "", nil
+}
+
+// prepareAgreement checks a new agreement against its property and fills in
+// what is up to the server rather than the client: the initial status and the
+// total payment.
+func (s *serviceImpl) prepareAgreement(agreement *models.CreatingAgreements) *apperror.AppError {
+	if agreement.DwellerUserId == agreement.OwnerUserId {
+		return apperror.
+			New(apperror.BadRequest).
+			Describe("The owner cannot be the dweller of their own agreement")
+	}
+
+	if agreement.DepositAmount < 0 || agreement.PaymentPerMonth < 0 || agreement.PaymentDuration < 0 {
+		return apperror.
+			New(apperror.BadRequest).
+			Describe("Payment amounts and duration cannot be negative")
+	}
+
+	var property models.AgreementProperties
+	err := s.repo.GetAgreementProperty(&property, agreement.PropertyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyNotFound).
+			Describe("Could not find the specified property")
+	} else if err != nil {
+		s.logger.Error("Could not get agreement property", zap.String("id", agreement.PropertyId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get agreement property")
+	}
+
+	if property.OwnerId != agreement.OwnerUserId {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner of the property can create an agreement for it")
+	}
+
+	switch agreement.AgreementType {
+	case enums.AgreementForRent:
+		if property.PricePerMonth == nil {
+			return apperror.
+				New(apperror.InvalidAgreementType).
+				Describe("The property is not for rent")
+		} else if *property.IsOccupied {
+			return apperror.
+				New(apperror.PropertyNotAvailable).
+				Describe("The property is already occupied")
+		} else if agreement.PaymentPerMonth == 0 || agreement.PaymentDuration == 0 {
+			return apperror.
+				New(apperror.BadRequest).
+				Describe("Renting agreements need a payment per month and a payment duration")
+		}
+
+		agreement.TotalPayment = agreement.DepositAmount + agreement.PaymentPerMonth*float64(agreement.PaymentDuration)
+
+	case enums.AgreementForSell:
+		if property.SellingPrice == nil {
+			return apperror.
+				New(apperror.InvalidAgreementType).
+				Describe("The property is not for sale")
+		} else if *property.IsSold {
+			return apperror.
+				New(apperror.PropertyNotAvailable).
+				Describe("The property has already been sold")
+		} else if agreement.DepositAmount > *property.SellingPrice {
+			return apperror.
+				New(apperror.BadRequest).
+				Describe("The deposit cannot be more than the selling price")
+		}
+
+		// the deposit is part of the price and there is nothing to pay monthly
+		agreement.PaymentPerMonth = 0
+		agreement.PaymentDuration = 0
+		agreement.TotalPayment = *property.SellingPrice
+
+	default:
+		return apperror.
+			New(apperror.InvalidAgreementType).
+			Describe("Agreement type can only be SELLING or RENTING")
+	}
+
+	var active int64
+	err = s.repo.CountActiveAgreements(&active, agreement.PropertyId)
+	if err != nil {
+		s.logger.Error("Could not count active agreements", zap.String("id", agreement.PropertyId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not count active agreements")
+	} else if active > 0 {
+		return apperror.
+			New(apperror.PropertyNotAvailable).
+			Describe("The property already has an ongoing agreement")
+	}
+
+	agreement.Status = enums.AwaitingDepositAgreement
+
+	return nil
+}
+
+// installmentSchedule splits a renting agreement into one installment per month
+// of its duration, the first one due on the agreement date. When the agreement
+// starts late in the month, installments of shorter months fall due on their
+// last day instead of spilling over into the next month.
+func installmentSchedule(agreement *models.CreatingAgreements) []models.AgreementInstallments {
+	installments := make([]models.AgreementInstallments, 0, agreement.PaymentDuration)
+	for i := 0; i < agreement.PaymentDuration; i++ {
+		installments = append(installments, models.AgreementInstallments{
+			InstallmentNumber: i + 1,
+			DueDate:           installmentDueDate(agreement.AgreementDate, i+1),
+			Amount:            agreement.PaymentPerMonth,
+			Status:            enums.UnpaidInstallment,
+		})
+	}
+
+	return installments
+}
+
+// installmentDueDate is the day the given installment of an agreement starting
+// on agreementDate is due, moved to the last day of shorter months.
+func installmentDueDate(agreementDate time.Time, installmentNumber int) time.Time {
+	start := agreementDate.In(utils.LocalTimezone)
+	month := time.Date(start.Year(), start.Month()+time.Month(installmentNumber-1), 1, 0, 0, 0, 0, time.UTC)
+	day := min(start.Day(), month.AddDate(0, 1, -1).Day())
+
+	return time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// agreementMonths counts the months from the start of an agreement to endDate.
+// It reports false unless endDate falls on the day the term would end.
+func agreementMonths(agreementDate time.Time, endDate time.Time) (int, bool) {
+	start := agreementDate.In(utils.LocalTimezone)
+	end := endDate.In(utils.LocalTimezone)
+
+	// adding months to the end of a long month spills over into the next one
+	months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+	for _, candidate := range []int{months, months - 1} {
+		if candidate > 0 && utils.StartOfDay(start.AddDate(0, candidate, 0)).Equal(utils.StartOfDay(end)) {
+			return candidate, true
+		}
+	}
+
+	return months, false
+}
+
+func isAmendable(agreement *models.Agreements) bool {
+	return agreement.AgreementType == enums.AgreementForRent && slices.Contains([]enums.AgreementStatus{
+		enums.AwaitingPaymentAgreement, enums.RentingAgreement, enums.OverdueAgreement,
+	}, agreement.Status)
+}
+
+// propertyFlags tells how moving an agreement to status changes its property:
+// a renting agreement occupies the property while it runs and frees it once it
+// is archived or cancelled, and a selling agreement sells the property when it
+// completes. It returns nil when the property is left as it is.
+func propertyFlags(agreement *models.Agreements, status enums.AgreementStatus) *models.AgreementPropertyFlags {
+	flags := models.AgreementPropertyFlags{
+		PropertyId: agreement.PropertyId,
+	}
+
+	switch agreement.AgreementType {
+	case enums.AgreementForRent:
+		switch status {
+		case enums.RentingAgreement:
+			occupied := true
+			flags.IsOccupied = &occupied
+		case enums.ArchivedAgreement, enums.CancelledAgreement:
+			// agreements that never started renting have not occupied the property
+			if agreement.Status != enums.RentingAgreement && agreement.Status != enums.OverdueAgreement {
+				return nil
+			}
+
+			occupied := false
+			flags.IsOccupied = &occupied
+		default:
+			return nil
+		}
+
+	case enums.AgreementForSell:
+		if agreement.Status != enums.AwaitingPaymentAgreement || status != enums.ArchivedAgreement {
+			return nil
+		}
+
+		sold := true
+		flags.IsSold = &sold
+
+	default:
+		return nil
+	}
+
+	return &flags
+}
+
+func agreementPhotoKey(agreementId string, photoPath string) string {
+	return fmt.Sprintf("agreements/%v/%v", agreementId, photoPath)
+}
+
+func agreementRole(agreement *models.Agreements, session *models.Sessions) (enums.ActorRoles, *apperror.AppError) {
+	switch session.UserId {
+	case agreement.OwnerUserId:
+		return enums.OwnerActor, nil
+	case agreement.DwellerUserId:
+		return enums.DwellerActor, nil
+	}
+
+	return "", apperror.
+		New(apperror.Forbidden).
+		Describe("Only the owner or the dweller can access this agreement")
+}
+
+func (s *serviceImpl) renderAgreementContract(agreement *models.AgreementDetails, language enums.ContractLanguages, version int) ([]byte, *apperror.AppError) {
+	// Helvetica has no Thai glyphs, so Thai contracts need a font to embed
+	var font []byte
+	if s.cfg.ContractFontPath != "" {
+		file, err := os.ReadFile(s.cfg.ContractFontPath)
+		if err != nil {
+			s.logger.Error("Could not read contract font", zap.String("path", s.cfg.ContractFontPath), zap.Error(err))
+			return nil, apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not generate contract")
+		}
+		font = file
+	} else if language == enums.ThaiContract {
+		s.logger.Error("Could not generate Thai contract without a contract font")
+		return nil, apperror.
+			New(apperror.ServiceUnavailable).
+			Describe("Thai contracts are not available at the moment")
+	}
+
+	endDate := agreement.AgreementDate.AddDate(0, agreement.PaymentDuration, 0)
+	contractTemplate := models.AgreementContractTemplates{
+		Language:        language,
+		Agreement:       agreement,
+		IsRenting:       agreement.AgreementType == enums.AgreementForRent,
+		Address:         contractAddress(&agreement.Property),
+		AgreementDate:   contractDate(agreement.AgreementDate, language),
+		EndDate:         contractDate(endDate, language),
+		DepositAmount:   utils.FormatAmount(agreement.DepositAmount),
+		PaymentPerMonth: utils.FormatAmount(agreement.PaymentPerMonth),
+		PaymentDuration: agreement.PaymentDuration,
+		TotalPayment:    utils.FormatAmount(agreement.TotalPayment),
+		Version:         version,
+		GeneratedAt:     contractDate(time.Now(), language),
+	}
+
+	t, err := template.ParseFiles(contractTemplate.Path())
+	if err != nil {
+		s.logger.Error("Could not parse contract template", zap.Error(err))
+		return nil, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not generate contract")
+	}
+
+	var text bytes.Buffer
+	if err := t.Execute(&text, contractTemplate); err != nil {
+		s.logger.Error("Could not execute contract template", zap.Error(err))
+		return nil, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not generate contract")
+	}
+
+	doc, err := utils.NewPDFDocument(font)
+	if err != nil {
+		s.logger.Error("Could not load contract font", zap.String("path", s.cfg.ContractFontPath), zap.Error(err))
+		return nil, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not generate contract")
+	}
+
+	doc.WriteText(text.String())
+	document, err := doc.Bytes()
+	if err != nil {
+		s.logger.Error("Could not write contract document", zap.Error(err))
+		return nil, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not generate contract")
+	}
+
+	return document, nil
+}
+
+func contractAddress(property *models.PropertyAgreementDetails) string {
+	parts := []string{}
+	for _, part := range []string{property.Address, property.Alley, property.Street, property.SubDistrict, property.District, property.Province, property.PostalCode} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// contractDate formats a date the way it is written in contracts. Thai
+// contracts use the Buddhist calendar, which is 543 years ahead.
+func contractDate(t time.Time, language enums.ContractLanguages) string {
+	t = t.In(utils.LocalTimezone)
+	if language == enums.ThaiContract {
+		return fmt.Sprintf("%d %v %d", t.Day(), thaiMonths[t.Month()-1], t.Year()+543)
+	}
+
+	return t.Format("2 January 2006")
+}
+
+// isFullySigned reports whether both the owner and the dweller have signed.
+func isFullySigned(signatures []models.AgreementSignatures) bool {
+	signed := map[enums.ActorRoles]bool{}
+	for _, signature := range signatures {
+		signed[signature.Role] = true
+	}
+
+	return signed[enums.OwnerActor] && signed[enums.DwellerActor]
+}
+
+// inspectionItems validates the items of an inspection report, which must be
+// unique by room and name, and keeps them in the order they were given.
+func inspectionItems(inspectionId uuid.UUID, creating []models.CreatingInspectionItems) ([]models.AgreementInspectionItems, *apperror.AppError) {
+	if len(creating) == 0 {
+		return nil, apperror.
+			New(apperror.InvalidInspection).
+			Describe("A report needs at least one item")
+	}
+
+	seen := map[string]bool{}
+	items := []models.AgreementInspectionItems{}
+	for i, item := range creating {
+		room := strings.TrimSpace(item.Room)
+		name := strings.TrimSpace(item.Item)
+		if room == "" || name == "" || len(room) > 50 || len(name) > 50 {
+			return nil, apperror.
+				New(apperror.InvalidInspection).
+				Describe("Every item needs a room and a name of at most 50 characters")
+		}
+
+		if _, ok := enums.ItemConditionsRank[item.Condition]; !ok {
+			return nil, apperror.
+				New(apperror.InvalidInspection).
+				Describe(fmt.Sprintf("Invalid condition %v of %v in %v", item.Condition, name, room))
+		}
+
+		key := inspectionItemKey(room, name)
+		if seen[key] {
+			return nil, apperror.
+				New(apperror.InvalidInspection).
+				Describe(fmt.Sprintf("%v in %v is listed more than once", name, room))
+		}
+		seen[key] = true
+
+		items = append(items, models.AgreementInspectionItems{
+			ItemId:       uuid.New(),
+			InspectionId: inspectionId,
+			Room:         room,
+			Item:         name,
+			Condition:    item.Condition,
+			Notes:        strings.TrimSpace(item.Notes),
+			ItemOrder:    i,
+		})
+	}
+
+	return items, nil
+}
+
+// compareInspectionItems lines up the items of the move-in and move-out reports
+// in the order of the move-in report, followed by the items that only appear at
+// move-out.
+func compareInspectionItems(moveIn []models.AgreementInspectionItems, moveOut []models.AgreementInspectionItems) []models.InspectionComparisonItems {
+	moveOutItems := map[string]*models.AgreementInspectionItems{}
+	for i := range moveOut {
+		moveOutItems[inspectionItemKey(moveOut[i].Room, moveOut[i].Item)] = &moveOut[i]
+	}
+
+	compared := map[string]bool{}
+	comparisons := []models.InspectionComparisonItems{}
+	for i := range moveIn {
+		key := inspectionItemKey(moveIn[i].Room, moveIn[i].Item)
+		compared[key] = true
+
+		comparison := models.InspectionComparisonItems{
+			Room:    moveIn[i].Room,
+			Item:    moveIn[i].Item,
+			MoveIn:  &moveIn[i],
+			MoveOut: moveOutItems[key],
+		}
+
+		if comparison.MoveOut == nil {
+			comparison.Changed = true
+		} else {
+			comparison.Changed = comparison.MoveIn.Condition != comparison.MoveOut.Condition || comparison.MoveIn.Notes != comparison.MoveOut.Notes
+			comparison.Worsened = enums.ItemConditionsRank[comparison.MoveOut.Condition] > enums.ItemConditionsRank[comparison.MoveIn.Condition]
+		}
+
+		comparisons = append(comparisons, comparison)
+	}
+
+	for i := range moveOut {
+		if compared[inspectionItemKey(moveOut[i].Room, moveOut[i].Item)] {
+			continue
+		}
+
+		comparisons = append(comparisons, models.InspectionComparisonItems{
+			Room:    moveOut[i].Room,
+			Item:    moveOut[i].Item,
+			MoveOut: &moveOut[i],
+			Changed: true,
+		})
+	}
+
+	return comparisons
+}
+
+func inspectionItemKey(room string, item string) string {
+	return strings.ToLower(room) + "\x00" + strings.ToLower(item)
+}
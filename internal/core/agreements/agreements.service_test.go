@@ -0,0 +1,977 @@
+package agreements
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/appointments"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/brain-flowing-company/pprp-backend/storage"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func localDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, utils.LocalTimezone)
+}
+
+func TestUpdateAgreementStatus(t *testing.T) {
+	owner, dweller := uuid.New(), uuid.New()
+	signedBy := func(roles ...enums.ActorRoles) []models.AgreementSignatures {
+		signatures := []models.AgreementSignatures{}
+		for _, role := range roles {
+			signatures = append(signatures, models.AgreementSignatures{Role: role})
+		}
+		return signatures
+	}
+
+	tests := []struct {
+		name          string
+		agreementType enums.AgreementTypes
+		from          enums.AgreementStatus
+		to            enums.AgreementStatus
+		session       models.Sessions
+		signatures    []models.AgreementSignatures
+		paid          float64
+		wantErr       *apperror.AppErrorType
+		wantOccupied  *bool
+	}{
+		{name: "deposit awaited until the contract is signed", from: enums.AwaitingDepositAgreement, to: enums.AwaitingPaymentAgreement,
+			session: models.Sessions{UserId: owner}, paid: 30000, wantErr: apperror.AgreementNotSigned},
+		{name: "deposit awaited until both parties sign", from: enums.AwaitingDepositAgreement, to: enums.AwaitingPaymentAgreement,
+			session: models.Sessions{UserId: owner}, signatures: signedBy(enums.OwnerActor), paid: 30000, wantErr: apperror.AgreementNotSigned},
+		{name: "deposit awaited until it is paid", from: enums.AwaitingDepositAgreement, to: enums.AwaitingPaymentAgreement,
+			session: models.Sessions{UserId: owner}, signatures: signedBy(enums.OwnerActor, enums.DwellerActor), paid: 29999, wantErr: apperror.AgreementNotPaid},
+		{name: "deposit paid and contract signed", from: enums.AwaitingDepositAgreement, to: enums.AwaitingPaymentAgreement,
+			session: models.Sessions{UserId: owner}, signatures: signedBy(enums.DwellerActor, enums.OwnerActor), paid: 30000},
+		{name: "dweller cannot confirm the deposit", from: enums.AwaitingDepositAgreement, to: enums.AwaitingPaymentAgreement,
+			session: models.Sessions{UserId: dweller}, signatures: signedBy(enums.OwnerActor, enums.DwellerActor), paid: 30000, wantErr: apperror.Forbidden},
+		{name: "outsider cannot move the agreement", from: enums.AwaitingDepositAgreement, to: enums.CancelledAgreement,
+			session: models.Sessions{UserId: uuid.New()}, wantErr: apperror.Forbidden},
+		{name: "renting awaits the first rent", from: enums.AwaitingPaymentAgreement, to: enums.RentingAgreement,
+			session: models.Sessions{UserId: owner}, paid: 14999, wantErr: apperror.AgreementNotPaid},
+		{name: "renting occupies the property", from: enums.AwaitingPaymentAgreement, to: enums.RentingAgreement,
+			session: models.Sessions{UserId: owner}, paid: 15000, wantOccupied: boolPointer(true)},
+		{name: "selling agreements do not rent", agreementType: enums.AgreementForSell, from: enums.AwaitingPaymentAgreement, to: enums.RentingAgreement,
+			session: models.Sessions{UserId: owner}, paid: 15000, wantErr: apperror.InvalidAgreementTransition},
+		{name: "renting cannot be cancelled", from: enums.RentingAgreement, to: enums.CancelledAgreement,
+			session: models.Sessions{UserId: owner}, wantErr: apperror.InvalidAgreementTransition},
+		{name: "renting cannot be archived before it ends", from: enums.RentingAgreement, to: enums.ArchivedAgreement,
+			session: models.Sessions{UserId: owner}, wantErr: apperror.InvalidAgreementTransition},
+		{name: "cancelling an overdue agreement frees the property", from: enums.OverdueAgreement, to: enums.CancelledAgreement,
+			session: models.Sessions{UserId: owner}, wantOccupied: boolPointer(false)},
+		{name: "unknown status", from: enums.RentingAgreement, to: "EVICTED",
+			session: models.Sessions{UserId: owner}, wantErr: apperror.InvalidAgreementStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agreementType := tt.agreementType
+			if agreementType == "" {
+				agreementType = enums.AgreementForRent
+			}
+
+			repo := &fakeRepository{
+				agreement: models.Agreements{
+					AgreementId:     uuid.New(),
+					AgreementType:   agreementType,
+					Status:          tt.from,
+					OwnerUserId:     owner,
+					DwellerUserId:   dweller,
+					AgreementDate:   time.Now(),
+					PaymentDuration: 12,
+					DepositAmount:   30000,
+					PaymentPerMonth: 15000,
+				},
+				paidAmount: tt.paid,
+				signatures: tt.signatures,
+			}
+			s := &serviceImpl{logger: zap.NewNop(), repo: repo, appointmentService: &fakeAppointmentService{}}
+
+			updating := models.UpdatingAgreementStatus{Status: tt.to, CancelledMessage: "Moving out"}
+			apperr := s.UpdateAgreementStatus(&updating, repo.agreement.AgreementId.String(), &tt.session)
+			if tt.wantErr == nil && apperr != nil {
+				t.Fatalf("UpdateAgreementStatus() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil {
+				if apperr == nil || apperr.Name() != tt.wantErr.Name {
+					t.Fatalf("UpdateAgreementStatus() error = %v, want %v", apperr, tt.wantErr.Name)
+				}
+				if repo.history != nil {
+					t.Errorf("refused transition wrote history %+v", repo.history)
+				}
+				return
+			}
+
+			if repo.history == nil || repo.history.FromStatus != tt.from || repo.history.ActorUserId == nil || *repo.history.ActorUserId != tt.session.UserId {
+				t.Errorf("history = %+v, want from %v by %v", repo.history, tt.from, tt.session.UserId)
+			}
+
+			if tt.wantOccupied == nil && repo.flags != nil {
+				t.Errorf("flags = %+v, want none", repo.flags)
+			} else if tt.wantOccupied != nil && (repo.flags == nil || repo.flags.IsOccupied == nil || *repo.flags.IsOccupied != *tt.wantOccupied) {
+				t.Errorf("flags = %+v, want occupied %v", repo.flags, *tt.wantOccupied)
+			}
+		})
+	}
+}
+
+func TestDeleteAgreement(t *testing.T) {
+	owner, dweller := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		status  enums.AgreementStatus
+		session models.Sessions
+		wantErr *apperror.AppErrorType
+	}{
+		{name: "owner deletes an awaiting agreement", status: enums.AwaitingDepositAgreement, session: models.Sessions{UserId: owner}},
+		{name: "owner deletes an archived agreement", status: enums.ArchivedAgreement, session: models.Sessions{UserId: owner}},
+		{name: "admin deletes a cancelled agreement", status: enums.CancelledAgreement, session: models.Sessions{UserId: uuid.New(), IsAdmin: true}},
+		{name: "dweller cannot delete", status: enums.AwaitingDepositAgreement, session: models.Sessions{UserId: dweller}, wantErr: apperror.Forbidden},
+		{name: "outsider cannot delete", status: enums.AwaitingDepositAgreement, session: models.Sessions{UserId: uuid.New()}, wantErr: apperror.Forbidden},
+		{name: "renting agreement occupies the property", status: enums.RentingAgreement, session: models.Sessions{UserId: owner}, wantErr: apperror.AgreementNotDeletable},
+		{name: "overdue agreement occupies the property", status: enums.OverdueAgreement, session: models.Sessions{UserId: owner, IsAdmin: true}, wantErr: apperror.AgreementNotDeletable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{
+				agreement: models.Agreements{
+					AgreementId:   uuid.New(),
+					AgreementType: enums.AgreementForRent,
+					Status:        tt.status,
+					OwnerUserId:   owner,
+					DwellerUserId: dweller,
+				},
+			}
+			s := &serviceImpl{logger: zap.NewNop(), repo: repo}
+
+			apperr := s.DeleteAgreement(repo.agreement.AgreementId.String(), &tt.session)
+			if tt.wantErr == nil && apperr != nil {
+				t.Fatalf("DeleteAgreement() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Fatalf("DeleteAgreement() error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+
+			if repo.deleted != (tt.wantErr == nil) {
+				t.Errorf("deleted = %v, want %v", repo.deleted, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestInstallmentSchedule(t *testing.T) {
+	tests := []struct {
+		name          string
+		agreementDate time.Time
+		duration      int
+		want          []string
+	}{
+		{
+			name:          "no installments",
+			agreementDate: localDate(2024, time.January, 10),
+			duration:      0,
+			want:          []string{},
+		},
+		{
+			name:          "same day every month",
+			agreementDate: localDate(2024, time.January, 10),
+			duration:      3,
+			want:          []string{"2024-01-10", "2024-02-10", "2024-03-10"},
+		},
+		{
+			name:          "end of month falls back in shorter months",
+			agreementDate: localDate(2024, time.January, 31),
+			duration:      4,
+			want:          []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"},
+		},
+		{
+			name:          "non-leap february",
+			agreementDate: localDate(2023, time.January, 30),
+			duration:      2,
+			want:          []string{"2023-01-30", "2023-02-28"},
+		},
+		{
+			name:          "across the year",
+			agreementDate: localDate(2024, time.November, 15),
+			duration:      3,
+			want:          []string{"2024-11-15", "2024-12-15", "2025-01-15"},
+		},
+		{
+			name:          "midnight in utc is still the local day",
+			agreementDate: time.Date(2024, time.January, 9, 18, 0, 0, 0, time.UTC),
+			duration:      1,
+			want:          []string{"2024-01-10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installments := installmentSchedule(&models.CreatingAgreements{
+				AgreementDate:   tt.agreementDate,
+				PaymentDuration: tt.duration,
+				PaymentPerMonth: 12000,
+			})
+
+			got := []string{}
+			for i, installment := range installments {
+				got = append(got, installment.DueDate.Format(time.DateOnly))
+
+				if installment.InstallmentNumber != i+1 || installment.Amount != 12000 || installment.Status != enums.UnpaidInstallment {
+					t.Errorf("got installment %+v", installment)
+				}
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got due dates %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLateFee(t *testing.T) {
+	tests := []struct {
+		name        string
+		feeType     string
+		rate        float64
+		cap         float64
+		amount      float64
+		chargedDays int
+		want        float64
+	}{
+		{name: "flat per day", feeType: "FLAT", rate: 50, amount: 12000, chargedDays: 3, want: 150},
+		{name: "percentage per day", feeType: "PERCENTAGE", rate: 0.5, amount: 12000, chargedDays: 3, want: 180},
+		{name: "percentage is rounded to satang", feeType: "PERCENTAGE", rate: 1, amount: 333.333, chargedDays: 1, want: 3.33},
+		{name: "capped", feeType: "FLAT", rate: 50, cap: 500, amount: 12000, chargedDays: 30, want: 500},
+		{name: "cap above the fee", feeType: "FLAT", rate: 50, cap: 500, amount: 12000, chargedDays: 2, want: 100},
+		{name: "no charged days", feeType: "FLAT", rate: 50, amount: 12000, chargedDays: 0, want: 0},
+		{name: "negative rate charges nothing", feeType: "FLAT", rate: -50, amount: 12000, chargedDays: 3, want: 0},
+		{name: "unknown type charges nothing", feeType: "DAILY", rate: 50, amount: 12000, chargedDays: 3, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &serviceImpl{
+				logger: zap.NewNop(),
+				cfg:    &config.Config{LateFeeType: tt.feeType, LateFeeRate: tt.rate, LateFeeCap: tt.cap},
+			}
+
+			if got := s.lateFee(tt.amount, tt.chargedDays); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPropertyFlags(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name          string
+		agreementType enums.AgreementTypes
+		from          enums.AgreementStatus
+		to            enums.AgreementStatus
+		wantNil       bool
+		wantSold      *bool
+		wantOccupied  *bool
+	}{
+		{name: "renting occupies", agreementType: enums.AgreementForRent, from: enums.AwaitingPaymentAgreement, to: enums.RentingAgreement, wantOccupied: &yes},
+		{name: "resuming an overdue agreement occupies", agreementType: enums.AgreementForRent, from: enums.OverdueAgreement, to: enums.RentingAgreement, wantOccupied: &yes},
+		{name: "archiving a renting agreement frees", agreementType: enums.AgreementForRent, from: enums.RentingAgreement, to: enums.ArchivedAgreement, wantOccupied: &no},
+		{name: "cancelling an overdue agreement frees", agreementType: enums.AgreementForRent, from: enums.OverdueAgreement, to: enums.CancelledAgreement, wantOccupied: &no},
+		{name: "cancelling before renting leaves the property", agreementType: enums.AgreementForRent, from: enums.AwaitingDepositAgreement, to: enums.CancelledAgreement, wantNil: true},
+		{name: "archiving a cancelled agreement leaves the property", agreementType: enums.AgreementForRent, from: enums.CancelledAgreement, to: enums.ArchivedAgreement, wantNil: true},
+		{name: "going overdue leaves the property", agreementType: enums.AgreementForRent, from: enums.RentingAgreement, to: enums.OverdueAgreement, wantNil: true},
+		{name: "completed sale sells", agreementType: enums.AgreementForSell, from: enums.AwaitingPaymentAgreement, to: enums.ArchivedAgreement, wantSold: &yes},
+		{name: "cancelled sale leaves the property", agreementType: enums.AgreementForSell, from: enums.AwaitingPaymentAgreement, to: enums.CancelledAgreement, wantNil: true},
+		{name: "archiving a cancelled sale leaves the property", agreementType: enums.AgreementForSell, from: enums.CancelledAgreement, to: enums.ArchivedAgreement, wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agreement := &models.Agreements{AgreementType: tt.agreementType, Status: tt.from}
+			flags := propertyFlags(agreement, tt.to)
+
+			if tt.wantNil {
+				if flags != nil {
+					t.Fatalf("got %+v, want nil", flags)
+				}
+				return
+			}
+
+			if flags == nil {
+				t.Fatal("got nil flags")
+			}
+			if !equalFlag(flags.IsSold, tt.wantSold) {
+				t.Errorf("got is_sold %v, want %v", flags.IsSold, tt.wantSold)
+			}
+			if !equalFlag(flags.IsOccupied, tt.wantOccupied) {
+				t.Errorf("got is_occupied %v, want %v", flags.IsOccupied, tt.wantOccupied)
+			}
+		})
+	}
+}
+
+func equalFlag(got *bool, want *bool) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return *got == *want
+}
+
+type fakeRepository struct {
+	Repository
+	agreement    models.Agreements
+	installments []models.AgreementInstallments
+	paidAmount   float64
+	deductions   []models.AgreementDepositDeductions
+	refund       *models.Payments
+	signatures   []models.AgreementSignatures
+	history      *models.AgreementStatusHistories
+	flags        *models.AgreementPropertyFlags
+	deleted      bool
+}
+
+func (repo *fakeRepository) GetSignedAgreementContract(contract *models.AgreementContracts, agreementId string) error {
+	if len(repo.signatures) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	contract.AgreementId = repo.agreement.AgreementId
+	return nil
+}
+
+func (repo *fakeRepository) GetContractSignatures(signatures *[]models.AgreementSignatures, contractId uuid.UUID) error {
+	*signatures = slices.Clone(repo.signatures)
+	return nil
+}
+
+func (repo *fakeRepository) UpdateAgreementStatus(updatingAgreement *models.UpdatingAgreementStatus, agreementId string, history *models.AgreementStatusHistories, flags *models.AgreementPropertyFlags) error {
+	repo.history, repo.flags = history, flags
+	return nil
+}
+
+func (repo *fakeRepository) GetUtilityRates(rates *models.AgreementUtilityRates, agreementId string) error {
+	*rates = models.AgreementUtilityRates{WaterRate: 18, ElectricityRate: 7}
+	return nil
+}
+
+func (repo *fakeRepository) GetLatestMeterReading(reading *models.AgreementMeterReadings, agreementId string, utilityType enums.UtilityTypes) error {
+	return gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) DeleteAgreement(agreementId string) error {
+	repo.deleted = true
+	return nil
+}
+
+// fakeAppointmentService leaves the appointments of a property alone.
+type fakeAppointmentService struct {
+	appointments.Service
+}
+
+func (s *fakeAppointmentService) CancelUpcomingAppointments(propertyId string, message string) {}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
+
+func (repo *fakeRepository) GetAgreement(agreement *models.Agreements, agreementId string) error {
+	if repo.agreement.AgreementId.String() != agreementId {
+		return gorm.ErrRecordNotFound
+	}
+
+	*agreement = repo.agreement
+	return nil
+}
+
+type fakeStorage struct {
+	storage.Storage
+	files map[string]string
+}
+
+func (s *fakeStorage) Download(key string) (io.ReadCloser, error) {
+	content, ok := s.files[key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (repo *fakeRepository) GetAgreementInstallments(installments *[]models.AgreementInstallments, agreementId string) error {
+	*installments = slices.Clone(repo.installments)
+	return nil
+}
+
+func (repo *fakeRepository) GetPaidAmount(amount *float64, agreementId string, paymentTypes ...enums.PaymentTypes) error {
+	*amount = repo.paidAmount
+	return nil
+}
+
+func (repo *fakeRepository) GetDepositDeductions(deductions *[]models.AgreementDepositDeductions, agreementId string) error {
+	*deductions = slices.Clone(repo.deductions)
+	return nil
+}
+
+func (repo *fakeRepository) GetDepositRefund(refund *models.Payments, agreementId string) error {
+	if repo.refund == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	*refund = *repo.refund
+	return nil
+}
+
+func TestInstallmentDueDate(t *testing.T) {
+	tests := []struct {
+		name              string
+		agreementDate     time.Time
+		installmentNumber int
+		want              string
+	}{
+		{"first installment", localDate(2024, time.March, 15), 1, "2024-03-15"},
+		{"next month", localDate(2024, time.March, 15), 2, "2024-04-15"},
+		{"next year", localDate(2024, time.November, 15), 3, "2025-01-15"},
+		{"short month", localDate(2024, time.January, 31), 2, "2024-02-29"},
+		{"back to long month", localDate(2024, time.January, 31), 3, "2024-03-31"},
+		{"thirty day month", localDate(2024, time.March, 31), 2, "2024-04-30"},
+		{"local midnight", time.Date(2024, time.March, 14, 18, 0, 0, 0, time.UTC), 1, "2024-03-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := installmentDueDate(tt.agreementDate, tt.installmentNumber).Format(time.DateOnly)
+			if got != tt.want {
+				t.Errorf("installmentDueDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgreementMonths(t *testing.T) {
+	tests := []struct {
+		name          string
+		agreementDate time.Time
+		endDate       time.Time
+		want          int
+		wantOk        bool
+	}{
+		{"one year", localDate(2024, time.March, 15), localDate(2025, time.March, 15), 12, true},
+		{"one month", localDate(2024, time.March, 15), localDate(2024, time.April, 15), 1, true},
+		{"end of long month", localDate(2024, time.January, 31), localDate(2024, time.March, 2), 1, true},
+		{"off by a day", localDate(2024, time.March, 15), localDate(2024, time.April, 16), 1, false},
+		{"same day", localDate(2024, time.March, 15), localDate(2024, time.March, 15), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := agreementMonths(tt.agreementDate, tt.endDate)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("agreementMonths() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestAmendedSchedule(t *testing.T) {
+	today := utils.StartOfDay(time.Now())
+	dueOn := func(days int) time.Time {
+		year, month, day := today.AddDate(0, 0, days).Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	agreement := models.Agreements{
+		AgreementId:   uuid.New(),
+		AgreementType: enums.AgreementForRent,
+		AgreementDate: today.AddDate(0, -1, -1),
+		DepositAmount: 1000,
+	}
+	installments := []models.AgreementInstallments{
+		{InstallmentNumber: 1, DueDate: dueOn(-32), Amount: 5000, Status: enums.PaidInstallment},
+		{InstallmentNumber: 2, DueDate: dueOn(-1), Amount: 5000, Status: enums.UnpaidInstallment},
+		{InstallmentNumber: 3, DueDate: dueOn(29), Amount: 5000, Status: enums.UnpaidInstallment},
+		{InstallmentNumber: 4, DueDate: dueOn(59), Amount: 5000, Status: enums.UnpaidInstallment},
+	}
+
+	tests := []struct {
+		name            string
+		duration        int
+		paymentPerMonth float64
+		metered         int
+		wantErr         bool
+		wantTotal       float64
+		wantSaved       []int
+		wantRemoved     []int
+	}{
+		{"same terms", 4, 5000, 0, false, 21000, nil, nil},
+		{"new rent from the next installment", 4, 6000, 0, false, 23000, []int{3, 4}, nil},
+		{"shorter term", 3, 5000, 0, false, 16000, nil, []int{4}},
+		{"shorter term with new rent", 3, 6000, 0, false, 17000, []int{3}, []int{4}},
+		{"longer term", 6, 5000, 0, false, 31000, []int{5, 6}, nil},
+		{"term ends before a due installment", 1, 5000, 0, true, 0, nil, nil},
+		{"new rent after a metered installment", 4, 6000, 3, false, 22000, []int{4}, nil},
+		{"term ends before a metered installment", 2, 5000, 3, true, 0, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installments := slices.Clone(installments)
+			for i := range installments {
+				if installments[i].InstallmentNumber == tt.metered {
+					installments[i].MeterReadings = []models.AgreementMeterReadings{{UtilityType: enums.WaterUtility}}
+				}
+			}
+			s := &serviceImpl{logger: zap.NewNop(), repo: &fakeRepository{installments: installments}}
+
+			changes, apperr := s.amendedSchedule(&agreement, tt.duration, tt.paymentPerMonth)
+			if (apperr != nil) != tt.wantErr {
+				t.Fatalf("amendedSchedule() error = %v, wantErr %v", apperr, tt.wantErr)
+			} else if apperr != nil {
+				return
+			}
+
+			var saved []int
+			for _, installment := range changes.Saved {
+				saved = append(saved, installment.InstallmentNumber)
+				if installment.Amount != tt.paymentPerMonth {
+					t.Errorf("installment %v amount = %v, want %v", installment.InstallmentNumber, installment.Amount, tt.paymentPerMonth)
+				}
+			}
+
+			if changes.TotalPayment != tt.wantTotal {
+				t.Errorf("TotalPayment = %v, want %v", changes.TotalPayment, tt.wantTotal)
+			}
+			if !slices.Equal(saved, tt.wantSaved) {
+				t.Errorf("Saved = %v, want %v", saved, tt.wantSaved)
+			}
+			if !slices.Equal(changes.RemovedNumbers, tt.wantRemoved) {
+				t.Errorf("RemovedNumbers = %v, want %v", changes.RemovedNumbers, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestCreateMeterReading(t *testing.T) {
+	owner := uuid.New()
+	today := utils.StartOfDay(time.Now())
+	dueOn := func(days int) time.Time {
+		year, month, day := today.AddDate(0, 0, days).Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name         string
+		installments []models.AgreementInstallments
+		number       int
+		wantErr      *apperror.AppErrorType
+	}{
+		{
+			name: "overdue installment",
+			installments: []models.AgreementInstallments{
+				{InstallmentNumber: 1, DueDate: dueOn(-40), Status: enums.PaidInstallment},
+				{InstallmentNumber: 2, DueDate: dueOn(-10), Status: enums.OverdueInstallment},
+				{InstallmentNumber: 3, DueDate: dueOn(20), Status: enums.UnpaidInstallment},
+			},
+			number: 2,
+		},
+		{
+			name: "installment falling due within the month",
+			installments: []models.AgreementInstallments{
+				{InstallmentNumber: 1, DueDate: dueOn(-10), Status: enums.PaidInstallment},
+				{InstallmentNumber: 2, DueDate: dueOn(20), Status: enums.UnpaidInstallment},
+			},
+			number: 2,
+		},
+		{
+			name: "installment after an unpaid one",
+			installments: []models.AgreementInstallments{
+				{InstallmentNumber: 1, DueDate: dueOn(-10), Status: enums.OverdueInstallment},
+				{InstallmentNumber: 2, DueDate: dueOn(20), Status: enums.UnpaidInstallment},
+			},
+			number:  2,
+			wantErr: apperror.MeterReadingNotAllowed,
+		},
+		{
+			name: "installment that is months away",
+			installments: []models.AgreementInstallments{
+				{InstallmentNumber: 1, DueDate: dueOn(40), Status: enums.UnpaidInstallment},
+			},
+			number:  1,
+			wantErr: apperror.MeterReadingNotAllowed,
+		},
+		{
+			name: "paid installment",
+			installments: []models.AgreementInstallments{
+				{InstallmentNumber: 1, DueDate: dueOn(-10), Status: enums.PaidInstallment},
+			},
+			number:  1,
+			wantErr: apperror.InstallmentAlreadyPaid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{
+				agreement: models.Agreements{
+					AgreementId:   uuid.New(),
+					AgreementType: enums.AgreementForRent,
+					Status:        enums.RentingAgreement,
+					OwnerUserId:   owner,
+					DwellerUserId: uuid.New(),
+				},
+				installments: tt.installments,
+			}
+			s := &serviceImpl{logger: zap.NewNop(), repo: repo}
+
+			// the first reading of a meter needs the reading it started from, so
+			// an installment that may be billed stops right after the checks
+			wantErr := tt.wantErr
+			if wantErr == nil {
+				wantErr = apperror.InvalidMeterReading
+			}
+
+			var reading models.AgreementMeterReadings
+			creating := models.CreatingMeterReadings{InstallmentNumber: tt.number, UtilityType: enums.WaterUtility, CurrentReading: 100}
+			apperr := s.CreateMeterReading(&reading, repo.agreement.AgreementId.String(), &creating, &multipart.FileHeader{}, &models.Sessions{UserId: owner})
+			if apperr == nil || apperr.Name() != wantErr.Name {
+				t.Fatalf("CreateMeterReading() error = %v, want %v", apperr, wantErr.Name)
+			}
+		})
+	}
+}
+
+func TestDeductionTransitions(t *testing.T) {
+	tests := []struct {
+		from    enums.DeductionStatus
+		to      enums.DeductionStatus
+		role    enums.ActorRoles
+		wantErr *apperror.AppErrorType
+	}{
+		{enums.DisputedDeduction, enums.AcceptedDeduction, enums.DwellerActor, nil},
+		{enums.DisputedDeduction, enums.AcceptedDeduction, enums.OwnerActor, apperror.Forbidden},
+		{enums.DisputedDeduction, enums.WithdrawnDeduction, enums.OwnerActor, nil},
+		{enums.DisputedDeduction, enums.WithdrawnDeduction, enums.DwellerActor, apperror.Forbidden},
+		{enums.DisputedDeduction, enums.EscalatedDeduction, enums.OwnerActor, nil},
+		{enums.DisputedDeduction, enums.EscalatedDeduction, enums.DwellerActor, nil},
+		{enums.DisputedDeduction, enums.AcceptedDeduction, enums.AdminActor, apperror.Forbidden},
+		{enums.DisputedDeduction, enums.PendingDeduction, enums.OwnerActor, apperror.InvalidDeduction},
+		{enums.EscalatedDeduction, enums.AcceptedDeduction, enums.AdminActor, nil},
+		{enums.EscalatedDeduction, enums.WithdrawnDeduction, enums.AdminActor, nil},
+		{enums.EscalatedDeduction, enums.AcceptedDeduction, enums.OwnerActor, apperror.Forbidden},
+		{enums.EscalatedDeduction, enums.EscalatedDeduction, enums.DwellerActor, apperror.InvalidDeduction},
+		{enums.PendingDeduction, enums.AcceptedDeduction, enums.DwellerActor, apperror.DeductionNotDisputed},
+		{enums.AcceptedDeduction, enums.WithdrawnDeduction, enums.AdminActor, apperror.DeductionNotDisputed},
+		{enums.WithdrawnDeduction, enums.AcceptedDeduction, enums.AdminActor, apperror.DeductionNotDisputed},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v to %v by %v", tt.from, tt.to, tt.role), func(t *testing.T) {
+			apperr := checkDeductionTransition(tt.from, tt.to, tt.role)
+			if tt.wantErr == nil && apperr != nil {
+				t.Errorf("checkDeductionTransition() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Errorf("checkDeductionTransition() error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+		})
+	}
+}
+
+func TestAgreementDeposit(t *testing.T) {
+	deduction := func(amount float64, status enums.DeductionStatus) models.AgreementDepositDeductions {
+		return models.AgreementDepositDeductions{Amount: amount, Status: status}
+	}
+
+	tests := []struct {
+		name           string
+		paidAmount     float64
+		deductions     []models.AgreementDepositDeductions
+		wantStatus     enums.DepositStatus
+		wantDeducted   float64
+		wantPending    float64
+		wantRefundable float64
+	}{
+		{"unpaid", 0, nil, enums.UnpaidDeposit, 0, 0, 0},
+		{"held", 30000, nil, enums.HeldDeposit, 0, 0, 30000},
+		{"accepted deduction", 30000, []models.AgreementDepositDeductions{
+			deduction(4500, enums.AcceptedDeduction),
+		}, enums.PartiallyDeductedDeposit, 4500, 0, 25500},
+		{"unresolved deductions", 30000, []models.AgreementDepositDeductions{
+			deduction(1000, enums.PendingDeduction),
+			deduction(2000, enums.DisputedDeduction),
+			deduction(3000, enums.EscalatedDeduction),
+		}, enums.HeldDeposit, 0, 6000, 30000},
+		{"withdrawn deduction", 30000, []models.AgreementDepositDeductions{
+			deduction(4500, enums.AcceptedDeduction),
+			deduction(1200, enums.WithdrawnDeduction),
+		}, enums.PartiallyDeductedDeposit, 4500, 0, 25500},
+		{"deductions over the deposit", 30000, []models.AgreementDepositDeductions{
+			deduction(20000, enums.AcceptedDeduction),
+			deduction(15000, enums.AcceptedDeduction),
+		}, enums.PartiallyDeductedDeposit, 35000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &serviceImpl{logger: zap.NewNop(), repo: &fakeRepository{paidAmount: tt.paidAmount, deductions: tt.deductions}}
+			agreement := models.Agreements{AgreementId: uuid.New(), DepositAmount: 30000}
+
+			var deposit models.AgreementDeposits
+			if apperr := s.getAgreementDeposit(&deposit, &agreement); apperr != nil {
+				t.Fatalf("getAgreementDeposit() error = %v", apperr)
+			}
+
+			if deposit.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", deposit.Status, tt.wantStatus)
+			}
+			if deposit.DeductedAmount != tt.wantDeducted || deposit.PendingAmount != tt.wantPending || deposit.RefundableAmount != tt.wantRefundable {
+				t.Errorf("deducted, pending, refundable = %v, %v, %v, want %v, %v, %v",
+					deposit.DeductedAmount, deposit.PendingAmount, deposit.RefundableAmount,
+					tt.wantDeducted, tt.wantPending, tt.wantRefundable)
+			}
+		})
+	}
+}
+
+func TestDownloadAgreementPhoto(t *testing.T) {
+	owner, dweller := uuid.New(), uuid.New()
+	agreement := models.Agreements{AgreementId: uuid.New(), OwnerUserId: owner, DwellerUserId: dweller}
+	agreementId := agreement.AgreementId.String()
+
+	files := map[string]string{
+		agreementPhotoKey(agreementId, "deductions/mirror-1.jpeg"):       "mirror",
+		agreementPhotoKey(agreementId, "inspections/report/sink-2.jpeg"): "sink",
+		agreementPhotoKey(agreementId, "contracts/contract.pdf"):         "contract",
+	}
+
+	tests := []struct {
+		name      string
+		photoPath string
+		session   models.Sessions
+		want      string
+		wantErr   *apperror.AppErrorType
+	}{
+		{"owner", "deductions/mirror-1.jpeg", models.Sessions{UserId: owner}, "mirror", nil},
+		{"dweller", "inspections/report/sink-2.jpeg", models.Sessions{UserId: dweller}, "sink", nil},
+		{"admin", "deductions/mirror-1.jpeg", models.Sessions{UserId: uuid.New(), IsAdmin: true}, "mirror", nil},
+		{"stranger", "deductions/mirror-1.jpeg", models.Sessions{UserId: uuid.New()}, "", apperror.Forbidden},
+		{"missing photo", "deductions/mirror-2.jpeg", models.Sessions{UserId: owner}, "", apperror.PhotoNotFound},
+		{"other folder", "contracts/contract.pdf", models.Sessions{UserId: owner}, "", apperror.PhotoNotFound},
+		{"path traversal", "deductions/../contracts/contract.pdf", models.Sessions{UserId: owner}, "", apperror.PhotoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &serviceImpl{
+				logger:  zap.NewNop(),
+				repo:    &fakeRepository{agreement: agreement},
+				storage: &fakeStorage{files: files},
+			}
+
+			var photo bytes.Buffer
+			apperr := s.DownloadAgreementPhoto(&photo, agreementId, tt.photoPath, &tt.session)
+			if tt.wantErr == nil && apperr != nil {
+				t.Fatalf("DownloadAgreementPhoto() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Fatalf("DownloadAgreementPhoto() error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+
+			if photo.String() != tt.want {
+				t.Errorf("photo = %q, want %q", photo.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckInspectionSignable(t *testing.T) {
+	author, other := uuid.New(), uuid.New()
+	signedAt := time.Now()
+	items := []models.AgreementInspectionItems{{Room: "Bathroom", Item: "Mirror", Condition: enums.GoodCondition}}
+
+	tests := []struct {
+		name       string
+		inspection models.AgreementInspections
+		role       enums.ActorRoles
+		session    models.Sessions
+		wantErr    *apperror.AppErrorType
+	}{
+		{"other party signs first", models.AgreementInspections{CreatedByUserId: author, Items: items},
+			enums.DwellerActor, models.Sessions{UserId: other}, nil},
+		{"author cannot sign first", models.AgreementInspections{CreatedByUserId: author, Items: items},
+			enums.OwnerActor, models.Sessions{UserId: author}, apperror.InspectionNotAllowed},
+		{"author signs after the other party", models.AgreementInspections{CreatedByUserId: author, Items: items, DwellerSignedAt: &signedAt},
+			enums.OwnerActor, models.Sessions{UserId: author}, nil},
+		{"dweller author signs after the owner", models.AgreementInspections{CreatedByUserId: author, Items: items, OwnerSignedAt: &signedAt},
+			enums.DwellerActor, models.Sessions{UserId: author}, nil},
+		{"already signed", models.AgreementInspections{CreatedByUserId: author, Items: items, DwellerSignedAt: &signedAt},
+			enums.DwellerActor, models.Sessions{UserId: other}, apperror.InspectionAlreadySigned},
+		{"empty report", models.AgreementInspections{CreatedByUserId: author},
+			enums.DwellerActor, models.Sessions{UserId: other}, apperror.InvalidInspection},
+		{"superseded report", models.AgreementInspections{CreatedByUserId: author, Items: items, SupersededAt: &signedAt},
+			enums.DwellerActor, models.Sessions{UserId: other}, apperror.InspectionSuperseded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apperr := checkInspectionSignable(&tt.inspection, tt.role, &tt.session)
+			if tt.wantErr == nil && apperr != nil {
+				t.Errorf("checkInspectionSignable() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Errorf("checkInspectionSignable() error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+		})
+	}
+}
+
+func TestCompareInspectionItems(t *testing.T) {
+	item := func(room string, name string, condition enums.ItemConditions, notes string) models.AgreementInspectionItems {
+		return models.AgreementInspectionItems{Room: room, Item: name, Condition: condition, Notes: notes}
+	}
+
+	type compared struct {
+		room       string
+		item       string
+		hasMoveIn  bool
+		hasMoveOut bool
+		changed    bool
+		worsened   bool
+	}
+
+	tests := []struct {
+		name    string
+		moveIn  []models.AgreementInspectionItems
+		moveOut []models.AgreementInspectionItems
+		want    []compared
+	}{
+		{"unchanged", []models.AgreementInspectionItems{
+			item("Bathroom", "Mirror", enums.GoodCondition, ""),
+		}, []models.AgreementInspectionItems{
+			item("Bathroom", "Mirror", enums.GoodCondition, ""),
+		}, []compared{
+			{"Bathroom", "Mirror", true, true, false, false},
+		}},
+		{"worsened", []models.AgreementInspectionItems{
+			item("Bathroom", "Mirror", enums.GoodCondition, ""),
+		}, []models.AgreementInspectionItems{
+			item("Bathroom", "Mirror", enums.DamagedCondition, "Cracked"),
+		}, []compared{
+			{"Bathroom", "Mirror", true, true, true, true},
+		}},
+		{"improved", []models.AgreementInspectionItems{
+			item("Kitchen", "Sink", enums.PoorCondition, ""),
+		}, []models.AgreementInspectionItems{
+			item("Kitchen", "Sink", enums.ExcellentCondition, ""),
+		}, []compared{
+			{"Kitchen", "Sink", true, true, true, false},
+		}},
+		{"notes changed", []models.AgreementInspectionItems{
+			item("Kitchen", "Sink", enums.FairCondition, "Slow drain"),
+		}, []models.AgreementInspectionItems{
+			item("Kitchen", "Sink", enums.FairCondition, ""),
+		}, []compared{
+			{"Kitchen", "Sink", true, true, true, false},
+		}},
+		{"matched regardless of case", []models.AgreementInspectionItems{
+			item("Bathroom", "Mirror", enums.GoodCondition, ""),
+		}, []models.AgreementInspectionItems{
+			item("bathroom", "MIRROR", enums.GoodCondition, ""),
+		}, []compared{
+			{"Bathroom", "Mirror", true, true, false, false},
+		}},
+		{"missing and added items", []models.AgreementInspectionItems{
+			item("Bedroom", "Lamp", enums.GoodCondition, ""),
+			item("Bathroom", "Mirror", enums.GoodCondition, ""),
+		}, []models.AgreementInspectionItems{
+			item("Living room", "Sofa", enums.FairCondition, ""),
+			item("Bathroom", "Mirror", enums.GoodCondition, ""),
+		}, []compared{
+			{"Bedroom", "Lamp", true, false, true, false},
+			{"Bathroom", "Mirror", true, true, false, false},
+			{"Living room", "Sofa", false, true, true, false},
+		}},
+		{"no items", nil, nil, []compared{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []compared{}
+			for _, comparison := range compareInspectionItems(tt.moveIn, tt.moveOut) {
+				got = append(got, compared{
+					comparison.Room, comparison.Item,
+					comparison.MoveIn != nil, comparison.MoveOut != nil,
+					comparison.Changed, comparison.Worsened,
+				})
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("compareInspectionItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPaymentRefundable(t *testing.T) {
+	rent := func(status enums.AgreementStatus) models.Agreements {
+		return models.Agreements{AgreementId: uuid.New(), AgreementType: enums.AgreementForRent, Status: status, DepositAmount: 30000, PaymentPerMonth: 15000}
+	}
+	sell := func(status enums.AgreementStatus) models.Agreements {
+		return models.Agreements{AgreementId: uuid.New(), AgreementType: enums.AgreementForSell, Status: status, DepositAmount: 100000, TotalPayment: 3000000}
+	}
+
+	tests := []struct {
+		name        string
+		agreement   models.Agreements
+		paymentType enums.PaymentTypes
+		price       float64
+		paid        float64
+		deductions  []models.AgreementDepositDeductions
+		refund      *models.Payments
+		wantErr     *apperror.AppErrorType
+	}{
+		{name: "deposit awaiting the deposit", agreement: rent(enums.AwaitingDepositAgreement), paymentType: enums.DepositPayment, price: 30000, paid: 30000},
+		{name: "deposit awaiting the first rent", agreement: rent(enums.AwaitingPaymentAgreement), paymentType: enums.DepositPayment, price: 30000, paid: 30000, wantErr: apperror.PaymentNotRefundable},
+		{name: "deposit paid twice", agreement: rent(enums.RentingAgreement), paymentType: enums.DepositPayment, price: 30000, paid: 60000},
+		{name: "deposit of a cancelled agreement", agreement: rent(enums.CancelledAgreement), paymentType: enums.DepositPayment, price: 30000, paid: 30000},
+		{name: "deposit with a deduction", agreement: rent(enums.CancelledAgreement), paymentType: enums.DepositPayment, price: 30000, paid: 30000, deductions: []models.AgreementDepositDeductions{
+			{Amount: 1000, Status: enums.PendingDeduction},
+		}, wantErr: apperror.PaymentNotRefundable},
+		{name: "deposit with a withdrawn deduction", agreement: rent(enums.CancelledAgreement), paymentType: enums.DepositPayment, price: 30000, paid: 30000, deductions: []models.AgreementDepositDeductions{
+			{Amount: 1000, Status: enums.WithdrawnDeduction},
+		}},
+		{name: "deposit already refunded", agreement: rent(enums.CancelledAgreement), paymentType: enums.DepositPayment, price: 30000, paid: 30000, refund: &models.Payments{Price: 30000}, wantErr: apperror.PaymentNotRefundable},
+		{name: "first rent awaiting it", agreement: rent(enums.AwaitingPaymentAgreement), paymentType: enums.RentPayment, price: 15000, paid: 15000},
+		{name: "first rent of a renting agreement", agreement: rent(enums.RentingAgreement), paymentType: enums.RentPayment, price: 15000, paid: 15000, wantErr: apperror.PaymentNotRefundable},
+		{name: "later rent of a renting agreement", agreement: rent(enums.RentingAgreement), paymentType: enums.RentPayment, price: 15000, paid: 45000},
+		{name: "first rent of an overdue agreement", agreement: rent(enums.OverdueAgreement), paymentType: enums.RentPayment, price: 15000, paid: 15000, wantErr: apperror.PaymentNotRefundable},
+		{name: "selling deposit awaiting the price", agreement: sell(enums.AwaitingPaymentAgreement), paymentType: enums.DepositPayment, price: 100000, paid: 100000, wantErr: apperror.PaymentNotRefundable},
+		{name: "purchase awaiting the price", agreement: sell(enums.AwaitingPaymentAgreement), paymentType: enums.PurchasePayment, price: 500000, paid: 600000},
+		{name: "purchase of a sold agreement", agreement: sell(enums.ArchivedAgreement), paymentType: enums.PurchasePayment, price: 500000, paid: 3000000, wantErr: apperror.PaymentNotRefundable},
+		{name: "overpaid purchase of a sold agreement", agreement: sell(enums.ArchivedAgreement), paymentType: enums.PurchasePayment, price: 500000, paid: 3500000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &serviceImpl{logger: zap.NewNop(), repo: &fakeRepository{agreement: tt.agreement, paidAmount: tt.paid, deductions: tt.deductions, refund: tt.refund}}
+			payment := models.Payments{AgreementId: &tt.agreement.AgreementId, PaymentType: tt.paymentType, Price: tt.price}
+
+			apperr := s.CheckPaymentRefundable(&payment)
+			if tt.wantErr == nil && apperr != nil {
+				t.Fatalf("CheckPaymentRefundable() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Fatalf("CheckPaymentRefundable() error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+		})
+	}
+}
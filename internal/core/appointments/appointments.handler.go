@@ -3,8 +3,10 @@ package appointments
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/brain-flowing-company/pprp-backend/internal/utils"
 	"github.com/gofiber/fiber/v2"
@@ -17,6 +19,24 @@ type Handler interface {
 	CreateAppointment(c *fiber.Ctx) error
 	DeleteAppointment(c *fiber.Ctx) error
 	UpdateAppointmentStatus(c *fiber.Ctx) error
+	GetAppointmentStatusHistories(c *fiber.Ctx) error
+	GetPropertyAvailabilities(c *fiber.Ctx) error
+	UpdatePropertyAvailabilities(c *fiber.Ctx) error
+	CreateAvailabilityException(c *fiber.Ctx) error
+	DeleteAvailabilityException(c *fiber.Ctx) error
+	GetOpenSlots(c *fiber.Ctx) error
+	GetAppointmentProposals(c *fiber.Ctx) error
+	CreateAppointmentProposal(c *fiber.Ctx) error
+	AcceptAppointmentProposal(c *fiber.Ctx) error
+	DeclineAppointmentProposal(c *fiber.Ctx) error
+	CheckInAppointment(c *fiber.Ctx) error
+	ReportNoShow(c *fiber.Ctx) error
+	GetAppointmentAttendances(c *fiber.Ctx) error
+	GetAttendanceStatistics(c *fiber.Ctx) error
+	GetCalendarFeed(c *fiber.Ctx) error
+	RegenerateCalendarFeed(c *fiber.Ctx) error
+	GetCalendarByToken(c *fiber.Ctx) error
+	GetAppointmentCalendar(c *fiber.Ctx) error
 }
 
 type handlerImpl struct {
@@ -48,7 +68,7 @@ func (h *handlerImpl) GetAllAppointments(c *fiber.Ctx) error {
 
 // @router      /api/v1/appointments/:appointmentId [get]
 // @summary     Get an appointment by id *use cookies*
-// @description Get the appointment and other related information by id
+// @description Get the appointment and other related information by id. The attendance of the dweller is only included for the owner and admins
 // @tags        appointments
 // @produce     json
 // @param       appointmentId path string true "Appointment ID"
@@ -58,9 +78,10 @@ func (h *handlerImpl) GetAllAppointments(c *fiber.Ctx) error {
 // @failure     500 {object} models.ErrorResponses "Could not get appointment by id"
 func (h *handlerImpl) GetAppointmentById(c *fiber.Ctx) error {
 	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
 
 	var appointment models.AppointmentDetails
-	err := h.service.GetAppointmentById(&appointment, appointmentId)
+	err := h.service.GetAppointmentById(&appointment, appointmentId, &session)
 	if err != nil {
 		return utils.ResponseError(c, err)
 	}
@@ -73,17 +94,43 @@ func (h *handlerImpl) GetAppointmentById(c *fiber.Ctx) error {
 // @description Get all appointments related to the user
 // @tags        appointments
 // @produce     json
-// @param       order query string false "Order by ASC or DESC"
+// @param       order query string false "Order by created time ASC or DESC, used when sort is not given"
+// @param       role query string false "Only list appointments as `OWNER` or as `DWELLER`"
+// @param       limit query int false "Pagination limit per page per role, max 50, default 20"
+// @param       page  query int false "Pagination page index as 1-based index, default 1"
+// @param       sort query string false "Sort in format `<json_field>:<direction>` where direction can only be `desc` or `asc`. Ex. `?sort=appointment_date:desc`"
+// @param       filter query string false "Filter in format `<json_field>[<operator>]:<value>` where operator can be `gte`, `lte` or `eql`. Filterable fields are `status`, `appointment_date` (RFC 3339 time or YYYY-MM-DD date) and `property.property_id`. Ex. `?filter=status[eql]:CONFIRMED,appointment_date[gte]:2024-02-01`"
 // @success     200	{object} models.MyAppointmentResponses
+// @failure     400 {object} models.ErrorResponses "Invalid role, sort or filter"
 // @failure     500 {object} models.ErrorResponses "Could not get my appointments"
 func (h *handlerImpl) GetMyAppointments(c *fiber.Ctx) error {
 	appointmentRequest := models.MyAppointmentRequests{
 		UserId: c.Locals("session").(models.Sessions).UserId,
 		Order: c.Query("order"),
+		Role: enums.ActorRoles(c.Query("role")),
 	}
 
+	sorted := utils.NewSortedQuery(models.AppointmentLists{})
+	if err := sorted.ParseQuery(c.Query("sort")); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(err.Error()))
+	}
+
+	filtered := utils.NewFilteredQuery(models.AppointmentLists{})
+	if err := filtered.ParseQuery(c.Query("filter")); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(err.Error()))
+	}
+
+	limit := utils.Clamp(c.QueryInt("limit", 20), 1, 50)
+	page := utils.Max(c.QueryInt("page", 1), 1)
+
+	paginated := utils.NewPaginatedQuery(page, limit)
+
 	var appointments models.MyAppointmentResponses
-	err := h.service.GetMyAppointments(&appointments, &appointmentRequest)
+	err := h.service.GetMyAppointments(&appointments, &appointmentRequest, paginated, sorted, filtered)
 	if err != nil {
 		return utils.ResponseError(c, err)
 	}
@@ -93,12 +140,15 @@ func (h *handlerImpl) GetMyAppointments(c *fiber.Ctx) error {
 
 // @router      /api/v1/appointments [post]
 // @summary     Create an appointment *use cookies*
-// @description Create an appointment by parsing the body (note is optional)
+// @description Create an appointment by parsing the body (note is optional). The appointment date must be the start time of one of the open slots of the property
 // @tags        appointments
 // @produce     json
 // @param       body body models.CreatingAppointments true "Appointment details"
 // @success     201	{object} models.MessageResponses "Appointments created"
 // @failure     400 {object} models.ErrorResponses "Empty dates or some of appointments duplicate with existing one"
+// @failure     403 {object} models.ErrorResponses "Owners cannot book their own property"
+// @failure     404 {object} models.ErrorResponses "Property not found"
+// @failure     409 {object} models.ErrorResponses "Appointment date is not a free slot"
 // @failure     500 {object} models.ErrorResponses "Could not create appointments"
 func (h *handlerImpl) CreateAppointment(c *fiber.Ctx) error {
 	appointment := &models.CreatingAppointments{
@@ -141,13 +191,16 @@ func (h *handlerImpl) DeleteAppointment(c *fiber.Ctx) error {
 
 // @router      /api/v1/appointments/:appointmentId [patch]
 // @summary     Update an appointment status by id *use cookies*
-// @description Update an appointment status by id with **status** and **cancelled_message**(optional)
+// @description Update an appointment status by id with **status** and **cancelled_message**(optional). Only the owner can confirm or reject a PENDING appointment, either party can cancel a PENDING or CONFIRMED appointment, and archiving is done by the system
 // @tags        appointments
 // @produce     json
 // @param       appointmentId path string true "Appointment ID"
 // @param       body body models.UpdatingAppointmentStatus true "Appointment status and cancelled message(optional)"
 // @success     200	{object} models.MessageResponses "Appointment state updated"
 // @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not allowed to make this transition"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     409 {object} models.ErrorResponses "Invalid appointment transition"
 // @failure     500 {object} models.ErrorResponses "Could not update appointment status"
 func (h *handlerImpl) UpdateAppointmentStatus(c *fiber.Ctx) error {
 	updatingAppointment := models.UpdatingAppointmentStatus{}
@@ -159,11 +212,478 @@ func (h *handlerImpl) UpdateAppointmentStatus(c *fiber.Ctx) error {
 	}
 
 	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
 
-	apperr := h.service.UpdateAppointmentStatus(&updatingAppointment, appointmentId)
+	apperr := h.service.UpdateAppointmentStatus(&updatingAppointment, appointmentId, &session)
 	if apperr != nil {
 		return utils.ResponseError(c, apperr)
 	}
 
 	return utils.ResponseMessage(c, http.StatusOK, "Appointment state updated")
 }
+
+// @router      /api/v1/appointments/:appointmentId/histories [get]
+// @summary     Get appointment status history *use cookies*
+// @description Get every status transition of an appointment with the actor and time, oldest first
+// @tags        appointments
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @success     200	{object} []models.AppointmentStatusHistories
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     500 {object} models.ErrorResponses "Could not get appointment status histories"
+func (h *handlerImpl) GetAppointmentStatusHistories(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	histories := []models.AppointmentStatusHistories{}
+	apperr := h.service.GetAppointmentStatusHistories(&histories, appointmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(histories)
+}
+
+// @router      /api/v1/properties/:propertyId/availabilities [get]
+// @summary     Get property viewing availability
+// @description Get the weekly viewing availability of a property and its upcoming exceptions. Day of week starts from 0 (Sunday) and times are in Bangkok time
+// @tags        appointments
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @success     200	{object} models.PropertyAvailabilityResponses
+// @failure     400 {object} models.ErrorResponses "Invalid property id"
+// @failure     404 {object} models.ErrorResponses "Property not found"
+// @failure     500 {object} models.ErrorResponses "Could not get property availabilities"
+func (h *handlerImpl) GetPropertyAvailabilities(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+
+	var availabilities models.PropertyAvailabilityResponses
+	apperr := h.service.GetPropertyAvailabilities(&availabilities, propertyId)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(availabilities)
+}
+
+// @router      /api/v1/properties/:propertyId/availabilities [put]
+// @summary     Set property viewing availability *use cookies*
+// @description Replace the weekly viewing availability of a property. Each availability is split into slots of **slot_duration** minutes (15 - 240). Availabilities on the same day must not overlap. Only the property owner or an admin can set availability
+// @tags        appointments
+// @accept      json
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       body body models.UpdatingPropertyAvailabilities true "Weekly availabilities"
+// @success     200	{object} models.MessageResponses "Property availabilities updated"
+// @failure     400 {object} models.ErrorResponses "Invalid availability"
+// @failure     403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property not found"
+// @failure     500 {object} models.ErrorResponses "Could not update property availabilities"
+func (h *handlerImpl) UpdatePropertyAvailabilities(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	session := c.Locals("session").(models.Sessions)
+
+	updating := models.UpdatingPropertyAvailabilities{}
+	err := c.BodyParser(&updating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	apperr := h.service.UpdatePropertyAvailabilities(propertyId, &updating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Property availabilities updated")
+}
+
+// @router      /api/v1/properties/:propertyId/availabilities/exceptions [post]
+// @summary     Add an availability exception *use cookies*
+// @description Block a whole day or a time range of a date by leaving **is_available** false (omit start and end time to block the whole day), or open extra slots on a date by setting **is_available** with start time, end time and slot duration. Only the property owner or an admin can add exceptions
+// @tags        appointments
+// @accept      json
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       body body models.CreatingAvailabilityExceptions true "Exception details"
+// @success     201	{object} models.PropertyAvailabilityExceptions
+// @failure     400 {object} models.ErrorResponses "Invalid exception"
+// @failure     403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property not found"
+// @failure     500 {object} models.ErrorResponses "Could not create availability exception"
+func (h *handlerImpl) CreateAvailabilityException(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	session := c.Locals("session").(models.Sessions)
+
+	creating := models.CreatingAvailabilityExceptions{}
+	err := c.BodyParser(&creating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	exception := models.PropertyAvailabilityExceptions{}
+	apperr := h.service.CreateAvailabilityException(&exception, propertyId, &creating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(exception)
+}
+
+// @router      /api/v1/properties/:propertyId/availabilities/exceptions/:exceptionId [delete]
+// @summary     Delete an availability exception *use cookies*
+// @description Delete an availability exception of a property. Only the property owner or an admin can delete exceptions
+// @tags        appointments
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       exceptionId path string true "Exception id"
+// @success     200	{object} models.MessageResponses "Availability exception deleted"
+// @failure     400 {object} models.ErrorResponses "Invalid property id or exception id"
+// @failure     403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property or exception not found"
+// @failure     500 {object} models.ErrorResponses "Could not delete availability exception"
+func (h *handlerImpl) DeleteAvailabilityException(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	exceptionId := c.Params("exceptionId")
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.DeleteAvailabilityException(propertyId, exceptionId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Availability exception deleted")
+}
+
+// @router      /api/v1/properties/:propertyId/slots [get]
+// @summary     Get open viewing slots
+// @description Get the viewing slots of a property that can still be booked. Dates are in Bangkok time and both ends are inclusive. The range defaults to the next 7 days and spans at most 31 days
+// @tags        appointments
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       from query string false "First date of the range in YYYY-MM-DD, default today"
+// @param       to query string false "Last date of the range in YYYY-MM-DD, default 6 days after from"
+// @success     200	{object} []models.AppointmentSlots
+// @failure     400 {object} models.ErrorResponses "Invalid property id or date range"
+// @failure     404 {object} models.ErrorResponses "Property not found"
+// @failure     500 {object} models.ErrorResponses "Could not get open slots"
+func (h *handlerImpl) GetOpenSlots(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+
+	var slots []models.AppointmentSlots
+	apperr := h.service.GetOpenSlots(&slots, propertyId, c.Query("from"), c.Query("to"))
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(slots)
+}
+
+// @router      /api/v1/appointments/:appointmentId/proposals [get]
+// @summary     Get appointment reschedule proposals *use cookies*
+// @description Get every reschedule proposal made on an appointment, oldest first
+// @tags        appointments
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @success     200	{object} []models.AppointmentProposals
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     500 {object} models.ErrorResponses "Could not get appointment proposals"
+func (h *handlerImpl) GetAppointmentProposals(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	proposals := []models.AppointmentProposals{}
+	apperr := h.service.GetAppointmentProposals(&proposals, appointmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(proposals)
+}
+
+// @router      /api/v1/appointments/:appointmentId/proposals [post]
+// @summary     Propose a new appointment date *use cookies*
+// @description Propose to move a PENDING or CONFIRMED appointment to another date. Dwellers can only propose open slots of the property while owners can propose any time the property is not booked. A new proposal supersedes the one still waiting for an answer
+// @tags        appointments
+// @accept      json
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @param       body body models.CreatingAppointmentProposals true "Proposed date and message(optional)"
+// @success     201	{object} models.AppointmentProposals
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id or body"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     409 {object} models.ErrorResponses "Appointment could not be rescheduled or the date is not free"
+// @failure     500 {object} models.ErrorResponses "Could not create appointment proposal"
+func (h *handlerImpl) CreateAppointmentProposal(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	creating := models.CreatingAppointmentProposals{}
+	err := c.BodyParser(&creating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	proposal := models.AppointmentProposals{}
+	apperr := h.service.CreateAppointmentProposal(&proposal, appointmentId, &creating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(proposal)
+}
+
+// @router      /api/v1/appointments/:appointmentId/proposals/:proposalId/accept [post]
+// @summary     Accept a reschedule proposal *use cookies*
+// @description Accept a proposal made by the other party and move the appointment to the proposed date
+// @tags        appointments
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @param       proposalId path string true "Proposal ID"
+// @success     200	{object} models.MessageResponses "Appointment rescheduled"
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id or proposal id"
+// @failure     403 {object} models.ErrorResponses "Not the other party of the proposal"
+// @failure     404 {object} models.ErrorResponses "Appointment or proposal not found"
+// @failure     409 {object} models.ErrorResponses "Proposal is not pending or the date is no longer free"
+// @failure     500 {object} models.ErrorResponses "Could not accept appointment proposal"
+func (h *handlerImpl) AcceptAppointmentProposal(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	proposalId := c.Params("proposalId")
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.AcceptAppointmentProposal(appointmentId, proposalId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Appointment rescheduled")
+}
+
+// @router      /api/v1/appointments/:appointmentId/proposals/:proposalId/decline [post]
+// @summary     Decline a reschedule proposal *use cookies*
+// @description Decline a proposal made by the other party. The appointment keeps its current date
+// @tags        appointments
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @param       proposalId path string true "Proposal ID"
+// @success     200	{object} models.MessageResponses "Proposal declined"
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id or proposal id"
+// @failure     403 {object} models.ErrorResponses "Not the other party of the proposal"
+// @failure     404 {object} models.ErrorResponses "Appointment or proposal not found"
+// @failure     409 {object} models.ErrorResponses "Proposal is not pending"
+// @failure     500 {object} models.ErrorResponses "Could not decline appointment proposal"
+func (h *handlerImpl) DeclineAppointmentProposal(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	proposalId := c.Params("proposalId")
+	session := c.Locals("session").(models.Sessions)
+
+	apperr := h.service.DeclineAppointmentProposal(appointmentId, proposalId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Proposal declined")
+}
+
+// @router      /api/v1/appointments/:appointmentId/check-in [post]
+// @summary     Check in to an appointment *use cookies*
+// @description Record that the current user showed up to a confirmed appointment. Check-in is open from 30 minutes before until 2 hours after the appointment. Checking in replaces a no-show the other party has reported
+// @tags        appointments
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @success     201	{object} models.AppointmentAttendances
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     409 {object} models.ErrorResponses "Appointment is not confirmed, outside the check-in window or attendance already recorded"
+// @failure     500 {object} models.ErrorResponses "Could not record attendance"
+func (h *handlerImpl) CheckInAppointment(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	attendance := models.AppointmentAttendances{}
+	apperr := h.service.CheckInAppointment(&attendance, appointmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(attendance)
+}
+
+// @router      /api/v1/appointments/:appointmentId/no-show [post]
+// @summary     Report the other party as a no-show *use cookies*
+// @description Record that the other party of a confirmed appointment did not show up. A no-show can be reported from 15 minutes after the start until 48 hours after the appointment, unless the other party has checked in. The report is replaced if the other party checks in later
+// @tags        appointments
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @success     201	{object} models.AppointmentAttendances
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     409 {object} models.ErrorResponses "Appointment is not confirmed, outside the no-show window or attendance already recorded"
+// @failure     500 {object} models.ErrorResponses "Could not record attendance"
+func (h *handlerImpl) ReportNoShow(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	attendance := models.AppointmentAttendances{}
+	apperr := h.service.ReportNoShow(&attendance, appointmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(attendance)
+}
+
+// @router      /api/v1/appointments/:appointmentId/attendances [get]
+// @summary     Get appointment attendances *use cookies*
+// @description Get the check-ins and no-shows recorded for an appointment
+// @tags        appointments
+// @produce     json
+// @param       appointmentId path string true "Appointment ID"
+// @success     200	{object} []models.AppointmentAttendances
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     500 {object} models.ErrorResponses "Could not get appointment attendances"
+func (h *handlerImpl) GetAppointmentAttendances(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	attendances := []models.AppointmentAttendances{}
+	apperr := h.service.GetAppointmentAttendances(&attendances, appointmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(attendances)
+}
+
+// @router      /api/v1/user/:userId/attendance [get]
+// @summary     Get user attendance statistics *use cookies*
+// @description Get how many viewings a user checked in to and how many they missed, optionally only as an owner or as a dweller. Only the user, admins and owners the user has made an appointment with can see it
+// @tags        appointments
+// @produce     json
+// @param       userId path string true "User ID"
+// @param       role query string false "Only count attendances as `OWNER` or as `DWELLER`"
+// @success     200	{object} models.AttendanceStatistics
+// @failure     400 {object} models.ErrorResponses "Invalid user id or role"
+// @failure     403 {object} models.ErrorResponses "No appointment with the user"
+// @failure     500 {object} models.ErrorResponses "Could not get attendance statistics"
+func (h *handlerImpl) GetAttendanceStatistics(c *fiber.Ctx) error {
+	userId := c.Params("userId")
+	role := enums.ActorRoles(c.Query("role"))
+	session := c.Locals("session").(models.Sessions)
+
+	statistics := models.AttendanceStatistics{}
+	apperr := h.service.GetAttendanceStatistics(&statistics, userId, role, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(statistics)
+}
+
+// @router      /api/v1/user/me/calendar [get]
+// @summary     Get my calendar feed *use cookies*
+// @description Get the secret iCalendar feed url listing every appointment of the current user as an owner and as a dweller. The feed is created on first use
+// @tags        appointments
+// @produce     json
+// @success     200	{object} models.CalendarFeeds
+// @failure     500 {object} models.ErrorResponses "Could not get calendar feed"
+func (h *handlerImpl) GetCalendarFeed(c *fiber.Ctx) error {
+	session := c.Locals("session").(models.Sessions)
+
+	feed := models.CalendarFeeds{}
+	apperr := h.service.GetCalendarFeed(&feed, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	feed.FeedUrl = calendarFeedUrl(c, feed.Token)
+
+	return c.JSON(feed)
+}
+
+// @router      /api/v1/user/me/calendar/regenerate [post]
+// @summary     Regenerate my calendar feed *use cookies*
+// @description Replace the secret token of the calendar feed of the current user. The old feed url stops working immediately
+// @tags        appointments
+// @produce     json
+// @success     200	{object} models.CalendarFeeds
+// @failure     500 {object} models.ErrorResponses "Could not create calendar feed"
+func (h *handlerImpl) RegenerateCalendarFeed(c *fiber.Ctx) error {
+	session := c.Locals("session").(models.Sessions)
+
+	feed := models.CalendarFeeds{}
+	apperr := h.service.RegenerateCalendarFeed(&feed, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	feed.FeedUrl = calendarFeedUrl(c, feed.Token)
+
+	return c.JSON(feed)
+}
+
+// @router      /api/v1/calendar/:token [get]
+// @summary     Get calendar feed
+// @description Get the iCalendar (RFC 5545) feed of the user owning the token. Cancelled and rejected appointments stay in the feed as cancelled events
+// @tags        appointments
+// @produce     text/calendar
+// @param       token path string true "Calendar feed token, optionally followed by .ics"
+// @success     200	{string} string "iCalendar feed"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified calendar feed"
+// @failure     500 {object} models.ErrorResponses "Could not get calendar feed"
+func (h *handlerImpl) GetCalendarByToken(c *fiber.Ctx) error {
+	token := strings.TrimSuffix(c.Params("token"), ".ics")
+
+	calendar := utils.ICalendars{}
+	apperr := h.service.GetCalendarByToken(&calendar, token)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.SendString(calendar.String())
+}
+
+// @router      /api/v1/appointments/:appointmentId/ics [get]
+// @summary     Download appointment as iCalendar *use cookies*
+// @description Download a single appointment as an .ics file with the property address and the contact of the other party
+// @tags        appointments
+// @produce     text/calendar
+// @param       appointmentId path string true "Appointment ID"
+// @success     200	{string} string "iCalendar file"
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Could not find the specified appointment"
+// @failure     500 {object} models.ErrorResponses "Could not get appointment by id"
+func (h *handlerImpl) GetAppointmentCalendar(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	calendar := utils.ICalendars{}
+	apperr := h.service.GetAppointmentCalendar(&calendar, appointmentId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	c.Attachment(fmt.Sprintf("appointment-%v.ics", appointmentId))
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.SendString(calendar.String())
+}
+
+func calendarFeedUrl(c *fiber.Ctx, token string) string {
+	return c.BaseURL() + "/api/v1/calendar/" + token + ".ics"
+}
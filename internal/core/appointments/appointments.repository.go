@@ -2,18 +2,65 @@ package appointments
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	errAppointmentOverlaps      = errors.New("appointment overlaps with an existing appointment")
+	errAppointmentStatusChanged = errors.New("appointment status has been changed concurrently")
+	errProposalNotPending       = errors.New("proposal is no longer pending")
+	errReminderAlreadySent      = errors.New("appointment reminder has already been sent")
+)
+
+// an appointment only holds its slot while it is still pending or confirmed
+const activeAppointmentsCondition = `status IN ('PENDING', 'CONFIRMED')`
+
 type Repository interface {
 	GetAllAppointments(*[]models.AppointmentLists) error
 	GetAppointmentById(*models.AppointmentDetails, string) error
-	GetAppointmentByUserId(*models.MyAppointmentResponses, *models.MyAppointmentRequests) error
+	GetAppointmentByUserId(*models.MyAppointmentResponses, *models.MyAppointmentRequests, *utils.PaginatedQuery, *utils.SortedQuery, *utils.FilteredQuery) error
 	CreateAppointment(*models.CreatingAppointments) error
 	DeleteAppointment(string) error
-	UpdateAppointmentStatus(*models.UpdatingAppointmentStatus, string) error
+	GetAppointment(*models.Appointments, string) error
+	UpdateAppointmentStatus(*models.UpdatingAppointmentStatus, string, *models.AppointmentStatusHistories) error
+	GetAppointmentStatusHistories(*[]models.AppointmentStatusHistories, string) error
+	GetAppointmentProposals(*[]models.AppointmentProposals, string) error
+	GetAppointmentProposal(*models.AppointmentProposals, string, string) error
+	CreateAppointmentProposal(*models.AppointmentProposals) error
+	AcceptAppointmentProposal(*models.AppointmentProposals, *models.Appointments, uuid.UUID) error
+	DeclineAppointmentProposal(*models.AppointmentProposals, uuid.UUID) error
+	GetEndedAppointments(*[]models.Appointments, enums.AppointmentStatus, time.Time) error
+	GetExpiredPendingAppointments(*[]models.Appointments, time.Time, time.Time) error
+	GetNotificationRecipient(*models.NotificationRecipients, uuid.UUID) error
+	GetDueAppointmentReminders(*[]models.DueAppointmentReminders, int64, time.Time, time.Time) error
+	CreateAppointmentReminder(*models.AppointmentReminders) error
+	DeleteAppointmentReminder(*models.AppointmentReminders) error
+	GetAppointmentAttendances(*[]models.AppointmentAttendances, string) error
+	CreateAppointmentAttendance(*models.AppointmentAttendances) error
+	CheckInAppointmentAttendance(*models.AppointmentAttendances) error
+	GetAttendanceStatistics(*models.AttendanceStatistics, string, enums.ActorRoles) error
+	CountOwnerAppointmentsWithDweller(*int64, uuid.UUID, string) error
+	GetCalendarAppointments(*[]models.AppointmentDetails, uuid.UUID, int) error
+	GetCalendarFeedByUserId(*models.CalendarFeeds, uuid.UUID) error
+	GetCalendarFeedByToken(*models.CalendarFeeds, string) error
+	SaveCalendarFeed(*models.CalendarFeeds) error
+	GetPropertyOwnerId(*uuid.UUID, string) error
+	GetPropertyAvailabilities(*[]models.PropertyAvailabilities, string) error
+	ReplacePropertyAvailabilities(string, []models.PropertyAvailabilities) error
+	GetAvailabilityExceptions(*[]models.PropertyAvailabilityExceptions, string, time.Time, time.Time) error
+	CreateAvailabilityException(*models.PropertyAvailabilityExceptions) error
+	DeleteAvailabilityException(string, string) error
+	GetActiveAppointmentsBetween(*[]models.Appointments, string, time.Time, time.Time) error
+	GetUpcomingActiveAppointments(*[]models.Appointments, string, time.Time) error
 }
 
 type repositoryImpl struct {
@@ -42,6 +89,7 @@ func (repo *repositoryImpl) GetAllAppointments(appointments *[]models.Appointmen
 					   p.*,
 					   o.*,
 					   a.appointment_date,
+					   a.duration_minutes,
 					   a.status,
 					   a.note,
 					   a.cancelled_message,
@@ -61,7 +109,7 @@ func (repo *repositoryImpl) GetAllAppointments(appointments *[]models.Appointmen
 				Raw(`
 					SELECT image_url
 					FROM property_images
-					WHERE property_id = @property_id
+					WHERE property_id = @property_id AND deleted_at IS NULL
 					`, sql.Named("property_id", appointment.Property.PropertyId)).
 				Pluck("image_url", &(*appointments)[i].Property.PropertyImages).Error; err != nil {
 				return err
@@ -77,8 +125,8 @@ func (repo *repositoryImpl) GetAppointmentById(appointment *models.AppointmentDe
 							p.alley, p.street, p.sub_district, p.district, p.province, p.country,
 							p.postal_code, s.price, r.price_per_month 
 						FROM properties p
-						LEFT JOIN selling_properties s ON p.property_id = s.property_id
-						LEFT JOIN renting_properties r ON p.property_id = r.property_id
+						LEFT JOIN selling_properties s ON (p.property_id = s.property_id AND s.deleted_at IS NULL)
+						LEFT JOIN renting_properties r ON (p.property_id = r.property_id AND r.deleted_at IS NULL)
 						WHERE p.property_id = @property_id`
 
 	ownerQuery := `SELECT user_id AS owner_user_id,
@@ -110,10 +158,12 @@ func (repo *repositoryImpl) GetAppointmentById(appointment *models.AppointmentDe
 					   o.*, 
 					   d.*,
 					   a.appointment_date, 
+					   a.duration_minutes,
 					   a.status,
 					   a.note,
 					   a.cancelled_message,
-					   a.created_at
+					   a.created_at,
+					   a.updated_at
 					FROM appointments a
 					JOIN (`+propertyQuery+`) p ON a.property_id = p.property_id
 					JOIN (`+ownerQuery+`) o ON a.owner_user_id = o.owner_user_id
@@ -129,7 +179,7 @@ func (repo *repositoryImpl) GetAppointmentById(appointment *models.AppointmentDe
 			Raw(`
 				SELECT image_url
 				FROM property_images
-				WHERE property_id = @property_id
+				WHERE property_id = @property_id AND deleted_at IS NULL
 				`, sql.Named("property_id", appointment.Property.PropertyId)).
 			Pluck("image_url", &appointment.Property.PropertyImages).Error; err != nil {
 			return err
@@ -139,7 +189,7 @@ func (repo *repositoryImpl) GetAppointmentById(appointment *models.AppointmentDe
 	})
 }
 
-func (repo *repositoryImpl) GetAppointmentByUserId(appointmentResponse *models.MyAppointmentResponses, appointmentRequest *models.MyAppointmentRequests) error {
+func (repo *repositoryImpl) GetAppointmentByUserId(appointmentResponse *models.MyAppointmentResponses, appointmentRequest *models.MyAppointmentRequests, paginated *utils.PaginatedQuery, sorted *utils.SortedQuery, filtered *utils.FilteredQuery) error {
 	propertiesQuery := `SELECT property_id, property_name, property_type FROM properties`
 
 	ownersQuery := `SELECT user_id AS owner_user_id,
@@ -152,53 +202,64 @@ func (repo *repositoryImpl) GetAppointmentByUserId(appointmentResponse *models.M
 								p.*,
 								o.*,
 								a.appointment_date,
+								a.duration_minutes,
 								a.status,
 								a.note,
 								a.cancelled_message,
-								a.created_at
+								a.created_at,
+								a.updated_at
 							FROM appointments a
 							JOIN (` + propertiesQuery + `) AS p ON a.property_id = p.property_id
 							JOIN (` + ownersQuery + `) AS o ON a.owner_user_id = o.owner_user_id`
 
-	return repo.db.Transaction(func(tx *gorm.DB) error {
+	orderSQL := sorted.SortedSQL()
+	if orderSQL == "" {
+		orderSQL = `ORDER BY a.created_at ` + appointmentRequest.Order
+	}
+
+	getAppointmentLists := func(tx *gorm.DB, userColumn string, appointments *[]models.AppointmentLists, total *int64) error {
+		whereQuery := fmt.Sprintf(`WHERE %s = @userId AND (%s)`, userColumn, filtered.FilteredSQL())
+		args := append([]interface{}{sql.Named("userId", appointmentRequest.UserId)}, filtered.FilteredArgs()...)
+
 		if err := tx.Model(&models.Appointments{}).
-			Raw(appointmentListsQuery+`
-				WHERE a.owner_user_id = @userId
-				ORDER BY a.created_at `+appointmentRequest.Order+`
-				`, sql.Named("userId", appointmentRequest.UserId)).
-			Scan(&appointmentResponse.OwnerAppointments).Error; err != nil {
+			Raw(`SELECT COUNT(*) FROM (`+appointmentListsQuery+` `+whereQuery+`) AS lists`, args...).
+			Scan(total).Error; err != nil {
 			return err
 		}
 
 		if err := tx.Model(&models.Appointments{}).
 			Raw(appointmentListsQuery+`
-				WHERE a.dweller_user_id = @userId
-				ORDER BY a.created_at `+appointmentRequest.Order+`
-				`, sql.Named("userId", appointmentRequest.UserId)).
-			Scan(&appointmentResponse.DwellerAppointments).Error; err != nil {
+				`+whereQuery+`
+				`+orderSQL+`
+				`+paginated.PaginatedSQL(), args...).
+			Scan(appointments).Error; err != nil {
 			return err
 		}
 
-		for i, appointment := range appointmentResponse.OwnerAppointments {
-			if err := repo.db.Model(&models.PropertyImages{}).
+		for i, appointment := range *appointments {
+			if err := tx.Model(&models.PropertyImages{}).
 				Raw(`
 					SELECT image_url
 					FROM property_images
-					WHERE property_id = @property_id
+					WHERE property_id = @property_id AND deleted_at IS NULL
 					`, sql.Named("property_id", appointment.Property.PropertyId)).
-				Pluck("image_url", &appointmentResponse.OwnerAppointments[i].Property.PropertyImages).Error; err != nil {
+				Pluck("image_url", &(*appointments)[i].Property.PropertyImages).Error; err != nil {
 				return err
 			}
 		}
 
-		for i, appointment := range appointmentResponse.DwellerAppointments {
-			if err := repo.db.Model(&models.PropertyImages{}).
-				Raw(`
-					SELECT image_url
-					FROM property_images
-					WHERE property_id = @property_id
-					`, sql.Named("property_id", appointment.Property.PropertyId)).
-				Pluck("image_url", &appointmentResponse.DwellerAppointments[i].Property.PropertyImages).Error; err != nil {
+		return nil
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if appointmentRequest.Role != enums.DwellerActor {
+			if err := getAppointmentLists(tx, "a.owner_user_id", &appointmentResponse.OwnerAppointments, &appointmentResponse.OwnerTotal); err != nil {
+				return err
+			}
+		}
+
+		if appointmentRequest.Role != enums.OwnerActor {
+			if err := getAppointmentLists(tx, "a.dweller_user_id", &appointmentResponse.DwellerAppointments, &appointmentResponse.DwellerTotal); err != nil {
 				return err
 			}
 		}
@@ -208,8 +269,14 @@ func (repo *repositoryImpl) GetAppointmentByUserId(appointmentResponse *models.M
 }
 
 func (repo *repositoryImpl) CreateAppointment(appointment *models.CreatingAppointments) error {
-	return repo.db.Exec(`INSERT INTO appointments (property_id, owner_user_id, dweller_user_id, appointment_date, note) VALUES (?, ?, ?, ?, ?)`,
-		appointment.PropertyId, appointment.OwnerUserId, appointment.DwellerUserId, appointment.AppointmentDate, appointment.Note).Error
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkOverlappingAppointments(tx, appointment.PropertyId, appointment.AppointmentDate, appointment.DurationMinutes, uuid.Nil); err != nil {
+			return err
+		}
+
+		return tx.Exec(`INSERT INTO appointments (property_id, owner_user_id, dweller_user_id, appointment_date, duration_minutes, note) VALUES (?, ?, ?, ?, ?, ?)`,
+			appointment.PropertyId, appointment.OwnerUserId, appointment.DwellerUserId, appointment.AppointmentDate, appointment.DurationMinutes, appointment.Note).Error
+	})
 }
 
 func (repo *repositoryImpl) DeleteAppointment(appointmentId string) error {
@@ -220,10 +287,437 @@ func (repo *repositoryImpl) DeleteAppointment(appointmentId string) error {
 	return repo.db.Where("appointment_id = ?", appointmentId).Delete(&models.Appointments{}).Error
 }
 
-func (repo *repositoryImpl) UpdateAppointmentStatus(updatingAppointment *models.UpdatingAppointmentStatus, appointmentId string) error {
-	if err := repo.db.Model(&models.Appointments{}).First(&models.Appointments{}, "appointment_id = ?", appointmentId).Error; err != nil {
+func (repo *repositoryImpl) GetAppointment(appointment *models.Appointments, appointmentId string) error {
+	return repo.db.Model(&models.Appointments{}).First(appointment, "appointment_id = ?", appointmentId).Error
+}
+
+func (repo *repositoryImpl) UpdateAppointmentStatus(updatingAppointment *models.UpdatingAppointmentStatus, appointmentId string, history *models.AppointmentStatusHistories) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// only move the appointment if nobody else has moved it since it was read
+		result := tx.Model(&models.Appointments{}).
+			Where("appointment_id = ? AND status = ?", appointmentId, history.FromStatus).
+			Updates(map[string]interface{}{
+				"status":            updatingAppointment.Status,
+				"cancelled_message": gorm.Expr("COALESCE(NULLIF(?, ''), cancelled_message)", updatingAppointment.CancelledMessage),
+				"updated_at":        gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errAppointmentStatusChanged
+		}
+
+		history.HistoryId = uuid.New()
+		history.AppointmentId = uuid.MustParse(appointmentId)
+		history.ToStatus = updatingAppointment.Status
+		history.Message = updatingAppointment.CancelledMessage
+
+		return tx.Create(history).Error
+	})
+}
+
+func (repo *repositoryImpl) GetAppointmentStatusHistories(histories *[]models.AppointmentStatusHistories, appointmentId string) error {
+	return repo.db.Model(&models.AppointmentStatusHistories{}).
+		Where("appointment_id = ?", appointmentId).
+		Order("created_at ASC").
+		Find(histories).Error
+}
+
+// GetEndedAppointments lists appointments in the status that were over before
+// endedBefore.
+func (repo *repositoryImpl) GetEndedAppointments(appointments *[]models.Appointments, status enums.AppointmentStatus, endedBefore time.Time) error {
+	return repo.db.Model(&models.Appointments{}).
+		Where("status = ? AND appointment_date + duration_minutes * INTERVAL '1 minute' <= ?", status, endedBefore).
+		Find(appointments).Error
+}
+
+// GetExpiredPendingAppointments lists pending appointments whose date has
+// passed or that were requested before requestedBefore.
+func (repo *repositoryImpl) GetExpiredPendingAppointments(appointments *[]models.Appointments, now time.Time, requestedBefore time.Time) error {
+	return repo.db.Model(&models.Appointments{}).
+		Where("status = ? AND (appointment_date <= ? OR created_at <= ?)", enums.PendingAppointment, now, requestedBefore).
+		Find(appointments).Error
+}
+
+func (repo *repositoryImpl) GetNotificationRecipient(recipient *models.NotificationRecipients, userId uuid.UUID) error {
+	result := repo.db.Raw(`
+		SELECT u.user_id,
+			   u.email,
+			   u.first_name,
+			   COALESCE(np.appointment_updates, TRUE) AS appointment_updates
+		FROM users u
+		LEFT JOIN user_notification_preferences np ON np.user_id = u.user_id
+		WHERE u.user_id = ?
+		`, userId).
+		Scan(recipient)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetDueAppointmentReminders lists one row per party of every confirmed
+// appointment dated within (from, to] whose reminder for the offset has not been
+// sent yet, leaving out users who turned appointment reminders off.
+func (repo *repositoryImpl) GetDueAppointmentReminders(reminders *[]models.DueAppointmentReminders, offsetSeconds int64, from time.Time, to time.Time) error {
+	return repo.db.Raw(`
+		WITH parties AS (
+			SELECT appointment_id, owner_user_id AS user_id, dweller_user_id AS counterpart_user_id, TRUE AS is_owner FROM appointments
+			UNION ALL
+			SELECT appointment_id, dweller_user_id AS user_id, owner_user_id AS counterpart_user_id, FALSE AS is_owner FROM appointments
+		)
+		SELECT a.appointment_id,
+			   a.appointment_date,
+			   a.duration_minutes,
+			   p.property_name,
+			   CONCAT_WS(', ', NULLIF(p.address, ''), NULLIF(p.alley, ''), NULLIF(p.street, ''), NULLIF(p.sub_district, ''),
+						 NULLIF(p.district, ''), NULLIF(p.province, ''), NULLIF(p.country, ''), NULLIF(p.postal_code, '')) AS location,
+			   u.user_id AS recipient_user_id,
+			   u.email AS recipient_email,
+			   u.first_name AS recipient_first_name,
+			   pt.is_owner AS recipient_is_owner,
+			   c.first_name AS counterpart_first_name,
+			   c.last_name AS counterpart_last_name,
+			   c.phone_number AS counterpart_phone_number
+		FROM appointments a
+		JOIN parties pt ON pt.appointment_id = a.appointment_id
+		JOIN properties p ON p.property_id = a.property_id
+		JOIN users u ON u.user_id = pt.user_id
+		JOIN users c ON c.user_id = pt.counterpart_user_id
+		LEFT JOIN user_notification_preferences np ON np.user_id = u.user_id
+		WHERE a.status = 'CONFIRMED'
+			AND a.appointment_date > @from
+			AND a.appointment_date <= @to
+			AND COALESCE(np.appointment_reminders, TRUE)
+			AND NOT EXISTS (
+				SELECT 1 FROM appointment_reminders r
+				WHERE r.appointment_id = a.appointment_id
+					AND r.user_id = u.user_id
+					AND r.offset_seconds = @offset
+					AND r.appointment_date = a.appointment_date
+			)
+		ORDER BY a.appointment_date
+		`, sql.Named("from", from), sql.Named("to", to), sql.Named("offset", offsetSeconds)).
+		Scan(reminders).Error
+}
+
+// CreateAppointmentReminder claims the reminder before it is sent so that two
+// instances of the job can never send it both.
+func (repo *repositoryImpl) CreateAppointmentReminder(reminder *models.AppointmentReminders) error {
+	result := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reminder)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errReminderAlreadySent
+	}
+
+	return nil
+}
+
+func (repo *repositoryImpl) DeleteAppointmentReminder(reminder *models.AppointmentReminders) error {
+	return repo.db.
+		Where("appointment_id = ? AND user_id = ? AND offset_seconds = ? AND appointment_date = ?",
+			reminder.AppointmentId, reminder.UserId, reminder.OffsetSeconds, reminder.AppointmentDate).
+		Delete(&models.AppointmentReminders{}).Error
+}
+
+func (repo *repositoryImpl) GetAppointmentAttendances(attendances *[]models.AppointmentAttendances, appointmentId string) error {
+	return repo.db.Model(&models.AppointmentAttendances{}).
+		Where("appointment_id = ?", appointmentId).
+		Order("created_at ASC").
+		Find(attendances).Error
+}
+
+func (repo *repositoryImpl) CreateAppointmentAttendance(attendance *models.AppointmentAttendances) error {
+	return repo.db.Create(attendance).Error
+}
+
+// CheckInAppointmentAttendance records a check-in, replacing a no-show someone
+// else has reported for the same party. It fails with gorm.ErrDuplicatedKey if
+// the party has already checked in.
+func (repo *repositoryImpl) CheckInAppointmentAttendance(attendance *models.AppointmentAttendances) error {
+	result := repo.db.Raw(`
+		INSERT INTO appointment_attendances (appointment_id, user_id, role, status, reported_by_user_id)
+		VALUES (@appointment_id, @user_id, @role, @status, @reported_by_user_id)
+		ON CONFLICT (appointment_id, user_id) DO UPDATE
+		SET status = EXCLUDED.status,
+			reported_by_user_id = EXCLUDED.reported_by_user_id,
+			created_at = CURRENT_TIMESTAMP
+		WHERE appointment_attendances.status = 'NO_SHOW'
+		RETURNING created_at
+		`, sql.Named("appointment_id", attendance.AppointmentId),
+		sql.Named("user_id", attendance.UserId),
+		sql.Named("role", attendance.Role),
+		sql.Named("status", attendance.Status),
+		sql.Named("reported_by_user_id", attendance.ReportedByUserId)).
+		Scan(&attendance.CreatedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrDuplicatedKey
+	}
+
+	return nil
+}
+
+// GetAttendanceStatistics counts check-ins and no-shows of the user, only in
+// the given role unless role is empty.
+func (repo *repositoryImpl) GetAttendanceStatistics(statistics *models.AttendanceStatistics, userId string, role enums.ActorRoles) error {
+	return repo.db.Raw(`
+		SELECT COUNT(*) FILTER (WHERE status = 'CHECKED_IN') AS check_ins,
+			   COUNT(*) FILTER (WHERE status = 'NO_SHOW') AS no_shows
+		FROM appointment_attendances
+		WHERE user_id = @user_id AND (@role = '' OR role::TEXT = @role)
+		`, sql.Named("user_id", userId), sql.Named("role", string(role))).
+		Scan(statistics).Error
+}
+
+// CountOwnerAppointmentsWithDweller counts the appointments, deleted or not,
+// that the dweller has made to view a property of the owner.
+func (repo *repositoryImpl) CountOwnerAppointmentsWithDweller(count *int64, ownerUserId uuid.UUID, dwellerUserId string) error {
+	return repo.db.Raw(`SELECT COUNT(*) FROM _appointments WHERE owner_user_id = ? AND dweller_user_id = ?`, ownerUserId, dwellerUserId).
+		Scan(count).Error
+}
+
+// GetCalendarAppointments gets the latest appointments the user is the owner or
+// the dweller of, together with their property and both parties, in a single
+// query. Property images are left out since calendars have no use for them.
+func (repo *repositoryImpl) GetCalendarAppointments(appointments *[]models.AppointmentDetails, userId uuid.UUID, limit int) error {
+	return repo.db.Model(&models.Appointments{}).
+		Raw(`
+			SELECT a.appointment_id,
+				   p.property_id, p.property_name, p.property_type, p.address,
+				   p.alley, p.street, p.sub_district, p.district, p.province, p.country,
+				   p.postal_code, s.price, r.price_per_month,
+				   o.user_id AS owner_user_id,
+				   o.first_name AS owner_first_name,
+				   o.last_name AS owner_last_name,
+				   o.profile_image_url AS owner_profile_image_url,
+				   o.phone_number AS owner_phone_number,
+				   d.user_id AS dweller_user_id,
+				   d.first_name AS dweller_first_name,
+				   d.last_name AS dweller_last_name,
+				   d.profile_image_url AS dweller_profile_image_url,
+				   d.phone_number AS dweller_phone_number,
+				   a.appointment_date,
+				   a.duration_minutes,
+				   a.status,
+				   a.note,
+				   a.cancelled_message,
+				   a.created_at,
+				   a.updated_at
+				FROM appointments a
+				JOIN properties p ON a.property_id = p.property_id
+				LEFT JOIN selling_properties s ON (p.property_id = s.property_id AND s.deleted_at IS NULL)
+				LEFT JOIN renting_properties r ON (p.property_id = r.property_id AND r.deleted_at IS NULL)
+				JOIN users o ON a.owner_user_id = o.user_id
+				JOIN users d ON a.dweller_user_id = d.user_id
+				WHERE a.owner_user_id = @user_id OR a.dweller_user_id = @user_id
+				ORDER BY a.appointment_date DESC
+				LIMIT @limit
+		`, sql.Named("user_id", userId), sql.Named("limit", limit)).
+		Scan(appointments).Error
+}
+
+func (repo *repositoryImpl) GetCalendarFeedByUserId(feed *models.CalendarFeeds, userId uuid.UUID) error {
+	return repo.db.Model(&models.CalendarFeeds{}).First(feed, "user_id = ?", userId).Error
+}
+
+func (repo *repositoryImpl) GetCalendarFeedByToken(feed *models.CalendarFeeds, token string) error {
+	return repo.db.Model(&models.CalendarFeeds{}).First(feed, "token = ?", token).Error
+}
+
+// SaveCalendarFeed replaces the token of the user's feed, creating the feed if
+// the user does not have one yet.
+func (repo *repositoryImpl) SaveCalendarFeed(feed *models.CalendarFeeds) error {
+	return repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token", "created_at"}),
+	}).Create(feed).Error
+}
+
+func (repo *repositoryImpl) GetPropertyOwnerId(ownerId *uuid.UUID, propertyId string) error {
+	var property models.Properties
+	if err := repo.db.Model(&models.Properties{}).Select("owner_id").First(&property, "property_id = ?", propertyId).Error; err != nil {
 		return err
 	}
-	
-	return repo.db.Model(&models.Appointments{}).Where("appointment_id = ?", appointmentId).Updates(updatingAppointment).Error
+
+	*ownerId = property.OwnerId
+	return nil
+}
+
+func (repo *repositoryImpl) GetPropertyAvailabilities(availabilities *[]models.PropertyAvailabilities, propertyId string) error {
+	return repo.db.Model(&models.PropertyAvailabilities{}).
+		Where("property_id = ?", propertyId).
+		Order("day_of_week ASC, start_time ASC").
+		Find(availabilities).Error
+}
+
+func (repo *repositoryImpl) ReplacePropertyAvailabilities(propertyId string, availabilities []models.PropertyAvailabilities) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("property_id = ?", propertyId).Delete(&models.PropertyAvailabilities{}).Error; err != nil {
+			return err
+		}
+
+		if len(availabilities) == 0 {
+			return nil
+		}
+
+		return tx.Create(&availabilities).Error
+	})
+}
+
+func (repo *repositoryImpl) GetAvailabilityExceptions(exceptions *[]models.PropertyAvailabilityExceptions, propertyId string, from time.Time, to time.Time) error {
+	return repo.db.Model(&models.PropertyAvailabilityExceptions{}).
+		Where("property_id = ? AND exception_date >= ?::date AND exception_date < ?::date", propertyId, from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Order("exception_date ASC, start_time ASC NULLS FIRST").
+		Find(exceptions).Error
+}
+
+func (repo *repositoryImpl) CreateAvailabilityException(exception *models.PropertyAvailabilityExceptions) error {
+	return repo.db.Create(exception).Error
+}
+
+func (repo *repositoryImpl) DeleteAvailabilityException(propertyId string, exceptionId string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.PropertyAvailabilityExceptions{}, "property_id = ? AND exception_id = ?", propertyId, exceptionId).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("exception_id = ?", exceptionId).Delete(&models.PropertyAvailabilityExceptions{}).Error
+	})
+}
+
+func (repo *repositoryImpl) GetActiveAppointmentsBetween(appointments *[]models.Appointments, propertyId string, from time.Time, to time.Time) error {
+	return repo.db.Model(&models.Appointments{}).
+		Where("property_id = ? AND "+activeAppointmentsCondition, propertyId).
+		Where("appointment_date < ? AND appointment_date + make_interval(mins => duration_minutes) > ?", to, from).
+		Find(appointments).Error
+}
+
+// GetUpcomingActiveAppointments lists the pending and confirmed appointments of
+// a property that start after from.
+func (repo *repositoryImpl) GetUpcomingActiveAppointments(appointments *[]models.Appointments, propertyId string, from time.Time) error {
+	return repo.db.Model(&models.Appointments{}).
+		Where("property_id = ? AND "+activeAppointmentsCondition, propertyId).
+		Where("appointment_date > ?", from).
+		Order("appointment_date ASC").
+		Find(appointments).Error
+}
+
+func (repo *repositoryImpl) GetAppointmentProposals(proposals *[]models.AppointmentProposals, appointmentId string) error {
+	return repo.db.Model(&models.AppointmentProposals{}).
+		Where("appointment_id = ?", appointmentId).
+		Order("created_at ASC").
+		Find(proposals).Error
+}
+
+func (repo *repositoryImpl) GetAppointmentProposal(proposal *models.AppointmentProposals, appointmentId string, proposalId string) error {
+	return repo.db.Model(&models.AppointmentProposals{}).
+		First(proposal, "appointment_id = ? AND proposal_id = ?", appointmentId, proposalId).Error
+}
+
+func (repo *repositoryImpl) CreateAppointmentProposal(proposal *models.AppointmentProposals) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// a new proposal is a counter-offer to whatever is still open
+		if err := tx.Model(&models.AppointmentProposals{}).
+			Where("appointment_id = ? AND status = ?", proposal.AppointmentId, enums.PendingProposal).
+			Update("status", enums.SupersededProposal).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(proposal).Error
+	})
+}
+
+func (repo *repositoryImpl) AcceptAppointmentProposal(proposal *models.AppointmentProposals, appointment *models.Appointments, respondedByUserId uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := respondToProposal(tx, proposal, enums.AcceptedProposal, respondedByUserId); err != nil {
+			return err
+		}
+
+		if err := checkOverlappingAppointments(tx, appointment.PropertyId, proposal.ProposedDate, proposal.DurationMinutes, appointment.AppointmentId); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Appointments{}).
+			Where("appointment_id = ?", appointment.AppointmentId).
+			Updates(map[string]interface{}{
+				"appointment_date": proposal.ProposedDate,
+				"duration_minutes": proposal.DurationMinutes,
+				"updated_at":       gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error
+	})
+}
+
+func (repo *repositoryImpl) DeclineAppointmentProposal(proposal *models.AppointmentProposals, respondedByUserId uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		return respondToProposal(tx, proposal, enums.DeclinedProposal, respondedByUserId)
+	})
+}
+
+func respondToProposal(tx *gorm.DB, proposal *models.AppointmentProposals, status enums.ProposalStatus, respondedByUserId uuid.UUID) error {
+	now := time.Now()
+	result := tx.Model(&models.AppointmentProposals{}).
+		Where("proposal_id = ? AND status = ?", proposal.ProposalId, enums.PendingProposal).
+		Updates(map[string]interface{}{
+			"status":               status,
+			"responded_by_user_id": respondedByUserId,
+			"responded_at":         now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errProposalNotPending
+	}
+
+	proposal.Status = status
+	proposal.RespondedByUserId = &respondedByUserId
+	proposal.RespondedAt = &now
+	return nil
+}
+
+// checkOverlappingAppointments locks the property, so that concurrent bookings
+// are serialized, and fails when [start, start + duration) overlaps another
+// active appointment of the property.
+func checkOverlappingAppointments(tx *gorm.DB, propertyId uuid.UUID, start time.Time, durationMinutes int64, excludeAppointmentId uuid.UUID) error {
+	if err := tx.Exec(`SELECT 1 FROM _properties WHERE property_id = ? FOR UPDATE`, propertyId).Error; err != nil {
+		return err
+	}
+
+	var overlaps int64
+	if err := tx.Raw(`
+		SELECT COUNT(*)
+		FROM appointments
+		WHERE property_id = @property_id
+			AND appointment_id <> @exclude_appointment_id
+			AND `+activeAppointmentsCondition+`
+			AND appointment_date < @end_time
+			AND appointment_date + make_interval(mins => duration_minutes) > @start_time
+		`, sql.Named("property_id", propertyId),
+		sql.Named("exclude_appointment_id", excludeAppointmentId),
+		sql.Named("start_time", start),
+		sql.Named("end_time", start.Add(time.Duration(durationMinutes)*time.Minute))).
+		Scan(&overlaps).Error; err != nil {
+		return err
+	}
+
+	if overlaps > 0 {
+		return errAppointmentOverlaps
+	}
+
+	return nil
 }
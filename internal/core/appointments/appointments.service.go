@@ -2,33 +2,122 @@ package appointments
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/emails"
 	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type Service interface {
 	GetAllAppointments(*[]models.AppointmentLists) *apperror.AppError
-	GetAppointmentById(*models.AppointmentDetails, string) *apperror.AppError
-	GetMyAppointments(*models.MyAppointmentResponses, *models.MyAppointmentRequests) *apperror.AppError
+	GetAppointmentById(*models.AppointmentDetails, string, *models.Sessions) *apperror.AppError
+	GetMyAppointments(*models.MyAppointmentResponses, *models.MyAppointmentRequests, *utils.PaginatedQuery, *utils.SortedQuery, *utils.FilteredQuery) *apperror.AppError
 	CreateAppointment(*models.CreatingAppointments) *apperror.AppError
 	DeleteAppointment(string) *apperror.AppError
-	UpdateAppointmentStatus(*models.UpdatingAppointmentStatus, string) *apperror.AppError
+	UpdateAppointmentStatus(*models.UpdatingAppointmentStatus, string, *models.Sessions) *apperror.AppError
+	ArchiveAppointment(string) *apperror.AppError
+	GetAppointmentStatusHistories(*[]models.AppointmentStatusHistories, string, *models.Sessions) *apperror.AppError
+	GetPropertyAvailabilities(*models.PropertyAvailabilityResponses, string) *apperror.AppError
+	UpdatePropertyAvailabilities(string, *models.UpdatingPropertyAvailabilities, *models.Sessions) *apperror.AppError
+	CreateAvailabilityException(*models.PropertyAvailabilityExceptions, string, *models.CreatingAvailabilityExceptions, *models.Sessions) *apperror.AppError
+	DeleteAvailabilityException(string, string, *models.Sessions) *apperror.AppError
+	GetOpenSlots(*[]models.AppointmentSlots, string, string, string) *apperror.AppError
+	GetAppointmentProposals(*[]models.AppointmentProposals, string, *models.Sessions) *apperror.AppError
+	CreateAppointmentProposal(*models.AppointmentProposals, string, *models.CreatingAppointmentProposals, *models.Sessions) *apperror.AppError
+	AcceptAppointmentProposal(string, string, *models.Sessions) *apperror.AppError
+	DeclineAppointmentProposal(string, string, *models.Sessions) *apperror.AppError
+	CheckInAppointment(*models.AppointmentAttendances, string, *models.Sessions) *apperror.AppError
+	ReportNoShow(*models.AppointmentAttendances, string, *models.Sessions) *apperror.AppError
+	GetAppointmentAttendances(*[]models.AppointmentAttendances, string, *models.Sessions) *apperror.AppError
+	GetAttendanceStatistics(*models.AttendanceStatistics, string, enums.ActorRoles, *models.Sessions) *apperror.AppError
+	GetCalendarFeed(*models.CalendarFeeds, *models.Sessions) *apperror.AppError
+	RegenerateCalendarFeed(*models.CalendarFeeds, *models.Sessions) *apperror.AppError
+	GetCalendarByToken(*utils.ICalendars, string) *apperror.AppError
+	GetAppointmentCalendar(*utils.ICalendars, string, *models.Sessions) *apperror.AppError
+	SendAppointmentReminders()
+	ArchivePastAppointments()
+	CancelUpcomingAppointments(string, string)
 }
 
+// appointmentTransitions lists, for every status, the statuses an appointment
+// may move to and who is allowed to make each move.
+var appointmentTransitions = map[enums.AppointmentStatus]map[enums.AppointmentStatus][]enums.ActorRoles{
+	enums.PendingAppointment: {
+		enums.ConfirmedAppointment: {enums.OwnerActor},
+		enums.RejectedAppointment:  {enums.OwnerActor},
+		enums.CancelledAppointment: {enums.OwnerActor, enums.DwellerActor, enums.SystemActor},
+		enums.ArchivedAppointment:  {enums.SystemActor},
+		enums.ExpiredAppointment:   {enums.SystemActor},
+	},
+	enums.ConfirmedAppointment: {
+		enums.CancelledAppointment: {enums.OwnerActor, enums.DwellerActor, enums.SystemActor},
+		enums.ArchivedAppointment:  {enums.SystemActor},
+	},
+	enums.RejectedAppointment: {
+		enums.ArchivedAppointment: {enums.SystemActor},
+	},
+	enums.CancelledAppointment: {
+		enums.ArchivedAppointment: {enums.SystemActor},
+	},
+}
+
+// appointmentEventStatus maps appointment statuses onto VEVENT statuses so that
+// calendar clients show rejected and cancelled appointments as cancelled events.
+var appointmentEventStatus = map[enums.AppointmentStatus]string{
+	enums.PendingAppointment:   "TENTATIVE",
+	enums.ConfirmedAppointment: "CONFIRMED",
+	enums.RejectedAppointment:  "CANCELLED",
+	enums.CancelledAppointment: "CANCELLED",
+	enums.ArchivedAppointment:  "CONFIRMED",
+	enums.ExpiredAppointment:   "CANCELLED",
+}
+
+const (
+	calendarFeedTokenSize = 32
+	maxCalendarEvents     = 500
+	calendarName          = "Suechaokhai Appointments"
+	emailDateLayout       = "Monday 2 January 2006, 15:04"
+)
+
+// a party can check in from shortly before the viewing until a while after it,
+// and can report the other party once they are late by the no-show grace
+const (
+	checkInOpensBefore = 30 * time.Minute
+	checkInClosesAfter = 2 * time.Hour
+	noShowGrace        = 15 * time.Minute
+	noShowClosesAfter  = 48 * time.Hour
+)
+
+const (
+	minSlotDuration = 15
+	maxSlotDuration = 240
+	maxSlotRange    = 31 * 24 * time.Hour
+)
+
 type serviceImpl struct {
-	logger *zap.Logger
-	repo   Repository
+	logger       *zap.Logger
+	cfg          *config.Config
+	repo         Repository
+	emailService emails.Service
 }
 
-func NewService(logger *zap.Logger, repo Repository) Service {
+func NewService(logger *zap.Logger, cfg *config.Config, repo Repository, emailService emails.Service) Service {
 	return &serviceImpl{
 		logger,
+		cfg,
 		repo,
+		emailService,
 	}
 }
 
@@ -44,7 +133,7 @@ func (s *serviceImpl) GetAllAppointments(appointments *[]models.AppointmentLists
 	return nil
 }
 
-func (s *serviceImpl) GetAppointmentById(appointment *models.AppointmentDetails, appointmentId string) *apperror.AppError {
+func (s *serviceImpl) GetAppointmentById(appointment *models.AppointmentDetails, appointmentId string, session *models.Sessions) *apperror.AppError {
 	if !utils.IsValidUUID(appointmentId) {
 		return apperror.
 			New(apperror.InvalidAppointmentId).
@@ -63,15 +152,30 @@ func (s *serviceImpl) GetAppointmentById(appointment *models.AppointmentDetails,
 			Describe("Could not get appointment by id")
 	}
 
-	return nil
+	// lets the owner judge how reliable the dweller is before confirming
+	if appointment.Owner.OwnerUserId != session.UserId && !session.IsAdmin {
+		return nil
+	}
+
+	appointment.DwellerAttendance = &models.AttendanceStatistics{}
+	return s.GetAttendanceStatistics(appointment.DwellerAttendance, appointment.Dweller.DwellerUserId.String(), enums.DwellerActor, session)
 }
 
-func (s *serviceImpl) GetMyAppointments(appointments *models.MyAppointmentResponses, appointmentRequest *models.MyAppointmentRequests) *apperror.AppError {
+func (s *serviceImpl) GetMyAppointments(appointments *models.MyAppointmentResponses, appointmentRequest *models.MyAppointmentRequests, paginated *utils.PaginatedQuery, sorted *utils.SortedQuery, filtered *utils.FilteredQuery) *apperror.AppError {
 	if appointmentRequest.Order != "ASC" && appointmentRequest.Order != "DESC" {
 		appointmentRequest.Order = "ASC"
 	}
 
-	err := s.repo.GetAppointmentByUserId(appointments, appointmentRequest)
+	if appointmentRequest.Role != "" && appointmentRequest.Role != enums.OwnerActor && appointmentRequest.Role != enums.DwellerActor {
+		return apperror.
+			New(apperror.BadRequest).
+			Describe("Role can only be OWNER or DWELLER")
+	}
+
+	appointments.OwnerAppointments = []models.AppointmentLists{}
+	appointments.DwellerAppointments = []models.AppointmentLists{}
+
+	err := s.repo.GetAppointmentByUserId(appointments, appointmentRequest, paginated, sorted, filtered)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return apperror.
 			New(apperror.AppointmentNotFound).
@@ -87,8 +191,48 @@ func (s *serviceImpl) GetMyAppointments(appointments *models.MyAppointmentRespon
 }
 
 func (s *serviceImpl) CreateAppointment(appointment *models.CreatingAppointments) *apperror.AppError {
+	if !appointment.AppointmentDate.After(time.Now()) {
+		return apperror.
+			New(apperror.AppointmentSlotNotFree).
+			Describe("Appointment date must be in the future")
+	}
+
+	// the owner always comes from the property, never from the body
+	if apperr := s.getPropertyOwnerId(&appointment.OwnerUserId, appointment.PropertyId.String()); apperr != nil {
+		return apperr
+	}
+
+	if appointment.OwnerUserId == appointment.DwellerUserId {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Owners cannot book appointments at their own property")
+	}
+
+	day := utils.StartOfDay(appointment.AppointmentDate)
+
+	var slots []models.AppointmentSlots
+	apperr := s.getOpenSlots(&slots, appointment.PropertyId.String(), day, day.AddDate(0, 0, 1), uuid.Nil)
+	if apperr != nil {
+		return apperr
+	}
+
+	idx := slices.IndexFunc(slots, func(slot models.AppointmentSlots) bool {
+		return slot.StartTime.Equal(appointment.AppointmentDate)
+	})
+	if idx == -1 {
+		return apperror.
+			New(apperror.AppointmentSlotNotFree).
+			Describe("Appointment date is not one of the open slots of the property")
+	}
+
+	appointment.DurationMinutes = int64(slots[idx].EndTime.Sub(slots[idx].StartTime) / time.Minute)
+
 	err := s.repo.CreateAppointment(appointment)
-	if err != nil {
+	if errors.Is(err, errAppointmentOverlaps) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.AppointmentSlotNotFree).
+			Describe("The slot has just been booked by someone else")
+	} else if err != nil {
 		s.logger.Error("Could not create appointments", zap.Error(err))
 		return apperror.
 			New(apperror.InternalServerError).
@@ -120,13 +264,7 @@ func (s *serviceImpl) DeleteAppointment(appointmentId string) *apperror.AppError
 	return nil
 }
 
-func (s *serviceImpl) UpdateAppointmentStatus(updatingAppointment *models.UpdatingAppointmentStatus, appointmentId string) *apperror.AppError {
-	if !utils.IsValidUUID(appointmentId) {
-		return apperror.
-			New(apperror.InvalidAppointmentId).
-			Describe("Invalid appointment id")
-	}
-
+func (s *serviceImpl) UpdateAppointmentStatus(updatingAppointment *models.UpdatingAppointmentStatus, appointmentId string, session *models.Sessions) *apperror.AppError {
 	_, ok := enums.AppointmentStatusMap[string(updatingAppointment.Status)]
 	if !ok {
 		return apperror.
@@ -134,17 +272,1268 @@ func (s *serviceImpl) UpdateAppointmentStatus(updatingAppointment *models.Updati
 			Describe("Invalid appointment status")
 	}
 
-	err := s.repo.UpdateAppointmentStatus(updatingAppointment, appointmentId)
+	var appointment models.Appointments
+	if apperr := s.getAppointment(&appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := appointmentRole(&appointment, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	return s.transitionAppointment(&appointment, updatingAppointment, role, &session.UserId)
+}
+
+func (s *serviceImpl) ArchiveAppointment(appointmentId string) *apperror.AppError {
+	var appointment models.Appointments
+	if apperr := s.getAppointment(&appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	updatingAppointment := models.UpdatingAppointmentStatus{
+		Status: enums.ArchivedAppointment,
+	}
+
+	return s.transitionAppointment(&appointment, &updatingAppointment, enums.SystemActor, nil)
+}
+
+func (s *serviceImpl) GetAppointmentStatusHistories(histories *[]models.AppointmentStatusHistories, appointmentId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	if apperr := s.getAppointment(&appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	if session.UserId != appointment.OwnerUserId && session.UserId != appointment.DwellerUserId && !session.IsAdmin {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner or the dweller can view this appointment")
+	}
+
+	err := s.repo.GetAppointmentStatusHistories(histories, appointmentId)
+	if err != nil {
+		s.logger.Error("Could not get appointment status histories", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get appointment status histories")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetAppointmentProposals(proposals *[]models.AppointmentProposals, appointmentId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	if apperr := s.getAppointment(&appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := appointmentRole(&appointment, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	err := s.repo.GetAppointmentProposals(proposals, appointmentId)
+	if err != nil {
+		s.logger.Error("Could not get appointment proposals", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get appointment proposals")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) CreateAppointmentProposal(proposal *models.AppointmentProposals, appointmentId string, creating *models.CreatingAppointmentProposals, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	if apperr := s.getAppointment(&appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := appointmentRole(&appointment, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !isReschedulable(&appointment) {
+		return apperror.
+			New(apperror.AppointmentNotReschedulable).
+			Describe("Only pending or confirmed appointments can be rescheduled")
+	}
+
+	if !creating.ProposedDate.After(time.Now()) || creating.ProposedDate.Equal(appointment.AppointmentDate) {
+		return apperror.
+			New(apperror.AppointmentSlotNotFree).
+			Describe("Proposed date must be in the future and differ from the current appointment date")
+	}
+
+	durationMinutes := appointment.DurationMinutes
+	if role == enums.OwnerActor {
+		// owners may offer any time outside of their published availability as
+		// long as the property is not booked for it
+		var booked []models.Appointments
+		end := creating.ProposedDate.Add(time.Duration(durationMinutes) * time.Minute)
+		if err := s.repo.GetActiveAppointmentsBetween(&booked, appointment.PropertyId.String(), creating.ProposedDate, end); err != nil {
+			s.logger.Error("Could not get booked appointments", zap.String("id", appointmentId), zap.Error(err))
+			return apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not create appointment proposal")
+		}
+
+		if slices.ContainsFunc(booked, func(other models.Appointments) bool { return other.AppointmentId != appointment.AppointmentId }) {
+			return apperror.
+				New(apperror.AppointmentSlotNotFree).
+				Describe("The property is already booked at the proposed date")
+		}
+	} else {
+		day := utils.StartOfDay(creating.ProposedDate)
+
+		var slots []models.AppointmentSlots
+		if apperr := s.getOpenSlots(&slots, appointment.PropertyId.String(), day, day.AddDate(0, 0, 1), appointment.AppointmentId); apperr != nil {
+			return apperr
+		}
+
+		idx := slices.IndexFunc(slots, func(slot models.AppointmentSlots) bool {
+			return slot.StartTime.Equal(creating.ProposedDate)
+		})
+		if idx == -1 {
+			return apperror.
+				New(apperror.AppointmentSlotNotFree).
+				Describe("Proposed date is not one of the open slots of the property")
+		}
+
+		durationMinutes = int64(slots[idx].EndTime.Sub(slots[idx].StartTime) / time.Minute)
+	}
+
+	*proposal = models.AppointmentProposals{
+		ProposalId:       uuid.New(),
+		AppointmentId:    appointment.AppointmentId,
+		ProposedByUserId: session.UserId,
+		ProposerRole:     role,
+		ProposedDate:     creating.ProposedDate,
+		DurationMinutes:  durationMinutes,
+		Status:           enums.PendingProposal,
+		Message:          creating.Message,
+	}
+
+	err := s.repo.CreateAppointmentProposal(proposal)
+	if err != nil {
+		s.logger.Error("Could not create appointment proposal", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create appointment proposal")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) AcceptAppointmentProposal(appointmentId string, proposalId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	var proposal models.AppointmentProposals
+	if apperr := s.getRespondableProposal(&appointment, &proposal, appointmentId, proposalId, session); apperr != nil {
+		return apperr
+	}
+
+	if !proposal.ProposedDate.After(time.Now()) {
+		return apperror.
+			New(apperror.AppointmentSlotNotFree).
+			Describe("Proposed date has already passed")
+	}
+
+	err := s.repo.AcceptAppointmentProposal(&proposal, &appointment, session.UserId)
+	if errors.Is(err, errProposalNotPending) {
+		return apperror.
+			New(apperror.ProposalNotPending).
+			Describe("Proposal has already been answered or superseded")
+	} else if errors.Is(err, errAppointmentOverlaps) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.AppointmentSlotNotFree).
+			Describe("The property has been booked at the proposed date in the meantime")
+	} else if err != nil {
+		s.logger.Error("Could not accept appointment proposal", zap.String("id", proposalId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not accept appointment proposal")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) DeclineAppointmentProposal(appointmentId string, proposalId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	var proposal models.AppointmentProposals
+	if apperr := s.getRespondableProposal(&appointment, &proposal, appointmentId, proposalId, session); apperr != nil {
+		return apperr
+	}
+
+	err := s.repo.DeclineAppointmentProposal(&proposal, session.UserId)
+	if errors.Is(err, errProposalNotPending) {
+		return apperror.
+			New(apperror.ProposalNotPending).
+			Describe("Proposal has already been answered or superseded")
+	} else if err != nil {
+		s.logger.Error("Could not decline appointment proposal", zap.String("id", proposalId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not decline appointment proposal")
+	}
+
+	return nil
+}
+
+// getRespondableProposal loads a pending proposal that the current user may
+// answer, which is any proposal made by the other party of the appointment.
+func (s *serviceImpl) getRespondableProposal(appointment *models.Appointments, proposal *models.AppointmentProposals, appointmentId string, proposalId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getAppointment(appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	role, apperr := appointmentRole(appointment, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	if !utils.IsValidUUID(proposalId) {
+		return apperror.
+			New(apperror.InvalidProposalId).
+			Describe("Invalid proposal id")
+	}
+
+	err := s.repo.GetAppointmentProposal(proposal, appointmentId, proposalId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return apperror.
-			New(apperror.AppointmentNotFound).
-			Describe("Could not find the specified appointment")
+			New(apperror.ProposalNotFound).
+			Describe("Could not find the specified proposal")
 	} else if err != nil {
-		s.logger.Error("Could not update appointment statue", zap.Error(err))
+		s.logger.Error("Could not get appointment proposal", zap.String("id", proposalId), zap.Error(err))
 		return apperror.
 			New(apperror.InternalServerError).
-			Describe("Could not set appointment status")
+			Describe("Could not get appointment proposal")
+	}
+
+	if proposal.Status != enums.PendingProposal {
+		return apperror.
+			New(apperror.ProposalNotPending).
+			Describe("Proposal has already been answered or superseded")
+	}
+
+	if proposal.ProposerRole == role {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the other party can answer this proposal")
+	}
+
+	if !isReschedulable(appointment) {
+		return apperror.
+			New(apperror.AppointmentNotReschedulable).
+			Describe("Only pending or confirmed appointments can be rescheduled")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) CheckInAppointment(attendance *models.AppointmentAttendances, appointmentId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	role, apperr := s.getAttendableAppointment(&appointment, appointmentId, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	now := time.Now()
+	start := appointment.AppointmentDate
+	end := start.Add(time.Duration(appointment.DurationMinutes) * time.Minute)
+	if now.Before(start.Add(-checkInOpensBefore)) || now.After(end.Add(checkInClosesAfter)) {
+		return apperror.
+			New(apperror.AttendanceWindowClosed).
+			Describe("Check-in is open from 30 minutes before until 2 hours after the appointment")
+	}
+
+	*attendance = models.AppointmentAttendances{
+		AppointmentId:    appointment.AppointmentId,
+		UserId:           session.UserId,
+		Role:             role,
+		Status:           enums.CheckedInAttendance,
+		ReportedByUserId: session.UserId,
+	}
+
+	// checking in overrides a no-show the other party reported too early
+	err := s.repo.CheckInAppointmentAttendance(attendance)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.AttendanceAlreadyRecorded).
+			Describe("You have already checked in to this appointment")
+	} else if err != nil {
+		s.logger.Error("Could not check in to appointment", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not record attendance")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) ReportNoShow(attendance *models.AppointmentAttendances, appointmentId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	role, apperr := s.getAttendableAppointment(&appointment, appointmentId, session)
+	if apperr != nil {
+		return apperr
+	}
+
+	now := time.Now()
+	start := appointment.AppointmentDate
+	end := start.Add(time.Duration(appointment.DurationMinutes) * time.Minute)
+	if now.Before(start.Add(noShowGrace)) || now.After(end.Add(noShowClosesAfter)) {
+		return apperror.
+			New(apperror.AttendanceWindowClosed).
+			Describe("A no-show can be reported from 15 minutes after the start until 48 hours after the appointment")
+	}
+
+	absentUserId, absentRole := appointment.DwellerUserId, enums.DwellerActor
+	if role == enums.DwellerActor {
+		absentUserId, absentRole = appointment.OwnerUserId, enums.OwnerActor
+	}
+
+	*attendance = models.AppointmentAttendances{
+		AppointmentId:    appointment.AppointmentId,
+		UserId:           absentUserId,
+		Role:             absentRole,
+		Status:           enums.NoShowAttendance,
+		ReportedByUserId: session.UserId,
+	}
+
+	return s.createAttendance(attendance)
+}
+
+func (s *serviceImpl) GetAppointmentAttendances(attendances *[]models.AppointmentAttendances, appointmentId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	if apperr := s.getAppointment(&appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := appointmentRole(&appointment, session); apperr != nil && !session.IsAdmin {
+		return apperr
+	}
+
+	err := s.repo.GetAppointmentAttendances(attendances, appointmentId)
+	if err != nil {
+		s.logger.Error("Could not get appointment attendances", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get appointment attendances")
+	}
+
+	return nil
+}
+
+// GetAttendanceStatistics gets how reliable a user is at turning up. Only the
+// user, an admin or an owner the user has made an appointment with can see it.
+func (s *serviceImpl) GetAttendanceStatistics(statistics *models.AttendanceStatistics, userId string, role enums.ActorRoles, session *models.Sessions) *apperror.AppError {
+	if !utils.IsValidUUID(userId) {
+		return apperror.
+			New(apperror.InvalidUserId).
+			Describe("Invalid user id")
+	}
+
+	if role != "" && role != enums.OwnerActor && role != enums.DwellerActor {
+		return apperror.
+			New(apperror.BadRequest).
+			Describe("Role can only be OWNER or DWELLER")
+	}
+
+	if session.UserId.String() != userId && !session.IsAdmin {
+		var appointments int64
+		err := s.repo.CountOwnerAppointmentsWithDweller(&appointments, session.UserId, userId)
+		if err != nil {
+			s.logger.Error("Could not count appointments with dweller", zap.String("user_id", userId), zap.Error(err))
+			return apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not get attendance statistics")
+		}
+
+		if appointments == 0 {
+			return apperror.
+				New(apperror.Forbidden).
+				Describe("Only owners the user has made an appointment with can see their attendance")
+		}
+	}
+
+	err := s.repo.GetAttendanceStatistics(statistics, userId, role)
+	if err != nil {
+		s.logger.Error("Could not get attendance statistics", zap.String("user_id", userId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get attendance statistics")
+	}
+
+	statistics.UserId = uuid.MustParse(userId)
+	statistics.NoShowRate = 0
+	if total := statistics.CheckIns + statistics.NoShows; total > 0 {
+		statistics.NoShowRate = float64(statistics.NoShows) / float64(total)
+	}
+
+	return nil
+}
+
+// getAttendableAppointment loads an appointment the session takes part in and
+// that was confirmed, returning the role of the session in it.
+func (s *serviceImpl) getAttendableAppointment(appointment *models.Appointments, appointmentId string, session *models.Sessions) (enums.ActorRoles, *apperror.AppError) {
+	if apperr := s.getAppointment(appointment, appointmentId); apperr != nil {
+		return "", apperr
+	}
+
+	role, apperr := appointmentRole(appointment, session)
+	if apperr != nil {
+		return "", apperr
+	}
+
+	// confirmed viewings are archived once they are over
+	if appointment.Status != enums.ConfirmedAppointment && appointment.Status != enums.ArchivedAppointment {
+		return "", apperror.
+			New(apperror.AppointmentNotAttendable).
+			Describe("Only confirmed appointments can be attended")
+	}
+
+	return role, nil
+}
+
+func (s *serviceImpl) createAttendance(attendance *models.AppointmentAttendances) *apperror.AppError {
+	err := s.repo.CreateAppointmentAttendance(attendance)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.AttendanceAlreadyRecorded).
+			Describe("Attendance of this party has already been recorded")
+	} else if err != nil {
+		s.logger.Error("Could not create appointment attendance", zap.String("id", attendance.AppointmentId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not record attendance")
 	}
 
 	return nil
 }
+
+func (s *serviceImpl) GetCalendarFeed(feed *models.CalendarFeeds, session *models.Sessions) *apperror.AppError {
+	err := s.repo.GetCalendarFeedByUserId(feed, session.UserId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.RegenerateCalendarFeed(feed, session)
+	} else if err != nil {
+		s.logger.Error("Could not get calendar feed", zap.String("user_id", session.UserId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get calendar feed")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) RegenerateCalendarFeed(feed *models.CalendarFeeds, session *models.Sessions) *apperror.AppError {
+	token, err := utils.RandomToken(calendarFeedTokenSize)
+	if err != nil {
+		s.logger.Error("Could not generate calendar feed token", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create calendar feed")
+	}
+
+	*feed = models.CalendarFeeds{
+		UserId: session.UserId,
+		Token:  token,
+	}
+
+	err = s.repo.SaveCalendarFeed(feed)
+	if err != nil {
+		s.logger.Error("Could not save calendar feed", zap.String("user_id", session.UserId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create calendar feed")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetCalendarByToken(calendar *utils.ICalendars, token string) *apperror.AppError {
+	var feed models.CalendarFeeds
+	err := s.repo.GetCalendarFeedByToken(&feed, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.CalendarFeedNotFound).
+			Describe("Could not find the specified calendar feed")
+	} else if err != nil {
+		s.logger.Error("Could not get calendar feed", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get calendar feed")
+	}
+
+	// the feed carries the latest appointments, which is plenty for what
+	// calendar clients show
+	var appointments []models.AppointmentDetails
+	err = s.repo.GetCalendarAppointments(&appointments, feed.UserId, maxCalendarEvents)
+	if err != nil {
+		s.logger.Error("Could not get calendar appointments", zap.String("user_id", feed.UserId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get calendar feed")
+	}
+
+	calendar.Name = calendarName
+	calendar.Events = []utils.ICalEvents{}
+	for i := range appointments {
+		calendar.Events = append(calendar.Events, appointmentEvent(&appointments[i], feed.UserId))
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetAppointmentCalendar(calendar *utils.ICalendars, appointmentId string, session *models.Sessions) *apperror.AppError {
+	var appointment models.Appointments
+	if apperr := s.getAppointment(&appointment, appointmentId); apperr != nil {
+		return apperr
+	}
+
+	if _, apperr := appointmentRole(&appointment, session); apperr != nil {
+		return apperr
+	}
+
+	var details models.AppointmentDetails
+	err := s.repo.GetAppointmentById(&details, appointmentId)
+	if err != nil {
+		s.logger.Error("Could not get appointment by id", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get appointment by id")
+	}
+
+	calendar.Name = calendarName
+	calendar.Events = []utils.ICalEvents{appointmentEvent(&details, session.UserId)}
+
+	return nil
+}
+
+// SendAppointmentReminders emails both parties of confirmed appointments that
+// are about to start. Every offset only covers the time up to the next smaller
+// offset, so an appointment booked at short notice, or a job that was down for
+// a while, does not send reminders that have already been overtaken.
+func (s *serviceImpl) SendAppointmentReminders() {
+	offsets := slices.Clone(s.cfg.ReminderOffsets)
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+
+	now := time.Now()
+	for i, offset := range offsets {
+		if offset <= 0 {
+			continue
+		}
+
+		until := 0
+		if i+1 < len(offsets) && offsets[i+1] > 0 {
+			until = offsets[i+1]
+		}
+
+		var reminders []models.DueAppointmentReminders
+		err := s.repo.GetDueAppointmentReminders(&reminders, int64(offset),
+			now.Add(time.Duration(until)*time.Second), now.Add(time.Duration(offset)*time.Second))
+		if err != nil {
+			s.logger.Error("Could not get due appointment reminders", zap.Int("offset", offset), zap.Error(err))
+			continue
+		}
+
+		for j := range reminders {
+			s.sendAppointmentReminder(&reminders[j], int64(offset))
+		}
+	}
+}
+
+func (s *serviceImpl) sendAppointmentReminder(due *models.DueAppointmentReminders, offsetSeconds int64) {
+	reminder := models.AppointmentReminders{
+		AppointmentId:   due.AppointmentId,
+		UserId:          due.RecipientUserId,
+		OffsetSeconds:   offsetSeconds,
+		AppointmentDate: due.AppointmentDate,
+	}
+
+	err := s.repo.CreateAppointmentReminder(&reminder)
+	if errors.Is(err, errReminderAlreadySent) {
+		return
+	} else if err != nil {
+		s.logger.Error("Could not create appointment reminder", zap.String("id", due.AppointmentId.String()), zap.Error(err))
+		return
+	}
+
+	counterpartRole := "Owner"
+	if due.RecipientIsOwner {
+		counterpartRole = "Dweller"
+	}
+
+	email := models.AppointmentReminderEmails{
+		FirstName:              due.RecipientFirstName,
+		PropertyName:           due.PropertyName,
+		AppointmentDate:        due.AppointmentDate.In(utils.LocalTimezone).Format(emailDateLayout),
+		Location:               due.Location,
+		CounterpartRole:        counterpartRole,
+		CounterpartName:        due.CounterpartFirstName + " " + due.CounterpartLastName,
+		CounterpartPhoneNumber: due.CounterpartPhoneNumber,
+	}
+
+	apperr := s.emailService.SendAppointmentReminderEmail(due.RecipientEmail, &email)
+	if apperr != nil {
+		// release the claim so that the next run tries again
+		if err := s.repo.DeleteAppointmentReminder(&reminder); err != nil {
+			s.logger.Error("Could not delete appointment reminder", zap.String("id", due.AppointmentId.String()), zap.Error(err))
+		}
+	}
+}
+
+// ArchivePastAppointments archives confirmed appointments once they have been
+// over for the archive grace period, and expires pending requests the owner did
+// not answer within the pending expiry or before the appointment date.
+func (s *serviceImpl) ArchivePastAppointments() {
+	now := time.Now()
+
+	var ended []models.Appointments
+	err := s.repo.GetEndedAppointments(&ended, enums.ConfirmedAppointment, now.Add(-time.Duration(s.cfg.ArchiveGrace)*time.Second))
+	if err != nil {
+		s.logger.Error("Could not get ended appointments", zap.Error(err))
+	}
+
+	for _, appointment := range ended {
+		appointmentId := appointment.AppointmentId.String()
+		if apperr := s.ArchiveAppointment(appointmentId); apperr != nil {
+			s.logger.Warn("Could not archive appointment", zap.String("id", appointmentId), zap.Error(apperr))
+		}
+	}
+
+	// a non positive expiry leaves pending requests open until their date
+	var requestedBefore time.Time
+	if s.cfg.PendingExpiry > 0 {
+		requestedBefore = now.Add(-time.Duration(s.cfg.PendingExpiry) * time.Second)
+	}
+
+	var expired []models.Appointments
+	err = s.repo.GetExpiredPendingAppointments(&expired, now, requestedBefore)
+	if err != nil {
+		s.logger.Error("Could not get expired pending appointments", zap.Error(err))
+	}
+
+	for i := range expired {
+		s.expireAppointment(&expired[i])
+	}
+}
+
+func (s *serviceImpl) expireAppointment(appointment *models.Appointments) {
+	appointmentId := appointment.AppointmentId.String()
+
+	updatingAppointment := models.UpdatingAppointmentStatus{
+		Status:           enums.ExpiredAppointment,
+		CancelledMessage: "The owner did not respond to the request in time",
+	}
+
+	if apperr := s.transitionAppointment(appointment, &updatingAppointment, enums.SystemActor, nil); apperr != nil {
+		s.logger.Warn("Could not expire appointment", zap.String("id", appointmentId), zap.Error(apperr))
+		return
+	}
+
+	var dweller models.NotificationRecipients
+	err := s.repo.GetNotificationRecipient(&dweller, appointment.DwellerUserId)
+	if err != nil {
+		s.logger.Error("Could not get dweller of expired appointment", zap.String("id", appointmentId), zap.Error(err))
+		return
+	}
+
+	if !dweller.AppointmentUpdates {
+		return
+	}
+
+	var details models.AppointmentDetails
+	err = s.repo.GetAppointmentById(&details, appointmentId)
+	if err != nil {
+		s.logger.Error("Could not get appointment by id", zap.String("id", appointmentId), zap.Error(err))
+		return
+	}
+
+	email := models.AppointmentExpiredEmails{
+		FirstName:       dweller.FirstName,
+		PropertyName:    details.Property.PropertyName,
+		AppointmentDate: appointment.AppointmentDate.In(utils.LocalTimezone).Format(emailDateLayout),
+	}
+
+	// the email service logs its own failures and the expiry stands either way
+	_ = s.emailService.SendAppointmentExpiredEmail(dweller.Email, &email)
+}
+
+// CancelUpcomingAppointments cancels the pending and confirmed appointments of
+// a property that has become unavailable, e.g. because it has been sold, and
+// lets the dwellers know why.
+func (s *serviceImpl) CancelUpcomingAppointments(propertyId string, reason string) {
+	var upcoming []models.Appointments
+	err := s.repo.GetUpcomingActiveAppointments(&upcoming, propertyId, time.Now())
+	if err != nil {
+		s.logger.Error("Could not get upcoming appointments", zap.String("id", propertyId), zap.Error(err))
+		return
+	}
+
+	for i := range upcoming {
+		s.cancelAppointment(&upcoming[i], reason)
+	}
+}
+
+func (s *serviceImpl) cancelAppointment(appointment *models.Appointments, reason string) {
+	appointmentId := appointment.AppointmentId.String()
+
+	updatingAppointment := models.UpdatingAppointmentStatus{
+		Status:           enums.CancelledAppointment,
+		CancelledMessage: reason,
+	}
+
+	if apperr := s.transitionAppointment(appointment, &updatingAppointment, enums.SystemActor, nil); apperr != nil {
+		s.logger.Warn("Could not cancel appointment", zap.String("id", appointmentId), zap.Error(apperr))
+		return
+	}
+
+	var dweller models.NotificationRecipients
+	err := s.repo.GetNotificationRecipient(&dweller, appointment.DwellerUserId)
+	if err != nil {
+		s.logger.Error("Could not get dweller of cancelled appointment", zap.String("id", appointmentId), zap.Error(err))
+		return
+	}
+
+	if !dweller.AppointmentUpdates {
+		return
+	}
+
+	var details models.AppointmentDetails
+	err = s.repo.GetAppointmentById(&details, appointmentId)
+	if err != nil {
+		s.logger.Error("Could not get appointment by id", zap.String("id", appointmentId), zap.Error(err))
+		return
+	}
+
+	email := models.AppointmentCancelledEmails{
+		FirstName:       dweller.FirstName,
+		PropertyName:    details.Property.PropertyName,
+		AppointmentDate: appointment.AppointmentDate.In(utils.LocalTimezone).Format(emailDateLayout),
+		Reason:          reason,
+	}
+
+	// the email service logs its own failures and the cancellation stands either way
+	_ = s.emailService.SendAppointmentCancelledEmail(dweller.Email, &email)
+}
+
+func (s *serviceImpl) getAppointment(appointment *models.Appointments, appointmentId string) *apperror.AppError {
+	if !utils.IsValidUUID(appointmentId) {
+		return apperror.
+			New(apperror.InvalidAppointmentId).
+			Describe("Invalid appointment id")
+	}
+
+	err := s.repo.GetAppointment(appointment, appointmentId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.AppointmentNotFound).
+			Describe("Could not find the specified appointment")
+	} else if err != nil {
+		s.logger.Error("Could not get appointment", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get appointment")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) transitionAppointment(appointment *models.Appointments, updatingAppointment *models.UpdatingAppointmentStatus, role enums.ActorRoles, actorUserId *uuid.UUID) *apperror.AppError {
+	roles, ok := appointmentTransitions[appointment.Status][updatingAppointment.Status]
+	if !ok {
+		return apperror.
+			New(apperror.InvalidAppointmentTransition).
+			Describe(fmt.Sprintf("Appointment could not be moved from %v to %v", appointment.Status, updatingAppointment.Status))
+	}
+
+	if !slices.Contains(roles, role) {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe(fmt.Sprintf("The %v is not allowed to move an appointment to %v", strings.ToLower(string(role)), updatingAppointment.Status))
+	}
+
+	// only rejections, cancellations and expiries carry a message
+	if updatingAppointment.Status != enums.RejectedAppointment && updatingAppointment.Status != enums.CancelledAppointment &&
+		updatingAppointment.Status != enums.ExpiredAppointment {
+		updatingAppointment.CancelledMessage = ""
+	}
+
+	history := models.AppointmentStatusHistories{
+		FromStatus:  appointment.Status,
+		ActorUserId: actorUserId,
+		ActorRole:   role,
+	}
+
+	appointmentId := appointment.AppointmentId.String()
+	err := s.repo.UpdateAppointmentStatus(updatingAppointment, appointmentId, &history)
+	if errors.Is(err, errAppointmentStatusChanged) {
+		return apperror.
+			New(apperror.InvalidAppointmentTransition).
+			Describe("Appointment status has just been changed. Please try again.")
+	} else if err != nil {
+		s.logger.Error("Could not update appointment status", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not set appointment status")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetPropertyAvailabilities(availabilities *models.PropertyAvailabilityResponses, propertyId string) *apperror.AppError {
+	if apperr := s.checkPropertyExists(propertyId); apperr != nil {
+		return apperr
+	}
+
+	availabilities.Availabilities = []models.PropertyAvailabilities{}
+	if err := s.repo.GetPropertyAvailabilities(&availabilities.Availabilities, propertyId); err != nil {
+		s.logger.Error("Could not get property availabilities", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get property availabilities")
+	}
+
+	// past exceptions are irrelevant to anyone planning a viewing
+	today := utils.StartOfDay(time.Now())
+	availabilities.Exceptions = []models.PropertyAvailabilityExceptions{}
+	if err := s.repo.GetAvailabilityExceptions(&availabilities.Exceptions, propertyId, today, today.AddDate(10, 0, 0)); err != nil {
+		s.logger.Error("Could not get availability exceptions", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get property availabilities")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) UpdatePropertyAvailabilities(propertyId string, updating *models.UpdatingPropertyAvailabilities, session *models.Sessions) *apperror.AppError {
+	if apperr := s.checkPropertyOwner(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	availabilities := updating.Availabilities
+	for i := range availabilities {
+		availability := &availabilities[i]
+
+		if availability.DayOfWeek < 0 || availability.DayOfWeek > 6 {
+			return apperror.
+				New(apperror.InvalidAvailability).
+				Describe("Day of week must be between 0 (Sunday) and 6 (Saturday)")
+		}
+
+		if apperr := validateWindow(&availability.StartTime, &availability.EndTime, availability.SlotDuration); apperr != nil {
+			return apperr
+		}
+
+		availability.AvailabilityId = uuid.New()
+		availability.PropertyId = uuid.MustParse(propertyId)
+	}
+
+	sort.Slice(availabilities, func(i, j int) bool {
+		if availabilities[i].DayOfWeek != availabilities[j].DayOfWeek {
+			return availabilities[i].DayOfWeek < availabilities[j].DayOfWeek
+		}
+		return availabilities[i].StartTime < availabilities[j].StartTime
+	})
+
+	for i := 1; i < len(availabilities); i++ {
+		prev, curr := availabilities[i-1], availabilities[i]
+		if prev.DayOfWeek == curr.DayOfWeek && curr.StartTime < prev.EndTime {
+			return apperror.
+				New(apperror.InvalidAvailability).
+				Describe("Availabilities on the same day must not overlap")
+		}
+	}
+
+	err := s.repo.ReplacePropertyAvailabilities(propertyId, availabilities)
+	if err != nil {
+		s.logger.Error("Could not update property availabilities", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not update property availabilities")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) CreateAvailabilityException(exception *models.PropertyAvailabilityExceptions, propertyId string, creating *models.CreatingAvailabilityExceptions, session *models.Sessions) *apperror.AppError {
+	if apperr := s.checkPropertyOwner(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	date, err := utils.ParseLocalDate(creating.ExceptionDate)
+	if err != nil {
+		return apperror.
+			New(apperror.InvalidAvailability).
+			Describe("Exception date must be in YYYY-MM-DD format")
+	}
+
+	if date.Before(utils.StartOfDay(time.Now())) {
+		return apperror.
+			New(apperror.InvalidAvailability).
+			Describe("Exception date must not be in the past")
+	}
+
+	if (creating.StartTime == nil) != (creating.EndTime == nil) {
+		return apperror.
+			New(apperror.InvalidAvailability).
+			Describe("Start time and end time must be given together")
+	}
+
+	if creating.IsAvailable {
+		if creating.StartTime == nil || creating.SlotDuration == nil {
+			return apperror.
+				New(apperror.InvalidAvailability).
+				Describe("An extra opening needs a start time, an end time and a slot duration")
+		}
+
+		if apperr := validateWindow(creating.StartTime, creating.EndTime, *creating.SlotDuration); apperr != nil {
+			return apperr
+		}
+	} else {
+		if creating.StartTime != nil && *creating.StartTime >= *creating.EndTime {
+			return apperror.
+				New(apperror.InvalidAvailability).
+				Describe("Start time must be before end time")
+		}
+
+		creating.SlotDuration = nil
+	}
+
+	*exception = models.PropertyAvailabilityExceptions{
+		ExceptionId: uuid.New(),
+		PropertyId:  uuid.MustParse(propertyId),
+		// DATE columns carry no timezone, so the calendar date is stored as is
+		ExceptionDate: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		StartTime:     creating.StartTime,
+		EndTime:       creating.EndTime,
+		IsAvailable:   creating.IsAvailable,
+		SlotDuration:  creating.SlotDuration,
+		Note:          creating.Note,
+	}
+
+	err = s.repo.CreateAvailabilityException(exception)
+	if err != nil {
+		s.logger.Error("Could not create availability exception", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create availability exception")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) DeleteAvailabilityException(propertyId string, exceptionId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.checkPropertyOwner(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	if !utils.IsValidUUID(exceptionId) {
+		return apperror.
+			New(apperror.InvalidExceptionId).
+			Describe("Invalid exception id")
+	}
+
+	err := s.repo.DeleteAvailabilityException(propertyId, exceptionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.ExceptionNotFound).
+			Describe("Could not find the specified exception")
+	} else if err != nil {
+		s.logger.Error("Could not delete availability exception", zap.String("id", exceptionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not delete availability exception")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetOpenSlots(slots *[]models.AppointmentSlots, propertyId string, fromDate string, toDate string) *apperror.AppError {
+	from := utils.StartOfDay(time.Now())
+	if fromDate != "" {
+		parsed, err := utils.ParseLocalDate(fromDate)
+		if err != nil {
+			return apperror.
+				New(apperror.InvalidSlotRange).
+				Describe("From date must be in YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, 7)
+	if toDate != "" {
+		parsed, err := utils.ParseLocalDate(toDate)
+		if err != nil {
+			return apperror.
+				New(apperror.InvalidSlotRange).
+				Describe("To date must be in YYYY-MM-DD format")
+		}
+		// the to date is inclusive
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) || to.Sub(from) > maxSlotRange {
+		return apperror.
+			New(apperror.InvalidSlotRange).
+			Describe("Slot range must span between 1 and 31 days")
+	}
+
+	return s.getOpenSlots(slots, propertyId, from, to, uuid.Nil)
+}
+
+// getOpenSlots ignores the excluded appointment when looking for booked slots so
+// that an appointment can be moved into a slot that overlaps its current one.
+func (s *serviceImpl) getOpenSlots(slots *[]models.AppointmentSlots, propertyId string, from time.Time, to time.Time, excludeAppointmentId uuid.UUID) *apperror.AppError {
+	if apperr := s.checkPropertyExists(propertyId); apperr != nil {
+		return apperr
+	}
+
+	var availabilities []models.PropertyAvailabilities
+	if err := s.repo.GetPropertyAvailabilities(&availabilities, propertyId); err != nil {
+		s.logger.Error("Could not get property availabilities", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get open slots")
+	}
+
+	var exceptions []models.PropertyAvailabilityExceptions
+	if err := s.repo.GetAvailabilityExceptions(&exceptions, propertyId, from, to); err != nil {
+		s.logger.Error("Could not get availability exceptions", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get open slots")
+	}
+
+	var booked []models.Appointments
+	if err := s.repo.GetActiveAppointmentsBetween(&booked, propertyId, from, to); err != nil {
+		s.logger.Error("Could not get booked appointments", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get open slots")
+	}
+
+	booked = slices.DeleteFunc(booked, func(appointment models.Appointments) bool {
+		return appointment.AppointmentId == excludeAppointmentId
+	})
+
+	*slots = buildOpenSlots(from, to, time.Now(), availabilities, exceptions, booked)
+	return nil
+}
+
+func (s *serviceImpl) checkPropertyExists(propertyId string) *apperror.AppError {
+	var ownerId uuid.UUID
+	return s.getPropertyOwnerId(&ownerId, propertyId)
+}
+
+func (s *serviceImpl) checkPropertyOwner(propertyId string, session *models.Sessions) *apperror.AppError {
+	var ownerId uuid.UUID
+	if apperr := s.getPropertyOwnerId(&ownerId, propertyId); apperr != nil {
+		return apperr
+	}
+
+	if ownerId != session.UserId && !session.IsAdmin {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the property owner can manage its availability")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) getPropertyOwnerId(ownerId *uuid.UUID, propertyId string) *apperror.AppError {
+	if !utils.IsValidUUID(propertyId) {
+		return apperror.
+			New(apperror.InvalidPropertyId).
+			Describe("Invalid property id")
+	}
+
+	err := s.repo.GetPropertyOwnerId(ownerId, propertyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyNotFound).
+			Describe("Could not find the specified property")
+	} else if err != nil {
+		s.logger.Error("Could not get property owner", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get property")
+	}
+
+	return nil
+}
+
+func appointmentRole(appointment *models.Appointments, session *models.Sessions) (enums.ActorRoles, *apperror.AppError) {
+	switch session.UserId {
+	case appointment.OwnerUserId:
+		return enums.OwnerActor, nil
+	case appointment.DwellerUserId:
+		return enums.DwellerActor, nil
+	}
+
+	return "", apperror.
+		New(apperror.Forbidden).
+		Describe("Only the owner or the dweller can access this appointment")
+}
+
+// appointmentEvent describes the appointment as seen by userId, with the contact
+// of the other party in the description. The UID stays the same for the whole
+// life of the appointment and the sequence grows with every update, so calendar
+// clients replace the old event instead of adding a new one.
+func appointmentEvent(appointment *models.AppointmentDetails, userId uuid.UUID) utils.ICalEvents {
+	property := appointment.Property
+
+	var counterpart string
+	if userId == appointment.Owner.OwnerUserId {
+		counterpart = fmt.Sprintf("Dweller: %v %v (%v)", appointment.Dweller.DwellerFirstName, appointment.Dweller.DwellerLastName, appointment.Dweller.DwellerPhoneNumber)
+	} else {
+		counterpart = fmt.Sprintf("Owner: %v %v (%v)", appointment.Owner.OwnerFirstName, appointment.Owner.OwnerLastName, appointment.Owner.OwnerPhoneNumber)
+	}
+
+	description := []string{counterpart}
+	if appointment.Note != "" {
+		description = append(description, "Note: "+appointment.Note)
+	}
+	if appointment.CancelledMessage != "" {
+		description = append(description, "Cancelled: "+appointment.CancelledMessage)
+	}
+
+	var location []string
+	for _, part := range []string{property.Address, property.Alley, property.Street, property.SubDistrict, property.District, property.Province, property.Country, property.PostalCode} {
+		if part != "" {
+			location = append(location, part)
+		}
+	}
+
+	event := utils.ICalEvents{
+		Uid:         appointment.AppointmentId.String() + "@suechaokhai",
+		Status:      appointmentEventStatus[appointment.Status],
+		Summary:     "Property viewing: " + property.PropertyName,
+		Description: strings.Join(description, "\n"),
+		Location:    strings.Join(location, ", "),
+		Start:       appointment.AppointmentDate,
+		End:         appointment.AppointmentDate.Add(time.Duration(appointment.DurationMinutes) * time.Minute),
+	}
+
+	if appointment.CreatedAt != nil {
+		event.CreatedAt = *appointment.CreatedAt
+	}
+
+	if appointment.UpdatedAt != nil {
+		event.LastModified = *appointment.UpdatedAt
+		if appointment.CreatedAt != nil {
+			event.Sequence = int64(appointment.UpdatedAt.Sub(*appointment.CreatedAt) / time.Second)
+		}
+	}
+
+	return event
+}
+
+func isReschedulable(appointment *models.Appointments) bool {
+	return appointment.Status == enums.PendingAppointment || appointment.Status == enums.ConfirmedAppointment
+}
+
+func validateWindow(startTime *models.ClockTimes, endTime *models.ClockTimes, slotDuration int64) *apperror.AppError {
+	if *startTime >= *endTime {
+		return apperror.
+			New(apperror.InvalidAvailability).
+			Describe("Start time must be before end time")
+	}
+
+	if slotDuration < minSlotDuration || slotDuration > maxSlotDuration {
+		return apperror.
+			New(apperror.InvalidAvailability).
+			Describe("Slot duration must be between 15 and 240 minutes")
+	}
+
+	if int64(*endTime-*startTime) < slotDuration {
+		return apperror.
+			New(apperror.InvalidAvailability).
+			Describe("Availability must be long enough to fit at least one slot")
+	}
+
+	return nil
+}
+
+type slotWindow struct {
+	start    time.Time
+	end      time.Time
+	duration time.Duration
+}
+
+// buildOpenSlots expands the weekly availabilities and the date exceptions into
+// slots between from and to, dropping slots that are in the past, fall into a
+// blocked exception or overlap an appointment that is still active.
+func buildOpenSlots(from time.Time, to time.Time, now time.Time, availabilities []models.PropertyAvailabilities, exceptions []models.PropertyAvailabilityExceptions, booked []models.Appointments) []models.AppointmentSlots {
+	var open []slotWindow
+	var blocked []slotWindow
+
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, availability := range availabilities {
+			if time.Weekday(availability.DayOfWeek) == day.Weekday() {
+				open = append(open, slotWindow{
+					day.Add(availability.StartTime.Minutes()),
+					day.Add(availability.EndTime.Minutes()),
+					time.Duration(availability.SlotDuration) * time.Minute,
+				})
+			}
+		}
+
+		year, month, date := day.Date()
+		for _, exception := range exceptions {
+			exceptionYear, exceptionMonth, exceptionDate := exception.ExceptionDate.Date()
+			if exceptionYear != year || exceptionMonth != month || exceptionDate != date {
+				continue
+			}
+
+			switch {
+			case exception.IsAvailable:
+				open = append(open, slotWindow{
+					day.Add(exception.StartTime.Minutes()),
+					day.Add(exception.EndTime.Minutes()),
+					time.Duration(*exception.SlotDuration) * time.Minute,
+				})
+
+			case exception.StartTime == nil:
+				blocked = append(blocked, slotWindow{start: day, end: day.AddDate(0, 0, 1)})
+
+			default:
+				blocked = append(blocked, slotWindow{start: day.Add(exception.StartTime.Minutes()), end: day.Add(exception.EndTime.Minutes())})
+			}
+		}
+	}
+
+	for _, appointment := range booked {
+		start := appointment.AppointmentDate
+		blocked = append(blocked, slotWindow{start: start, end: start.Add(time.Duration(appointment.DurationMinutes) * time.Minute)})
+	}
+
+	seen := map[time.Time]bool{}
+	slots := []models.AppointmentSlots{}
+	for _, window := range open {
+		for start := window.start; !start.Add(window.duration).After(window.end); start = start.Add(window.duration) {
+			end := start.Add(window.duration)
+			if start.Before(now) || seen[start] || overlapsAny(start, end, blocked) {
+				continue
+			}
+
+			seen[start] = true
+			slots = append(slots, models.AppointmentSlots{StartTime: start, EndTime: end})
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].StartTime.Before(slots[j].StartTime)
+	})
+
+	return slots
+}
+
+func overlapsAny(start time.Time, end time.Time, windows []slotWindow) bool {
+	for _, window := range windows {
+		if start.Before(window.end) && window.start.Before(end) {
+			return true
+		}
+	}
+	return false
+}
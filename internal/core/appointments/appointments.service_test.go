@@ -0,0 +1,585 @@
+package appointments
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeRepository keeps the appointments of a single property in memory. The
+// methods a test does not need are left to the embedded interface.
+type fakeRepository struct {
+	Repository
+	ownerId        uuid.UUID
+	availabilities []models.PropertyAvailabilities
+	appointments   []models.Appointments
+	details        []models.AppointmentDetails
+	feed           models.CalendarFeeds
+	attendances    map[uuid.UUID]models.AppointmentAttendances
+	history        *models.AppointmentStatusHistories
+}
+
+func (repo *fakeRepository) GetAppointment(appointment *models.Appointments, appointmentId string) error {
+	for _, existing := range repo.appointments {
+		if existing.AppointmentId.String() == appointmentId {
+			*appointment = existing
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) UpdateAppointmentStatus(updatingAppointment *models.UpdatingAppointmentStatus, appointmentId string, history *models.AppointmentStatusHistories) error {
+	repo.history = history
+	return nil
+}
+
+func (repo *fakeRepository) CreateAppointmentAttendance(attendance *models.AppointmentAttendances) error {
+	if _, ok := repo.attendances[attendance.UserId]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	repo.attendances[attendance.UserId] = *attendance
+	return nil
+}
+
+func (repo *fakeRepository) CheckInAppointmentAttendance(attendance *models.AppointmentAttendances) error {
+	if existing, ok := repo.attendances[attendance.UserId]; ok && existing.Status != enums.NoShowAttendance {
+		return gorm.ErrDuplicatedKey
+	}
+	repo.attendances[attendance.UserId] = *attendance
+	return nil
+}
+
+func (repo *fakeRepository) GetCalendarFeedByToken(feed *models.CalendarFeeds, token string) error {
+	if token != repo.feed.Token {
+		return gorm.ErrRecordNotFound
+	}
+	*feed = repo.feed
+	return nil
+}
+
+func (repo *fakeRepository) GetCalendarAppointments(appointments *[]models.AppointmentDetails, userId uuid.UUID, limit int) error {
+	for _, appointment := range repo.details {
+		if len(*appointments) < limit && (appointment.Owner.OwnerUserId == userId || appointment.Dweller.DwellerUserId == userId) {
+			*appointments = append(*appointments, appointment)
+		}
+	}
+	return nil
+}
+
+func (repo *fakeRepository) GetPropertyOwnerId(ownerId *uuid.UUID, propertyId string) error {
+	*ownerId = repo.ownerId
+	return nil
+}
+
+func (repo *fakeRepository) GetPropertyAvailabilities(availabilities *[]models.PropertyAvailabilities, propertyId string) error {
+	*availabilities = repo.availabilities
+	return nil
+}
+
+func (repo *fakeRepository) GetAvailabilityExceptions(exceptions *[]models.PropertyAvailabilityExceptions, propertyId string, from time.Time, to time.Time) error {
+	return nil
+}
+
+func (repo *fakeRepository) GetActiveAppointmentsBetween(appointments *[]models.Appointments, propertyId string, from time.Time, to time.Time) error {
+	for _, appointment := range repo.appointments {
+		if isReschedulable(&appointment) && !appointment.AppointmentDate.Before(from) && appointment.AppointmentDate.Before(to) {
+			*appointments = append(*appointments, appointment)
+		}
+	}
+	return nil
+}
+
+// CreateAppointment enforces idx_appointments_active_slot, which only covers
+// pending and confirmed appointments.
+func (repo *fakeRepository) CreateAppointment(appointment *models.CreatingAppointments) error {
+	for _, existing := range repo.appointments {
+		if isReschedulable(&existing) && existing.AppointmentDate.Equal(appointment.AppointmentDate) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	repo.appointments = append(repo.appointments, models.Appointments{
+		AppointmentId:   uuid.New(),
+		PropertyId:      appointment.PropertyId,
+		OwnerUserId:     appointment.OwnerUserId,
+		DwellerUserId:   appointment.DwellerUserId,
+		AppointmentDate: appointment.AppointmentDate,
+		DurationMinutes: appointment.DurationMinutes,
+		Status:          enums.PendingAppointment,
+	})
+	return nil
+}
+
+func (repo *fakeRepository) CountOwnerAppointmentsWithDweller(count *int64, ownerUserId uuid.UUID, dwellerUserId string) error {
+	for _, appointment := range repo.appointments {
+		if appointment.OwnerUserId == ownerUserId && appointment.DwellerUserId.String() == dwellerUserId {
+			*count++
+		}
+	}
+	return nil
+}
+
+func (repo *fakeRepository) GetAttendanceStatistics(statistics *models.AttendanceStatistics, userId string, role enums.ActorRoles) error {
+	statistics.CheckIns, statistics.NoShows = 3, 1
+	return nil
+}
+
+func newFakeService(repo *fakeRepository) Service {
+	return NewService(zap.NewNop(), &config.Config{}, repo, nil)
+}
+
+// tomorrowAt is a slot of tomorrow in the platform's timezone, so that it is
+// always in the future.
+func tomorrowAt(hour int) time.Time {
+	return utils.StartOfDay(time.Now()).AddDate(0, 0, 1).Add(time.Duration(hour) * time.Hour)
+}
+
+func TestCreateAppointmentRebooksRejectedSlot(t *testing.T) {
+	slot := tomorrowAt(10)
+	repo := &fakeRepository{
+		ownerId: uuid.New(),
+		availabilities: []models.PropertyAvailabilities{
+			{DayOfWeek: int64(slot.Weekday()), StartTime: 9 * 60, EndTime: 12 * 60, SlotDuration: 60},
+		},
+	}
+	service := newFakeService(repo)
+
+	booking := func() *models.CreatingAppointments {
+		return &models.CreatingAppointments{
+			PropertyId:      uuid.New(),
+			DwellerUserId:   uuid.New(),
+			AppointmentDate: slot,
+		}
+	}
+
+	if apperr := service.CreateAppointment(booking()); apperr != nil {
+		t.Fatalf("first booking: %v", apperr)
+	}
+
+	if apperr := service.CreateAppointment(booking()); apperr == nil {
+		t.Fatal("booking a pending slot again should fail")
+	}
+
+	for _, status := range []enums.AppointmentStatus{enums.RejectedAppointment, enums.CancelledAppointment} {
+		repo.appointments[len(repo.appointments)-1].Status = status
+
+		if apperr := service.CreateAppointment(booking()); apperr != nil {
+			t.Fatalf("rebooking after %v: %v", status, apperr)
+		}
+	}
+
+	if len(repo.appointments) != 3 {
+		t.Errorf("got %v appointments, want 3", len(repo.appointments))
+	}
+}
+
+func clockTime(value string) *models.ClockTimes {
+	clock, err := models.ParseClockTime(value)
+	if err != nil {
+		panic(err)
+	}
+	return &clock
+}
+
+func TestBuildOpenSlots(t *testing.T) {
+	monday := time.Date(2024, 2, 19, 0, 0, 0, 0, time.UTC)
+	thirty := int64(30)
+
+	mondayMornings := []models.PropertyAvailabilities{
+		{DayOfWeek: int64(time.Monday), StartTime: *clockTime("09:00"), EndTime: *clockTime("11:00"), SlotDuration: 60},
+	}
+
+	tests := []struct {
+		name           string
+		from           time.Time
+		to             time.Time
+		now            time.Time
+		availabilities []models.PropertyAvailabilities
+		exceptions     []models.PropertyAvailabilityExceptions
+		booked         []models.Appointments
+		want           []string
+	}{
+		{
+			name:           "slots of the availability",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			availabilities: mondayMornings,
+			want:           []string{"2024-02-19 09:00", "2024-02-19 10:00"},
+		},
+		{
+			name:           "slot that does not fit is dropped",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			availabilities: []models.PropertyAvailabilities{{DayOfWeek: int64(time.Monday), StartTime: *clockTime("09:00"), EndTime: *clockTime("10:30"), SlotDuration: 60}},
+			want:           []string{"2024-02-19 09:00"},
+		},
+		{
+			name:           "other weekdays are closed",
+			from:           monday.AddDate(0, 0, 1),
+			to:             monday.AddDate(0, 0, 7),
+			availabilities: mondayMornings,
+			want:           []string{},
+		},
+		{
+			name:           "every matching day in the range",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 8),
+			availabilities: mondayMornings,
+			want:           []string{"2024-02-19 09:00", "2024-02-19 10:00", "2024-02-26 09:00", "2024-02-26 10:00"},
+		},
+		{
+			name:           "past slots are dropped",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			now:            monday.Add(9*time.Hour + 30*time.Minute),
+			availabilities: mondayMornings,
+			want:           []string{"2024-02-19 10:00"},
+		},
+		{
+			name:           "whole day exception",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			availabilities: mondayMornings,
+			exceptions:     []models.PropertyAvailabilityExceptions{{ExceptionDate: monday}},
+			want:           []string{},
+		},
+		{
+			name:           "partial exception blocks overlapping slots",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			availabilities: mondayMornings,
+			exceptions:     []models.PropertyAvailabilityExceptions{{ExceptionDate: monday, StartTime: clockTime("09:30"), EndTime: clockTime("09:45")}},
+			want:           []string{"2024-02-19 10:00"},
+		},
+		{
+			name:           "available exception opens extra slots",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			availabilities: mondayMornings,
+			exceptions:     []models.PropertyAvailabilityExceptions{{ExceptionDate: monday, StartTime: clockTime("14:00"), EndTime: clockTime("15:00"), IsAvailable: true, SlotDuration: &thirty}},
+			want:           []string{"2024-02-19 09:00", "2024-02-19 10:00", "2024-02-19 14:00", "2024-02-19 14:30"},
+		},
+		{
+			name:           "overlapping windows do not repeat slots",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			availabilities: append(mondayMornings, models.PropertyAvailabilities{DayOfWeek: int64(time.Monday), StartTime: *clockTime("10:00"), EndTime: *clockTime("11:00"), SlotDuration: 60}),
+			want:           []string{"2024-02-19 09:00", "2024-02-19 10:00"},
+		},
+		{
+			name:           "booked appointments block their slots",
+			from:           monday,
+			to:             monday.AddDate(0, 0, 1),
+			availabilities: mondayMornings,
+			booked:         []models.Appointments{{AppointmentDate: monday.Add(10 * time.Hour), DurationMinutes: 30}},
+			want:           []string{"2024-02-19 09:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := buildOpenSlots(tt.from, tt.to, tt.now, tt.availabilities, tt.exceptions, tt.booked)
+
+			got := []string{}
+			for _, slot := range slots {
+				got = append(got, slot.StartTime.Format("2006-01-02 15:04"))
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAppointmentOwner(t *testing.T) {
+	ownerId := uuid.New()
+	slot := tomorrowAt(10)
+
+	tests := []struct {
+		name          string
+		dwellerUserId uuid.UUID
+		bodyOwnerId   uuid.UUID
+		wantErr       bool
+	}{
+		{"owner comes from the property", uuid.New(), uuid.Nil, false},
+		{"owner in the body is ignored", uuid.New(), uuid.New(), false},
+		{"owner cannot book own property", ownerId, uuid.Nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{
+				ownerId: ownerId,
+				availabilities: []models.PropertyAvailabilities{
+					{DayOfWeek: int64(slot.Weekday()), StartTime: 9 * 60, EndTime: 12 * 60, SlotDuration: 60},
+				},
+			}
+
+			apperr := newFakeService(repo).CreateAppointment(&models.CreatingAppointments{
+				PropertyId:      uuid.New(),
+				OwnerUserId:     tt.bodyOwnerId,
+				DwellerUserId:   tt.dwellerUserId,
+				AppointmentDate: slot,
+			})
+			if (apperr != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", apperr, tt.wantErr)
+			}
+
+			if !tt.wantErr && repo.appointments[0].OwnerUserId != ownerId {
+				t.Errorf("got owner %v, want %v", repo.appointments[0].OwnerUserId, ownerId)
+			}
+		})
+	}
+}
+
+func TestUpdateAppointmentStatus(t *testing.T) {
+	owner, dweller := uuid.New(), uuid.New()
+
+	tests := []struct {
+		from    enums.AppointmentStatus
+		to      enums.AppointmentStatus
+		role    enums.ActorRoles
+		wantErr *apperror.AppErrorType
+	}{
+		{enums.PendingAppointment, enums.ConfirmedAppointment, enums.OwnerActor, nil},
+		{enums.PendingAppointment, enums.ConfirmedAppointment, enums.DwellerActor, apperror.Forbidden},
+		{enums.PendingAppointment, enums.RejectedAppointment, enums.OwnerActor, nil},
+		{enums.PendingAppointment, enums.RejectedAppointment, enums.DwellerActor, apperror.Forbidden},
+		{enums.PendingAppointment, enums.CancelledAppointment, enums.DwellerActor, nil},
+		{enums.PendingAppointment, enums.ExpiredAppointment, enums.OwnerActor, apperror.Forbidden},
+		{enums.ConfirmedAppointment, enums.CancelledAppointment, enums.OwnerActor, nil},
+		{enums.ConfirmedAppointment, enums.RejectedAppointment, enums.OwnerActor, apperror.InvalidAppointmentTransition},
+		{enums.ConfirmedAppointment, enums.PendingAppointment, enums.OwnerActor, apperror.InvalidAppointmentTransition},
+		{enums.RejectedAppointment, enums.ConfirmedAppointment, enums.OwnerActor, apperror.InvalidAppointmentTransition},
+		{enums.RejectedAppointment, enums.ArchivedAppointment, enums.SystemActor, nil},
+		{enums.CancelledAppointment, enums.PendingAppointment, enums.DwellerActor, apperror.InvalidAppointmentTransition},
+		{enums.ExpiredAppointment, enums.ArchivedAppointment, enums.SystemActor, apperror.InvalidAppointmentTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to)+" by "+string(tt.role), func(t *testing.T) {
+			appointment := models.Appointments{AppointmentId: uuid.New(), OwnerUserId: owner, DwellerUserId: dweller, Status: tt.from}
+			repo := &fakeRepository{appointments: []models.Appointments{appointment}}
+			s := &serviceImpl{logger: zap.NewNop(), repo: repo}
+
+			var apperr *apperror.AppError
+			switch tt.role {
+			case enums.OwnerActor:
+				apperr = s.UpdateAppointmentStatus(&models.UpdatingAppointmentStatus{Status: tt.to}, appointment.AppointmentId.String(), &models.Sessions{UserId: owner})
+			case enums.DwellerActor:
+				apperr = s.UpdateAppointmentStatus(&models.UpdatingAppointmentStatus{Status: tt.to}, appointment.AppointmentId.String(), &models.Sessions{UserId: dweller})
+			case enums.SystemActor:
+				apperr = s.ArchiveAppointment(appointment.AppointmentId.String())
+			}
+
+			if tt.wantErr == nil && apperr != nil {
+				t.Fatalf("error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Fatalf("error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+
+			if tt.wantErr != nil && repo.history != nil {
+				t.Errorf("refused transition wrote history %+v", repo.history)
+			} else if tt.wantErr == nil && (repo.history == nil || repo.history.FromStatus != tt.from || repo.history.ActorRole != tt.role) {
+				t.Errorf("history = %+v, want from %v by %v", repo.history, tt.from, tt.role)
+			}
+		})
+	}
+}
+
+// TestGetCalendarByToken relies on the embedded nil Repository to panic should
+// the feed fall back to loading appointments one by one.
+func TestGetCalendarByToken(t *testing.T) {
+	userId := uuid.New()
+	appointment := func(ownerUserId uuid.UUID, dwellerUserId uuid.UUID, status enums.AppointmentStatus) models.AppointmentDetails {
+		return models.AppointmentDetails{
+			AppointmentId:   uuid.New(),
+			Property:        models.PropertyAppointmentDetails{PropertyName: "The Base"},
+			Owner:           models.OwnerAppointmentDetails{OwnerUserId: ownerUserId, OwnerFirstName: "Owner"},
+			Dweller:         models.DwellerAppointmentDetails{DwellerUserId: dwellerUserId, DwellerFirstName: "Dweller"},
+			AppointmentDate: tomorrowAt(10),
+			DurationMinutes: 60,
+			Status:          status,
+		}
+	}
+
+	repo := &fakeRepository{
+		feed: models.CalendarFeeds{UserId: userId, Token: "token"},
+		details: []models.AppointmentDetails{
+			appointment(userId, uuid.New(), enums.PendingAppointment),
+			appointment(uuid.New(), userId, enums.CancelledAppointment),
+			appointment(uuid.New(), uuid.New(), enums.ConfirmedAppointment),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		wantErr     bool
+		wantStatus  []string
+		wantSummary string
+	}{
+		{"unknown token", "other", true, nil, ""},
+		{"appointments of both roles", "token", false, []string{"TENTATIVE", "CANCELLED"}, "Property viewing: The Base"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calendar utils.ICalendars
+			apperr := newFakeService(repo).GetCalendarByToken(&calendar, tt.token)
+			if (apperr != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", apperr, tt.wantErr)
+			}
+
+			var status []string
+			for _, event := range calendar.Events {
+				status = append(status, event.Status)
+				if event.Summary != tt.wantSummary {
+					t.Errorf("got summary %q, want %q", event.Summary, tt.wantSummary)
+				}
+			}
+
+			if !slices.Equal(status, tt.wantStatus) {
+				t.Errorf("got events %v, want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAppointmentAttendance(t *testing.T) {
+	owner := &models.Sessions{UserId: uuid.New()}
+	dweller := &models.Sessions{UserId: uuid.New()}
+
+	type step struct {
+		session *models.Sessions
+		noShow  bool
+		wantErr bool
+	}
+
+	tests := []struct {
+		name        string
+		steps       []step
+		wantOwner   enums.AttendanceStatus
+		wantDweller enums.AttendanceStatus
+	}{
+		{
+			name:        "both check in",
+			steps:       []step{{session: owner}, {session: dweller}},
+			wantOwner:   enums.CheckedInAttendance,
+			wantDweller: enums.CheckedInAttendance,
+		},
+		{
+			name:        "no-show stands without a check-in",
+			steps:       []step{{session: owner}, {session: owner, noShow: true}},
+			wantOwner:   enums.CheckedInAttendance,
+			wantDweller: enums.NoShowAttendance,
+		},
+		{
+			name:        "late check-in overrides the no-show",
+			steps:       []step{{session: owner, noShow: true}, {session: dweller}},
+			wantDweller: enums.CheckedInAttendance,
+		},
+		{
+			name:        "no-show cannot override a check-in",
+			steps:       []step{{session: dweller}, {session: owner, noShow: true, wantErr: true}},
+			wantDweller: enums.CheckedInAttendance,
+		},
+		{
+			name:      "checking in twice",
+			steps:     []step{{session: owner}, {session: owner, wantErr: true}},
+			wantOwner: enums.CheckedInAttendance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appointment := models.Appointments{
+				AppointmentId:   uuid.New(),
+				OwnerUserId:     owner.UserId,
+				DwellerUserId:   dweller.UserId,
+				AppointmentDate: time.Now().Add(-30 * time.Minute),
+				DurationMinutes: 60,
+				Status:          enums.ConfirmedAppointment,
+			}
+			repo := &fakeRepository{
+				appointments: []models.Appointments{appointment},
+				attendances:  map[uuid.UUID]models.AppointmentAttendances{},
+			}
+			service := newFakeService(repo)
+
+			for i, step := range tt.steps {
+				var attendance models.AppointmentAttendances
+				var apperr error
+				if step.noShow {
+					if err := service.ReportNoShow(&attendance, appointment.AppointmentId.String(), step.session); err != nil {
+						apperr = err
+					}
+				} else {
+					if err := service.CheckInAppointment(&attendance, appointment.AppointmentId.String(), step.session); err != nil {
+						apperr = err
+					}
+				}
+
+				if (apperr != nil) != step.wantErr {
+					t.Fatalf("step %v: got error %v, want error %v", i, apperr, step.wantErr)
+				}
+			}
+
+			if got := repo.attendances[owner.UserId].Status; got != tt.wantOwner {
+				t.Errorf("got owner attendance %q, want %q", got, tt.wantOwner)
+			}
+			if got := repo.attendances[dweller.UserId].Status; got != tt.wantDweller {
+				t.Errorf("got dweller attendance %q, want %q", got, tt.wantDweller)
+			}
+		})
+	}
+}
+
+func TestGetAttendanceStatistics(t *testing.T) {
+	owner, dweller, otherOwner := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		session models.Sessions
+		wantErr bool
+	}{
+		{name: "the user", session: models.Sessions{UserId: dweller}},
+		{name: "an owner the user has an appointment with", session: models.Sessions{UserId: owner}},
+		{name: "an admin", session: models.Sessions{UserId: uuid.New(), IsAdmin: true}},
+		{name: "another owner", session: models.Sessions{UserId: otherOwner}, wantErr: true},
+		{name: "another user", session: models.Sessions{UserId: uuid.New()}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{
+				appointments: []models.Appointments{
+					{AppointmentId: uuid.New(), OwnerUserId: owner, DwellerUserId: dweller},
+					// the user being the owner does not let the dweller see them
+					{AppointmentId: uuid.New(), OwnerUserId: dweller, DwellerUserId: otherOwner},
+				},
+			}
+
+			var statistics models.AttendanceStatistics
+			apperr := newFakeService(repo).GetAttendanceStatistics(&statistics, dweller.String(), "", &tt.session)
+			if (apperr != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", apperr, tt.wantErr)
+			}
+
+			if !tt.wantErr && statistics.NoShowRate != 0.25 {
+				t.Errorf("got no-show rate %v, want 0.25", statistics.NoShowRate)
+			}
+		})
+	}
+}
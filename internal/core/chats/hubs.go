@@ -3,6 +3,7 @@ package chats
 import (
 	"sync"
 
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/google/uuid"
 )
 
@@ -38,6 +39,20 @@ func (h *Hub) IsUserInChat(sendUserId uuid.UUID, recvUserId uuid.UUID) bool {
 		*sendUser.RecvUserId == recvUserId && *recvUser.RecvUserId == sendUserId
 }
 
+// Notify pushes an event to a user if they are connected, and reports whether
+// they were.
+func (h *Hub) Notify(userId uuid.UUID, payload models.OutBoundPayload) bool {
+	h.Lock()
+	client, online := h.clients[userId]
+	h.Unlock()
+
+	if online {
+		client.SendMessage(payload.ToOutBound())
+	}
+
+	return online
+}
+
 func (h *Hub) Register(client *WebsocketClients) {
 	h.Lock()
 	_, ok := h.clients[client.UserId]
@@ -19,6 +19,10 @@ import (
 type Service interface {
 	SendVerificationEmail([]string) *apperror.AppError
 	VerifyEmail(*models.Callbacks, *models.CallbackResponses) *apperror.AppError
+	SendAppointmentReminderEmail(string, *models.AppointmentReminderEmails) *apperror.AppError
+	SendAppointmentExpiredEmail(string, *models.AppointmentExpiredEmails) *apperror.AppError
+	SendAppointmentCancelledEmail(string, *models.AppointmentCancelledEmails) *apperror.AppError
+	SendAgreementOverdueEmail(string, *models.AgreementOverdueEmails) *apperror.AppError
 }
 
 type serviceImpl struct {
@@ -83,6 +87,30 @@ func (s *serviceImpl) SendVerificationEmail(emails []string) *apperror.AppError
 	return s.sendEmail(emails, subject, emailStructure)
 }
 
+func (s *serviceImpl) SendAppointmentReminderEmail(email string, reminder *models.AppointmentReminderEmails) *apperror.AppError {
+	subject := "Appointment Reminder from suechaokhai.com"
+
+	return s.sendEmail([]string{email}, subject, reminder)
+}
+
+func (s *serviceImpl) SendAppointmentExpiredEmail(email string, expired *models.AppointmentExpiredEmails) *apperror.AppError {
+	subject := "Appointment Request Expired on suechaokhai.com"
+
+	return s.sendEmail([]string{email}, subject, expired)
+}
+
+func (s *serviceImpl) SendAppointmentCancelledEmail(email string, cancelled *models.AppointmentCancelledEmails) *apperror.AppError {
+	subject := "Appointment Cancelled on suechaokhai.com"
+
+	return s.sendEmail([]string{email}, subject, cancelled)
+}
+
+func (s *serviceImpl) SendAgreementOverdueEmail(email string, overdue *models.AgreementOverdueEmails) *apperror.AppError {
+	subject := "Overdue Rent Notice from suechaokhai.com"
+
+	return s.sendEmail([]string{email}, subject, overdue)
+}
+
 func (s *serviceImpl) sendEmail(to []string, subject string, emailStructure models.EmailType) *apperror.AppError {
 	smtpHost := s.cfg.SmtpHost
 	smtpPort := s.cfg.SmtpPort
@@ -0,0 +1,150 @@
+package maintenance
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler interface {
+	GetMaintenanceTickets(c *fiber.Ctx) error
+	GetMaintenanceTicketById(c *fiber.Ctx) error
+	CreateMaintenanceTicket(c *fiber.Ctx) error
+	UpdateMaintenanceTicket(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handlerImpl{
+		service,
+	}
+}
+
+// @router      /api/v1/agreements/:agreementId/maintenance-tickets [get]
+// @summary     Get maintenance tickets of an agreement *use cookies*
+// @description Get the maintenance tickets of an agreement with their photos, newest first
+// @tags        maintenance
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @success     200	{object} []models.MaintenanceTickets
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     500 {object} models.ErrorResponses "Could not get maintenance tickets"
+func (h *handlerImpl) GetMaintenanceTickets(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	tickets := []models.MaintenanceTickets{}
+	apperr := h.service.GetMaintenanceTickets(&tickets, agreementId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(tickets)
+}
+
+// @router      /api/v1/maintenance-tickets/:ticketId [get]
+// @summary     Get a maintenance ticket *use cookies*
+// @description Get a maintenance ticket with its photos and every status update
+// @tags        maintenance
+// @produce     json
+// @param       ticketId path string true "Ticket ID"
+// @success     200	{object} models.MaintenanceTickets
+// @failure     400 {object} models.ErrorResponses "Invalid ticket id"
+// @failure     403 {object} models.ErrorResponses "Not the owner or the dweller"
+// @failure     404 {object} models.ErrorResponses "Ticket not found"
+// @failure     500 {object} models.ErrorResponses "Could not get maintenance ticket"
+func (h *handlerImpl) GetMaintenanceTicketById(c *fiber.Ctx) error {
+	ticketId := c.Params("ticketId")
+	session := c.Locals("session").(models.Sessions)
+
+	ticket := models.MaintenanceTickets{}
+	apperr := h.service.GetMaintenanceTicketById(&ticket, ticketId, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(ticket)
+}
+
+// @router      /api/v1/agreements/:agreementId/maintenance-tickets [post]
+// @summary     Open a maintenance ticket *use cookies*
+// @description Report something to repair in a rented unit. Category is one of AIR_CONDITIONING, PLUMBING, ELECTRICAL, APPLIANCE, STRUCTURAL, PEST_CONTROL or OTHER and priority one of LOW, MEDIUM, HIGH or URGENT. Photos (.jpg / .png, up to 10) are uploaded in formData with field `photos` and kept private, so only the parties can download them through their `image_url`. Only the dweller can open tickets, while the agreement is RENTING. The owner receives the ticket as a `TICKET` event on the chat websocket
+// @tags        maintenance
+// @produce     json
+// @param       agreementId path string true "Agreement ID"
+// @param       formData formData models.CreatingMaintenanceTickets true "Ticket details"
+// @success     201	{object} models.MaintenanceTickets
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id, ticket details or photos"
+// @failure     403 {object} models.ErrorResponses "Not the dweller"
+// @failure     404 {object} models.ErrorResponses "Agreement not found"
+// @failure     409 {object} models.ErrorResponses "Agreement is not renting"
+// @failure     500 {object} models.ErrorResponses "Could not create maintenance ticket"
+func (h *handlerImpl) CreateMaintenanceTicket(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	var creating models.CreatingMaintenanceTickets
+	if err := c.BodyParser(&creating); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidBody).
+			Describe("Invalid request body"))
+	}
+
+	var photos []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		photos = form.File["photos"]
+	}
+
+	ticket := models.MaintenanceTickets{}
+	apperr := h.service.CreateMaintenanceTicket(&ticket, agreementId, &creating, photos, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.Status(http.StatusCreated).JSON(ticket)
+}
+
+// @router      /api/v1/maintenance-tickets/:ticketId [patch]
+// @summary     Update the status of a maintenance ticket *use cookies*
+// @description Move a ticket from OPEN to ACKNOWLEDGED, SCHEDULED and RESOLVED, with an optional note. Scheduling needs the time of the visit and a scheduled ticket can be scheduled again. Only the owner can update tickets. The dweller receives the updated ticket as a `TICKET` event on the chat websocket
+// @tags        maintenance
+// @accept      json
+// @produce     json
+// @param       ticketId path string true "Ticket ID"
+// @param       body body models.UpdatingMaintenanceTickets true "New status"
+// @success     200	{object} models.MaintenanceTickets
+// @failure     400 {object} models.ErrorResponses "Invalid ticket id, status or visit time"
+// @failure     403 {object} models.ErrorResponses "Not the owner"
+// @failure     404 {object} models.ErrorResponses "Ticket not found"
+// @failure     409 {object} models.ErrorResponses "Status cannot be moved to the given one"
+// @failure     500 {object} models.ErrorResponses "Could not update maintenance ticket"
+func (h *handlerImpl) UpdateMaintenanceTicket(c *fiber.Ctx) error {
+	ticketId := c.Params("ticketId")
+	session := c.Locals("session").(models.Sessions)
+
+	updating := models.UpdatingMaintenanceTickets{}
+	err := c.BodyParser(&updating)
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.BadRequest).
+			Describe(fmt.Sprintf("Could not parse body: %v", err.Error())))
+	}
+
+	ticket := models.MaintenanceTickets{}
+	apperr := h.service.UpdateMaintenanceTicket(&ticket, ticketId, &updating, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(ticket)
+}
@@ -0,0 +1,73 @@
+package maintenance
+
+import (
+	"errors"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	errTicketStatusChanged = errors.New("maintenance ticket status has been changed concurrently")
+)
+
+type Repository interface {
+	GetMaintenanceTickets(*[]models.MaintenanceTickets, string) error
+	GetMaintenanceTicket(*models.MaintenanceTickets, string) error
+	CreateMaintenanceTicket(*models.MaintenanceTickets) error
+	UpdateMaintenanceTicket(*models.MaintenanceTickets, *models.MaintenanceTicketUpdates) error
+}
+
+type repositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{
+		db,
+	}
+}
+
+func (repo *repositoryImpl) GetMaintenanceTickets(tickets *[]models.MaintenanceTickets, agreementId string) error {
+	return repo.db.Model(&models.MaintenanceTickets{}).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Where("agreement_id = ?", agreementId).
+		Order("created_at DESC").
+		Find(tickets).Error
+}
+
+func (repo *repositoryImpl) GetMaintenanceTicket(ticket *models.MaintenanceTickets, ticketId string) error {
+	return repo.db.Model(&models.MaintenanceTickets{}).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Updates", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		First(ticket, "ticket_id = ?", ticketId).Error
+}
+
+func (repo *repositoryImpl) CreateMaintenanceTicket(ticket *models.MaintenanceTickets) error {
+	// the photos are created along with the ticket
+	return repo.db.Create(ticket).Error
+}
+
+// UpdateMaintenanceTicket moves a ticket on from the status it was read in and
+// records the update.
+func (repo *repositoryImpl) UpdateMaintenanceTicket(ticket *models.MaintenanceTickets, update *models.MaintenanceTicketUpdates) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.MaintenanceTickets{}).
+			Where("ticket_id = ? AND status = ?", ticket.TicketId, update.FromStatus).
+			Updates(map[string]interface{}{
+				"status":       ticket.Status,
+				"scheduled_at": ticket.ScheduledAt,
+				"resolved_at":  ticket.ResolvedAt,
+				"updated_at":   gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errTicketStatusChanged
+		}
+
+		return tx.Create(update).Error
+	})
+}
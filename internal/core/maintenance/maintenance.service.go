@@ -0,0 +1,249 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/agreements"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/chats"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	GetMaintenanceTickets(*[]models.MaintenanceTickets, string, *models.Sessions) *apperror.AppError
+	GetMaintenanceTicketById(*models.MaintenanceTickets, string, *models.Sessions) *apperror.AppError
+	CreateMaintenanceTicket(*models.MaintenanceTickets, string, *models.CreatingMaintenanceTickets, []*multipart.FileHeader, *models.Sessions) *apperror.AppError
+	UpdateMaintenanceTicket(*models.MaintenanceTickets, string, *models.UpdatingMaintenanceTickets, *models.Sessions) *apperror.AppError
+}
+
+// ticketTransitions lists the statuses the owner may move a ticket to. A
+// scheduled ticket can be scheduled again to move the visit.
+var ticketTransitions = map[enums.MaintenanceStatus][]enums.MaintenanceStatus{
+	enums.OpenMaintenance:         {enums.AcknowledgedMaintenance, enums.ScheduledMaintenance, enums.ResolvedMaintenance},
+	enums.AcknowledgedMaintenance: {enums.ScheduledMaintenance, enums.ResolvedMaintenance},
+	enums.ScheduledMaintenance:    {enums.ScheduledMaintenance, enums.ResolvedMaintenance},
+}
+
+const maxTicketPhotos = 10
+
+type serviceImpl struct {
+	repo             Repository
+	logger           *zap.Logger
+	agreementService agreements.Service
+	hub              *chats.Hub
+}
+
+func NewService(logger *zap.Logger, repo Repository, agreementService agreements.Service, hub *chats.Hub) Service {
+	return &serviceImpl{
+		repo,
+		logger,
+		agreementService,
+		hub,
+	}
+}
+
+func (s *serviceImpl) GetMaintenanceTickets(tickets *[]models.MaintenanceTickets, agreementId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if _, apperr := s.agreementService.GetAgreementRole(&agreement, agreementId, session); apperr != nil {
+		return apperr
+	}
+
+	err := s.repo.GetMaintenanceTickets(tickets, agreementId)
+	if err != nil {
+		s.logger.Error("Could not get maintenance tickets", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get maintenance tickets")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetMaintenanceTicketById(ticket *models.MaintenanceTickets, ticketId string, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	_, apperr := s.getTicket(ticket, &agreement, ticketId, session)
+	return apperr
+}
+
+// CreateMaintenanceTicket lets the dweller of an agreement they are renting
+// report something to repair. The owner is told right away if they are online.
+func (s *serviceImpl) CreateMaintenanceTicket(ticket *models.MaintenanceTickets, agreementId string, creating *models.CreatingMaintenanceTickets, photos []*multipart.FileHeader, session *models.Sessions) *apperror.AppError {
+	var agreement models.Agreements
+	if role, apperr := s.agreementService.GetAgreementRole(&agreement, agreementId, session); apperr != nil {
+		return apperr
+	} else if role != enums.DwellerActor {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the dweller can open maintenance tickets")
+	}
+
+	if agreement.Status != enums.RentingAgreement {
+		return apperror.
+			New(apperror.MaintenanceNotAllowed).
+			Describe("Maintenance tickets can only be opened while renting")
+	}
+
+	creating.Title = strings.TrimSpace(creating.Title)
+	creating.Description = strings.TrimSpace(creating.Description)
+	if creating.Title == "" || len(creating.Title) > 100 || creating.Description == "" {
+		return apperror.
+			New(apperror.InvalidMaintenanceTicket).
+			Describe("A ticket needs a title of at most 100 characters and a description")
+	}
+
+	if _, ok := enums.MaintenanceCategoriesMap[string(creating.Category)]; !ok {
+		return apperror.
+			New(apperror.InvalidMaintenanceTicket).
+			Describe(fmt.Sprintf("Invalid category %v", creating.Category))
+	}
+
+	if _, ok := enums.MaintenancePrioritiesMap[string(creating.Priority)]; !ok {
+		return apperror.
+			New(apperror.InvalidMaintenanceTicket).
+			Describe(fmt.Sprintf("Invalid priority %v", creating.Priority))
+	}
+
+	if len(photos) > maxTicketPhotos {
+		return apperror.
+			New(apperror.InvalidMaintenanceTicket).
+			Describe(fmt.Sprintf("A ticket can have up to %v photos", maxTicketPhotos))
+	}
+
+	*ticket = models.MaintenanceTickets{
+		TicketId:        uuid.New(),
+		AgreementId:     agreement.AgreementId,
+		CreatedByUserId: session.UserId,
+		Title:           creating.Title,
+		Description:     creating.Description,
+		Category:        creating.Category,
+		Priority:        creating.Priority,
+		Status:          enums.OpenMaintenance,
+		Photos:          []models.MaintenanceTicketPhotos{},
+		Updates:         []models.MaintenanceTicketUpdates{},
+	}
+
+	urls, apperr := s.agreementService.UploadAgreementPhotos(agreementId, fmt.Sprintf("maintenance/%v", ticket.TicketId), photos, apperror.InvalidMaintenanceTicket)
+	if apperr != nil {
+		return apperr
+	}
+
+	for _, url := range urls {
+		ticket.Photos = append(ticket.Photos, models.MaintenanceTicketPhotos{
+			TicketId: ticket.TicketId,
+			ImageUrl: url,
+		})
+	}
+
+	err := s.repo.CreateMaintenanceTicket(ticket)
+	if err != nil {
+		s.logger.Error("Could not create maintenance ticket", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create maintenance ticket")
+	}
+
+	s.hub.Notify(agreement.OwnerUserId, ticket)
+	return nil
+}
+
+// UpdateMaintenanceTicket lets the owner move a ticket along and pushes the
+// ticket to the dweller over the chat websocket.
+func (s *serviceImpl) UpdateMaintenanceTicket(ticket *models.MaintenanceTickets, ticketId string, updating *models.UpdatingMaintenanceTickets, session *models.Sessions) *apperror.AppError {
+	if _, ok := enums.MaintenanceStatusMap[string(updating.Status)]; !ok {
+		return apperror.
+			New(apperror.InvalidMaintenanceStatus).
+			Describe(fmt.Sprintf("Invalid status %v", updating.Status))
+	}
+
+	var agreement models.Agreements
+	if role, apperr := s.getTicket(ticket, &agreement, ticketId, session); apperr != nil {
+		return apperr
+	} else if role != enums.OwnerActor {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner can update maintenance tickets")
+	}
+
+	if !slices.Contains(ticketTransitions[ticket.Status], updating.Status) {
+		return apperror.
+			New(apperror.InvalidMaintenanceTransition).
+			Describe(fmt.Sprintf("Cannot move a ticket from %v to %v", ticket.Status, updating.Status))
+	}
+
+	now := time.Now()
+	switch updating.Status {
+	case enums.ScheduledMaintenance:
+		if updating.ScheduledAt == nil || !updating.ScheduledAt.After(now) {
+			return apperror.
+				New(apperror.InvalidMaintenanceTicket).
+				Describe("Scheduled tickets need a visit time in the future")
+		}
+		ticket.ScheduledAt = updating.ScheduledAt
+	case enums.ResolvedMaintenance:
+		ticket.ResolvedAt = &now
+	}
+
+	update := models.MaintenanceTicketUpdates{
+		UpdateId:        uuid.New(),
+		TicketId:        ticket.TicketId,
+		FromStatus:      ticket.Status,
+		ToStatus:        updating.Status,
+		ScheduledAt:     ticket.ScheduledAt,
+		Note:            strings.TrimSpace(updating.Note),
+		UpdatedByUserId: &session.UserId,
+		CreatedAt:       now,
+	}
+	ticket.Status = updating.Status
+
+	err := s.repo.UpdateMaintenanceTicket(ticket, &update)
+	if errors.Is(err, errTicketStatusChanged) {
+		return apperror.
+			New(apperror.InvalidMaintenanceTransition).
+			Describe("Ticket has been updated in the meantime")
+	} else if err != nil {
+		s.logger.Error("Could not update maintenance ticket", zap.String("id", ticketId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not update maintenance ticket")
+	}
+
+	ticket.UpdatedAt = now
+	ticket.Updates = append(ticket.Updates, update)
+
+	s.hub.Notify(agreement.DwellerUserId, ticket)
+	return nil
+}
+
+// getTicket loads a ticket along with its agreement and the role the session
+// plays in it.
+func (s *serviceImpl) getTicket(ticket *models.MaintenanceTickets, agreement *models.Agreements, ticketId string, session *models.Sessions) (enums.ActorRoles, *apperror.AppError) {
+	if !utils.IsValidUUID(ticketId) {
+		return "", apperror.
+			New(apperror.InvalidMaintenanceTicketId).
+			Describe("Invalid maintenance ticket id")
+	}
+
+	err := s.repo.GetMaintenanceTicket(ticket, ticketId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", apperror.
+			New(apperror.MaintenanceTicketNotFound).
+			Describe("Could not find the specified maintenance ticket")
+	} else if err != nil {
+		s.logger.Error("Could not get maintenance ticket", zap.String("id", ticketId), zap.Error(err))
+		return "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get maintenance ticket")
+	}
+
+	return s.agreementService.GetAgreementRole(agreement, ticket.AgreementId.String(), session)
+}
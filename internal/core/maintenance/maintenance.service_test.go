@@ -0,0 +1,112 @@
+package maintenance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/agreements"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/chats"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type fakeRepository struct {
+	Repository
+	ticket models.MaintenanceTickets
+}
+
+func (repo *fakeRepository) GetMaintenanceTicket(ticket *models.MaintenanceTickets, ticketId string) error {
+	if repo.ticket.TicketId.String() != ticketId {
+		return gorm.ErrRecordNotFound
+	}
+
+	*ticket = repo.ticket
+	return nil
+}
+
+func (repo *fakeRepository) UpdateMaintenanceTicket(ticket *models.MaintenanceTickets, update *models.MaintenanceTicketUpdates) error {
+	return nil
+}
+
+type fakeAgreementService struct {
+	agreements.Service
+	agreement models.Agreements
+}
+
+func (s *fakeAgreementService) GetAgreementRole(agreement *models.Agreements, agreementId string, session *models.Sessions) (enums.ActorRoles, *apperror.AppError) {
+	*agreement = s.agreement
+	switch {
+	case session.UserId == agreement.OwnerUserId:
+		return enums.OwnerActor, nil
+	case session.UserId == agreement.DwellerUserId:
+		return enums.DwellerActor, nil
+	case session.IsAdmin:
+		return enums.AdminActor, nil
+	}
+
+	return "", apperror.New(apperror.Forbidden)
+}
+
+func TestUpdateMaintenanceTicket(t *testing.T) {
+	owner, dweller := uuid.New(), uuid.New()
+	agreement := models.Agreements{AgreementId: uuid.New(), OwnerUserId: owner, DwellerUserId: dweller}
+	tomorrow := time.Now().Add(24 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		from        enums.MaintenanceStatus
+		to          enums.MaintenanceStatus
+		session     models.Sessions
+		scheduledAt *time.Time
+		wantErr     *apperror.AppErrorType
+	}{
+		{enums.OpenMaintenance, enums.AcknowledgedMaintenance, models.Sessions{UserId: owner}, nil, nil},
+		{enums.OpenMaintenance, enums.ScheduledMaintenance, models.Sessions{UserId: owner}, &tomorrow, nil},
+		{enums.OpenMaintenance, enums.ResolvedMaintenance, models.Sessions{UserId: owner}, nil, nil},
+		{enums.AcknowledgedMaintenance, enums.ScheduledMaintenance, models.Sessions{UserId: owner}, &tomorrow, nil},
+		{enums.AcknowledgedMaintenance, enums.ResolvedMaintenance, models.Sessions{UserId: owner}, nil, nil},
+		{enums.ScheduledMaintenance, enums.ScheduledMaintenance, models.Sessions{UserId: owner}, &tomorrow, nil},
+		{enums.ScheduledMaintenance, enums.ResolvedMaintenance, models.Sessions{UserId: owner}, nil, nil},
+		{enums.AcknowledgedMaintenance, enums.OpenMaintenance, models.Sessions{UserId: owner}, nil, apperror.InvalidMaintenanceTransition},
+		{enums.ScheduledMaintenance, enums.AcknowledgedMaintenance, models.Sessions{UserId: owner}, nil, apperror.InvalidMaintenanceTransition},
+		{enums.ResolvedMaintenance, enums.OpenMaintenance, models.Sessions{UserId: owner}, nil, apperror.InvalidMaintenanceTransition},
+		{enums.ResolvedMaintenance, enums.ScheduledMaintenance, models.Sessions{UserId: owner}, &tomorrow, apperror.InvalidMaintenanceTransition},
+		{enums.OpenMaintenance, enums.ScheduledMaintenance, models.Sessions{UserId: owner}, nil, apperror.InvalidMaintenanceTicket},
+		{enums.OpenMaintenance, enums.ScheduledMaintenance, models.Sessions{UserId: owner}, &yesterday, apperror.InvalidMaintenanceTicket},
+		{enums.OpenMaintenance, enums.AcknowledgedMaintenance, models.Sessions{UserId: dweller}, nil, apperror.Forbidden},
+		{enums.OpenMaintenance, enums.AcknowledgedMaintenance, models.Sessions{UserId: uuid.New(), IsAdmin: true}, nil, apperror.Forbidden},
+		{enums.OpenMaintenance, enums.AcknowledgedMaintenance, models.Sessions{UserId: uuid.New()}, nil, apperror.Forbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v to %v", tt.from, tt.to), func(t *testing.T) {
+			ticket := models.MaintenanceTickets{TicketId: uuid.New(), AgreementId: agreement.AgreementId, Status: tt.from}
+			s := &serviceImpl{
+				repo:             &fakeRepository{ticket: ticket},
+				logger:           zap.NewNop(),
+				agreementService: &fakeAgreementService{agreement: agreement},
+				hub:              chats.NewHub(),
+			}
+
+			updating := models.UpdatingMaintenanceTickets{Status: tt.to, ScheduledAt: tt.scheduledAt}
+			apperr := s.UpdateMaintenanceTicket(&ticket, ticket.TicketId.String(), &updating, &tt.session)
+			if tt.wantErr == nil && apperr != nil {
+				t.Fatalf("UpdateMaintenanceTicket() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Fatalf("UpdateMaintenanceTicket() error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+
+			if tt.wantErr == nil && ticket.Status != tt.to {
+				t.Errorf("Status = %v, want %v", ticket.Status, tt.to)
+			}
+			if tt.wantErr == nil && len(ticket.Updates) != 1 {
+				t.Errorf("Updates = %v, want one update", len(ticket.Updates))
+			}
+		})
+	}
+}
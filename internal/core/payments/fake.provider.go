@@ -0,0 +1,118 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// fakeProvider keeps checkout sessions in memory so that payments can be made
+// offline, in development and in tests. Its checkout url is straight away the
+// success url. A session is paid, or expired, by posting
+// {"session_id": "...", "status": "PAID"} to the payment webhook with the
+// webhook secret as the signature.
+type fakeProvider struct {
+	mu            sync.Mutex
+	checkouts     map[string]*fakeCheckout
+	webhookSecret string
+	successUrl    string
+}
+
+type fakeCheckout struct {
+	session  models.CheckoutSessions
+	amount   float64
+	refunded float64
+}
+
+func NewFakeProvider(cfg *config.Config) PaymentProvider {
+	return &fakeProvider{
+		checkouts:     map[string]*fakeCheckout{},
+		webhookSecret: cfg.PaymentWebhookSecret,
+		successUrl:    cfg.PaymentSuccessUrl,
+	}
+}
+
+func (p *fakeProvider) CreateCheckout(checkout *models.CheckoutSessions, creating *models.CreatingCheckouts) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sessionId := fmt.Sprintf("fake_cs_%v", uuid.New())
+	*checkout = models.CheckoutSessions{
+		SessionId: sessionId,
+		Url:       strings.ReplaceAll(p.successUrl, checkoutSessionIdTemplate, sessionId),
+		Status:    enums.OpenCheckout,
+	}
+
+	p.checkouts[sessionId] = &fakeCheckout{
+		session: *checkout,
+		amount:  creating.Amount,
+	}
+
+	return nil
+}
+
+func (p *fakeProvider) GetCheckout(checkout *models.CheckoutSessions, sessionId string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fake, ok := p.checkouts[sessionId]
+	if !ok {
+		return errCheckoutNotFound
+	}
+
+	*checkout = fake.session
+	return nil
+}
+
+func (p *fakeProvider) Refund(sessionId string, amount float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fake, ok := p.checkouts[sessionId]
+	if !ok {
+		return errCheckoutNotFound
+	}
+
+	if fake.session.Status != enums.PaidCheckout || amount <= 0 || fake.refunded+amount > fake.amount {
+		return errNotRefundable
+	}
+
+	fake.refunded += amount
+	return nil
+}
+
+func (p *fakeProvider) VerifyWebhook(event *models.CheckoutEvents, payload []byte, signature string) error {
+	if !hmac.Equal([]byte(signature), []byte(p.webhookSecret)) {
+		return fmt.Errorf("%w: signature does not match", errInvalidWebhook)
+	}
+
+	if err := json.Unmarshal(payload, event); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidWebhook, err)
+	}
+
+	if event.Status != enums.PaidCheckout && event.Status != enums.ExpiredCheckout {
+		return fmt.Errorf("%w: status must be PAID or EXPIRED", errInvalidWebhook)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fake, ok := p.checkouts[event.SessionId]
+	if !ok {
+		return fmt.Errorf("%w: %v", errInvalidWebhook, errCheckoutNotFound)
+	}
+
+	if fake.session.Status != enums.OpenCheckout && fake.session.Status != event.Status {
+		return fmt.Errorf("%w: checkout session is %v", errInvalidWebhook, fake.session.Status)
+	}
+
+	fake.session.Status = event.Status
+	return nil
+}
@@ -0,0 +1,56 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+)
+
+var (
+	errInvalidWebhook   = errors.New("invalid webhook")
+	errCheckoutNotFound = errors.New("checkout session not found")
+	errNotRefundable    = errors.New("checkout session not refundable")
+)
+
+// checkoutSessionIdTemplate is replaced by providers with the id of the
+// checkout session in the success url, so the frontend can look it up.
+const checkoutSessionIdTemplate = "{CHECKOUT_SESSION_ID}"
+
+// PaymentProvider takes payments on behalf of the platform. Dwellers pay on a
+// checkout page hosted by the provider, which then tells the platform through
+// a webhook whether the payment went through.
+type PaymentProvider interface {
+	CreateCheckout(*models.CheckoutSessions, *models.CreatingCheckouts) error
+	GetCheckout(*models.CheckoutSessions, string) error
+	Refund(string, float64) error
+	VerifyWebhook(*models.CheckoutEvents, []byte, string) error
+}
+
+// NewPaymentProvider sets up the configured provider. The webhook completes
+// payments without a session, so it is refused without a secret to verify it
+// with, and the fake provider, which takes no money, is refused outside of
+// development.
+func NewPaymentProvider(cfg *config.Config) (PaymentProvider, error) {
+	if cfg.PaymentWebhookSecret == "" {
+		return nil, errors.New("PAYMENT_WEBHOOK_SECRET is not set")
+	}
+
+	switch enums.PaymentProviders(strings.ToUpper(cfg.PaymentProvider)) {
+	case enums.StripePaymentProvider, "":
+		if cfg.StripeSecretKey == "" {
+			return nil, errors.New("STRIPE_SECRET_KEY is not set")
+		}
+		return NewStripeProvider(cfg), nil
+	case enums.FakePaymentProvider:
+		if !cfg.IsDevelopment() {
+			return nil, errors.New("the FAKE payment provider can only be used in development")
+		}
+		return NewFakeProvider(cfg), nil
+	}
+
+	return nil, fmt.Errorf("unknown payment provider %v", cfg.PaymentProvider)
+}
@@ -1,14 +1,31 @@
 package payments
 
 import (
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errPaymentCompleted     = errors.New("payment already completed")
+	errPaymentNotRefundable = errors.New("payment not refundable")
 )
 
 type Repository interface {
 	CreatePayment(*models.Payments) error
 	GetPaymentByUserId(*models.MyPaymentsResponse, uuid.UUID) error
+	GetPaymentById(*models.Payments, string) error
+	GetPaymentByCheckoutSessionId(*models.Payments, string) error
+	CompletePayment(*models.Payments) error
+	RefundPayment(*models.Payments) error
+	GetDwellerInstallment(*models.AgreementInstallments, uuid.UUID, uuid.UUID) error
+	GetDwellerAgreement(*models.Agreements, uuid.UUID, uuid.UUID) error
 }
 
 type repositoryImpl struct {
@@ -23,18 +40,200 @@ func NewRepository(db *gorm.DB) Repository {
 
 func (r *repositoryImpl) CreatePayment(payment *models.Payments) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		paymentQuery := `INSERT INTO payments (payment_id , user_id , price ,IsSuccess ,name) VALUES (?,?,?,?,?)`
-		if err := tx.Exec(paymentQuery, payment.PaymentId, payment.UserId, payment.Price, payment.IsSuccess, payment.Name).Error; err != nil {
+		if payment.IsSuccess {
+			paidAt := time.Now()
+			payment.PaidAt = &paidAt
+		}
+
+		paymentQuery := `INSERT INTO payments (payment_id , user_id , price ,IsSuccess ,name ,agreement_id ,payment_type ,installment_id ,checkout_session_id ,paid_at) VALUES (?,?,?,?,?,?,NULLIF(?, '')::payment_types,?,?,?)`
+		if err := tx.Exec(paymentQuery, payment.PaymentId, payment.UserId, payment.Price, payment.IsSuccess, payment.Name, payment.AgreementId, payment.PaymentType, payment.InstallmentId, payment.CheckoutSessionId, payment.PaidAt).Error; err != nil {
 			return err
 		}
-		return nil
+
+		if !payment.IsSuccess || payment.InstallmentId == nil {
+			return nil
+		}
+
+		return settleInstallment(tx, payment)
 	})
 }
 
 func (r *repositoryImpl) GetPaymentByUserId(payments *models.MyPaymentsResponse, userId uuid.UUID) error {
-	paymentQuery := `SELECT * FROM payments WHERE user_id = ?`
-	if err := r.db.Raw(paymentQuery, userId).Scan(&payments.Payments).Error; err != nil {
+	// money paid to the user, such as deposit refunds, is part of their history too
+	paymentQuery := `SELECT *, issuccess AS is_success FROM payments WHERE user_id = @user_id OR recipient_user_id = @user_id`
+	if err := r.db.Raw(paymentQuery, sql.Named("user_id", userId)).Scan(&payments.Payments).Error; err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *repositoryImpl) GetPaymentById(payment *models.Payments, paymentId string) error {
+	return r.getPayment(payment, `SELECT *, issuccess AS is_success FROM payments WHERE payment_id = ? AND deleted_at IS NULL`, paymentId)
+}
+
+func (r *repositoryImpl) GetPaymentByCheckoutSessionId(payment *models.Payments, sessionId string) error {
+	return r.getPayment(payment, `SELECT *, issuccess AS is_success FROM payments WHERE checkout_session_id = ? AND deleted_at IS NULL`, sessionId)
+}
+
+func (r *repositoryImpl) getPayment(payment *models.Payments, query string, value string) error {
+	result := r.db.Raw(query, value).Scan(payment)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// CompletePayment marks a payment as successful once the provider says it has
+// been paid, settling the installment it was made for. Webhooks can be sent
+// more than once, so a payment is only completed the first time.
+func (r *repositoryImpl) CompletePayment(payment *models.Payments) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		paidAt := time.Now()
+		result := tx.Exec(`UPDATE payments SET issuccess = TRUE, paid_at = ?, updated_at = CURRENT_TIMESTAMP
+							WHERE payment_id = ? AND NOT issuccess AND refunded_at IS NULL AND deleted_at IS NULL`, paidAt, payment.PaymentId)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errPaymentCompleted
+		}
+
+		payment.IsSuccess = true
+		payment.PaidAt = &paidAt
+		if payment.InstallmentId == nil {
+			return nil
+		}
+
+		return settleInstallment(tx, payment)
+	})
+}
+
+// RefundPayment marks a refunded payment as no longer successful, so it stops
+// counting towards what was paid. The installment it settled is due again and
+// goes back to UNPAID, from where the overdue job picks it up if it is late.
+func (r *repositoryImpl) RefundPayment(payment *models.Payments) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		refundedAt := time.Now()
+		result := tx.Exec(`UPDATE payments SET issuccess = FALSE, refunded_at = ?, updated_at = CURRENT_TIMESTAMP
+							WHERE payment_id = ? AND issuccess AND refunded_at IS NULL AND deleted_at IS NULL`, refundedAt, payment.PaymentId)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errPaymentNotRefundable
+		}
+
+		payment.IsSuccess = false
+		payment.RefundedAt = &refundedAt
+		if payment.InstallmentId == nil {
+			return nil
+		}
+
+		reopenQuery := `UPDATE agreement_installments
+						SET status = 'UNPAID', paid_at = NULL, updated_at = CURRENT_TIMESTAMP
+						WHERE installment_id = @installment_id AND status = 'PAID' AND amount + late_fee + utility_amount > (
+							SELECT COALESCE(SUM(price), 0)
+							FROM payments
+							WHERE installment_id = @installment_id AND issuccess AND deleted_at IS NULL
+						)`
+		return tx.Exec(reopenQuery, sql.Named("installment_id", payment.InstallmentId)).Error
+	})
+}
+
+// GetDwellerInstallment gets an installment of an agreement in which userId is the dweller.
+func (r *repositoryImpl) GetDwellerInstallment(installment *models.AgreementInstallments, installmentId uuid.UUID, userId uuid.UUID) error {
+	return r.db.Model(&models.AgreementInstallments{}).
+		Joins("JOIN agreements a ON a.agreement_id = agreement_installments.agreement_id").
+		Where("agreement_installments.installment_id = ? AND a.dweller_user_id = ?", installmentId, userId).
+		First(installment).Error
+}
+
+// GetDwellerAgreement gets an agreement in which userId is the dweller.
+func (r *repositoryImpl) GetDwellerAgreement(agreement *models.Agreements, agreementId uuid.UUID, userId uuid.UUID) error {
+	return r.db.Model(&models.Agreements{}).
+		Where("agreement_id = ? AND dweller_user_id = ?", agreementId, userId).
+		First(agreement).Error
+}
+
+// settleInstallment records what a payment paid of its installment and settles
+// the installment once its successful payments cover its amount, late fee and
+// utilities. The split is kept on the payment, so a late fee or a reading
+// added to the installment afterwards does not change what was paid before.
+func settleInstallment(tx *gorm.DB, payment *models.Payments) error {
+	var installment models.AgreementInstallments
+	if err := tx.Model(&models.AgreementInstallments{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&installment, "installment_id = ?", payment.InstallmentId).Error; err != nil {
+		return err
+	}
+
+	var paid paymentSplits
+	paidQuery := `SELECT COALESCE(SUM(rent_amount), 0) AS rent, COALESCE(SUM(late_fee_amount), 0) AS late_fee, COALESCE(SUM(utility_amount), 0) AS utility
+					FROM payments
+					WHERE installment_id = ? AND payment_id <> ? AND issuccess AND deleted_at IS NULL`
+	if err := tx.Raw(paidQuery, payment.InstallmentId, payment.PaymentId).Scan(&paid).Error; err != nil {
+		return err
+	}
+
+	split := splitInstallmentPayment(payment.Price, paymentSplits{
+		Rent:    installment.Amount - paid.Rent,
+		LateFee: installment.LateFee - paid.LateFee,
+		Utility: installment.UtilityAmount - paid.Utility,
+	})
+	payment.RentAmount, payment.LateFeeAmount, payment.UtilityAmount = &split.Rent, &split.LateFee, &split.Utility
+
+	if err := tx.Exec(`UPDATE payments SET rent_amount = ?, late_fee_amount = ?, utility_amount = ? WHERE payment_id = ?`,
+		split.Rent, split.LateFee, split.Utility, payment.PaymentId).Error; err != nil {
+		return err
+	}
+
+	settleQuery := `UPDATE agreement_installments
+					SET status = 'PAID', paid_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+					WHERE installment_id = @installment_id AND status <> 'PAID' AND amount + late_fee + utility_amount <= (
+						SELECT COALESCE(SUM(price), 0)
+						FROM payments
+						WHERE installment_id = @installment_id AND issuccess AND deleted_at IS NULL
+					)`
+	return tx.Exec(settleQuery, sql.Named("installment_id", payment.InstallmentId)).Error
+}
+
+// paymentSplits are the parts of an installment a payment goes towards.
+type paymentSplits struct {
+	Rent    float64
+	LateFee float64
+	Utility float64
+}
+
+// splitInstallmentPayment splits a payment over what is still owed of the
+// rent, late fee and utilities of an installment, in proportion to each. Paying
+// more than is owed, or paying a settled installment, counts the rest as rent.
+func splitInstallmentPayment(price float64, owed paymentSplits) paymentSplits {
+	owed.Rent = math.Max(owed.Rent, 0)
+	owed.LateFee = math.Max(owed.LateFee, 0)
+	owed.Utility = math.Max(owed.Utility, 0)
+
+	due := owed.Rent + owed.LateFee + owed.Utility
+	if due <= 0 {
+		return paymentSplits{Rent: price}
+	}
+
+	share := math.Min(price/due, 1)
+	lateFee := roundAmount(owed.LateFee * share)
+	utility := roundAmount(owed.Utility * share)
+	return paymentSplits{
+		Rent:    roundAmount(price - lateFee - utility),
+		LateFee: lateFee,
+		Utility: utility,
+	}
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -1,36 +1,150 @@
 package payments
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/agreements"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type Service interface {
-	CreatePayment(*models.Payments) error
+	CreatePayment(*models.CreatedPayments, *models.Payments) *apperror.AppError
 	GetPaymentByUserId(*models.MyPaymentsResponse, uuid.UUID) error
+	GetPaymentById(*models.Payments, string, *models.Sessions) *apperror.AppError
+	RefundPayment(*models.Payments, string) *apperror.AppError
+	HandleWebhook([]byte, string) *apperror.AppError
 }
 
 type serviceImpl struct {
-	repo   Repository
-	logger *zap.Logger
+	repo              Repository
+	logger            *zap.Logger
+	agreementsService agreements.Service
+	provider          PaymentProvider
 }
 
-func NewService(logger *zap.Logger, repo Repository) Service {
+func NewService(logger *zap.Logger, repo Repository, agreementsService agreements.Service, provider PaymentProvider) Service {
 	return &serviceImpl{
 		repo,
 		logger,
+		agreementsService,
+		provider,
 	}
 }
 
-func (s *serviceImpl) CreatePayment(payment *models.Payments) error {
-	fmt.Println("service payment = ", payment)
-	err := s.repo.CreatePayment(payment)
+// CreatePayment records a pending payment and opens a checkout session for it
+// with the payment provider. The payment only succeeds once the provider says
+// it has been paid.
+func (s *serviceImpl) CreatePayment(created *models.CreatedPayments, payment *models.Payments) *apperror.AppError {
+	if _, ok := enums.PaymentTypesMap[string(payment.PaymentType)]; payment.PaymentType != "" && !ok {
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe(fmt.Sprintf("Invalid payment type %v", payment.PaymentType))
+	}
+
+	if payment.PaymentType == enums.DepositRefundPayment {
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe("Deposit refunds are made by the owner from the deposit of the agreement")
+	}
+
+	// rent only counts once it settles an installment, so it is paid per installment
+	switch {
+	case payment.InstallmentId != nil && payment.PaymentType != "" && payment.PaymentType != enums.RentPayment:
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe("Installments can only be paid with RENT payments")
+	case payment.InstallmentId == nil && payment.PaymentType == enums.RentPayment:
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe("Rent must be paid for an installment")
+	case payment.InstallmentId == nil && payment.AgreementId != nil && payment.PaymentType != enums.DepositPayment && payment.PaymentType != enums.PurchasePayment:
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe("Payments for an agreement must be a DEPOSIT or a PURCHASE")
+	case payment.InstallmentId == nil && payment.AgreementId == nil && payment.PaymentType != "":
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe(fmt.Sprintf("%v payments must be made for an agreement", payment.PaymentType))
+	}
+	payment.IsSuccess = false
+	payment.RecipientUserId = nil
+
+	if payment.Price <= 0 {
+		return apperror.
+			New(apperror.InvalidBody).
+			Describe("Price must be positive")
+	}
+
+	if payment.InstallmentId != nil {
+		var installment models.AgreementInstallments
+		err := s.repo.GetDwellerInstallment(&installment, *payment.InstallmentId, payment.UserId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.
+				New(apperror.InstallmentNotFound).
+				Describe("Could not find the specified installment in your agreements")
+		} else if err != nil {
+			s.logger.Error("Failed to get installment", zap.Error(err))
+			return apperror.
+				New(apperror.InternalServerError).
+				Describe("Failed to create payment")
+		}
+
+		if installment.Status == enums.PaidInstallment {
+			return apperror.
+				New(apperror.InstallmentAlreadyPaid).
+				Describe("This installment has already been paid")
+		}
+
+		payment.AgreementId = &installment.AgreementId
+		payment.PaymentType = enums.RentPayment
+	} else if payment.AgreementId != nil {
+		// deposits and purchases are paid by the dweller of the agreement only
+		var agreement models.Agreements
+		err := s.repo.GetDwellerAgreement(&agreement, *payment.AgreementId, payment.UserId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.
+				New(apperror.AgreementNotFound).
+				Describe("Could not find the specified agreement in your agreements")
+		} else if err != nil {
+			s.logger.Error("Failed to get agreement", zap.Error(err))
+			return apperror.
+				New(apperror.InternalServerError).
+				Describe("Failed to create payment")
+		}
+	}
+
+	var checkout models.CheckoutSessions
+	err := s.provider.CreateCheckout(&checkout, &models.CreatingCheckouts{
+		PaymentId: payment.PaymentId,
+		Name:      payment.Name,
+		Amount:    payment.Price,
+	})
+	if err != nil {
+		s.logger.Error("Could not create checkout session", zap.String("id", payment.PaymentId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.PaymentProviderError).
+			Describe("Could not start the checkout")
+	}
+	payment.CheckoutSessionId = &checkout.SessionId
+
+	err = s.repo.CreatePayment(payment)
 	if err != nil {
 		s.logger.Error("Failed to create payment", zap.Error(err))
-		return err
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Failed to create payment")
+	}
+
+	*created = models.CreatedPayments{
+		PaymentId:   payment.PaymentId,
+		CheckoutUrl: checkout.Url,
 	}
 	return nil
 }
@@ -43,3 +157,161 @@ func (s *serviceImpl) GetPaymentByUserId(payments *models.MyPaymentsResponse, us
 	}
 	return nil
 }
+
+// GetPaymentById gets a payment, asking the provider how its checkout is going
+// if it is still pending, in case the webhook has not come in yet.
+func (s *serviceImpl) GetPaymentById(payment *models.Payments, paymentId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.getPayment(payment, paymentId); apperr != nil {
+		return apperr
+	}
+
+	if payment.UserId != session.UserId && !session.IsAdmin {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the payer can see this payment")
+	}
+
+	if payment.IsSuccess || payment.RefundedAt != nil || payment.CheckoutSessionId == nil {
+		return nil
+	}
+
+	var checkout models.CheckoutSessions
+	err := s.provider.GetCheckout(&checkout, *payment.CheckoutSessionId)
+	if err != nil {
+		s.logger.Error("Could not get checkout session", zap.String("id", paymentId), zap.Error(err))
+		return apperror.
+			New(apperror.PaymentProviderError).
+			Describe("Could not get the status of the checkout")
+	}
+
+	if checkout.Status != enums.PaidCheckout {
+		return nil
+	}
+
+	return s.completePayment(payment)
+}
+
+// RefundPayment gives a successful payment back through the payment provider,
+// unless its agreement still needs it, e.g. the deposit of an agreement that
+// has been moved on to AWAITING_PAYMENT.
+func (s *serviceImpl) RefundPayment(payment *models.Payments, paymentId string) *apperror.AppError {
+	if apperr := s.getPayment(payment, paymentId); apperr != nil {
+		return apperr
+	}
+
+	if !payment.IsSuccess || payment.CheckoutSessionId == nil {
+		return apperror.
+			New(apperror.PaymentNotRefundable).
+			Describe("Only successful payments made through checkout can be refunded")
+	}
+
+	if apperr := s.agreementsService.CheckPaymentRefundable(payment); apperr != nil {
+		return apperr
+	}
+
+	err := s.provider.Refund(*payment.CheckoutSessionId, payment.Price)
+	if errors.Is(err, errNotRefundable) {
+		return apperror.
+			New(apperror.PaymentNotRefundable).
+			Describe("The payment provider cannot refund this payment")
+	} else if err != nil {
+		s.logger.Error("Could not refund payment", zap.String("id", paymentId), zap.Error(err))
+		return apperror.
+			New(apperror.PaymentProviderError).
+			Describe("Could not refund payment")
+	}
+
+	err = s.repo.RefundPayment(payment)
+	if errors.Is(err, errPaymentNotRefundable) {
+		return apperror.
+			New(apperror.PaymentNotRefundable).
+			Describe("The payment has already been refunded")
+	} else if err != nil {
+		// the money is already back with the payer, so this needs fixing by hand
+		s.logger.Error("Could not record payment refund", zap.String("id", paymentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not record payment refund")
+	}
+
+	return nil
+}
+
+// HandleWebhook completes the payment of a checkout session the provider says
+// has been paid. Other events are acknowledged and ignored.
+func (s *serviceImpl) HandleWebhook(payload []byte, signature string) *apperror.AppError {
+	var event models.CheckoutEvents
+	err := s.provider.VerifyWebhook(&event, payload, signature)
+	if errors.Is(err, errInvalidWebhook) {
+		s.logger.Warn("Rejected payment webhook", zap.Error(err))
+		return apperror.
+			New(apperror.InvalidWebhook).
+			Describe("Could not verify webhook")
+	} else if err != nil {
+		s.logger.Error("Could not verify payment webhook", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not verify webhook")
+	}
+
+	if event.Status != enums.PaidCheckout {
+		return nil
+	}
+
+	var payment models.Payments
+	err = s.repo.GetPaymentByCheckoutSessionId(&payment, event.SessionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// not made through the platform, nothing to complete
+		s.logger.Warn("Paid checkout session has no payment", zap.String("session", event.SessionId))
+		return nil
+	} else if err != nil {
+		s.logger.Error("Could not get payment by checkout session", zap.String("session", event.SessionId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not complete payment")
+	}
+
+	return s.completePayment(&payment)
+}
+
+func (s *serviceImpl) getPayment(payment *models.Payments, paymentId string) *apperror.AppError {
+	if !utils.IsValidUUID(paymentId) {
+		return apperror.
+			New(apperror.InvalidPaymentId).
+			Describe("Invalid payment id")
+	}
+
+	err := s.repo.GetPaymentById(payment, paymentId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PaymentNotFound).
+			Describe("Could not find the specified payment")
+	} else if err != nil {
+		s.logger.Error("Could not get payment", zap.String("id", paymentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get payment")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) completePayment(payment *models.Payments) *apperror.AppError {
+	err := s.repo.CompletePayment(payment)
+	if errors.Is(err, errPaymentCompleted) {
+		return nil
+	} else if err != nil {
+		s.logger.Error("Could not complete payment", zap.String("id", payment.PaymentId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not complete payment")
+	}
+
+	// settling the arrears puts an overdue agreement back to renting
+	if payment.InstallmentId != nil && payment.AgreementId != nil {
+		if apperr := s.agreementsService.ResumeOverdueAgreement(payment.AgreementId.String()); apperr != nil {
+			s.logger.Warn("Could not resume overdue agreement", zap.String("id", payment.AgreementId.String()), zap.Error(apperr))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package payments
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/core/agreements"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeRepository keeps payments and the agreements they are made for in
+// memory. The methods a test does not need are left to the embedded interface.
+type fakeRepository struct {
+	Repository
+	agreements   []models.Agreements
+	installments []models.AgreementInstallments
+	payments     []models.Payments
+}
+
+func (repo *fakeRepository) CreatePayment(payment *models.Payments) error {
+	repo.payments = append(repo.payments, *payment)
+	return nil
+}
+
+func (repo *fakeRepository) GetDwellerAgreement(agreement *models.Agreements, agreementId uuid.UUID, userId uuid.UUID) error {
+	for _, existing := range repo.agreements {
+		if existing.AgreementId == agreementId && existing.DwellerUserId == userId {
+			*agreement = existing
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) GetDwellerInstallment(installment *models.AgreementInstallments, installmentId uuid.UUID, userId uuid.UUID) error {
+	for _, existing := range repo.installments {
+		if existing.InstallmentId != installmentId {
+			continue
+		}
+
+		var agreement models.Agreements
+		if err := repo.GetDwellerAgreement(&agreement, existing.AgreementId, userId); err != nil {
+			return err
+		}
+
+		*installment = existing
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) GetPaymentById(payment *models.Payments, paymentId string) error {
+	for _, existing := range repo.payments {
+		if existing.PaymentId.String() == paymentId {
+			*payment = existing
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (repo *fakeRepository) RefundPayment(payment *models.Payments) error {
+	for i := range repo.payments {
+		if repo.payments[i].PaymentId == payment.PaymentId {
+			repo.payments[i].IsSuccess = false
+		}
+	}
+	payment.IsSuccess = false
+	return nil
+}
+
+// fakeAgreementsService refuses refunds with refundErr.
+type fakeAgreementsService struct {
+	agreements.Service
+	refundErr *apperror.AppError
+}
+
+func (s *fakeAgreementsService) CheckPaymentRefundable(payment *models.Payments) *apperror.AppError {
+	return s.refundErr
+}
+
+func newFakeService(repo *fakeRepository) Service {
+	return NewService(zap.NewNop(), repo, nil, NewFakeProvider(&config.Config{PaymentWebhookSecret: "secret"}))
+}
+
+func TestCreatePaymentPayer(t *testing.T) {
+	dwellerId := uuid.New()
+	agreement := models.Agreements{AgreementId: uuid.New(), OwnerUserId: uuid.New(), DwellerUserId: dwellerId}
+	installment := models.AgreementInstallments{InstallmentId: uuid.New(), AgreementId: agreement.AgreementId, Status: enums.UnpaidInstallment}
+
+	tests := []struct {
+		name          string
+		userId        uuid.UUID
+		paymentType   enums.PaymentTypes
+		agreementId   *uuid.UUID
+		installmentId *uuid.UUID
+		wantErr       *apperror.AppErrorType
+	}{
+		{name: "deposit by the dweller", userId: dwellerId, paymentType: enums.DepositPayment, agreementId: &agreement.AgreementId},
+		{name: "purchase by the dweller", userId: dwellerId, paymentType: enums.PurchasePayment, agreementId: &agreement.AgreementId},
+		{name: "deposit by the owner", userId: agreement.OwnerUserId, paymentType: enums.DepositPayment, agreementId: &agreement.AgreementId, wantErr: apperror.AgreementNotFound},
+		{name: "purchase by someone else", userId: uuid.New(), paymentType: enums.PurchasePayment, agreementId: &agreement.AgreementId, wantErr: apperror.AgreementNotFound},
+		{name: "unknown agreement", userId: dwellerId, paymentType: enums.DepositPayment, agreementId: &installment.InstallmentId, wantErr: apperror.AgreementNotFound},
+		{name: "rent by the dweller", userId: dwellerId, installmentId: &installment.InstallmentId},
+		{name: "rent by someone else", userId: uuid.New(), installmentId: &installment.InstallmentId, wantErr: apperror.InstallmentNotFound},
+		{name: "deposit refund", userId: dwellerId, paymentType: enums.DepositRefundPayment, agreementId: &agreement.AgreementId, wantErr: apperror.InvalidBody},
+		{name: "rent for an installment", userId: dwellerId, paymentType: enums.RentPayment, installmentId: &installment.InstallmentId},
+		{name: "rent without an installment", userId: dwellerId, paymentType: enums.RentPayment, agreementId: &agreement.AgreementId, wantErr: apperror.InvalidBody},
+		{name: "deposit for an installment", userId: dwellerId, paymentType: enums.DepositPayment, installmentId: &installment.InstallmentId, wantErr: apperror.InvalidBody},
+		{name: "untyped payment for an agreement", userId: dwellerId, agreementId: &agreement.AgreementId, wantErr: apperror.InvalidBody},
+		{name: "deposit without an agreement", userId: dwellerId, paymentType: enums.DepositPayment, wantErr: apperror.InvalidBody},
+		{name: "unknown payment type", userId: dwellerId, paymentType: "TIP", agreementId: &agreement.AgreementId, wantErr: apperror.InvalidBody},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepository{
+				agreements:   []models.Agreements{agreement},
+				installments: []models.AgreementInstallments{installment},
+			}
+
+			var created models.CreatedPayments
+			apperr := newFakeService(repo).CreatePayment(&created, &models.Payments{
+				PaymentId:     uuid.New(),
+				UserId:        tt.userId,
+				Price:         1000,
+				IsSuccess:     true,
+				PaymentType:   tt.paymentType,
+				AgreementId:   tt.agreementId,
+				InstallmentId: tt.installmentId,
+			})
+
+			if tt.wantErr != nil {
+				if apperr == nil || apperr.Name() != tt.wantErr.Name {
+					t.Fatalf("got error %v, want %v", apperr, tt.wantErr.Name)
+				}
+				if len(repo.payments) != 0 {
+					t.Errorf("got %v payments, want none", len(repo.payments))
+				}
+				return
+			}
+
+			if apperr != nil {
+				t.Fatalf("got error %v", apperr)
+			}
+
+			if len(repo.payments) != 1 || repo.payments[0].IsSuccess {
+				t.Fatalf("want a single pending payment, got %+v", repo.payments)
+			}
+		})
+	}
+}
+
+func TestSplitInstallmentPayment(t *testing.T) {
+	tests := []struct {
+		name  string
+		price float64
+		owed  paymentSplits
+		want  paymentSplits
+	}{
+		{
+			name:  "paid in full",
+			price: 11700,
+			owed:  paymentSplits{Rent: 10000, LateFee: 500, Utility: 1200},
+			want:  paymentSplits{Rent: 10000, LateFee: 500, Utility: 1200},
+		},
+		{
+			name:  "paid in part is split in proportion",
+			price: 5000,
+			owed:  paymentSplits{Rent: 8000, LateFee: 0, Utility: 2000},
+			want:  paymentSplits{Rent: 4000, LateFee: 0, Utility: 1000},
+		},
+		{
+			name:  "a late fee added after a partial payment is paid by the rest",
+			price: 5500,
+			owed:  paymentSplits{Rent: 5000, LateFee: 500, Utility: 0},
+			want:  paymentSplits{Rent: 5000, LateFee: 500, Utility: 0},
+		},
+		{
+			name:  "paying more than is owed counts the rest as rent",
+			price: 12000,
+			owed:  paymentSplits{Rent: 10000, LateFee: 500, Utility: 1200},
+			want:  paymentSplits{Rent: 10300, LateFee: 500, Utility: 1200},
+		},
+		{
+			name:  "a settled installment counts as rent",
+			price: 300,
+			owed:  paymentSplits{Rent: 0, LateFee: 0, Utility: 0},
+			want:  paymentSplits{Rent: 300},
+		},
+		{
+			name:  "overpaid parts are not owed",
+			price: 1000,
+			owed:  paymentSplits{Rent: 1000, LateFee: -200, Utility: 0},
+			want:  paymentSplits{Rent: 1000, LateFee: 0, Utility: 0},
+		},
+		{
+			name:  "rounded to satang and adds up to the price",
+			price: 100,
+			owed:  paymentSplits{Rent: 100, LateFee: 100, Utility: 100},
+			want:  paymentSplits{Rent: 33.34, LateFee: 33.33, Utility: 33.33},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitInstallmentPayment(tt.price, tt.owed); got != tt.want {
+				t.Errorf("splitInstallmentPayment() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPaymentProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		wantErr bool
+	}{
+		{name: "fake in development", cfg: config.Config{AppEnv: "development", PaymentProvider: "FAKE", PaymentWebhookSecret: "secret"}},
+		{name: "fake in lowercase", cfg: config.Config{AppEnv: "development", PaymentProvider: "fake", PaymentWebhookSecret: "secret"}},
+		{name: "fake in production", cfg: config.Config{AppEnv: "production", PaymentProvider: "FAKE", PaymentWebhookSecret: "secret"}, wantErr: true},
+		{name: "fake without an environment", cfg: config.Config{PaymentProvider: "FAKE", PaymentWebhookSecret: "secret"}, wantErr: true},
+		{name: "fake without a webhook secret", cfg: config.Config{AppEnv: "development", PaymentProvider: "FAKE"}, wantErr: true},
+		{name: "stripe", cfg: config.Config{AppEnv: "production", PaymentProvider: "STRIPE", StripeSecretKey: "sk_test", PaymentWebhookSecret: "whsec"}},
+		{name: "stripe by default", cfg: config.Config{AppEnv: "production", StripeSecretKey: "sk_test", PaymentWebhookSecret: "whsec"}},
+		{name: "stripe without a secret key", cfg: config.Config{AppEnv: "production", PaymentProvider: "STRIPE", PaymentWebhookSecret: "whsec"}, wantErr: true},
+		{name: "stripe without a webhook secret", cfg: config.Config{AppEnv: "production", PaymentProvider: "STRIPE", StripeSecretKey: "sk_test"}, wantErr: true},
+		{name: "unknown provider", cfg: config.Config{AppEnv: "development", PaymentProvider: "PAYPAL", PaymentWebhookSecret: "secret"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewPaymentProvider(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPaymentProvider() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && provider == nil {
+				t.Errorf("NewPaymentProvider() returned no provider")
+			}
+		})
+	}
+}
+
+func TestFakeProviderVerifyWebhook(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+		status    enums.CheckoutStatus
+		wantErr   bool
+	}{
+		{name: "paid with the secret", signature: "secret", status: enums.PaidCheckout},
+		{name: "expired with the secret", signature: "secret", status: enums.ExpiredCheckout},
+		{name: "no signature", signature: "", status: enums.PaidCheckout, wantErr: true},
+		{name: "wrong signature", signature: "secreT", status: enums.PaidCheckout, wantErr: true},
+		{name: "prefix of the secret", signature: "secre", status: enums.PaidCheckout, wantErr: true},
+		{name: "open is not an event", signature: "secret", status: enums.OpenCheckout, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakeProvider(&config.Config{PaymentWebhookSecret: "secret"})
+
+			var checkout models.CheckoutSessions
+			if err := provider.CreateCheckout(&checkout, &models.CreatingCheckouts{PaymentId: uuid.New(), Amount: 1000}); err != nil {
+				t.Fatalf("CreateCheckout() error = %v", err)
+			}
+
+			payload, _ := json.Marshal(models.CheckoutEvents{SessionId: checkout.SessionId, Status: tt.status})
+
+			var event models.CheckoutEvents
+			err := provider.VerifyWebhook(&event, payload, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyWebhook() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRefundPayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		refundErr  *apperror.AppError
+		wantErr    *apperror.AppErrorType
+		wantRefund bool
+	}{
+		{name: "not needed by the agreement", wantRefund: true},
+		{name: "needed by the agreement", refundErr: apperror.New(apperror.PaymentNotRefundable), wantErr: apperror.PaymentNotRefundable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakeProvider(&config.Config{PaymentWebhookSecret: "secret"})
+
+			var checkout models.CheckoutSessions
+			if err := provider.CreateCheckout(&checkout, &models.CreatingCheckouts{PaymentId: uuid.New(), Amount: 30000}); err != nil {
+				t.Fatalf("CreateCheckout() error = %v", err)
+			}
+			payload, _ := json.Marshal(models.CheckoutEvents{SessionId: checkout.SessionId, Status: enums.PaidCheckout})
+			if err := provider.VerifyWebhook(&models.CheckoutEvents{}, payload, "secret"); err != nil {
+				t.Fatalf("VerifyWebhook() error = %v", err)
+			}
+
+			agreementId := uuid.New()
+			payment := models.Payments{
+				PaymentId:         uuid.New(),
+				Price:             30000,
+				IsSuccess:         true,
+				AgreementId:       &agreementId,
+				PaymentType:       enums.DepositPayment,
+				CheckoutSessionId: &checkout.SessionId,
+			}
+			repo := &fakeRepository{payments: []models.Payments{payment}}
+			s := NewService(zap.NewNop(), repo, &fakeAgreementsService{refundErr: tt.refundErr}, provider)
+
+			var refunded models.Payments
+			apperr := s.RefundPayment(&refunded, payment.PaymentId.String())
+			if tt.wantErr == nil && apperr != nil {
+				t.Fatalf("RefundPayment() error = %v, want nil", apperr)
+			} else if tt.wantErr != nil && (apperr == nil || apperr.Name() != tt.wantErr.Name) {
+				t.Fatalf("RefundPayment() error = %v, want %v", apperr, tt.wantErr.Name)
+			}
+
+			if refundedPayment := !repo.payments[0].IsSuccess; refundedPayment != tt.wantRefund {
+				t.Errorf("payment refunded = %v, want %v", refundedPayment, tt.wantRefund)
+			}
+
+			// a payment the provider has given back cannot be refunded again
+			providerRefunded := provider.Refund(checkout.SessionId, payment.Price) != nil
+			if providerRefunded != tt.wantRefund {
+				t.Errorf("provider refunded = %v, want %v", providerRefunded, tt.wantRefund)
+			}
+		})
+	}
+}
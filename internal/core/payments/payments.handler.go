@@ -1,9 +1,6 @@
 package payments
 
 import (
-	"fmt"
-	"net/http"
-
 	"github.com/brain-flowing-company/pprp-backend/apperror"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/brain-flowing-company/pprp-backend/internal/utils"
@@ -14,6 +11,9 @@ import (
 type Handler interface {
 	CreatePayment(c *fiber.Ctx) error
 	GetPaymentByUserId(c *fiber.Ctx) error
+	GetPaymentById(c *fiber.Ctx) error
+	RefundPayment(c *fiber.Ctx) error
+	HandleWebhook(c *fiber.Ctx) error
 }
 
 type handlerImpl struct {
@@ -26,30 +26,35 @@ func NewHandler(service Service) Handler {
 	}
 }
 
+// @router      /api/v1/payments [post]
+// @summary     Create a payment *use cookies*
+// @description Create a pending payment and open a checkout session for it with the payment provider. Redirect the user to `checkout_url` to pay. The payment succeeds once the provider confirms it through the webhook
+// @tags        payments
+// @accept      json
+// @produce     json
+// @param       body body models.Payments true "Payment to make"
+// @success     201	{object} models.CreatedPayments
+// @failure     400 {object} models.ErrorResponses "Invalid payment body"
+// @failure     404 {object} models.ErrorResponses "Installment or agreement not found"
+// @failure     409 {object} models.ErrorResponses "Installment already paid"
+// @failure     500 {object} models.ErrorResponses "Failed to create payment"
+// @failure     502 {object} models.ErrorResponses "Could not start the checkout"
 func (h *handlerImpl) CreatePayment(c *fiber.Ctx) error {
-	payment := models.Payments{
-		PaymentId: uuid.New(),
-	}
+	payment := models.Payments{}
 
 	if err := c.BodyParser(&payment); err != nil {
 		return utils.ResponseError(c, apperror.New(apperror.InvalidBody).Describe("Invalid payment body"))
 	}
 
-	userId := c.Locals("session").(models.Sessions).UserId
-	fmt.Println(userId)
-	payment.UserId = userId
-	fmt.Println("payment = ", payment)
+	payment.PaymentId = uuid.New()
+	payment.UserId = c.Locals("session").(models.Sessions).UserId
 
-	if err := h.service.CreatePayment(&payment); err != nil {
-		return utils.ResponseError(c, err)
-	}
-	err := CheckoutV2(c, payment.Name, payment.Price)
-	if err != nil {
-		return utils.ResponseError(c, apperror.New(apperror.InternalServerError).Describe("Failed to create payment"))
+	created := models.CreatedPayments{}
+	if apperr := h.service.CreatePayment(&created, &payment); apperr != nil {
+		return utils.ResponseError(c, apperr)
 	}
 
-	return utils.ResponseMessage(c, http.StatusOK, "Payment created successfully")
-
+	return c.Status(fiber.StatusCreated).JSON(created)
 }
 
 func (h *handlerImpl) GetPaymentByUserId(c *fiber.Ctx) error {
@@ -64,3 +69,69 @@ func (h *handlerImpl) GetPaymentByUserId(c *fiber.Ctx) error {
 	}
 	return nil
 }
+
+// @router      /api/v1/payments/:paymentId [get]
+// @summary     Get a payment *use cookies*
+// @description Get a payment of the current user. A pending payment is checked with the payment provider, so the success page can poll it until the payment goes through
+// @tags        payments
+// @produce     json
+// @param       paymentId path string true "Payment ID"
+// @success     200	{object} models.Payments
+// @failure     400 {object} models.ErrorResponses "Invalid payment id"
+// @failure     403 {object} models.ErrorResponses "Not the payer"
+// @failure     404 {object} models.ErrorResponses "Payment not found"
+// @failure     500 {object} models.ErrorResponses "Could not get payment"
+// @failure     502 {object} models.ErrorResponses "Could not get the status of the checkout"
+func (h *handlerImpl) GetPaymentById(c *fiber.Ctx) error {
+	paymentId := c.Params("paymentId")
+	session := c.Locals("session").(models.Sessions)
+
+	payment := models.Payments{}
+	if apperr := h.service.GetPaymentById(&payment, paymentId, &session); apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(payment)
+}
+
+// @router      /api/v1/payments/:paymentId/refund [post]
+// @summary     Refund a payment *use cookies*
+// @description Refund a successful payment in full through the payment provider. The payment stops counting as paid, and the installment it settled is due again. Admin only
+// @tags        payments
+// @produce     json
+// @param       paymentId path string true "Payment ID"
+// @success     200	{object} models.Payments
+// @failure     400 {object} models.ErrorResponses "Invalid payment id"
+// @failure     404 {object} models.ErrorResponses "Payment not found"
+// @failure     409 {object} models.ErrorResponses "Payment not refundable"
+// @failure     500 {object} models.ErrorResponses "Could not record payment refund"
+// @failure     502 {object} models.ErrorResponses "Could not refund payment"
+func (h *handlerImpl) RefundPayment(c *fiber.Ctx) error {
+	paymentId := c.Params("paymentId")
+
+	payment := models.Payments{}
+	if apperr := h.service.RefundPayment(&payment, paymentId); apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(payment)
+}
+
+// @router      /api/v1/payments/webhook [post]
+// @summary     Receive payment provider events
+// @description Called by the payment provider when a checkout session changes. The body is verified against the `Stripe-Signature` header before a paid session completes its payment
+// @tags        payments
+// @accept      json
+// @produce     json
+// @param       Stripe-Signature header string true "Signature of the body"
+// @success     200	{object} models.MessageResponses
+// @failure     400 {object} models.ErrorResponses "Could not verify webhook"
+// @failure     500 {object} models.ErrorResponses "Could not complete payment"
+func (h *handlerImpl) HandleWebhook(c *fiber.Ctx) error {
+	apperr := h.service.HandleWebhook(c.Body(), c.Get("Stripe-Signature"))
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, fiber.StatusOK, "Webhook received")
+}
@@ -0,0 +1,140 @@
+package payments
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+type stripeProvider struct {
+	client        *client.API
+	webhookSecret string
+	successUrl    string
+	cancelUrl     string
+}
+
+func NewStripeProvider(cfg *config.Config) PaymentProvider {
+	return &stripeProvider{
+		client:        client.New(cfg.StripeSecretKey, nil),
+		webhookSecret: cfg.PaymentWebhookSecret,
+		successUrl:    cfg.PaymentSuccessUrl,
+		cancelUrl:     cfg.PaymentCancelUrl,
+	}
+}
+
+func (p *stripeProvider) CreateCheckout(checkout *models.CheckoutSessions, creating *models.CreatingCheckouts) error {
+	params := &stripe.CheckoutSessionParams{
+		Mode:              stripe.String(string(stripe.CheckoutSessionModePayment)),
+		ClientReferenceID: stripe.String(creating.PaymentId.String()),
+		PaymentMethodTypes: []*string{
+			stripe.String(string(stripe.PaymentMethodTypeCard)),
+			stripe.String(string(stripe.PaymentMethodTypePromptPay)),
+		},
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+					Currency: stripe.String(string(stripe.CurrencyTHB)),
+					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+						Name: stripe.String(creating.Name),
+					},
+					UnitAmount: stripe.Int64(toSatang(creating.Amount)),
+				},
+				Quantity: stripe.Int64(1),
+			},
+		},
+		// Stripe fills in the session id itself
+		SuccessURL: stripe.String(p.successUrl),
+		CancelURL:  stripe.String(p.cancelUrl),
+	}
+
+	session, err := p.client.CheckoutSessions.New(params)
+	if err != nil {
+		return err
+	}
+
+	*checkout = stripeCheckout(session)
+	return nil
+}
+
+func (p *stripeProvider) GetCheckout(checkout *models.CheckoutSessions, sessionId string) error {
+	session, err := p.client.CheckoutSessions.Get(sessionId, nil)
+	if err != nil {
+		return err
+	}
+
+	*checkout = stripeCheckout(session)
+	return nil
+}
+
+// Refund refunds amount of what was paid through a checkout session. Refunding
+// less than the whole payment is allowed.
+func (p *stripeProvider) Refund(sessionId string, amount float64) error {
+	session, err := p.client.CheckoutSessions.Get(sessionId, nil)
+	if err != nil {
+		return err
+	}
+
+	if session.PaymentIntent == nil {
+		return errNotRefundable
+	}
+
+	_, err = p.client.Refunds.New(&stripe.RefundParams{
+		PaymentIntent: stripe.String(session.PaymentIntent.ID),
+		Amount:        stripe.Int64(toSatang(amount)),
+	})
+	return err
+}
+
+func (p *stripeProvider) VerifyWebhook(event *models.CheckoutEvents, payload []byte, signature string) error {
+	stripeEvent, err := webhook.ConstructEvent(payload, signature, p.webhookSecret)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidWebhook, err)
+	}
+
+	*event = models.CheckoutEvents{}
+
+	// PromptPay payments complete the session before the money arrives, and
+	// are only paid once async_payment_succeeded comes in
+	switch stripeEvent.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded", "checkout.session.expired":
+		var session stripe.CheckoutSession
+		if err := json.Unmarshal(stripeEvent.Data.Raw, &session); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidWebhook, err)
+		}
+
+		checkout := stripeCheckout(&session)
+		event.SessionId = checkout.SessionId
+		event.Status = checkout.Status
+	}
+
+	return nil
+}
+
+func stripeCheckout(session *stripe.CheckoutSession) models.CheckoutSessions {
+	checkout := models.CheckoutSessions{
+		SessionId: session.ID,
+		Url:       session.URL,
+		Status:    enums.OpenCheckout,
+	}
+
+	switch {
+	case session.Status == stripe.CheckoutSessionStatusExpired:
+		checkout.Status = enums.ExpiredCheckout
+	case session.Status == stripe.CheckoutSessionStatusComplete && session.PaymentStatus != stripe.CheckoutSessionPaymentStatusUnpaid:
+		checkout.Status = enums.PaidCheckout
+	}
+
+	return checkout
+}
+
+// toSatang converts an amount of baht to satang, the unit Stripe charges in.
+func toSatang(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -21,6 +21,12 @@ type Handler interface {
 	RemoveFavoriteProperty(c *fiber.Ctx) error
 	GetMyFavoriteProperties(c *fiber.Ctx) error
 	GetTop10Properties(c *fiber.Ctx) error
+	GetPropertyHistories(c *fiber.Ctx) error
+	RevertPropertyById(c *fiber.Ctx) error
+	GetPropertyAttachments(c *fiber.Ctx) error
+	CreatePropertyAttachment(c *fiber.Ctx) error
+	DeletePropertyAttachmentById(c *fiber.Ctx) error
+	ReorderPropertyAttachments(c *fiber.Ctx) error
 }
 
 type handlerImpl struct {
@@ -35,7 +41,7 @@ func NewHandler(service Service) Handler {
 
 // @router      /api/v1/properties/:propertyId [get]
 // @summary     Get property by propertyId
-// @description Get property by its id
+// @description Get property by its id, including its images and attachments
 // @tags        property
 // @produce     json
 // @param	    propertyId path string true "Property id"
@@ -237,8 +243,9 @@ func (h *handlerImpl) UpdatePropertyById(c *fiber.Ctx) error {
 // @failure     500 {object} models.ErrorResponses "Could not delete property"
 func (h *handlerImpl) DeletePropertyById(c *fiber.Ctx) error {
 	propertyId := c.Params("propertyId")
+	userId := c.Locals("session").(models.Sessions).UserId
 
-	err := h.service.DeletePropertyById(propertyId)
+	err := h.service.DeletePropertyById(propertyId, userId)
 	if err != nil {
 		return utils.ResponseError(c, err)
 	}
@@ -349,3 +356,179 @@ func (h *handlerImpl) GetTop10Properties(c *fiber.Ctx) error {
 
 	return c.JSON(properties)
 }
+
+// @router      /api/v1/properties/:propertyId/histories [get]
+// @summary     Get property version history *use cookies*
+// @description Get the audit trail of a property, newest version first. Only the property owner or an admin can view it
+// @tags        property
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       limit query int false "Pagination limit per page, max 50, default 20"
+// @param       page  query int false "Pagination page index as 1-based index, default 1"
+// @success     200	{object} models.PropertyHistoriesResponses
+// @failure     400 {object} models.ErrorResponses "Invalid property id"
+// @failure	    403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property id not found"
+// @failure     500 {object} models.ErrorResponses "Could not get property histories"
+func (h *handlerImpl) GetPropertyHistories(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	session := c.Locals("session").(models.Sessions)
+
+	limit := utils.Clamp(c.QueryInt("limit", 20), 1, 50)
+	page := utils.Max(c.QueryInt("page", 1), 1)
+
+	paginated := utils.NewPaginatedQuery(page, limit)
+
+	histories := models.PropertyHistoriesResponses{}
+	err := h.service.GetPropertyHistories(&histories, propertyId, &session, paginated)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return c.JSON(histories)
+}
+
+// @router      /api/v1/properties/:propertyId/histories/:version/revert [post]
+// @summary     Revert a property to a previous version *use cookies*
+// @description Restore the property details, prices and images recorded at the given version. The revert is recorded as a new version. Only the property owner or an admin can revert
+// @tags        property
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       version path int true "Version to revert to"
+// @success     200	{object} models.MessageResponses "Property reverted"
+// @failure     400 {object} models.ErrorResponses "Invalid property id or version"
+// @failure	    403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property or version not found"
+// @failure     500 {object} models.ErrorResponses "Could not revert property"
+func (h *handlerImpl) RevertPropertyById(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	session := c.Locals("session").(models.Sessions)
+
+	version, err := c.ParamsInt("version")
+	if err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidPropertyHistoryVersion).
+			Describe("Invalid property history version"))
+	}
+
+	apperr := h.service.RevertPropertyById(propertyId, int64(version), &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Property reverted")
+}
+
+// @router      /api/v1/properties/:propertyId/attachments [get]
+// @summary     Get property attachments
+// @description Get the documents, floor plans, video and tour links of a property in display order
+// @tags        property
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @success     200	{object} []models.PropertyAttachments
+// @failure     400 {object} models.ErrorResponses "Invalid property id"
+// @failure     404 {object} models.ErrorResponses "Property id not found"
+// @failure     500 {object} models.ErrorResponses "Could not get property attachments"
+func (h *handlerImpl) GetPropertyAttachments(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+
+	attachments := []models.PropertyAttachments{}
+	err := h.service.GetPropertyAttachments(&attachments, propertyId)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return c.JSON(attachments)
+}
+
+// @router      /api/v1/properties/:propertyId/attachments [post]
+// @summary     Add a property attachment *use cookies*
+// @description Add an attachment to a property. DOCUMENT and FLOOR_PLAN attachments are uploaded in formData with field `attachment`. Documents must be .pdf and floor plans .pdf / .png / .jpg. VIDEO_URL and TOUR_URL attachments take a link in field `url` instead. Only the property owner or an admin can add attachments
+// @tags        property
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       formData formData models.CreatingPropertyAttachments true "Attachment details"
+// @success     201	{object} models.PropertyAttachments
+// @failure     400 {object} models.ErrorResponses "Invalid attachment type, content type or url"
+// @failure	    403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property id not found"
+// @failure     500 {object} models.ErrorResponses "Could not create property attachment"
+func (h *handlerImpl) CreatePropertyAttachment(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	session := c.Locals("session").(models.Sessions)
+
+	var creating models.CreatingPropertyAttachments
+	if err := c.BodyParser(&creating); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidBody).
+			Describe("Invalid request body"))
+	}
+
+	file, _ := c.FormFile("attachment")
+
+	attachment := models.PropertyAttachments{}
+	err := h.service.CreatePropertyAttachment(&attachment, propertyId, &creating, file, &session)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(attachment)
+}
+
+// @router      /api/v1/properties/:propertyId/attachments/:attachmentId [delete]
+// @summary     Delete a property attachment *use cookies*
+// @description Delete an attachment of a property. Only the property owner or an admin can delete attachments
+// @tags        property
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       attachmentId path string true "Attachment id"
+// @success     200	{object} models.MessageResponses "Property attachment deleted"
+// @failure     400 {object} models.ErrorResponses "Invalid property id or attachment id"
+// @failure	    403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property or attachment not found"
+// @failure     500 {object} models.ErrorResponses "Could not delete property attachment"
+func (h *handlerImpl) DeletePropertyAttachmentById(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	attachmentId := c.Params("attachmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	err := h.service.DeletePropertyAttachmentById(propertyId, attachmentId, &session)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Property attachment deleted")
+}
+
+// @router      /api/v1/properties/:propertyId/attachments/order [put]
+// @summary     Reorder property attachments *use cookies*
+// @description Set the display order of the attachments of a property. The body must list every attachment id of the property exactly once. Only the property owner or an admin can reorder attachments
+// @tags        property
+// @accept      json
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @param       body body models.ReorderingPropertyAttachments true "Attachment ids in the new order"
+// @success     200	{object} []models.PropertyAttachments "Attachments in their new order"
+// @failure     400 {object} models.ErrorResponses "Invalid request body or attachment order"
+// @failure	    403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property id not found"
+// @failure     500 {object} models.ErrorResponses "Could not reorder property attachments"
+func (h *handlerImpl) ReorderPropertyAttachments(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	session := c.Locals("session").(models.Sessions)
+
+	var reordering models.ReorderingPropertyAttachments
+	if err := c.BodyParser(&reordering); err != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidBody).
+			Describe("Invalid request body"))
+	}
+
+	attachments := []models.PropertyAttachments{}
+	err := h.service.ReorderPropertyAttachments(&attachments, propertyId, &reordering, &session)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return c.JSON(attachments)
+}
@@ -2,26 +2,38 @@ package properties
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var errInvalidAttachmentOrder = errors.New("attachment ids are not a permutation of the attachments")
+
 type Repository interface {
 	GetAllProperties(*models.AllPropertiesResponses, string, string, *utils.PaginatedQuery, *utils.SortedQuery, *utils.FilteredQuery) error
 	GetPropertyById(*models.Properties, string, string) error
 	GetPropertyByOwnerId(*models.MyPropertiesResponses, string, *utils.PaginatedQuery, *utils.SortedQuery) error
-	CreateProperty(*models.PropertyInfos) error
-	UpdatePropertyById(*models.PropertyInfos, string) error
-	DeletePropertyById(string) error
+	GetPropertyInfosById(*models.PropertyInfos, string) error
+	CreateProperty(*models.PropertyInfos, *models.PropertyHistories) error
+	UpdatePropertyById(*models.PropertyInfos, string, *models.PropertyHistories) error
+	DeletePropertyById(string, *models.PropertyHistories) error
 	CountProperty(*int64, string) error
 	CountPropertyImages(*int64, string) error
 	AddFavoriteProperty(*models.FavoriteProperties) error
 	RemoveFavoriteProperty(string, string) error
 	GetFavoritePropertiesByUserId(*models.MyFavoritePropertiesResponses, string, *utils.PaginatedQuery, *utils.SortedQuery) error
 	GetTop10Properties(*[]models.Properties, string) error
+	GetPropertyHistories(*models.PropertyHistoriesResponses, string, *utils.PaginatedQuery) error
+	GetPropertyHistoryByVersion(*models.PropertyHistories, string, int64) error
+	GetPropertyAttachments(*[]models.PropertyAttachments, string) error
+	CreatePropertyAttachment(*models.PropertyAttachments) error
+	DeletePropertyAttachmentById(string, string) error
+	ReorderPropertyAttachments(*[]models.PropertyAttachments, string, []uuid.UUID) error
 }
 
 type repositoryImpl struct {
@@ -41,9 +53,10 @@ func (repo *repositoryImpl) GetAllProperties(properties *models.AllPropertiesRes
 				FROM (
 					SELECT properties.*
 					FROM properties
-					LEFT JOIN selling_properties ON properties.property_id = selling_properties.property_id
-					LEFT JOIN renting_properties ON properties.property_id = renting_properties.property_id
-					WHERE (LOWER(property_name) LIKE @query OR LOWER(property_description) LIKE @query)
+					LEFT JOIN selling_properties ON (properties.property_id = selling_properties.property_id AND selling_properties.deleted_at IS NULL)
+					LEFT JOIN renting_properties ON (properties.property_id = renting_properties.property_id AND renting_properties.deleted_at IS NULL)
+					WHERE properties.deleted_at IS NULL
+					AND (LOWER(property_name) LIKE @query OR LOWER(property_description) LIKE @query)
 					AND (%s)
 				) AS props`, filtered.FilteredSQL(),
 		)
@@ -69,9 +82,10 @@ func (repo *repositoryImpl) GetAllProperties(properties *models.AllPropertiesRes
 					renting_properties.price_per_month,
 					renting_properties.is_occupied
 				FROM properties
-				LEFT JOIN selling_properties ON properties.property_id = selling_properties.property_id
-				LEFT JOIN renting_properties ON properties.property_id = renting_properties.property_id
-				WHERE (LOWER(property_name) LIKE @query OR LOWER(property_description) LIKE @query)
+				LEFT JOIN selling_properties ON (properties.property_id = selling_properties.property_id AND selling_properties.deleted_at IS NULL)
+				LEFT JOIN renting_properties ON (properties.property_id = renting_properties.property_id AND renting_properties.deleted_at IS NULL)
+				WHERE properties.deleted_at IS NULL
+				AND (LOWER(property_name) LIKE @query OR LOWER(property_description) LIKE @query)
 				AND (%s)
 			) AS props
 			LEFT JOIN favorite_properties ON (
@@ -124,8 +138,8 @@ func (repo *repositoryImpl) GetPropertyById(property *models.Properties, propert
 						ELSE FALSE
 					END AS is_favorite
 				FROM properties
-				LEFT JOIN selling_properties ON properties.property_id = selling_properties.property_id
-				LEFT JOIN renting_properties ON properties.property_id = renting_properties.property_id
+				LEFT JOIN selling_properties ON (properties.property_id = selling_properties.property_id AND selling_properties.deleted_at IS NULL)
+				LEFT JOIN renting_properties ON (properties.property_id = renting_properties.property_id AND renting_properties.deleted_at IS NULL)
 				LEFT JOIN favorite_properties ON (
 					favorite_properties.property_id = properties.property_id AND
 					favorite_properties.user_id = @user_id
@@ -146,7 +160,7 @@ func (repo *repositoryImpl) GetPropertyById(property *models.Properties, propert
 			return err
 		}
 
-		return nil
+		return getPropertyAttachments(tx, &property.Attachments, propertyId)
 	})
 
 }
@@ -187,8 +201,8 @@ func (repo *repositoryImpl) GetPropertyByOwnerId(properties *models.MyProperties
 					renting_properties.price_per_month,
 					renting_properties.is_occupied
 				FROM properties
-				LEFT JOIN selling_properties ON properties.property_id = selling_properties.property_id
-				LEFT JOIN renting_properties ON properties.property_id = renting_properties.property_id
+				LEFT JOIN selling_properties ON (properties.property_id = selling_properties.property_id AND selling_properties.deleted_at IS NULL)
+				LEFT JOIN renting_properties ON (properties.property_id = renting_properties.property_id AND renting_properties.deleted_at IS NULL)
 				WHERE properties.owner_id = @owner_id
 			) AS props
 			LEFT JOIN favorite_properties ON (
@@ -222,7 +236,11 @@ func (repo *repositoryImpl) GetPropertyByOwnerId(properties *models.MyProperties
 	})
 }
 
-func (repo *repositoryImpl) CreateProperty(property *models.PropertyInfos) error {
+func (repo *repositoryImpl) GetPropertyInfosById(property *models.PropertyInfos, propertyId string) error {
+	return repo.getPropertyInfos(repo.db, property, propertyId)
+}
+
+func (repo *repositoryImpl) CreateProperty(property *models.PropertyInfos, history *models.PropertyHistories) error {
 	return repo.db.Transaction(func(tx *gorm.DB) error {
 		propertyQuery := `INSERT INTO properties (property_id, owner_id, property_name, property_description, property_type, address, alley, street, sub_district, district, province, country, postal_code, bedrooms, bathrooms, furnishing, floor, floor_size, floor_size_unit, unit_number)
             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
@@ -245,30 +263,40 @@ func (repo *repositoryImpl) CreateProperty(property *models.PropertyInfos) error
 		}
 
 		if property.Price > 0 {
-			sellingQuery := `INSERT INTO selling_properties (property_id, price, is_sold) VALUES (?, ?, ?);`
-			if err := tx.Exec(sellingQuery, property.PropertyId, property.Price, property.IsSold).Error; err != nil {
+			sellingQuery := `INSERT INTO selling_properties (property_id, price, is_sold) VALUES (?, ?, FALSE);`
+			if err := tx.Exec(sellingQuery, property.PropertyId, property.Price).Error; err != nil {
 				return err
 			}
 		}
 
 		if property.PricePerMonth > 0 {
-			rentingQuery := `INSERT INTO renting_properties (property_id, price_per_month, is_occupied) VALUES (?, ?, ?);`
-			if err := tx.Exec(rentingQuery, property.PropertyId, property.PricePerMonth, property.IsOccupied).Error; err != nil {
+			rentingQuery := `INSERT INTO renting_properties (property_id, price_per_month, is_occupied) VALUES (?, ?, FALSE);`
+			if err := tx.Exec(rentingQuery, property.PropertyId, property.PricePerMonth).Error; err != nil {
 				return err
 			}
 		}
 
-		return nil
+		var createdProperty models.PropertyInfos
+		if err := repo.getPropertyInfos(tx, &createdProperty, property.PropertyId.String()); err != nil {
+			return err
+		}
+
+		return repo.createPropertyHistory(tx, history, nil, &createdProperty)
 	})
 }
 
-func (repo *repositoryImpl) UpdatePropertyById(property *models.PropertyInfos, propertyId string) error {
+func (repo *repositoryImpl) UpdatePropertyById(property *models.PropertyInfos, propertyId string, history *models.PropertyHistories) error {
 	return repo.db.Transaction(func(tx *gorm.DB) error {
 		var existingProperty models.Properties
 		if err := tx.Model(&models.Properties{}).First(&existingProperty, "property_id = ?", propertyId).Error; err != nil {
 			return err
 		}
 
+		var oldProperty models.PropertyInfos
+		if err := repo.getPropertyInfos(tx, &oldProperty, propertyId); err != nil {
+			return err
+		}
+
 		propertyQuery := `UPDATE properties SET property_name = ?, property_description = ?, property_type = ?, address = ?, alley = ?, street = ?, sub_district = ?, district = ?, province = ?, country = ?, postal_code = ?, bedrooms = ?, bathrooms = ?, furnishing = ?, floor = ?, floor_size = ?, floor_size_unit = ?, unit_number = ?, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?`
 		if err := tx.Exec(propertyQuery,
 			property.PropertyName, property.PropertyDescription, property.PropertyType, property.Address,
@@ -279,7 +307,7 @@ func (repo *repositoryImpl) UpdatePropertyById(property *models.PropertyInfos, p
 			return err
 		}
 
-		if err := tx.Where("property_id = ?", propertyId).Delete(&models.PropertyImages{}).Error; err != nil {
+		if err := tx.Where("property_id = ? AND deleted_at IS NULL", propertyId).Delete(&models.PropertyImages{}).Error; err != nil {
 			return err
 		} else if len(property.ImageUrls) != 0 {
 			createImageQuery := `INSERT INTO property_images (property_id, image_url) VALUES (?, ?);`
@@ -299,30 +327,43 @@ func (repo *repositoryImpl) UpdatePropertyById(property *models.PropertyInfos, p
 			}
 		}
 
-		if property.Price != existingProperty.SellingProperty.Price || property.IsSold != existingProperty.SellingProperty.IsSold {
-			sellingQuery := `UPDATE selling_properties SET price = ?, is_sold = ?, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?;`
-			if err := tx.Exec(sellingQuery, property.Price, property.IsSold, propertyId).Error; err != nil {
+		// is_sold and is_occupied are only ever set by agreements
+		if property.Price != existingProperty.SellingProperty.Price {
+			sellingQuery := `UPDATE selling_properties SET price = ?, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?;`
+			if err := tx.Exec(sellingQuery, property.Price, propertyId).Error; err != nil {
 				return err
 			}
 		}
 
-		if property.PricePerMonth != existingProperty.RentingProperty.PricePerMonth || property.IsOccupied != existingProperty.RentingProperty.IsOccupied {
-			rentingQuery := `UPDATE renting_properties SET price_per_month = ?, is_occupied = ?, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?;`
-			if err := tx.Exec(rentingQuery, property.PricePerMonth, property.IsOccupied, propertyId).Error; err != nil {
+		if property.PricePerMonth != existingProperty.RentingProperty.PricePerMonth {
+			rentingQuery := `UPDATE renting_properties SET price_per_month = ?, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?;`
+			if err := tx.Exec(rentingQuery, property.PricePerMonth, propertyId).Error; err != nil {
 				return err
 			}
 		}
 
-		return nil
+		var updatedProperty models.PropertyInfos
+		if err := repo.getPropertyInfos(tx, &updatedProperty, propertyId); err != nil {
+			return err
+		}
+
+		return repo.createPropertyHistory(tx, history, &oldProperty, &updatedProperty)
 	})
 }
 
-func (repo *repositoryImpl) DeletePropertyById(propertyId string) error {
-	if err := repo.db.First(&models.Properties{}, "property_id = ?", propertyId).Error; err != nil {
-		return err
-	}
+func (repo *repositoryImpl) DeletePropertyById(propertyId string, history *models.PropertyHistories) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var deletedProperty models.PropertyInfos
+		if err := repo.getPropertyInfos(tx, &deletedProperty, propertyId); err != nil {
+			return err
+		}
+
+		if err := repo.createPropertyHistory(tx, history, &deletedProperty, nil); err != nil {
+			return err
+		}
 
-	return repo.db.Where("property_id = ?", propertyId).Delete(&models.Properties{}).Error
+		return tx.Where("property_id = ?", propertyId).Delete(&models.Properties{}).Error
+	})
 }
 
 func (repo *repositoryImpl) CountProperty(countProperty *int64, propertyId string) error {
@@ -380,8 +421,8 @@ func (repo *repositoryImpl) GetFavoritePropertiesByUserId(properties *models.MyF
 				renting_properties.price_per_month,
 				renting_properties.is_occupied
 				FROM properties
-				LEFT JOIN selling_properties ON properties.property_id = selling_properties.property_id
-				LEFT JOIN renting_properties ON properties.property_id = renting_properties.property_id
+				LEFT JOIN selling_properties ON (properties.property_id = selling_properties.property_id AND selling_properties.deleted_at IS NULL)
+				LEFT JOIN renting_properties ON (properties.property_id = renting_properties.property_id AND renting_properties.deleted_at IS NULL)
 			) AS props ON favorite_properties.property_id = props.property_id
 			WHERE favorite_properties.user_id = @user_id
 			%s %s`,
@@ -441,8 +482,8 @@ func (repo *repositoryImpl) GetTop10Properties(properties *[]models.Properties,
 					LIMIT 10
 				) AS top10
 				LEFT JOIN properties ON top10.property_id = properties.property_id
-				LEFT JOIN selling_properties ON top10.property_id = selling_properties.property_id
-				LEFT JOIN renting_properties ON top10.property_id = renting_properties.property_id
+				LEFT JOIN selling_properties ON (top10.property_id = selling_properties.property_id AND selling_properties.deleted_at IS NULL)
+				LEFT JOIN renting_properties ON (top10.property_id = renting_properties.property_id AND renting_properties.deleted_at IS NULL)
 			) AS props
 			LEFT JOIN favorite_properties ON (
 				favorite_properties.property_id = props.property_id AND
@@ -469,3 +510,184 @@ func (repo *repositoryImpl) GetTop10Properties(properties *[]models.Properties,
 	})
 
 }
+
+func (repo *repositoryImpl) GetPropertyHistories(histories *models.PropertyHistoriesResponses, propertyId string, paginated *utils.PaginatedQuery) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PropertyHistories{}).
+			Where("property_id = ?", propertyId).
+			Count(&histories.Total).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.PropertyHistories{}).
+			Raw(fmt.Sprintf(`
+				SELECT property_histories.*,
+					users.first_name AS actor_first_name,
+					users.last_name AS actor_last_name
+				FROM property_histories
+				LEFT JOIN users ON property_histories.actor_user_id = users.user_id
+				WHERE property_histories.property_id = @property_id
+				ORDER BY property_histories.version DESC
+				%s`, paginated.PaginatedSQL()),
+				sql.Named("property_id", propertyId)).
+			Scan(&histories.Histories).Error
+	})
+}
+
+func (repo *repositoryImpl) GetPropertyHistoryByVersion(history *models.PropertyHistories, propertyId string, version int64) error {
+	return repo.db.Model(&models.PropertyHistories{}).
+		First(history, "property_id = ? AND version = ?", propertyId, version).Error
+}
+
+func (repo *repositoryImpl) getPropertyInfos(tx *gorm.DB, property *models.PropertyInfos, propertyId string) error {
+	result := tx.Model(&models.Properties{}).
+		Raw(`
+			SELECT properties.*,
+				selling_properties.price,
+				selling_properties.is_sold,
+				renting_properties.price_per_month,
+				renting_properties.is_occupied
+			FROM properties
+			LEFT JOIN selling_properties ON (properties.property_id = selling_properties.property_id AND selling_properties.deleted_at IS NULL)
+			LEFT JOIN renting_properties ON (properties.property_id = renting_properties.property_id AND renting_properties.deleted_at IS NULL)
+			WHERE properties.property_id = @property_id
+			`, sql.Named("property_id", propertyId)).
+		Scan(property)
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return tx.Model(&models.PropertyImages{}).
+		Raw(`
+			SELECT image_url
+			FROM property_images
+			WHERE property_id = @property_id AND deleted_at IS NULL
+			ORDER BY image_url
+			`, sql.Named("property_id", propertyId)).
+		Pluck("image_url", &property.ImageUrls).Error
+}
+
+func (repo *repositoryImpl) createPropertyHistory(tx *gorm.DB, history *models.PropertyHistories, oldProperty *models.PropertyInfos, newProperty *models.PropertyInfos) error {
+	if history == nil {
+		return nil
+	}
+
+	history.HistoryId = uuid.New()
+	history.Changes = utils.DiffFields(oldProperty, newProperty)
+
+	switch {
+	case newProperty != nil:
+		history.PropertyId = newProperty.PropertyId
+		history.Snapshot = models.PropertySnapshots(*newProperty)
+
+	case oldProperty != nil:
+		history.PropertyId = oldProperty.PropertyId
+		history.Snapshot = models.PropertySnapshots(*oldProperty)
+		history.Changes = models.FieldChanges{}
+	}
+
+	if history.Action != enums.DeletePropertyHistory && len(history.Changes) == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&models.PropertyHistories{}).
+		Raw(`SELECT COALESCE(MAX(version), 0) + 1 FROM property_histories WHERE property_id = ?`, history.PropertyId).
+		Scan(&history.Version).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(history).Error
+}
+
+func (repo *repositoryImpl) GetPropertyAttachments(attachments *[]models.PropertyAttachments, propertyId string) error {
+	return getPropertyAttachments(repo.db, attachments, propertyId)
+}
+
+// CreatePropertyAttachment appends an attachment after the others. The property
+// is locked so that concurrent uploads and reorders do not share an order.
+func (repo *repositoryImpl) CreatePropertyAttachment(attachment *models.PropertyAttachments) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProperty(tx, attachment.PropertyId.String()); err != nil {
+			return err
+		}
+
+		if err := tx.Raw(`
+			SELECT COALESCE(MAX(display_order), 0) + 1
+			FROM property_attachments
+			WHERE property_id = ?
+			`, attachment.PropertyId).
+			Scan(&attachment.DisplayOrder).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(attachment).Error
+	})
+}
+
+func (repo *repositoryImpl) DeletePropertyAttachmentById(propertyId string, attachmentId string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.PropertyAttachments{}, "property_id = ? AND attachment_id = ?", propertyId, attachmentId).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("attachment_id = ?", attachmentId).Delete(&models.PropertyAttachments{}).Error
+	})
+}
+
+// ReorderPropertyAttachments checks the new order against the attachments as
+// they are in the transaction, so an attachment added or deleted meanwhile
+// cannot be left out, and returns the attachments in their committed order.
+func (repo *repositoryImpl) ReorderPropertyAttachments(attachments *[]models.PropertyAttachments, propertyId string, attachmentIds []uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProperty(tx, propertyId); err != nil {
+			return err
+		}
+
+		var current []uuid.UUID
+		if err := tx.Raw(`SELECT attachment_id FROM _property_attachments WHERE property_id = ? AND deleted_at IS NULL`, propertyId).
+			Scan(&current).Error; err != nil {
+			return err
+		}
+
+		// the new order has to be a permutation of the current attachments
+		remaining := make(map[uuid.UUID]bool, len(current))
+		for _, attachmentId := range current {
+			remaining[attachmentId] = true
+		}
+
+		for _, attachmentId := range attachmentIds {
+			if !remaining[attachmentId] {
+				return errInvalidAttachmentOrder
+			}
+			delete(remaining, attachmentId)
+		}
+
+		if len(remaining) != 0 {
+			return errInvalidAttachmentOrder
+		}
+
+		for i, attachmentId := range attachmentIds {
+			if err := tx.Model(&models.PropertyAttachments{}).
+				Where("property_id = ? AND attachment_id = ?", propertyId, attachmentId).
+				Update("display_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+
+		return getPropertyAttachments(tx, attachments, propertyId)
+	})
+}
+
+func getPropertyAttachments(db *gorm.DB, attachments *[]models.PropertyAttachments, propertyId string) error {
+	return db.Model(&models.PropertyAttachments{}).
+		Where("property_id = ?", propertyId).
+		Order("display_order ASC").
+		Find(attachments).Error
+}
+
+// lockProperty locks the row of a property until the end of the transaction.
+func lockProperty(tx *gorm.DB, propertyId string) error {
+	return tx.Exec(`SELECT 1 FROM _properties WHERE property_id = ? FOR UPDATE`, propertyId).Error
+}
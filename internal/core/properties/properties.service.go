@@ -3,12 +3,18 @@ package properties
 import (
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"mime/multipart"
+	"net/http"
+	"net/url"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/brain-flowing-company/pprp-backend/internal/utils"
 	"github.com/brain-flowing-company/pprp-backend/storage"
@@ -23,11 +29,30 @@ type Service interface {
 	GetPropertyByOwnerId(*models.MyPropertiesResponses, string, *utils.PaginatedQuery, *utils.SortedQuery) *apperror.AppError
 	CreateProperty(*models.PropertyInfos, []*multipart.FileHeader) *apperror.AppError
 	UpdatePropertyById(*models.PropertyInfos, string, []*multipart.FileHeader) *apperror.AppError
-	DeletePropertyById(string) *apperror.AppError
+	DeletePropertyById(string, uuid.UUID) *apperror.AppError
 	AddFavoriteProperty(string, uuid.UUID) *apperror.AppError
 	RemoveFavoriteProperty(string, uuid.UUID) *apperror.AppError
 	GetFavoritePropertiesByUserId(*models.MyFavoritePropertiesResponses, string, *utils.PaginatedQuery, *utils.SortedQuery) *apperror.AppError
 	GetTop10Properties(*[]models.Properties, string) *apperror.AppError
+	GetPropertyHistories(*models.PropertyHistoriesResponses, string, *models.Sessions, *utils.PaginatedQuery) *apperror.AppError
+	RevertPropertyById(string, int64, *models.Sessions) *apperror.AppError
+	GetPropertyAttachments(*[]models.PropertyAttachments, string) *apperror.AppError
+	CreatePropertyAttachment(*models.PropertyAttachments, string, *models.CreatingPropertyAttachments, *multipart.FileHeader, *models.Sessions) *apperror.AppError
+	DeletePropertyAttachmentById(string, string, *models.Sessions) *apperror.AppError
+	ReorderPropertyAttachments(*[]models.PropertyAttachments, string, *models.ReorderingPropertyAttachments, *models.Sessions) *apperror.AppError
+}
+
+// Uploaded attachments are sniffed rather than trusted by their extension.
+// Link attachments (video and tour urls) carry no file at all.
+var attachmentContentTypes = map[enums.PropertyAttachmentTypes][]string{
+	enums.DocumentAttachment:  {"application/pdf"},
+	enums.FloorPlanAttachment: {"application/pdf", "image/png", "image/jpeg"},
+}
+
+var attachmentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/png":       ".png",
+	"image/jpeg":      ".jpeg",
 }
 
 type serviceImpl struct {
@@ -127,7 +152,12 @@ func (s *serviceImpl) CreateProperty(property *models.PropertyInfos, propertyIma
 
 	property.ImageUrls = propertyImageUrls
 
-	err := s.repo.CreateProperty(property)
+	history := models.PropertyHistories{
+		ActorUserId: &property.OwnerId,
+		Action:      enums.CreatePropertyHistory,
+	}
+
+	err := s.repo.CreateProperty(property, &history)
 	if err != nil {
 		s.logger.Error("Could not create property", zap.Error(err))
 		return apperror.
@@ -154,7 +184,12 @@ func (s *serviceImpl) UpdatePropertyById(property *models.PropertyInfos, propert
 		property.ImageUrls = append(property.ImageUrls, newPropertyImageUrls...)
 	}
 
-	err := s.repo.UpdatePropertyById(property, propertyId)
+	history := models.PropertyHistories{
+		ActorUserId: &property.OwnerId,
+		Action:      enums.UpdatePropertyHistory,
+	}
+
+	err := s.repo.UpdatePropertyById(property, propertyId, &history)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return apperror.
 			New(apperror.PropertyNotFound).
@@ -169,14 +204,19 @@ func (s *serviceImpl) UpdatePropertyById(property *models.PropertyInfos, propert
 	return nil
 }
 
-func (s *serviceImpl) DeletePropertyById(propertyId string) *apperror.AppError {
+func (s *serviceImpl) DeletePropertyById(propertyId string, userId uuid.UUID) *apperror.AppError {
 	if !utils.IsValidUUID(propertyId) {
 		return apperror.
 			New(apperror.InvalidPropertyId).
 			Describe("Invalid property id")
 	}
 
-	err := s.repo.DeletePropertyById(propertyId)
+	history := models.PropertyHistories{
+		ActorUserId: &userId,
+		Action:      enums.DeletePropertyHistory,
+	}
+
+	err := s.repo.DeletePropertyById(propertyId, &history)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return apperror.
 			New(apperror.PropertyNotFound).
@@ -281,6 +321,229 @@ func (s *serviceImpl) GetTop10Properties(properties *[]models.Properties, userId
 	return nil
 }
 
+func (s *serviceImpl) GetPropertyHistories(histories *models.PropertyHistoriesResponses, propertyId string, session *models.Sessions, paginated *utils.PaginatedQuery) *apperror.AppError {
+	if apperr := s.checkPropertyAccess(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	err := s.repo.GetPropertyHistories(histories, propertyId, paginated)
+	if err != nil {
+		s.logger.Error("Could not get property histories", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get property histories. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) RevertPropertyById(propertyId string, version int64, session *models.Sessions) *apperror.AppError {
+	if version <= 0 {
+		return apperror.
+			New(apperror.InvalidPropertyHistoryVersion).
+			Describe("Invalid property history version")
+	}
+
+	if apperr := s.checkPropertyAccess(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	var target models.PropertyHistories
+	err := s.repo.GetPropertyHistoryByVersion(&target, propertyId, version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyHistoryNotFound).
+			Describe("Could not find the specified property version")
+	} else if err != nil {
+		s.logger.Error("Could not get property history", zap.String("id", propertyId), zap.Int64("version", version), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not revert property. Please try again later.")
+	}
+
+	property := models.PropertyInfos(target.Snapshot)
+
+	history := models.PropertyHistories{
+		ActorUserId:         &session.UserId,
+		Action:              enums.RevertPropertyHistory,
+		RevertedFromVersion: &version,
+	}
+
+	err = s.repo.UpdatePropertyById(&property, propertyId, &history)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyNotFound).
+			Describe("Could not find the specified property")
+	} else if err != nil {
+		s.logger.Error("Could not revert property", zap.String("id", propertyId), zap.Int64("version", version), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not revert property. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) GetPropertyAttachments(attachments *[]models.PropertyAttachments, propertyId string) *apperror.AppError {
+	if !utils.IsValidUUID(propertyId) {
+		return apperror.
+			New(apperror.InvalidPropertyId).
+			Describe("Invalid property id")
+	}
+
+	var property models.PropertyInfos
+	err := s.repo.GetPropertyInfosById(&property, propertyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyNotFound).
+			Describe("Could not find the specified property")
+	} else if err != nil {
+		s.logger.Error("Could not get property by id", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get property attachments. Please try again later.")
+	}
+
+	err = s.repo.GetPropertyAttachments(attachments, propertyId)
+	if err != nil {
+		s.logger.Error("Could not get property attachments", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get property attachments. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) CreatePropertyAttachment(attachment *models.PropertyAttachments, propertyId string, creating *models.CreatingPropertyAttachments, file *multipart.FileHeader, session *models.Sessions) *apperror.AppError {
+	if apperr := s.checkPropertyAccess(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	if _, ok := enums.PropertyAttachmentTypesMap[string(creating.AttachmentType)]; !ok {
+		return apperror.
+			New(apperror.InvalidPropertyAttachmentType).
+			Describe("Attachment type must be one of DOCUMENT, FLOOR_PLAN, VIDEO_URL or TOUR_URL")
+	}
+
+	title := strings.TrimSpace(creating.Title)
+	if title == "" || len(title) > 200 {
+		return apperror.
+			New(apperror.BadRequest).
+			Describe("Attachment title must be between 1 and 200 characters")
+	}
+
+	*attachment = models.PropertyAttachments{
+		AttachmentId:   uuid.New(),
+		PropertyId:     uuid.MustParse(propertyId),
+		AttachmentType: creating.AttachmentType,
+		Title:          title,
+	}
+
+	if _, isFile := attachmentContentTypes[creating.AttachmentType]; isFile {
+		url, contentType, apperr := s.uploadPropertyAttachment(attachment, file)
+		if apperr != nil {
+			return apperr
+		}
+
+		attachment.Url = url
+		attachment.ContentType = contentType
+	} else {
+		if !isValidAttachmentUrl(creating.Url) {
+			return apperror.
+				New(apperror.InvalidPropertyAttachmentUrl).
+				Describe("Attachment url must be an absolute http or https url")
+		}
+
+		attachment.Url = creating.Url
+	}
+
+	err := s.repo.CreatePropertyAttachment(attachment)
+	if err != nil {
+		s.logger.Error("Could not create property attachment", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not create property attachment. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) DeletePropertyAttachmentById(propertyId string, attachmentId string, session *models.Sessions) *apperror.AppError {
+	if apperr := s.checkPropertyAccess(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	if !utils.IsValidUUID(attachmentId) {
+		return apperror.
+			New(apperror.InvalidPropertyAttachmentId).
+			Describe("Invalid attachment id")
+	}
+
+	err := s.repo.DeletePropertyAttachmentById(propertyId, attachmentId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyAttachmentNotFound).
+			Describe("Could not find the specified attachment")
+	} else if err != nil {
+		s.logger.Error("Could not delete property attachment", zap.String("id", attachmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not delete property attachment. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) ReorderPropertyAttachments(attachments *[]models.PropertyAttachments, propertyId string, reordering *models.ReorderingPropertyAttachments, session *models.Sessions) *apperror.AppError {
+	if apperr := s.checkPropertyAccess(propertyId, session); apperr != nil {
+		return apperr
+	}
+
+	err := s.repo.ReorderPropertyAttachments(attachments, propertyId, reordering.AttachmentIds)
+	if errors.Is(err, errInvalidAttachmentOrder) {
+		return apperror.
+			New(apperror.InvalidPropertyAttachmentOrder).
+			Describe("Attachment ids must list every attachment of the property exactly once")
+	} else if err != nil {
+		s.logger.Error("Could not reorder property attachments", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not reorder property attachments. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) checkPropertyAccess(propertyId string, session *models.Sessions) *apperror.AppError {
+	if !utils.IsValidUUID(propertyId) {
+		return apperror.
+			New(apperror.InvalidPropertyId).
+			Describe("Invalid property id")
+	}
+
+	var property models.PropertyInfos
+	err := s.repo.GetPropertyInfosById(&property, propertyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyNotFound).
+			Describe("Could not find the specified property")
+	} else if err != nil {
+		s.logger.Error("Could not get property by id", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get property. Please try again later.")
+	}
+
+	if property.OwnerId != session.UserId && !session.IsAdmin {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the property owner can access this resource")
+	}
+
+	return nil
+}
+
 func (s *serviceImpl) uploadPropertyImages(propertyId uuid.UUID, propertyImages []*multipart.FileHeader) ([]string, *apperror.AppError) {
 	var urls []string
 
@@ -354,3 +617,60 @@ func (s *serviceImpl) uploadPropertyImages(propertyId uuid.UUID, propertyImages
 
 	return urls, nil
 }
+
+func (s *serviceImpl) uploadPropertyAttachment(attachment *models.PropertyAttachments, fileHeader *multipart.FileHeader) (string, string, *apperror.AppError) {
+	if fileHeader == nil {
+		return "", "", apperror.
+			New(apperror.BadRequest).
+			Describe("No attachment file found")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not upload property attachment")
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not read property attachment")
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if !slices.Contains(attachmentContentTypes[attachment.AttachmentType], contentType) {
+		return "", "", apperror.
+			New(apperror.InvalidPropertyAttachmentContentType).
+			Describe(fmt.Sprintf("App does not support %v for %v attachments", contentType, attachment.AttachmentType))
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not read property attachment")
+	}
+
+	filename := fmt.Sprintf("properties/attachments/%v/%v%v", attachment.PropertyId.String(), attachment.AttachmentId.String(), attachmentExtensions[contentType])
+	url, err := s.storage.Upload(filename, file, types.ObjectCannedACLPublicRead)
+	if err != nil {
+		s.logger.Error("Could not upload property attachment", zap.Error(err))
+		return "", "", apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not upload property attachment")
+	}
+
+	return url, contentType, nil
+}
+
+func isValidAttachmentUrl(rawUrl string) bool {
+	u, err := url.ParseRequestURI(rawUrl)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && len(rawUrl) <= 2000
+}
@@ -0,0 +1,98 @@
+package statements
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler interface {
+	GetMonthlyStatement(c *fiber.Ctx) error
+	GetAnnualStatement(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handlerImpl{
+		service,
+	}
+}
+
+// @router      /api/v1/user/me/statements [get]
+// @summary     Get my monthly statement *use cookies*
+// @description Get what the current user received as an owner in a month, per property: rent collected, late fees, utilities, deposits received and refunded, and the deposits held at the end of the month. A refunded payment is taken off in the month it was refunded. Months are in Thai time and default to the current one
+// @tags        statements
+// @produce     json
+// @param       year  query int false "Year, defaults to the current year"
+// @param       month query int false "Month from 1 to 12, defaults to the current month"
+// @success     200	{object} models.MonthlyStatements
+// @failure     400 {object} models.ErrorResponses "Invalid year or month"
+// @failure     500 {object} models.ErrorResponses "Could not get statement"
+func (h *handlerImpl) GetMonthlyStatement(c *fiber.Ctx) error {
+	session := c.Locals("session").(models.Sessions)
+	now := time.Now().In(utils.LocalTimezone)
+
+	statement := models.MonthlyStatements{}
+	apperr := h.service.GetMonthlyStatement(&statement, c.QueryInt("year", now.Year()), c.QueryInt("month", int(now.Month())), &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(statement)
+}
+
+// @router      /api/v1/user/me/statements/annual [get]
+// @summary     Get my annual statement *use cookies*
+// @description Get a year of what the current user received as an owner, month by month and property by property, to declare rental income. Set `format` to CSV or PDF to download it as a file instead of JSON
+// @tags        statements
+// @produce     json,text/csv,application/pdf
+// @param       year   query int    false "Year, defaults to the current year"
+// @param       format query string false "JSON, CSV or PDF, defaults to JSON"
+// @success     200	{object} models.AnnualStatements
+// @failure     400 {object} models.ErrorResponses "Invalid year or format"
+// @failure     500 {object} models.ErrorResponses "Could not get statement"
+func (h *handlerImpl) GetAnnualStatement(c *fiber.Ctx) error {
+	session := c.Locals("session").(models.Sessions)
+	year := c.QueryInt("year", time.Now().In(utils.LocalTimezone).Year())
+
+	format, ok := enums.StatementFormatsMap[strings.ToUpper(c.Query("format", string(enums.JSONStatement)))]
+	if !ok {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidStatementFormat).
+			Describe("Format must be JSON, CSV or PDF"))
+	}
+
+	if format == enums.JSONStatement {
+		statement := models.AnnualStatements{}
+		apperr := h.service.GetAnnualStatement(&statement, year, &session)
+		if apperr != nil {
+			return utils.ResponseError(c, apperr)
+		}
+
+		return c.JSON(statement)
+	}
+
+	var document bytes.Buffer
+	apperr := h.service.ExportAnnualStatement(&document, year, format, &session)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	c.Attachment(fmt.Sprintf("statement-%v.%v", year, strings.ToLower(string(format))))
+	if format == enums.CSVStatement {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	} else {
+		c.Set(fiber.HeaderContentType, "application/pdf")
+	}
+	return c.Send(document.Bytes())
+}
@@ -0,0 +1,88 @@
+package statements
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetStatementPayments(*[]models.StatementPayments, uuid.UUID, time.Time, time.Time) error
+	GetHeldDeposits(*[]models.StatementHeldDeposits, uuid.UUID, time.Time) error
+}
+
+type repositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{
+		db,
+	}
+}
+
+// Agreements and properties are read from the underlying tables so that money
+// received on something deleted since still shows up in the statements. Rent is
+// split as it was when it was paid, so issued statements do not change. For the
+// same reason a refunded payment stays in the period it was paid in, and its
+// refund is taken off in the period it was refunded in.
+
+const statementPaymentsQuery = `
+	SELECT p.payment_id, p.payment_type, p.price, p.paid_at, p.refunded_at, FALSE AS refunded,
+		a.agreement_id, a.property_id, pr.property_name, p.rent_amount, p.late_fee_amount, p.utility_amount
+	FROM payments p
+	JOIN _agreements a ON a.agreement_id = p.agreement_id
+	JOIN _properties pr ON pr.property_id = a.property_id
+	WHERE a.owner_user_id = @owner_user_id AND a.agreement_type = @agreement_type
+		AND (p.issuccess OR p.refunded_at IS NOT NULL) AND p.deleted_at IS NULL AND p.payment_type IN @payment_types
+		AND p.paid_at >= @from AND p.paid_at < @to
+	UNION ALL
+	SELECT p.payment_id, p.payment_type, p.price, p.paid_at, p.refunded_at, TRUE AS refunded,
+		a.agreement_id, a.property_id, pr.property_name, p.rent_amount, p.late_fee_amount, p.utility_amount
+	FROM payments p
+	JOIN _agreements a ON a.agreement_id = p.agreement_id
+	JOIN _properties pr ON pr.property_id = a.property_id
+	WHERE a.owner_user_id = @owner_user_id AND a.agreement_type = @agreement_type
+		AND p.deleted_at IS NULL AND p.payment_type IN @payment_types
+		AND p.refunded_at >= @from AND p.refunded_at < @to
+	ORDER BY paid_at ASC`
+
+// heldDepositsQuery nets the deposits an owner had received against the ones
+// given back, by refunding the deposit or the deposit payment itself, as of a
+// point in time.
+const heldDepositsQuery = `
+	SELECT a.property_id, pr.property_name,
+		SUM(CASE WHEN p.payment_type = @deposit THEN p.price ELSE -p.price END) AS amount
+	FROM payments p
+	JOIN _agreements a ON a.agreement_id = p.agreement_id
+	JOIN _properties pr ON pr.property_id = a.property_id
+	WHERE a.owner_user_id = @owner_user_id AND a.agreement_type = @agreement_type
+		AND (p.issuccess OR p.refunded_at IS NOT NULL) AND p.deleted_at IS NULL
+		AND p.payment_type IN (@deposit, @deposit_refund)
+		AND p.paid_at < @at AND (p.refunded_at IS NULL OR p.refunded_at >= @at)
+	GROUP BY a.property_id, pr.property_name
+	HAVING SUM(CASE WHEN p.payment_type = @deposit THEN p.price ELSE -p.price END) <> 0`
+
+func (repo *repositoryImpl) GetStatementPayments(payments *[]models.StatementPayments, ownerUserId uuid.UUID, from time.Time, to time.Time) error {
+	return repo.db.Raw(statementPaymentsQuery,
+		sql.Named("owner_user_id", ownerUserId),
+		sql.Named("agreement_type", enums.AgreementForRent),
+		sql.Named("payment_types", []enums.PaymentTypes{enums.DepositPayment, enums.RentPayment, enums.DepositRefundPayment}),
+		sql.Named("from", from),
+		sql.Named("to", to)).
+		Scan(payments).Error
+}
+
+func (repo *repositoryImpl) GetHeldDeposits(deposits *[]models.StatementHeldDeposits, ownerUserId uuid.UUID, at time.Time) error {
+	return repo.db.Raw(heldDepositsQuery,
+		sql.Named("owner_user_id", ownerUserId),
+		sql.Named("agreement_type", enums.AgreementForRent),
+		sql.Named("deposit", enums.DepositPayment),
+		sql.Named("deposit_refund", enums.DepositRefundPayment),
+		sql.Named("at", at)).
+		Scan(deposits).Error
+}
@@ -0,0 +1,412 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	GetMonthlyStatement(*models.MonthlyStatements, int, int, *models.Sessions) *apperror.AppError
+	GetAnnualStatement(*models.AnnualStatements, int, *models.Sessions) *apperror.AppError
+	ExportAnnualStatement(*bytes.Buffer, int, enums.StatementFormats, *models.Sessions) *apperror.AppError
+}
+
+// firstStatementYear is the earliest year a statement can be asked for
+const firstStatementYear = 2000
+
+type serviceImpl struct {
+	logger *zap.Logger
+	cfg    *config.Config
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, cfg *config.Config, repo Repository) Service {
+	return &serviceImpl{
+		logger,
+		cfg,
+		repo,
+	}
+}
+
+// GetMonthlyStatement sums up, per property, what the owner received in a
+// calendar month of Thai time and the deposits they held at its end.
+func (s *serviceImpl) GetMonthlyStatement(statement *models.MonthlyStatements, year int, month int, session *models.Sessions) *apperror.AppError {
+	if apperr := validateStatementYear(year); apperr != nil {
+		return apperr
+	}
+
+	if month < 1 || month > 12 {
+		return apperror.
+			New(apperror.InvalidStatementPeriod).
+			Describe("Month must be between 1 and 12")
+	}
+
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, utils.LocalTimezone)
+	held, apperr := s.getHeldDeposits(session.UserId, from)
+	if apperr != nil {
+		return apperr
+	}
+
+	payments, apperr := s.getStatementPayments(session.UserId, from, from.AddDate(0, 1, 0))
+	if apperr != nil {
+		return apperr
+	}
+
+	*statement = monthlyStatement(year, month, held, payments)
+	return nil
+}
+
+// GetAnnualStatement sums up a year of payments month by month and property
+// by property, which is what an owner needs to declare their rental income.
+func (s *serviceImpl) GetAnnualStatement(statement *models.AnnualStatements, year int, session *models.Sessions) *apperror.AppError {
+	if apperr := validateStatementYear(year); apperr != nil {
+		return apperr
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, utils.LocalTimezone)
+	held, apperr := s.getHeldDeposits(session.UserId, from)
+	if apperr != nil {
+		return apperr
+	}
+
+	payments, apperr := s.getStatementPayments(session.UserId, from, from.AddDate(1, 0, 0))
+	if apperr != nil {
+		return apperr
+	}
+
+	byMonth := make([][]models.StatementPayments, 12)
+	for _, payment := range payments {
+		month := statementDate(&payment).In(utils.LocalTimezone).Month()
+		byMonth[month-1] = append(byMonth[month-1], payment)
+	}
+
+	*statement = models.AnnualStatements{
+		Year:   year,
+		Months: make([]models.MonthlyStatements, 0, 12),
+	}
+
+	// each month starts with the deposits held at the end of the one before
+	monthHeld := held
+	for month, monthPayments := range byMonth {
+		monthStatement := monthlyStatement(year, month+1, monthHeld, monthPayments)
+		statement.Months = append(statement.Months, monthStatement)
+		monthHeld = heldDeposits(monthStatement.Properties)
+	}
+
+	statement.Properties, statement.Total = propertyStatements(held, payments)
+	return nil
+}
+
+func (s *serviceImpl) ExportAnnualStatement(document *bytes.Buffer, year int, format enums.StatementFormats, session *models.Sessions) *apperror.AppError {
+	var statement models.AnnualStatements
+	if apperr := s.GetAnnualStatement(&statement, year, session); apperr != nil {
+		return apperr
+	}
+
+	switch format {
+	case enums.CSVStatement:
+		return s.renderStatementCSV(document, &statement)
+	case enums.PDFStatement:
+		return s.renderStatementPDF(document, &statement, session)
+	}
+
+	return apperror.
+		New(apperror.InvalidStatementFormat).
+		Describe(fmt.Sprintf("Statements cannot be exported as %v", format))
+}
+
+func (s *serviceImpl) getStatementPayments(ownerUserId uuid.UUID, from time.Time, to time.Time) ([]models.StatementPayments, *apperror.AppError) {
+	payments := []models.StatementPayments{}
+	err := s.repo.GetStatementPayments(&payments, ownerUserId, from, to)
+	if err != nil {
+		s.logger.Error("Could not get statement payments", zap.String("id", ownerUserId.String()), zap.Error(err))
+		return nil, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get statement")
+	}
+
+	return payments, nil
+}
+
+func (s *serviceImpl) getHeldDeposits(ownerUserId uuid.UUID, at time.Time) ([]models.StatementHeldDeposits, *apperror.AppError) {
+	deposits := []models.StatementHeldDeposits{}
+	err := s.repo.GetHeldDeposits(&deposits, ownerUserId, at)
+	if err != nil {
+		s.logger.Error("Could not get held deposits", zap.String("id", ownerUserId.String()), zap.Error(err))
+		return nil, apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get statement")
+	}
+
+	return deposits, nil
+}
+
+func (s *serviceImpl) renderStatementCSV(document *bytes.Buffer, statement *models.AnnualStatements) *apperror.AppError {
+	writer := csv.NewWriter(document)
+	writer.Write([]string{"Month", "Property", "Rent collected", "Late fees", "Utilities", "Rental income", "Deposits received", "Deposits refunded", "Deposits held"})
+
+	for _, month := range statement.Months {
+		period := fmt.Sprintf("%04d-%02d", month.Year, month.Month)
+		for _, property := range month.Properties {
+			writer.Write(statementRow(period, property.PropertyName, &property.StatementAmounts))
+		}
+	}
+
+	period := fmt.Sprintf("%04d", statement.Year)
+	for _, property := range statement.Properties {
+		writer.Write(statementRow(period, property.PropertyName, &property.StatementAmounts))
+	}
+	writer.Write(statementRow(period, "All properties", &statement.Total))
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		s.logger.Error("Could not write statement CSV", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not export statement")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) renderStatementPDF(document *bytes.Buffer, statement *models.AnnualStatements, session *models.Sessions) *apperror.AppError {
+	// Property names are often in Thai, which Helvetica has no glyphs for
+	var font []byte
+	if s.cfg.ContractFontPath != "" {
+		file, err := os.ReadFile(s.cfg.ContractFontPath)
+		if err != nil {
+			s.logger.Error("Could not read contract font", zap.String("path", s.cfg.ContractFontPath), zap.Error(err))
+			return apperror.
+				New(apperror.InternalServerError).
+				Describe("Could not export statement")
+		}
+		font = file
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "# Rental income statement %v\n\n", statement.Year)
+	fmt.Fprintf(&text, "Prepared for %v on %v. Amounts are in baht and cover the payments received from 1 January to 31 December %v, Thai time.\n",
+		session.Email, time.Now().In(utils.LocalTimezone).Format("2 January 2006"), statement.Year)
+
+	text.WriteString("\n## Summary\n")
+	writeStatementAmounts(&text, &statement.Total)
+
+	text.WriteString("\n## By property\n")
+	if len(statement.Properties) == 0 {
+		text.WriteString("No payments were received and no deposits were held this year.\n")
+	}
+	for _, property := range statement.Properties {
+		fmt.Fprintf(&text, "\n%v\n", property.PropertyName)
+		writeStatementAmounts(&text, &property.StatementAmounts)
+	}
+
+	text.WriteString("\n## By month\n")
+	for _, month := range statement.Months {
+		fmt.Fprintf(&text, "%v: rental income %v, deposits received %v, deposits refunded %v, deposits held %v\n",
+			time.Month(month.Month), utils.FormatAmount(month.Total.RentalIncome),
+			utils.FormatAmount(month.Total.DepositsReceived), utils.FormatAmount(month.Total.DepositsRefunded),
+			utils.FormatAmount(month.Total.DepositsHeld))
+	}
+
+	text.WriteString("\nRental income is the rent, late fees and utilities collected. A payment refunded to the dweller is taken off in the month it was refunded. Deposits are held on behalf of the dweller, so they are listed for reference and not counted as income. Deposits held is the balance at the end of the period.\n")
+
+	doc, err := utils.NewPDFDocument(font)
+	if err != nil {
+		s.logger.Error("Could not load contract font", zap.String("path", s.cfg.ContractFontPath), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not export statement")
+	}
+
+	doc.WriteText(text.String())
+	pdf, err := doc.Bytes()
+	if err != nil {
+		s.logger.Error("Could not write statement document", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not export statement")
+	}
+
+	document.Write(pdf)
+	return nil
+}
+
+func validateStatementYear(year int) *apperror.AppError {
+	if year < firstStatementYear || year > time.Now().In(utils.LocalTimezone).Year()+1 {
+		return apperror.
+			New(apperror.InvalidStatementPeriod).
+			Describe(fmt.Sprintf("Invalid year %v", year))
+	}
+
+	return nil
+}
+
+func monthlyStatement(year int, month int, held []models.StatementHeldDeposits, payments []models.StatementPayments) models.MonthlyStatements {
+	statement := models.MonthlyStatements{
+		Year:  year,
+		Month: month,
+	}
+
+	statement.Properties, statement.Total = propertyStatements(held, payments)
+	return statement
+}
+
+// propertyStatements sums payments per property, sorted by property name, and
+// returns the total of every property along with them. The deposits held at the
+// start of the period carry over, so a property holding a deposit is listed
+// even when nothing was paid for it.
+func propertyStatements(held []models.StatementHeldDeposits, payments []models.StatementPayments) ([]models.PropertyStatements, models.StatementAmounts) {
+	byProperty := map[uuid.UUID]*models.PropertyStatements{}
+	var total models.StatementAmounts
+
+	for _, deposit := range held {
+		byProperty[deposit.PropertyId] = &models.PropertyStatements{
+			PropertyId:       deposit.PropertyId,
+			PropertyName:     deposit.PropertyName,
+			StatementAmounts: models.StatementAmounts{DepositsHeld: deposit.Amount},
+		}
+		total.DepositsHeld += deposit.Amount
+	}
+
+	for i := range payments {
+		property, ok := byProperty[payments[i].PropertyId]
+		if !ok {
+			property = &models.PropertyStatements{
+				PropertyId:   payments[i].PropertyId,
+				PropertyName: payments[i].PropertyName,
+			}
+			byProperty[payments[i].PropertyId] = property
+		}
+
+		addStatementPayment(&property.StatementAmounts, &payments[i])
+		addStatementPayment(&total, &payments[i])
+	}
+
+	properties := make([]models.PropertyStatements, 0, len(byProperty))
+	for _, property := range byProperty {
+		roundStatementAmounts(&property.StatementAmounts)
+		properties = append(properties, *property)
+	}
+
+	sort.Slice(properties, func(i, j int) bool {
+		if properties[i].PropertyName != properties[j].PropertyName {
+			return properties[i].PropertyName < properties[j].PropertyName
+		}
+		return properties[i].PropertyId.String() < properties[j].PropertyId.String()
+	})
+
+	roundStatementAmounts(&total)
+	return properties, total
+}
+
+// addStatementPayment adds a payment to the amounts, or takes it off again when
+// it has been refunded. A rent payment settles an installment with its late fee
+// and utilities all at once, so it is added as it was split when it was paid.
+func addStatementPayment(amounts *models.StatementAmounts, payment *models.StatementPayments) {
+	sign := 1.0
+	if payment.Refunded {
+		sign = -1
+	}
+
+	switch payment.PaymentType {
+	case enums.DepositPayment:
+		amounts.DepositsReceived += sign * payment.Price
+		amounts.DepositsHeld += sign * payment.Price
+	case enums.DepositRefundPayment:
+		amounts.DepositsRefunded += sign * payment.Price
+		amounts.DepositsHeld -= sign * payment.Price
+	case enums.RentPayment:
+		if payment.RentAmount == nil {
+			amounts.RentCollected += sign * payment.Price
+			break
+		}
+
+		amounts.RentCollected += sign * *payment.RentAmount
+		amounts.LateFees += sign * valueOrZero(payment.LateFeeAmount)
+		amounts.Utilities += sign * valueOrZero(payment.UtilityAmount)
+	}
+}
+
+// statementDate is when a payment counts in a statement: when it was paid, or
+// when it was refunded for the refund of it.
+func statementDate(payment *models.StatementPayments) time.Time {
+	if payment.Refunded && payment.RefundedAt != nil {
+		return *payment.RefundedAt
+	}
+	return payment.PaidAt
+}
+
+// heldDeposits reads back the deposits held at the end of a period from its
+// property statements.
+func heldDeposits(properties []models.PropertyStatements) []models.StatementHeldDeposits {
+	held := []models.StatementHeldDeposits{}
+	for _, property := range properties {
+		if property.DepositsHeld != 0 {
+			held = append(held, models.StatementHeldDeposits{
+				PropertyId:   property.PropertyId,
+				PropertyName: property.PropertyName,
+				Amount:       property.DepositsHeld,
+			})
+		}
+	}
+	return held
+}
+
+func roundStatementAmounts(amounts *models.StatementAmounts) {
+	amounts.RentCollected = roundAmount(amounts.RentCollected)
+	amounts.LateFees = roundAmount(amounts.LateFees)
+	amounts.Utilities = roundAmount(amounts.Utilities)
+	amounts.RentalIncome = roundAmount(amounts.RentCollected + amounts.LateFees + amounts.Utilities)
+	amounts.DepositsReceived = roundAmount(amounts.DepositsReceived)
+	amounts.DepositsRefunded = roundAmount(amounts.DepositsRefunded)
+	amounts.DepositsHeld = roundAmount(amounts.DepositsHeld)
+}
+
+func statementRow(period string, property string, amounts *models.StatementAmounts) []string {
+	return []string{
+		period,
+		property,
+		fmt.Sprintf("%.2f", amounts.RentCollected),
+		fmt.Sprintf("%.2f", amounts.LateFees),
+		fmt.Sprintf("%.2f", amounts.Utilities),
+		fmt.Sprintf("%.2f", amounts.RentalIncome),
+		fmt.Sprintf("%.2f", amounts.DepositsReceived),
+		fmt.Sprintf("%.2f", amounts.DepositsRefunded),
+		fmt.Sprintf("%.2f", amounts.DepositsHeld),
+	}
+}
+
+func writeStatementAmounts(text *strings.Builder, amounts *models.StatementAmounts) {
+	fmt.Fprintf(text, "Rent collected: %v\n", utils.FormatAmount(amounts.RentCollected))
+	fmt.Fprintf(text, "Late fees: %v\n", utils.FormatAmount(amounts.LateFees))
+	fmt.Fprintf(text, "Utilities: %v\n", utils.FormatAmount(amounts.Utilities))
+	fmt.Fprintf(text, "Rental income: %v\n", utils.FormatAmount(amounts.RentalIncome))
+	fmt.Fprintf(text, "Deposits received: %v\n", utils.FormatAmount(amounts.DepositsReceived))
+	fmt.Fprintf(text, "Deposits refunded: %v\n", utils.FormatAmount(amounts.DepositsRefunded))
+	fmt.Fprintf(text, "Deposits held: %v\n", utils.FormatAmount(amounts.DepositsHeld))
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func valueOrZero(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,224 @@
+package statements
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// fakeRepository returns the payments paid or refunded within the asked period,
+// and no deposits held before it.
+type fakeRepository struct {
+	Repository
+	payments []models.StatementPayments
+}
+
+func (repo *fakeRepository) GetStatementPayments(payments *[]models.StatementPayments, ownerUserId uuid.UUID, from time.Time, to time.Time) error {
+	for _, payment := range repo.payments {
+		if !payment.PaidAt.Before(from) && payment.PaidAt.Before(to) {
+			*payments = append(*payments, payment)
+		}
+		if payment.RefundedAt != nil && !payment.RefundedAt.Before(from) && payment.RefundedAt.Before(to) {
+			payment.Refunded = true
+			*payments = append(*payments, payment)
+		}
+	}
+	return nil
+}
+
+func (repo *fakeRepository) GetHeldDeposits(deposits *[]models.StatementHeldDeposits, ownerUserId uuid.UUID, at time.Time) error {
+	return nil
+}
+
+func amount(value float64) *float64 {
+	return &value
+}
+
+func TestPropertyStatements(t *testing.T) {
+	condo := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	house := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	sameName := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+
+	tests := []struct {
+		name           string
+		held           []models.StatementHeldDeposits
+		payments       []models.StatementPayments
+		wantProperties []models.PropertyStatements
+		wantTotal      models.StatementAmounts
+	}{
+		{
+			name:           "no payments",
+			payments:       nil,
+			wantProperties: []models.PropertyStatements{},
+		},
+		{
+			name: "rent is added as it was split when paid",
+			payments: []models.StatementPayments{
+				{PropertyId: condo, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 10500, RentAmount: amount(10000), LateFeeAmount: amount(500), UtilityAmount: amount(0)},
+				{PropertyId: condo, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 11200, RentAmount: amount(10000), LateFeeAmount: amount(0), UtilityAmount: amount(1200)},
+			},
+			wantProperties: []models.PropertyStatements{
+				{PropertyId: condo, PropertyName: "Condo", StatementAmounts: models.StatementAmounts{RentCollected: 20000, LateFees: 500, Utilities: 1200, RentalIncome: 21700}},
+			},
+			wantTotal: models.StatementAmounts{RentCollected: 20000, LateFees: 500, Utilities: 1200, RentalIncome: 21700},
+		},
+		{
+			name: "rent without a split counts as rent",
+			payments: []models.StatementPayments{
+				{PropertyId: condo, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 8000},
+			},
+			wantProperties: []models.PropertyStatements{
+				{PropertyId: condo, PropertyName: "Condo", StatementAmounts: models.StatementAmounts{RentCollected: 8000, RentalIncome: 8000}},
+			},
+			wantTotal: models.StatementAmounts{RentCollected: 8000, RentalIncome: 8000},
+		},
+		{
+			name: "deposits are not income",
+			payments: []models.StatementPayments{
+				{PropertyId: house, PropertyName: "House", PaymentType: enums.DepositPayment, Price: 30000},
+				{PropertyId: house, PropertyName: "House", PaymentType: enums.DepositRefundPayment, Price: 25500},
+			},
+			wantProperties: []models.PropertyStatements{
+				{PropertyId: house, PropertyName: "House", StatementAmounts: models.StatementAmounts{DepositsReceived: 30000, DepositsRefunded: 25500, DepositsHeld: 4500}},
+			},
+			wantTotal: models.StatementAmounts{DepositsReceived: 30000, DepositsRefunded: 25500, DepositsHeld: 4500},
+		},
+		{
+			name: "deposits held carry over without payments",
+			held: []models.StatementHeldDeposits{{PropertyId: house, PropertyName: "House", Amount: 30000}},
+			wantProperties: []models.PropertyStatements{
+				{PropertyId: house, PropertyName: "House", StatementAmounts: models.StatementAmounts{DepositsHeld: 30000}},
+			},
+			wantTotal: models.StatementAmounts{DepositsHeld: 30000},
+		},
+		{
+			name: "refunds are taken off",
+			held: []models.StatementHeldDeposits{{PropertyId: house, PropertyName: "House", Amount: 30000}},
+			payments: []models.StatementPayments{
+				{PropertyId: condo, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 10500, RentAmount: amount(10000), LateFeeAmount: amount(500), UtilityAmount: amount(0), Refunded: true},
+				{PropertyId: condo, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 8000},
+				{PropertyId: house, PropertyName: "House", PaymentType: enums.DepositPayment, Price: 30000, Refunded: true},
+			},
+			wantProperties: []models.PropertyStatements{
+				{PropertyId: condo, PropertyName: "Condo", StatementAmounts: models.StatementAmounts{RentCollected: -2000, LateFees: -500, RentalIncome: -2500}},
+				{PropertyId: house, PropertyName: "House", StatementAmounts: models.StatementAmounts{DepositsReceived: -30000}},
+			},
+			wantTotal: models.StatementAmounts{RentCollected: -2000, LateFees: -500, RentalIncome: -2500, DepositsReceived: -30000},
+		},
+		{
+			name: "properties are sorted by name then id and rounded to satang",
+			payments: []models.StatementPayments{
+				{PropertyId: house, PropertyName: "House", PaymentType: enums.RentPayment, Price: 0.1, RentAmount: amount(0.1)},
+				{PropertyId: house, PropertyName: "House", PaymentType: enums.RentPayment, Price: 0.2, RentAmount: amount(0.2)},
+				{PropertyId: sameName, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 100, RentAmount: amount(100)},
+				{PropertyId: condo, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 200, RentAmount: amount(200)},
+			},
+			wantProperties: []models.PropertyStatements{
+				{PropertyId: condo, PropertyName: "Condo", StatementAmounts: models.StatementAmounts{RentCollected: 200, RentalIncome: 200}},
+				{PropertyId: sameName, PropertyName: "Condo", StatementAmounts: models.StatementAmounts{RentCollected: 100, RentalIncome: 100}},
+				{PropertyId: house, PropertyName: "House", StatementAmounts: models.StatementAmounts{RentCollected: 0.3, RentalIncome: 0.3}},
+			},
+			wantTotal: models.StatementAmounts{RentCollected: 300.3, RentalIncome: 300.3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties, total := propertyStatements(tt.held, tt.payments)
+			if !reflect.DeepEqual(properties, tt.wantProperties) {
+				t.Errorf("propertyStatements() properties = %+v, want %+v", properties, tt.wantProperties)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("propertyStatements() total = %+v, want %+v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestAnnualStatementMonths(t *testing.T) {
+	property := uuid.New()
+	payment := func(paidAt time.Time) models.StatementPayments {
+		return models.StatementPayments{PropertyId: property, PropertyName: "Condo", PaymentType: enums.RentPayment, Price: 1000, PaidAt: paidAt}
+	}
+
+	tests := []struct {
+		name      string
+		paidAt    time.Time
+		wantMonth int
+	}{
+		{name: "paid mid month", paidAt: time.Date(2024, time.March, 15, 12, 0, 0, 0, utils.LocalTimezone), wantMonth: 3},
+		{name: "paid at midnight Thai time", paidAt: time.Date(2024, time.April, 1, 0, 0, 0, 0, utils.LocalTimezone), wantMonth: 4},
+		{name: "paid before midnight Thai time", paidAt: time.Date(2024, time.March, 31, 23, 59, 59, 0, utils.LocalTimezone), wantMonth: 3},
+		{name: "paid on the last day of the month in UTC", paidAt: time.Date(2024, time.May, 31, 20, 0, 0, 0, time.UTC), wantMonth: 6},
+		{name: "paid on new year's eve in UTC", paidAt: time.Date(2023, time.December, 31, 18, 0, 0, 0, time.UTC), wantMonth: 1},
+		{name: "paid in another year", paidAt: time.Date(2023, time.December, 31, 16, 59, 59, 0, time.UTC), wantMonth: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &serviceImpl{
+				logger: zap.NewNop(),
+				repo:   &fakeRepository{payments: []models.StatementPayments{payment(tt.paidAt)}},
+			}
+
+			var statement models.AnnualStatements
+			if apperr := s.GetAnnualStatement(&statement, 2024, &models.Sessions{UserId: uuid.New()}); apperr != nil {
+				t.Fatalf("GetAnnualStatement() error = %v", apperr)
+			}
+
+			if len(statement.Months) != 12 {
+				t.Fatalf("GetAnnualStatement() months = %v, want 12", len(statement.Months))
+			}
+
+			for _, month := range statement.Months {
+				want := 0.0
+				if month.Month == tt.wantMonth {
+					want = 1000
+				}
+				if month.Total.RentCollected != want {
+					t.Errorf("month %v rent collected = %v, want %v", month.Month, month.Total.RentCollected, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAnnualStatementRefunds(t *testing.T) {
+	house := uuid.New()
+	paidAt := time.Date(2024, time.March, 10, 12, 0, 0, 0, utils.LocalTimezone)
+	refundedAt := time.Date(2024, time.May, 3, 12, 0, 0, 0, utils.LocalTimezone)
+
+	s := &serviceImpl{
+		logger: zap.NewNop(),
+		repo: &fakeRepository{payments: []models.StatementPayments{
+			{PropertyId: house, PropertyName: "House", PaymentType: enums.DepositPayment, Price: 30000, PaidAt: paidAt, RefundedAt: &refundedAt},
+			{PropertyId: house, PropertyName: "House", PaymentType: enums.RentPayment, Price: 8000, PaidAt: paidAt, RefundedAt: &refundedAt},
+		}},
+	}
+
+	var statement models.AnnualStatements
+	if apperr := s.GetAnnualStatement(&statement, 2024, &models.Sessions{UserId: uuid.New()}); apperr != nil {
+		t.Fatalf("GetAnnualStatement() error = %v", apperr)
+	}
+
+	want := map[int]models.StatementAmounts{
+		3: {RentCollected: 8000, RentalIncome: 8000, DepositsReceived: 30000, DepositsHeld: 30000},
+		4: {DepositsHeld: 30000},
+		5: {RentCollected: -8000, RentalIncome: -8000, DepositsReceived: -30000},
+	}
+	for _, month := range statement.Months {
+		if month.Total != want[month.Month] {
+			t.Errorf("month %v total = %+v, want %+v", month.Month, month.Total, want[month.Month])
+		}
+	}
+
+	if statement.Total != (models.StatementAmounts{}) {
+		t.Errorf("year total = %+v, want nothing once everything was refunded", statement.Total)
+	}
+}
@@ -0,0 +1,160 @@
+package trash
+
+import (
+	"net/http"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler interface {
+	GetMyTrash(c *fiber.Ctx) error
+	GetAllTrash(c *fiber.Ctx) error
+	RestorePropertyById(c *fiber.Ctx) error
+	RestoreAppointmentById(c *fiber.Ctx) error
+	RestoreAgreementById(c *fiber.Ctx) error
+	RestoreUserById(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	service Service
+}
+
+func NewHandler(service Service) Handler {
+	return &handlerImpl{
+		service,
+	}
+}
+
+// @router      /api/v1/user/me/trash [get]
+// @summary     Get my trash *use cookies*
+// @description Get the properties, appointments and agreements of the current user that have been deleted but not purged yet
+// @tags        trash
+// @produce     json
+// @success     200	{object} models.TrashResponses
+// @failure     500 {object} models.ErrorResponses
+func (h *handlerImpl) GetMyTrash(c *fiber.Ctx) error {
+	session := c.Locals("session").(models.Sessions)
+
+	trash := models.TrashResponses{}
+	err := h.service.GetMyTrash(&trash, session.UserId)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return c.JSON(trash)
+}
+
+// @router      /api/v1/trash [get]
+// @summary     Get all trash *use cookies*
+// @description Get every deleted user, property, appointment and agreement that has not been purged yet. Admin only
+// @tags        trash
+// @produce     json
+// @success     200	{object} models.TrashResponses
+// @failure     403 {object} models.ErrorResponses "Not an admin"
+// @failure     500 {object} models.ErrorResponses
+func (h *handlerImpl) GetAllTrash(c *fiber.Ctx) error {
+	trash := models.TrashResponses{}
+	err := h.service.GetAllTrash(&trash)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return c.JSON(trash)
+}
+
+// @router      /api/v1/trash/properties/:propertyId/restore [post]
+// @summary     Restore a deleted property *use cookies*
+// @description Restore a deleted property together with its images, attachments, prices and appointments that were deleted with it. Only the property owner or an admin can restore
+// @tags        trash
+// @produce     json
+// @param       propertyId path string true "Property id"
+// @success     200	{object} models.MessageResponses "Property restored"
+// @failure     400 {object} models.ErrorResponses "Invalid property id"
+// @failure     403 {object} models.ErrorResponses "Not the property owner"
+// @failure     404 {object} models.ErrorResponses "Property not found in trash"
+// @failure     409 {object} models.ErrorResponses "Property owner has been deleted"
+// @failure     500 {object} models.ErrorResponses "Could not restore property"
+func (h *handlerImpl) RestorePropertyById(c *fiber.Ctx) error {
+	propertyId := c.Params("propertyId")
+	session := c.Locals("session").(models.Sessions)
+
+	err := h.service.RestorePropertyById(propertyId, &session)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Property restored")
+}
+
+// @router      /api/v1/trash/appointments/:appointmentId/restore [post]
+// @summary     Restore a deleted appointment *use cookies*
+// @description Restore a deleted appointment. Only the owner, the dweller or an admin can restore
+// @tags        trash
+// @produce     json
+// @param       appointmentId path string true "Appointment id"
+// @success     200	{object} models.MessageResponses "Appointment restored"
+// @failure     400 {object} models.ErrorResponses "Invalid appointment id"
+// @failure     403 {object} models.ErrorResponses "Not in the appointment"
+// @failure     404 {object} models.ErrorResponses "Appointment not found in trash"
+// @failure     409 {object} models.ErrorResponses "Property or user has been deleted, or the slot has been booked since"
+// @failure     500 {object} models.ErrorResponses "Could not restore appointment"
+func (h *handlerImpl) RestoreAppointmentById(c *fiber.Ctx) error {
+	appointmentId := c.Params("appointmentId")
+	session := c.Locals("session").(models.Sessions)
+
+	err := h.service.RestoreAppointmentById(appointmentId, &session)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Appointment restored")
+}
+
+// @router      /api/v1/trash/agreements/:agreementId/restore [post]
+// @summary     Restore a deleted agreement *use cookies*
+// @description Restore a deleted agreement. Only the owner, the dweller or an admin can restore
+// @tags        trash
+// @produce     json
+// @param       agreementId path string true "Agreement id"
+// @success     200	{object} models.MessageResponses "Agreement restored"
+// @failure     400 {object} models.ErrorResponses "Invalid agreement id or duplicate agreement"
+// @failure     403 {object} models.ErrorResponses "Not in the agreement"
+// @failure     404 {object} models.ErrorResponses "Agreement not found in trash"
+// @failure     409 {object} models.ErrorResponses "Property or user has been deleted"
+// @failure     500 {object} models.ErrorResponses "Could not restore agreement"
+func (h *handlerImpl) RestoreAgreementById(c *fiber.Ctx) error {
+	agreementId := c.Params("agreementId")
+	session := c.Locals("session").(models.Sessions)
+
+	err := h.service.RestoreAgreementById(agreementId, &session)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "Agreement restored")
+}
+
+// @router      /api/v1/trash/users/:userId/restore [post]
+// @summary     Restore a deleted user *use cookies*
+// @description Restore a deleted user together with the properties, appointments and financial information deleted with it. Favorites and verifications are not restored. Admin only
+// @tags        trash
+// @produce     json
+// @param       userId path string true "User id"
+// @success     200	{object} models.MessageResponses "User restored"
+// @failure     400 {object} models.ErrorResponses "Invalid user id"
+// @failure     403 {object} models.ErrorResponses "Not an admin"
+// @failure     404 {object} models.ErrorResponses "User not found in trash"
+// @failure     409 {object} models.ErrorResponses "Email or phone number has been taken"
+// @failure     500 {object} models.ErrorResponses "Could not restore user"
+func (h *handlerImpl) RestoreUserById(c *fiber.Ctx) error {
+	userId := c.Params("userId")
+
+	err := h.service.RestoreUserById(userId)
+	if err != nil {
+		return utils.ResponseError(c, err)
+	}
+
+	return utils.ResponseMessage(c, http.StatusOK, "User restored")
+}
@@ -0,0 +1,293 @@
+package trash
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	errAppointmentOverlaps = errors.New("appointment overlaps another appointment")
+	errPropertyTaken       = errors.New("property has another ongoing agreement")
+)
+
+type Repository interface {
+	GetTrashedProperties(*[]models.TrashedProperties, string) error
+	GetTrashedAppointments(*[]models.TrashedAppointments, string) error
+	GetTrashedAgreements(*[]models.TrashedAgreements, string) error
+	GetTrashedUsers(*[]models.TrashedUsers) error
+	GetTrashedPropertyById(*models.TrashedProperties, string) error
+	GetTrashedAppointmentById(*models.TrashedAppointments, string) error
+	GetTrashedAgreementById(*models.TrashedAgreements, string) error
+	GetTrashedUserById(*models.TrashedUsers, string) error
+	RestorePropertyById(string) error
+	RestoreAppointmentById(string) error
+	RestoreAgreementById(string) error
+	RestoreUserById(string) error
+	PurgeDeletedBefore(time.Time) error
+}
+
+type repositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repositoryImpl{
+		db,
+	}
+}
+
+// Trashed rows are read straight from the underlying tables since the views
+// filter out everything that has been soft-deleted.
+
+const trashedPropertiesQuery = `
+	SELECT p.property_id, p.owner_id, p.property_name, p.property_type, p.deleted_at,
+		   (o.deleted_at IS NULL) AS restorable
+		FROM _properties p
+		JOIN _users o ON p.owner_id = o.user_id
+		WHERE p.deleted_at IS NOT NULL`
+
+const trashedAppointmentsQuery = `
+	SELECT a.appointment_id, a.property_id, p.property_name, a.owner_user_id, a.dweller_user_id,
+		   a.appointment_date, a.status, a.deleted_at,
+		   (p.deleted_at IS NULL AND o.deleted_at IS NULL AND d.deleted_at IS NULL) AS restorable
+		FROM _appointments a
+		JOIN _properties p ON a.property_id = p.property_id
+		JOIN _users o ON a.owner_user_id = o.user_id
+		JOIN _users d ON a.dweller_user_id = d.user_id
+		WHERE a.deleted_at IS NOT NULL`
+
+const trashedAgreementsQuery = `
+	SELECT a.agreement_id, a.agreement_type, a.property_id, p.property_name, a.owner_user_id,
+		   a.dweller_user_id, a.agreement_date, a.status, a.deleted_at,
+		   (p.deleted_at IS NULL AND o.deleted_at IS NULL AND d.deleted_at IS NULL) AS restorable
+		FROM _agreements a
+		JOIN _properties p ON a.property_id = p.property_id
+		JOIN _users o ON a.owner_user_id = o.user_id
+		JOIN _users d ON a.dweller_user_id = d.user_id
+		WHERE a.deleted_at IS NOT NULL`
+
+const trashedUsersQuery = `
+	SELECT u.user_id, u.email, u.first_name, u.last_name, u.deleted_at,
+		   NOT EXISTS (
+			   SELECT 1 FROM _users active
+			   WHERE active.deleted_at IS NULL
+			   AND (active.email = u.email OR active.phone_number = u.phone_number)
+		   ) AS restorable
+		FROM _users u
+		WHERE u.deleted_at IS NOT NULL`
+
+func (repo *repositoryImpl) GetTrashedProperties(properties *[]models.TrashedProperties, userId string) error {
+	query := trashedPropertiesQuery
+	if userId != "" {
+		query += ` AND p.owner_id = @user_id`
+	}
+
+	return repo.db.Raw(query+` ORDER BY p.deleted_at DESC`, sql.Named("user_id", userId)).
+		Scan(properties).Error
+}
+
+func (repo *repositoryImpl) GetTrashedAppointments(appointments *[]models.TrashedAppointments, userId string) error {
+	query := trashedAppointmentsQuery
+	if userId != "" {
+		query += ` AND (a.owner_user_id = @user_id OR a.dweller_user_id = @user_id)`
+	}
+
+	return repo.db.Raw(query+` ORDER BY a.deleted_at DESC`, sql.Named("user_id", userId)).
+		Scan(appointments).Error
+}
+
+func (repo *repositoryImpl) GetTrashedAgreements(agreements *[]models.TrashedAgreements, userId string) error {
+	query := trashedAgreementsQuery
+	if userId != "" {
+		query += ` AND (a.owner_user_id = @user_id OR a.dweller_user_id = @user_id)`
+	}
+
+	return repo.db.Raw(query+` ORDER BY a.deleted_at DESC`, sql.Named("user_id", userId)).
+		Scan(agreements).Error
+}
+
+func (repo *repositoryImpl) GetTrashedUsers(users *[]models.TrashedUsers) error {
+	return repo.db.Raw(trashedUsersQuery + ` ORDER BY u.deleted_at DESC`).
+		Scan(users).Error
+}
+
+func (repo *repositoryImpl) GetTrashedPropertyById(property *models.TrashedProperties, propertyId string) error {
+	result := repo.db.Raw(trashedPropertiesQuery+` AND p.property_id = @property_id`, sql.Named("property_id", propertyId)).
+		Scan(property)
+	return notFoundIfEmpty(result)
+}
+
+func (repo *repositoryImpl) GetTrashedAppointmentById(appointment *models.TrashedAppointments, appointmentId string) error {
+	result := repo.db.Raw(trashedAppointmentsQuery+` AND a.appointment_id = @appointment_id`, sql.Named("appointment_id", appointmentId)).
+		Scan(appointment)
+	return notFoundIfEmpty(result)
+}
+
+func (repo *repositoryImpl) GetTrashedAgreementById(agreement *models.TrashedAgreements, agreementId string) error {
+	result := repo.db.Raw(trashedAgreementsQuery+` AND a.agreement_id = @agreement_id`, sql.Named("agreement_id", agreementId)).
+		Scan(agreement)
+	return notFoundIfEmpty(result)
+}
+
+func (repo *repositoryImpl) GetTrashedUserById(user *models.TrashedUsers, userId string) error {
+	result := repo.db.Raw(trashedUsersQuery+` AND u.user_id = @user_id`, sql.Named("user_id", userId)).
+		Scan(user)
+	return notFoundIfEmpty(result)
+}
+
+// Restoring clears deleted_at and lets the restore rules bring back the rows
+// that were soft-deleted together with the parent.
+
+func (repo *repositoryImpl) RestorePropertyById(propertyId string) error {
+	return repo.db.Exec(`UPDATE _properties SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE property_id = ? AND deleted_at IS NOT NULL`, propertyId).Error
+}
+
+// RestoreAppointmentById restores an appointment, as long as it would not hold
+// a slot that has been booked since it was deleted. The property is locked the
+// same way bookings lock it, so a booking cannot slip in meanwhile.
+func (repo *repositoryImpl) RestoreAppointmentById(appointmentId string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var appointment models.Appointments
+		if err := tx.Raw(`SELECT * FROM _appointments WHERE appointment_id = ? AND deleted_at IS NOT NULL`, appointmentId).
+			Scan(&appointment).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`SELECT 1 FROM _properties WHERE property_id = ? FOR UPDATE`, appointment.PropertyId).Error; err != nil {
+			return err
+		}
+
+		var overlaps int64
+		if err := tx.Raw(`
+			SELECT COUNT(*)
+			FROM appointments
+			WHERE property_id = @property_id
+				AND @status IN ('PENDING', 'CONFIRMED')
+				AND status IN ('PENDING', 'CONFIRMED')
+				AND appointment_date < @end_time
+				AND appointment_date + make_interval(mins => duration_minutes) > @start_time
+			`, sql.Named("property_id", appointment.PropertyId),
+			sql.Named("status", appointment.Status),
+			sql.Named("start_time", appointment.AppointmentDate),
+			sql.Named("end_time", appointment.AppointmentDate.Add(time.Duration(appointment.DurationMinutes)*time.Minute))).
+			Scan(&overlaps).Error; err != nil {
+			return err
+		}
+
+		if overlaps > 0 {
+			return errAppointmentOverlaps
+		}
+
+		return tx.Exec(`UPDATE _appointments SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE appointment_id = ? AND deleted_at IS NOT NULL`, appointmentId).Error
+	})
+}
+
+// RestoreAgreementById restores an agreement, unless it is still ongoing and
+// the property has got another ongoing agreement since it was deleted. Restoring
+// a renting agreement marks the property as occupied again.
+func (repo *repositoryImpl) RestoreAgreementById(agreementId string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var agreement models.Agreements
+		if err := tx.Raw(`SELECT * FROM _agreements WHERE agreement_id = ? AND deleted_at IS NOT NULL`, agreementId).
+			Scan(&agreement).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`SELECT 1 FROM _properties WHERE property_id = ? FOR UPDATE`, agreement.PropertyId).Error; err != nil {
+			return err
+		}
+
+		var taken int64
+		if err := tx.Raw(`
+			SELECT COUNT(*)
+			FROM agreements
+			WHERE property_id = @property_id
+				AND @status IN ('AWAITING_DEPOSIT', 'AWAITING_PAYMENT', 'RENTING', 'OVERDUE')
+				AND status IN ('AWAITING_DEPOSIT', 'AWAITING_PAYMENT', 'RENTING', 'OVERDUE')
+			`, sql.Named("property_id", agreement.PropertyId),
+			sql.Named("status", agreement.Status)).
+			Scan(&taken).Error; err != nil {
+			return err
+		}
+
+		if taken > 0 {
+			return errPropertyTaken
+		}
+
+		if err := tx.Exec(`UPDATE _agreements SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE agreement_id = ? AND deleted_at IS NOT NULL`, agreementId).Error; err != nil {
+			return err
+		}
+
+		// a renting agreement occupies the property again once it is back
+		if agreement.AgreementType != enums.AgreementForRent ||
+			(agreement.Status != enums.RentingAgreement && agreement.Status != enums.OverdueAgreement) {
+			return nil
+		}
+
+		return tx.Exec(`UPDATE _renting_properties SET is_occupied = TRUE, updated_at = CURRENT_TIMESTAMP WHERE property_id = ?`, agreement.PropertyId).Error
+	})
+}
+
+func (repo *repositoryImpl) RestoreUserById(userId string) error {
+	return repo.db.Exec(`UPDATE _users SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND deleted_at IS NOT NULL`, userId).Error
+}
+
+// PurgeDeletedBefore hard-deletes every row that was soft-deleted before the
+// cutoff. Children go first so that a parent is only removed once nothing
+// references it anymore. Agreements with payments are kept for good, since
+// the payments would lose their agreement and drop out of owner statements.
+func (repo *repositoryImpl) PurgeDeletedBefore(cutoff time.Time) error {
+	queries := []string{
+		`DELETE FROM _appointments WHERE deleted_at < @cutoff`,
+		`DELETE FROM _agreements a
+			WHERE a.deleted_at < @cutoff
+			AND NOT EXISTS (SELECT 1 FROM payments WHERE agreement_id = a.agreement_id)`,
+		`DELETE FROM _property_images WHERE deleted_at < @cutoff`,
+		`DELETE FROM _property_attachments WHERE deleted_at < @cutoff`,
+		`DELETE FROM _selling_properties WHERE deleted_at < @cutoff`,
+		`DELETE FROM _renting_properties WHERE deleted_at < @cutoff`,
+		`DELETE FROM _properties p
+			WHERE p.deleted_at < @cutoff
+			AND NOT EXISTS (SELECT 1 FROM _appointments WHERE property_id = p.property_id)
+			AND NOT EXISTS (SELECT 1 FROM _agreements WHERE property_id = p.property_id)
+			AND NOT EXISTS (SELECT 1 FROM _property_images WHERE property_id = p.property_id)
+			AND NOT EXISTS (SELECT 1 FROM _property_attachments WHERE property_id = p.property_id)
+			AND NOT EXISTS (SELECT 1 FROM _selling_properties WHERE property_id = p.property_id)
+			AND NOT EXISTS (SELECT 1 FROM _renting_properties WHERE property_id = p.property_id)`,
+		`DELETE FROM _user_financial_informations WHERE deleted_at < @cutoff`,
+		`DELETE FROM _users u
+			WHERE u.deleted_at < @cutoff
+			AND NOT EXISTS (SELECT 1 FROM _properties WHERE owner_id = u.user_id)
+			AND NOT EXISTS (SELECT 1 FROM _appointments WHERE owner_user_id = u.user_id OR dweller_user_id = u.user_id)
+			AND NOT EXISTS (SELECT 1 FROM _agreements WHERE owner_user_id = u.user_id OR dweller_user_id = u.user_id)
+			AND NOT EXISTS (SELECT 1 FROM _user_financial_informations WHERE user_id = u.user_id)
+			AND NOT EXISTS (SELECT 1 FROM payments WHERE user_id = u.user_id)`,
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, query := range queries {
+			if err := tx.Exec(query, sql.Named("cutoff", cutoff)).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func notFoundIfEmpty(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+package trash
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/apperror"
+	"github.com/brain-flowing-company/pprp-backend/config"
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+	"github.com/brain-flowing-company/pprp-backend/internal/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	GetMyTrash(*models.TrashResponses, uuid.UUID) *apperror.AppError
+	GetAllTrash(*models.TrashResponses) *apperror.AppError
+	RestorePropertyById(string, *models.Sessions) *apperror.AppError
+	RestoreAppointmentById(string, *models.Sessions) *apperror.AppError
+	RestoreAgreementById(string, *models.Sessions) *apperror.AppError
+	RestoreUserById(string) *apperror.AppError
+	PurgeExpiredTrash()
+}
+
+type serviceImpl struct {
+	logger *zap.Logger
+	cfg    *config.Config
+	repo   Repository
+}
+
+func NewService(logger *zap.Logger, cfg *config.Config, repo Repository) Service {
+	return &serviceImpl{
+		logger,
+		cfg,
+		repo,
+	}
+}
+
+func (s *serviceImpl) GetMyTrash(trash *models.TrashResponses, userId uuid.UUID) *apperror.AppError {
+	return s.getTrash(trash, userId.String())
+}
+
+func (s *serviceImpl) GetAllTrash(trash *models.TrashResponses) *apperror.AppError {
+	apperr := s.getTrash(trash, "")
+	if apperr != nil {
+		return apperr
+	}
+
+	trash.Users = []models.TrashedUsers{}
+	err := s.repo.GetTrashedUsers(&trash.Users)
+	if err != nil {
+		s.logger.Error("Could not get trashed users", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get trash. Please try again later.")
+	}
+
+	for i := range trash.Users {
+		trash.Users[i].PurgeAt = s.purgeAt(trash.Users[i].DeletedAt)
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) RestorePropertyById(propertyId string, session *models.Sessions) *apperror.AppError {
+	if !utils.IsValidUUID(propertyId) {
+		return apperror.
+			New(apperror.InvalidPropertyId).
+			Describe("Invalid property id")
+	}
+
+	var property models.TrashedProperties
+	err := s.repo.GetTrashedPropertyById(&property, propertyId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.PropertyNotFound).
+			Describe("Could not find the specified property in trash")
+	} else if err != nil {
+		s.logger.Error("Could not get trashed property", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore property. Please try again later.")
+	}
+
+	if property.OwnerId != session.UserId && !session.IsAdmin {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the property owner can restore this property")
+	}
+
+	if !property.Restorable {
+		return apperror.
+			New(apperror.ResourceNotRestorable).
+			Describe("The property owner has been deleted. Restore the owner first.")
+	}
+
+	err = s.repo.RestorePropertyById(propertyId)
+	if err != nil {
+		s.logger.Error("Could not restore property", zap.String("id", propertyId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore property. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) RestoreAppointmentById(appointmentId string, session *models.Sessions) *apperror.AppError {
+	if !utils.IsValidUUID(appointmentId) {
+		return apperror.
+			New(apperror.InvalidAppointmentId).
+			Describe("Invalid appointment id")
+	}
+
+	var appointment models.TrashedAppointments
+	err := s.repo.GetTrashedAppointmentById(&appointment, appointmentId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.AppointmentNotFound).
+			Describe("Could not find the specified appointment in trash")
+	} else if err != nil {
+		s.logger.Error("Could not get trashed appointment", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore appointment. Please try again later.")
+	}
+
+	if !isParticipant(session, appointment.OwnerUserId, appointment.DwellerUserId) {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner or the dweller can restore this appointment")
+	}
+
+	if !appointment.Restorable {
+		return apperror.
+			New(apperror.ResourceNotRestorable).
+			Describe("The property or one of the users of this appointment has been deleted")
+	}
+
+	err = s.repo.RestoreAppointmentById(appointmentId)
+	if errors.Is(err, errAppointmentOverlaps) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.AppointmentSlotNotFree).
+			Describe("The slot of this appointment has been booked since it was deleted")
+	} else if err != nil {
+		s.logger.Error("Could not restore appointment", zap.String("id", appointmentId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore appointment. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) RestoreAgreementById(agreementId string, session *models.Sessions) *apperror.AppError {
+	if !utils.IsValidUUID(agreementId) {
+		return apperror.
+			New(apperror.InvalidAgreementId).
+			Describe("Invalid agreement id")
+	}
+
+	var agreement models.TrashedAgreements
+	err := s.repo.GetTrashedAgreementById(&agreement, agreementId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.AgreementNotFound).
+			Describe("Could not find the specified agreement in trash")
+	} else if err != nil {
+		s.logger.Error("Could not get trashed agreement", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore agreement. Please try again later.")
+	}
+
+	if !isParticipant(session, agreement.OwnerUserId, agreement.DwellerUserId) {
+		return apperror.
+			New(apperror.Forbidden).
+			Describe("Only the owner or the dweller can restore this agreement")
+	}
+
+	if !agreement.Restorable {
+		return apperror.
+			New(apperror.ResourceNotRestorable).
+			Describe("The property or one of the users of this agreement has been deleted")
+	}
+
+	// an ongoing agreement clashing on a unique index means the property has been taken meanwhile
+	active := slices.Contains([]enums.AgreementStatus{
+		enums.AwaitingDepositAgreement, enums.AwaitingPaymentAgreement, enums.RentingAgreement, enums.OverdueAgreement,
+	}, agreement.Status)
+
+	err = s.repo.RestoreAgreementById(agreementId)
+	if errors.Is(err, errPropertyTaken) || (errors.Is(err, gorm.ErrDuplicatedKey) && active) {
+		return apperror.
+			New(apperror.PropertyNotAvailable).
+			Describe("The property has got another ongoing agreement since this one was deleted")
+	} else if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.DuplicateAgreement).
+			Describe("Another agreement has already been made at the same date")
+	} else if err != nil {
+		s.logger.Error("Could not restore agreement", zap.String("id", agreementId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore agreement. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) RestoreUserById(userId string) *apperror.AppError {
+	if !utils.IsValidUUID(userId) {
+		return apperror.
+			New(apperror.InvalidUserId).
+			Describe("Invalid user id")
+	}
+
+	var user models.TrashedUsers
+	err := s.repo.GetTrashedUserById(&user, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.
+			New(apperror.UserNotFound).
+			Describe("Could not find the specified user in trash")
+	} else if err != nil {
+		s.logger.Error("Could not get trashed user", zap.String("id", userId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore user. Please try again later.")
+	}
+
+	if !user.Restorable {
+		return apperror.
+			New(apperror.ResourceNotRestorable).
+			Describe("The email or phone number of this user has been taken by another account")
+	}
+
+	err = s.repo.RestoreUserById(userId)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return apperror.
+			New(apperror.ResourceNotRestorable).
+			Describe("The email or phone number of this user has been taken by another account")
+	} else if err != nil {
+		s.logger.Error("Could not restore user", zap.String("id", userId), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not restore user. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) PurgeExpiredTrash() {
+	cutoff := time.Now().Add(-s.retention())
+
+	err := s.repo.PurgeDeletedBefore(cutoff)
+	if err != nil {
+		s.logger.Error("Could not purge expired trash", zap.Time("cutoff", cutoff), zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Purged expired trash", zap.Time("cutoff", cutoff))
+}
+
+func (s *serviceImpl) getTrash(trash *models.TrashResponses, userId string) *apperror.AppError {
+	trash.Properties = []models.TrashedProperties{}
+	trash.Appointments = []models.TrashedAppointments{}
+	trash.Agreements = []models.TrashedAgreements{}
+
+	if err := s.repo.GetTrashedProperties(&trash.Properties, userId); err != nil {
+		s.logger.Error("Could not get trashed properties", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get trash. Please try again later.")
+	}
+
+	if err := s.repo.GetTrashedAppointments(&trash.Appointments, userId); err != nil {
+		s.logger.Error("Could not get trashed appointments", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get trash. Please try again later.")
+	}
+
+	if err := s.repo.GetTrashedAgreements(&trash.Agreements, userId); err != nil {
+		s.logger.Error("Could not get trashed agreements", zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get trash. Please try again later.")
+	}
+
+	for i := range trash.Properties {
+		trash.Properties[i].PurgeAt = s.purgeAt(trash.Properties[i].DeletedAt)
+	}
+
+	for i := range trash.Appointments {
+		trash.Appointments[i].PurgeAt = s.purgeAt(trash.Appointments[i].DeletedAt)
+	}
+
+	for i := range trash.Agreements {
+		trash.Agreements[i].PurgeAt = s.purgeAt(trash.Agreements[i].DeletedAt)
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) retention() time.Duration {
+	return time.Duration(s.cfg.TrashRetention) * time.Second
+}
+
+func (s *serviceImpl) purgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.Add(s.retention())
+}
+
+func isParticipant(session *models.Sessions, ownerUserId uuid.UUID, dwellerUserId uuid.UUID) bool {
+	return session.IsAdmin || session.UserId == ownerUserId || session.UserId == dwellerUserId
+}
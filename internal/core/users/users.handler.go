@@ -22,6 +22,8 @@ type Handler interface {
 	DeleteUser(c *fiber.Ctx) error
 	GetRegisteredType(c *fiber.Ctx) error
 	VerifyCitizenId(c *fiber.Ctx) error
+	GetNotificationPreferences(c *fiber.Ctx) error
+	UpdateNotificationPreferences(c *fiber.Ctx) error
 }
 
 type handlerImpl struct {
@@ -269,3 +271,51 @@ func (h *handlerImpl) VerifyCitizenId(c *fiber.Ctx) error {
 
 	return utils.ResponseMessage(c, http.StatusOK, "Verified")
 }
+
+// @router      /api/v1/user/me/notification-preferences [get]
+// @summary     Get current user notification preferences *use cookies*
+// @description Get which notifications the current user receives by email
+// @tags        users
+// @produce     json
+// @success     200 {object} models.UserNotificationPreferences
+// @failure     500 {object} models.ErrorResponses
+func (h *handlerImpl) GetNotificationPreferences(c *fiber.Ctx) error {
+	session := c.Locals("session").(models.Sessions)
+
+	preferences := models.UserNotificationPreferences{}
+	apperr := h.service.GetNotificationPreferences(&preferences, session.UserId)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(preferences)
+}
+
+// @router      /api/v1/user/me/notification-preferences [put]
+// @summary     Update current user notification preferences *use cookies*
+// @description Update which notifications the current user receives by email. Preferences left out of the body are unchanged
+// @tags        users
+// @produce     json
+// @param       body body models.UpdatingUserNotificationPreferences true "Notification preferences"
+// @success     200 {object} models.UserNotificationPreferences
+// @failure     400 {object} models.ErrorResponses "Invalid request body"
+// @failure     500 {object} models.ErrorResponses "Could not update notification preferences"
+func (h *handlerImpl) UpdateNotificationPreferences(c *fiber.Ctx) error {
+	session := c.Locals("session").(models.Sessions)
+
+	updating := models.UpdatingUserNotificationPreferences{}
+	bodyErr := c.BodyParser(&updating)
+	if bodyErr != nil {
+		return utils.ResponseError(c, apperror.
+			New(apperror.InvalidBody).
+			Describe("Invalid request body"))
+	}
+
+	preferences := models.UserNotificationPreferences{}
+	apperr := h.service.UpdateNotificationPreferences(&preferences, &updating, session.UserId)
+	if apperr != nil {
+		return utils.ResponseError(c, apperr)
+	}
+
+	return c.JSON(preferences)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/brain-flowing-company/pprp-backend/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
@@ -19,6 +20,8 @@ type Repository interface {
 	CountPhoneNumber(*int64, uuid.UUID, string) error
 	CreateUserVerification(*models.UserVerifications) error
 	CountUserVerification(cnt *int64, userId uuid.UUID) error
+	GetNotificationPreferences(*models.UserNotificationPreferences, uuid.UUID) error
+	SaveNotificationPreferences(*models.UserNotificationPreferences) error
 }
 
 type repositoryImpl struct {
@@ -109,3 +112,14 @@ func (repo *repositoryImpl) CountUserVerification(cnt *int64, userId uuid.UUID)
 func (repo *repositoryImpl) CreateUserVerification(user *models.UserVerifications) error {
 	return repo.db.Model(&models.UserVerifications{}).Create(user).Error
 }
+
+func (repo *repositoryImpl) GetNotificationPreferences(preferences *models.UserNotificationPreferences, userId uuid.UUID) error {
+	return repo.db.Model(&models.UserNotificationPreferences{}).First(preferences, "user_id = ?", userId).Error
+}
+
+func (repo *repositoryImpl) SaveNotificationPreferences(preferences *models.UserNotificationPreferences) error {
+	return repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		UpdateAll: true,
+	}).Create(preferences).Error
+}
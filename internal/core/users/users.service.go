@@ -30,6 +30,8 @@ type Service interface {
 	DeleteUser(string) *apperror.AppError
 	GetUserByEmail(*models.Users, string) *apperror.AppError
 	VerifyCitizenId(*models.UserVerifications, *multipart.FileHeader) *apperror.AppError
+	GetNotificationPreferences(*models.UserNotificationPreferences, uuid.UUID) *apperror.AppError
+	UpdateNotificationPreferences(*models.UserNotificationPreferences, *models.UpdatingUserNotificationPreferences, uuid.UUID) *apperror.AppError
 }
 
 type serviceImpl struct {
@@ -455,3 +457,46 @@ func (s *serviceImpl) uploadCitizenImage(userId uuid.UUID, profileImage *multipa
 
 	return url, nil
 }
+
+func (s *serviceImpl) GetNotificationPreferences(preferences *models.UserNotificationPreferences, userId uuid.UUID) *apperror.AppError {
+	err := s.repo.GetNotificationPreferences(preferences, userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// users who never changed their preferences get every notification
+		*preferences = models.UserNotificationPreferences{
+			UserId:               userId,
+			AppointmentReminders: true,
+			AppointmentUpdates:   true,
+		}
+	} else if err != nil {
+		s.logger.Error("Could not get notification preferences", zap.String("id", userId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not get notification preferences. Please try again later.")
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) UpdateNotificationPreferences(preferences *models.UserNotificationPreferences, updating *models.UpdatingUserNotificationPreferences, userId uuid.UUID) *apperror.AppError {
+	if apperr := s.GetNotificationPreferences(preferences, userId); apperr != nil {
+		return apperr
+	}
+
+	if updating.AppointmentReminders != nil {
+		preferences.AppointmentReminders = *updating.AppointmentReminders
+	}
+
+	if updating.AppointmentUpdates != nil {
+		preferences.AppointmentUpdates = *updating.AppointmentUpdates
+	}
+
+	err := s.repo.SaveNotificationPreferences(preferences)
+	if err != nil {
+		s.logger.Error("Could not update notification preferences", zap.String("id", userId.String()), zap.Error(err))
+		return apperror.
+			New(apperror.InternalServerError).
+			Describe("Could not update notification preferences. Please try again later.")
+	}
+
+	return nil
+}
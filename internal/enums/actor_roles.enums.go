@@ -0,0 +1,10 @@
+package enums
+
+type ActorRoles string
+
+const (
+	OwnerActor   ActorRoles = "OWNER"
+	DwellerActor ActorRoles = "DWELLER"
+	SystemActor  ActorRoles = "SYSTEM"
+	AdminActor   ActorRoles = "ADMIN"
+)
@@ -0,0 +1,8 @@
+package enums
+
+type AmendmentTypes string
+
+const (
+	RenewalAmendment AmendmentTypes = "RENEWAL"
+	TermsAmendment   AmendmentTypes = "AMENDMENT"
+)
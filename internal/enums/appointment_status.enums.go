@@ -8,6 +8,7 @@ const (
 	RejectedAppointment  AppointmentStatus = "REJECTED"
 	CancelledAppointment AppointmentStatus = "CANCELLED"
 	ArchivedAppointment  AppointmentStatus = "ARCHIVED"
+	ExpiredAppointment   AppointmentStatus = "EXPIRED"
 )
 
 var AppointmentStatusMap = map[string]AppointmentStatus{
@@ -16,4 +17,5 @@ var AppointmentStatusMap = map[string]AppointmentStatus{
 	"REJECTED":  RejectedAppointment,
 	"CANCELLED": CancelledAppointment,
 	"ARCHIVED":  ArchivedAppointment,
+	"EXPIRED":   ExpiredAppointment,
 }
@@ -0,0 +1,8 @@
+package enums
+
+type AttendanceStatus string
+
+const (
+	CheckedInAttendance AttendanceStatus = "CHECKED_IN"
+	NoShowAttendance    AttendanceStatus = "NO_SHOW"
+)
@@ -0,0 +1,15 @@
+package enums
+
+type CheckoutStatus string
+
+const (
+	OpenCheckout    CheckoutStatus = "OPEN"
+	PaidCheckout    CheckoutStatus = "PAID"
+	ExpiredCheckout CheckoutStatus = "EXPIRED"
+)
+
+var CheckoutStatusMap = map[string]CheckoutStatus{
+	"OPEN":    OpenCheckout,
+	"PAID":    PaidCheckout,
+	"EXPIRED": ExpiredCheckout,
+}
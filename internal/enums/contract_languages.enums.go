@@ -0,0 +1,13 @@
+package enums
+
+type ContractLanguages string
+
+const (
+	EnglishContract ContractLanguages = "EN"
+	ThaiContract    ContractLanguages = "TH"
+)
+
+var ContractLanguagesMap = map[string]ContractLanguages{
+	"EN": EnglishContract,
+	"TH": ThaiContract,
+}
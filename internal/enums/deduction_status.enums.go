@@ -0,0 +1,11 @@
+package enums
+
+type DeductionStatus string
+
+const (
+	PendingDeduction   DeductionStatus = "PENDING"
+	AcceptedDeduction  DeductionStatus = "ACCEPTED"
+	DisputedDeduction  DeductionStatus = "DISPUTED"
+	EscalatedDeduction DeductionStatus = "ESCALATED"
+	WithdrawnDeduction DeductionStatus = "WITHDRAWN"
+)
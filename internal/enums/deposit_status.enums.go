@@ -0,0 +1,10 @@
+package enums
+
+type DepositStatus string
+
+const (
+	UnpaidDeposit            DepositStatus = "UNPAID"
+	HeldDeposit              DepositStatus = "HELD"
+	PartiallyDeductedDeposit DepositStatus = "PARTIALLY_DEDUCTED"
+	RefundedDeposit          DepositStatus = "REFUNDED"
+)
@@ -0,0 +1,13 @@
+package enums
+
+type InspectionTypes string
+
+const (
+	MoveInInspection  InspectionTypes = "MOVE_IN"
+	MoveOutInspection InspectionTypes = "MOVE_OUT"
+)
+
+var InspectionTypesMap = map[string]InspectionTypes{
+	"MOVE_IN":  MoveInInspection,
+	"MOVE_OUT": MoveOutInspection,
+}
@@ -0,0 +1,9 @@
+package enums
+
+type InstallmentStatus string
+
+const (
+	UnpaidInstallment  InstallmentStatus = "UNPAID"
+	PaidInstallment    InstallmentStatus = "PAID"
+	OverdueInstallment InstallmentStatus = "OVERDUE"
+)
@@ -0,0 +1,21 @@
+package enums
+
+type ItemConditions string
+
+const (
+	ExcellentCondition ItemConditions = "EXCELLENT"
+	GoodCondition      ItemConditions = "GOOD"
+	FairCondition      ItemConditions = "FAIR"
+	PoorCondition      ItemConditions = "POOR"
+	DamagedCondition   ItemConditions = "DAMAGED"
+)
+
+// ItemConditionsRank orders the conditions from best to worst, so that a
+// move-out report can tell which items got worse.
+var ItemConditionsRank = map[ItemConditions]int{
+	ExcellentCondition: 0,
+	GoodCondition:      1,
+	FairCondition:      2,
+	PoorCondition:      3,
+	DamagedCondition:   4,
+}
@@ -0,0 +1,8 @@
+package enums
+
+type LateFeeTypes string
+
+const (
+	FlatLateFee       LateFeeTypes = "FLAT"
+	PercentageLateFee LateFeeTypes = "PERCENTAGE"
+)
@@ -0,0 +1,23 @@
+package enums
+
+type MaintenanceCategories string
+
+const (
+	AirConditioningMaintenance MaintenanceCategories = "AIR_CONDITIONING"
+	PlumbingMaintenance        MaintenanceCategories = "PLUMBING"
+	ElectricalMaintenance      MaintenanceCategories = "ELECTRICAL"
+	ApplianceMaintenance       MaintenanceCategories = "APPLIANCE"
+	StructuralMaintenance      MaintenanceCategories = "STRUCTURAL"
+	PestControlMaintenance     MaintenanceCategories = "PEST_CONTROL"
+	OtherMaintenance           MaintenanceCategories = "OTHER"
+)
+
+var MaintenanceCategoriesMap = map[string]MaintenanceCategories{
+	"AIR_CONDITIONING": AirConditioningMaintenance,
+	"PLUMBING":         PlumbingMaintenance,
+	"ELECTRICAL":       ElectricalMaintenance,
+	"APPLIANCE":        ApplianceMaintenance,
+	"STRUCTURAL":       StructuralMaintenance,
+	"PEST_CONTROL":     PestControlMaintenance,
+	"OTHER":            OtherMaintenance,
+}
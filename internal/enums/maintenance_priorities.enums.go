@@ -0,0 +1,17 @@
+package enums
+
+type MaintenancePriorities string
+
+const (
+	LowPriority    MaintenancePriorities = "LOW"
+	MediumPriority MaintenancePriorities = "MEDIUM"
+	HighPriority   MaintenancePriorities = "HIGH"
+	UrgentPriority MaintenancePriorities = "URGENT"
+)
+
+var MaintenancePrioritiesMap = map[string]MaintenancePriorities{
+	"LOW":    LowPriority,
+	"MEDIUM": MediumPriority,
+	"HIGH":   HighPriority,
+	"URGENT": UrgentPriority,
+}
@@ -0,0 +1,17 @@
+package enums
+
+type MaintenanceStatus string
+
+const (
+	OpenMaintenance         MaintenanceStatus = "OPEN"
+	AcknowledgedMaintenance MaintenanceStatus = "ACKNOWLEDGED"
+	ScheduledMaintenance    MaintenanceStatus = "SCHEDULED"
+	ResolvedMaintenance     MaintenanceStatus = "RESOLVED"
+)
+
+var MaintenanceStatusMap = map[string]MaintenanceStatus{
+	"OPEN":         OpenMaintenance,
+	"ACKNOWLEDGED": AcknowledgedMaintenance,
+	"SCHEDULED":    ScheduledMaintenance,
+	"RESOLVED":     ResolvedMaintenance,
+}
@@ -11,7 +11,8 @@ const (
 type MessageOutboundEvents string
 
 const (
-	OUTBOUND_MSG  MessageOutboundEvents = "MSG"
-	OUTBOUND_READ MessageOutboundEvents = "READ"
-	OUTBOUND_OK   MessageOutboundEvents = "OK"
+	OUTBOUND_MSG    MessageOutboundEvents = "MSG"
+	OUTBOUND_READ   MessageOutboundEvents = "READ"
+	OUTBOUND_OK     MessageOutboundEvents = "OK"
+	OUTBOUND_TICKET MessageOutboundEvents = "TICKET"
 )
@@ -0,0 +1,8 @@
+package enums
+
+type PaymentProviders string
+
+const (
+	StripePaymentProvider PaymentProviders = "STRIPE"
+	FakePaymentProvider   PaymentProviders = "FAKE"
+)
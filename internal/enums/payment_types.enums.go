@@ -0,0 +1,17 @@
+package enums
+
+type PaymentTypes string
+
+const (
+	DepositPayment       PaymentTypes = "DEPOSIT"
+	RentPayment          PaymentTypes = "RENT"
+	PurchasePayment      PaymentTypes = "PURCHASE"
+	DepositRefundPayment PaymentTypes = "DEPOSIT_REFUND"
+)
+
+var PaymentTypesMap = map[string]PaymentTypes{
+	"DEPOSIT":        DepositPayment,
+	"RENT":           RentPayment,
+	"PURCHASE":       PurchasePayment,
+	"DEPOSIT_REFUND": DepositRefundPayment,
+}
@@ -0,0 +1,17 @@
+package enums
+
+type PropertyAttachmentTypes string
+
+const (
+	DocumentAttachment  PropertyAttachmentTypes = "DOCUMENT"
+	FloorPlanAttachment PropertyAttachmentTypes = "FLOOR_PLAN"
+	VideoUrlAttachment  PropertyAttachmentTypes = "VIDEO_URL"
+	TourUrlAttachment   PropertyAttachmentTypes = "TOUR_URL"
+)
+
+var PropertyAttachmentTypesMap = map[string]PropertyAttachmentTypes{
+	"DOCUMENT":   DocumentAttachment,
+	"FLOOR_PLAN": FloorPlanAttachment,
+	"VIDEO_URL":  VideoUrlAttachment,
+	"TOUR_URL":   TourUrlAttachment,
+}
@@ -0,0 +1,10 @@
+package enums
+
+type PropertyHistoryActions string
+
+const (
+	CreatePropertyHistory PropertyHistoryActions = "CREATE"
+	UpdatePropertyHistory PropertyHistoryActions = "UPDATE"
+	DeletePropertyHistory PropertyHistoryActions = "DELETE"
+	RevertPropertyHistory PropertyHistoryActions = "REVERT"
+)
@@ -0,0 +1,10 @@
+package enums
+
+type ProposalStatus string
+
+const (
+	PendingProposal    ProposalStatus = "PENDING"
+	AcceptedProposal   ProposalStatus = "ACCEPTED"
+	DeclinedProposal   ProposalStatus = "DECLINED"
+	SupersededProposal ProposalStatus = "SUPERSEDED"
+)
@@ -0,0 +1,13 @@
+package enums
+
+type SignatureTypes string
+
+const (
+	TypedSignature SignatureTypes = "TYPED"
+	DrawnSignature SignatureTypes = "DRAWN"
+)
+
+var SignatureTypesMap = map[string]SignatureTypes{
+	"TYPED": TypedSignature,
+	"DRAWN": DrawnSignature,
+}
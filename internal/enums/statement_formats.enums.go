@@ -0,0 +1,15 @@
+package enums
+
+type StatementFormats string
+
+const (
+	JSONStatement StatementFormats = "JSON"
+	CSVStatement  StatementFormats = "CSV"
+	PDFStatement  StatementFormats = "PDF"
+)
+
+var StatementFormatsMap = map[string]StatementFormats{
+	"JSON": JSONStatement,
+	"CSV":  CSVStatement,
+	"PDF":  PDFStatement,
+}
@@ -0,0 +1,13 @@
+package enums
+
+type UtilityTypes string
+
+const (
+	WaterUtility       UtilityTypes = "WATER"
+	ElectricityUtility UtilityTypes = "ELECTRICITY"
+)
+
+var UtilityTypesMap = map[string]UtilityTypes{
+	"WATER":       WaterUtility,
+	"ELECTRICITY": ElectricityUtility,
+}
@@ -30,6 +30,21 @@ func (m *Middleware) AuthMiddlewareWrapper(next func(*fiber.Ctx) error) func(*fi
 	}
 }
 
+func (m *Middleware) AdminMiddlewareWrapper(next func(*fiber.Ctx) error) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		session, ok := c.Locals("session").(models.Sessions)
+		if !ok {
+			return utils.ResponseMessage(c, http.StatusUnauthorized, "Unauthorized")
+		}
+
+		if !session.IsAdmin {
+			return utils.ResponseMessage(c, http.StatusForbidden, "Forbidden")
+		}
+
+		return next(c)
+	}
+}
+
 func (m *Middleware) SessionMiddleware(c *fiber.Ctx) error {
 	cookie := new(models.Cookies)
 
@@ -40,7 +55,9 @@ func (m *Middleware) SessionMiddleware(c *fiber.Ctx) error {
 
 	claim, err := utils.ParseToken(cookie.Session, m.cfg.JWTSecret)
 	if err == nil {
-		c.Locals("session", claim.Session)
+		session := claim.Session
+		session.IsAdmin = m.cfg.IsAdmin(session.Email)
+		c.Locals("session", session)
 	}
 
 	return c.Next()
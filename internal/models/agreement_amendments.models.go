@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+// AgreementAmendments are requests to change the term or the rent of a running
+// agreement. Accepted amendments are numbered, the original terms being
+// version 1, and keep the terms they replaced.
+type AgreementAmendments struct {
+	AmendmentId             uuid.UUID            `json:"amendment_id"               example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementId             uuid.UUID            `json:"-"`
+	AmendmentType           enums.AmendmentTypes `json:"amendment_type"             example:"RENEWAL"`
+	Version                 *int                 `json:"version"                    example:"2"`
+	ProposedByUserId        uuid.UUID            `json:"proposed_by_user_id"        example:"123e4567-e89b-12d3-a456-426614174000"`
+	ProposerRole            enums.ActorRoles     `json:"proposer_role"              example:"OWNER"`
+	PreviousPaymentDuration int                  `json:"previous_payment_duration"  example:"12"`
+	PreviousPaymentPerMonth float64              `json:"previous_payment_per_month" example:"15000"`
+	PaymentDuration         int                  `json:"payment_duration"           example:"24"`
+	PaymentPerMonth         float64              `json:"payment_per_month"          example:"16000"`
+	EndDate                 time.Time            `json:"end_date"                   example:"2026-02-18T00:00:00+07:00"`
+	Status                  enums.ProposalStatus `json:"status"                     example:"PENDING"`
+	Message                 string               `json:"message"                    example:"Renewing for another year" gorm:"default:null"`
+	RespondedByUserId       *uuid.UUID           `json:"responded_by_user_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
+	RespondedAt             *time.Time           `json:"responded_at"               example:"2024-02-17T11:00:00Z"`
+	CreatedAt               time.Time            `json:"created_at"                 example:"2024-02-17T10:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AgreementAmendments) TableName() string {
+	return "agreement_amendments"
+}
+
+// CreatingAgreementAmendments changes the term either by its length in months
+// or by its end date, which must fall on the day of the month the agreement
+// started. Fields left out keep their current value.
+type CreatingAgreementAmendments struct {
+	PaymentDuration *int       `json:"payment_duration"  example:"24"`
+	PaymentPerMonth *float64   `json:"payment_per_month" example:"16000"`
+	EndDate         *time.Time `json:"end_date"          example:"2026-02-18T00:00:00+07:00"`
+	Message         string     `json:"message"           example:"Renewing for another year"`
+}
+
+// AgreementScheduleChanges is how accepting an amendment reshapes the
+// installment schedule of its agreement.
+type AgreementScheduleChanges struct {
+	TotalPayment   float64
+	Saved          []AgreementInstallments
+	RemovedNumbers []int
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type AgreementContracts struct {
+	ContractId       uuid.UUID               `json:"contract_id"        example:"123e4567-e89b-12d3-a456-426614174000" gorm:"default:gen_random_uuid()"`
+	AgreementId      uuid.UUID               `json:"agreement_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
+	Language         enums.ContractLanguages `json:"language"           example:"TH"`
+	Version          int                     `json:"version"            example:"1"`
+	AgreementVersion int                     `json:"agreement_version"  example:"1"`
+	FileKey          string                  `json:"-"`
+	DocumentHash     string                  `json:"document_hash"      example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	CreatedByUserId  *uuid.UUID              `json:"created_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt        time.Time               `json:"created_at"         example:"2024-02-18T11:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AgreementContracts) TableName() string {
+	return "agreement_contracts"
+}
+
+// AgreementContractTemplates is what contract templates are rendered from.
+// Dates and amounts are formatted for the language of the contract.
+type AgreementContractTemplates struct {
+	Language        enums.ContractLanguages
+	Agreement       *AgreementDetails
+	IsRenting       bool
+	Address         string
+	AgreementDate   string
+	EndDate         string
+	DepositAmount   string
+	PaymentPerMonth string
+	PaymentDuration int
+	TotalPayment    string
+	Version         int
+	GeneratedAt     string
+}
+
+func (a AgreementContractTemplates) Path() string {
+	return "internal/templates/AgreementContract" + string(a.Language) + ".txt"
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+// AgreementDeposits is where the deposit of a renting agreement stands. The
+// held amount is what the dweller has paid and the refundable amount is what
+// is left of it after the accepted deductions.
+type AgreementDeposits struct {
+	AgreementId      uuid.UUID                    `json:"agreement_id"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	Status           enums.DepositStatus          `json:"status"            example:"PARTIALLY_DEDUCTED"`
+	DepositAmount    float64                      `json:"deposit_amount"    example:"30000"`
+	HeldAmount       float64                      `json:"held_amount"       example:"30000"`
+	DeductedAmount   float64                      `json:"deducted_amount"   example:"4500"`
+	PendingAmount    float64                      `json:"pending_amount"    example:"1200"`
+	RefundableAmount float64                      `json:"refundable_amount" example:"25500"`
+	Refund           *Payments                    `json:"refund"`
+	Deductions       []AgreementDepositDeductions `json:"deductions"`
+}
+
+type AgreementDepositDeductions struct {
+	DeductionId     uuid.UUID                     `json:"deduction_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementId     uuid.UUID                     `json:"-"`
+	Description     string                        `json:"description"        example:"Broken bathroom mirror"`
+	Amount          float64                       `json:"amount"             example:"1200"`
+	Status          enums.DeductionStatus         `json:"status"             example:"PENDING"`
+	DisputeReason   string                        `json:"dispute_reason"     example:"The mirror was already cracked when I moved in" gorm:"default:null"`
+	CreatedByUserId uuid.UUID                     `json:"created_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RespondedAt     *time.Time                    `json:"responded_at"       example:"2024-02-17T11:00:00Z"`
+	ResolvedAt      *time.Time                    `json:"resolved_at"        example:"2024-02-20T09:00:00Z"`
+	CreatedAt       time.Time                     `json:"created_at"         example:"2024-02-17T10:00:00Z" gorm:"autoCreateTime"`
+	Evidences       []AgreementDeductionEvidences `json:"evidences"          gorm:"foreignKey:DeductionId; references:DeductionId"`
+}
+
+func (a AgreementDepositDeductions) TableName() string {
+	return "agreement_deposit_deductions"
+}
+
+type AgreementDeductionEvidences struct {
+	DeductionId uuid.UUID `json:"-"`
+	ImageUrl    string    `json:"image_url" example:"https://image_url.com/abcd"`
+	CreatedAt   time.Time `json:"-"         gorm:"autoCreateTime"`
+}
+
+func (a AgreementDeductionEvidences) TableName() string {
+	return "agreement_deduction_evidences"
+}
+
+type CreatingDepositDeductions struct {
+	Description string  `form:"description" example:"Broken bathroom mirror"`
+	Amount      float64 `form:"amount"      example:"1200"`
+}
+
+type DisputingDepositDeductions struct {
+	DisputeReason string `json:"dispute_reason" example:"The mirror was already cracked when I moved in"`
+}
+
+// ResolvingDepositDeductions settles a disputed deduction: the dweller accepts
+// it after all, the owner withdraws it, or either of them escalates it to an
+// admin, who then accepts or withdraws it.
+type ResolvingDepositDeductions struct {
+	Status enums.DeductionStatus `json:"status" example:"ESCALATED"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+// AgreementInspections are room-by-room reports of the condition of a unit
+// when the dweller moves in and out. A report cannot be changed once one of
+// the parties has signed it off, and it is final once both have. Until then a
+// party that disagrees with it can supersede it with a new report.
+type AgreementInspections struct {
+	InspectionId    uuid.UUID                  `json:"inspection_id"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementId     uuid.UUID                  `json:"-"`
+	InspectionType  enums.InspectionTypes      `json:"inspection_type"    example:"MOVE_IN"`
+	CreatedByUserId uuid.UUID                  `json:"created_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OwnerSignedAt   *time.Time                 `json:"owner_signed_at"    example:"2024-02-17T11:00:00Z"`
+	DwellerSignedAt *time.Time                 `json:"dweller_signed_at"  example:"2024-02-17T11:30:00Z"`
+	SupersededAt    *time.Time                 `json:"superseded_at"      example:"2024-02-18T09:00:00Z"`
+	CreatedAt       time.Time                  `json:"created_at"         example:"2024-02-17T10:00:00Z" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time                  `json:"updated_at"         example:"2024-02-17T10:00:00Z" gorm:"autoUpdateTime"`
+	Items           []AgreementInspectionItems `json:"items"              gorm:"foreignKey:InspectionId; references:InspectionId"`
+}
+
+func (a AgreementInspections) TableName() string {
+	return "agreement_inspections"
+}
+
+type AgreementInspectionItems struct {
+	ItemId       uuid.UUID                   `json:"item_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	InspectionId uuid.UUID                   `json:"-"`
+	Room         string                      `json:"room"      example:"Bathroom"`
+	Item         string                      `json:"item"      example:"Mirror"`
+	Condition    enums.ItemConditions        `json:"condition" example:"GOOD"`
+	Notes        string                      `json:"notes"     example:"Small scratch on the lower left corner" gorm:"default:null"`
+	ItemOrder    int                         `json:"-"`
+	Photos       []AgreementInspectionPhotos `json:"photos"    gorm:"foreignKey:ItemId; references:ItemId"`
+}
+
+func (a AgreementInspectionItems) TableName() string {
+	return "agreement_inspection_items"
+}
+
+type AgreementInspectionPhotos struct {
+	ItemId     uuid.UUID `json:"-"`
+	ImageUrl   string    `json:"image_url" example:"https://image_url.com/abcd"`
+	PhotoOrder int       `json:"-"`
+	CreatedAt  time.Time `json:"-"         gorm:"autoCreateTime"`
+}
+
+func (a AgreementInspectionPhotos) TableName() string {
+	return "agreement_inspection_photos"
+}
+
+type CreatingInspections struct {
+	InspectionType enums.InspectionTypes     `json:"inspection_type" example:"MOVE_IN"`
+	Items          []CreatingInspectionItems `json:"items"`
+}
+
+type UpdatingInspections struct {
+	Items []CreatingInspectionItems `json:"items"`
+}
+
+type CreatingInspectionItems struct {
+	Room      string               `json:"room"      example:"Bathroom"`
+	Item      string               `json:"item"      example:"Mirror"`
+	Condition enums.ItemConditions `json:"condition" example:"GOOD"`
+	Notes     string               `json:"notes"     example:"Small scratch on the lower left corner"`
+}
+
+// InspectionComparisons puts the move-out report of an agreement side by side
+// with its move-in report. Items are matched by room and name, and an item
+// missing from one of the reports is null on that side.
+type InspectionComparisons struct {
+	MoveInInspectionId  uuid.UUID                   `json:"move_in_inspection_id"  example:"123e4567-e89b-12d3-a456-426614174000"`
+	MoveOutInspectionId uuid.UUID                   `json:"move_out_inspection_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Items               []InspectionComparisonItems `json:"items"`
+}
+
+type InspectionComparisonItems struct {
+	Room     string                    `json:"room"     example:"Bathroom"`
+	Item     string                    `json:"item"     example:"Mirror"`
+	MoveIn   *AgreementInspectionItems `json:"move_in"`
+	MoveOut  *AgreementInspectionItems `json:"move_out"`
+	Changed  bool                      `json:"changed"  example:"true"`
+	Worsened bool                      `json:"worsened" example:"true"`
+}
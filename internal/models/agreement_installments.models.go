@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type AgreementInstallments struct {
+	InstallmentId     uuid.UUID                `json:"installment_id"     example:"123e4567-e89b-12d3-a456-426614174000" gorm:"default:gen_random_uuid()"`
+	AgreementId       uuid.UUID                `json:"agreement_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
+	InstallmentNumber int                      `json:"installment_number" example:"1"`
+	DueDate           time.Time                `json:"due_date"           example:"2024-02-18T00:00:00Z"`
+	Amount            float64                  `json:"amount"             example:"15000"`
+	LateFee           float64                  `json:"late_fee"           example:"750"`
+	UtilityAmount     float64                  `json:"utility_amount"     example:"2236"`
+	Status            enums.InstallmentStatus  `json:"status"             example:"UNPAID"`
+	PaidAt            *time.Time               `json:"paid_at"            example:"2024-02-17T11:00:00Z"`
+	CreatedAt         time.Time                `json:"-"                  gorm:"autoCreateTime"`
+	UpdatedAt         time.Time                `json:"-"                  gorm:"autoUpdateTime"`
+	MeterReadings     []AgreementMeterReadings `json:"meter_readings"     gorm:"foreignKey:InstallmentId; references:InstallmentId"`
+}
+
+func (a AgreementInstallments) TableName() string {
+	return "agreement_installments"
+}
+
+type AgreementInstallmentSchedules struct {
+	AgreementId       uuid.UUID               `json:"agreement_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
+	PaidAmount        float64                 `json:"paid_amount"        example:"30000"`
+	OutstandingAmount float64                 `json:"outstanding_amount" example:"150000"`
+	Installments      []AgreementInstallments `json:"installments"`
+}
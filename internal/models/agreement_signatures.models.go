@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type AgreementSignatures struct {
+	SignatureId       uuid.UUID            `json:"signature_id"   example:"123e4567-e89b-12d3-a456-426614174000" gorm:"default:gen_random_uuid()"`
+	AgreementId       uuid.UUID            `json:"-"`
+	ContractId        uuid.UUID            `json:"contract_id"    example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserId            uuid.UUID            `json:"user_id"        example:"123e4567-e89b-12d3-a456-426614174000"`
+	Role              enums.ActorRoles     `json:"role"           example:"OWNER"`
+	SignatureType     enums.SignatureTypes `json:"signature_type" example:"TYPED"`
+	SignedName        string               `json:"signed_name"    example:"John Doe"`
+	SignatureImageKey *string              `json:"-"`
+	DocumentHash      string               `json:"document_hash"  example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	IpAddress         string               `json:"ip_address"     example:"203.0.113.7"`
+	UserAgent         string               `json:"user_agent"     example:"Mozilla/5.0" gorm:"default:null"`
+	SignedAt          time.Time            `json:"signed_at"      example:"2024-02-18T11:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AgreementSignatures) TableName() string {
+	return "agreement_signatures"
+}
+
+type SigningAgreements struct {
+	Language      enums.ContractLanguages `form:"language"       example:"TH"`
+	SignatureType enums.SignatureTypes    `form:"signature_type" example:"TYPED"`
+	SignedName    string                  `form:"signed_name"    example:"John Doe"`
+	IpAddress     string                  `form:"-"              swaggerignore:"true"`
+	UserAgent     string                  `form:"-"              swaggerignore:"true"`
+}
+
+// AgreementSignatureVerifications is the audit trail of a signed contract.
+// The document is intact when the stored file still hashes to the hash that
+// was frozen at the first signature.
+type AgreementSignatureVerifications struct {
+	AgreementId      uuid.UUID             `json:"agreement_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
+	Contract         AgreementContracts    `json:"contract"`
+	FrozenHash       string                `json:"frozen_hash"        example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	CurrentHash      string                `json:"current_hash"       example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	IsDocumentIntact bool                  `json:"is_document_intact" example:"true"`
+	IsFullySigned    bool                  `json:"is_fully_signed"    example:"true"`
+	Signatures       []AgreementSignatures `json:"signatures"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type AgreementStatusHistories struct {
+	HistoryId   uuid.UUID             `json:"history_id"    example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementId uuid.UUID             `json:"-"`
+	FromStatus  enums.AgreementStatus `json:"from_status"   example:"AWAITING_DEPOSIT"`
+	ToStatus    enums.AgreementStatus `json:"to_status"     example:"AWAITING_PAYMENT"`
+	ActorUserId *uuid.UUID            `json:"actor_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ActorRole   enums.ActorRoles      `json:"actor_role"    example:"OWNER"`
+	Message     string                `json:"message"       example:"This is a cancelled message" gorm:"default:null"`
+	CreatedAt   time.Time             `json:"created_at"    example:"2024-02-18T11:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AgreementStatusHistories) TableName() string {
+	return "agreement_status_histories"
+}
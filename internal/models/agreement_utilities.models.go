@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+// AgreementUtilityRates are what the owner charges per unit of water and
+// electricity, usually above the tariff of the utility.
+type AgreementUtilityRates struct {
+	AgreementId     uuid.UUID `json:"agreement_id"     example:"123e4567-e89b-12d3-a456-426614174000" gorm:"primaryKey"`
+	WaterRate       float64   `json:"water_rate"       example:"18"`
+	ElectricityRate float64   `json:"electricity_rate" example:"8"`
+	UpdatedAt       time.Time `json:"updated_at"       example:"2024-02-17T10:00:00Z" gorm:"autoUpdateTime"`
+}
+
+func (a AgreementUtilityRates) TableName() string {
+	return "agreement_utility_rates"
+}
+
+type UpdatingUtilityRates struct {
+	WaterRate       float64 `json:"water_rate"       example:"18"`
+	ElectricityRate float64 `json:"electricity_rate" example:"8"`
+}
+
+// AgreementMeterReadings are the monthly readings of the water and electricity
+// meters. The units used since the previous reading are charged at the rate of
+// the time with the rent installment of that month.
+type AgreementMeterReadings struct {
+	ReadingId         uuid.UUID          `json:"reading_id"          example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementId       uuid.UUID          `json:"-"`
+	InstallmentId     uuid.UUID          `json:"installment_id"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	InstallmentNumber int                `json:"installment_number"  example:"3"`
+	UtilityType       enums.UtilityTypes `json:"utility_type"        example:"ELECTRICITY"`
+	PreviousReading   float64            `json:"previous_reading"    example:"10240"`
+	CurrentReading    float64            `json:"current_reading"     example:"10452"`
+	Units             float64            `json:"units"               example:"212"`
+	UnitRate          float64            `json:"unit_rate"           example:"8"`
+	Amount            float64            `json:"amount"              example:"1696"`
+	ImageUrl          string             `json:"image_url"           example:"https://image_url.com/abcd"`
+	RecordedByUserId  uuid.UUID          `json:"recorded_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt         time.Time          `json:"created_at"          example:"2024-02-17T10:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AgreementMeterReadings) TableName() string {
+	return "agreement_meter_readings"
+}
+
+// CreatingMeterReadings takes the previous reading only for the first reading
+// of a meter. Later ones carry on from the reading before them.
+type CreatingMeterReadings struct {
+	InstallmentNumber int                `form:"installment_number" example:"3"`
+	UtilityType       enums.UtilityTypes `form:"utility_type"       example:"ELECTRICITY"`
+	PreviousReading   *float64           `form:"previous_reading"   example:"10240"`
+	CurrentReading    float64            `form:"current_reading"    example:"10452"`
+}
@@ -24,16 +24,17 @@ type Agreements struct {
 }
 
 type CreatingAgreements struct {
+	AgreementId   uuid.UUID `json:"-"`
 	AgreementType enums.AgreementTypes `json:"agreement_type" example:"SELLING"`
 	PropertyId    uuid.UUID `json:"property_id" example:"00000000-0000-0000-0000-000000000000"`
 	OwnerUserId   uuid.UUID `json:"-"`
 	DwellerUserId uuid.UUID `json:"dweller_user_id" example:"00000000-0000-0000-0000-000000000000"`
 	AgreementDate time.Time `json:"agreement_date" example:"2021-01-01T00:00:00Z"`
-	Status enums.AgreementStatus `json:"status" example:"AWAITING_DEPOSIT"`
+	Status enums.AgreementStatus `json:"-"`
 	DepositAmount float64 `json:"deposit_amount" example:"1000000"`
 	PaymentPerMonth float64 `json:"payment_per_month" example:"1000000"`
 	PaymentDuration int `json:"payment_duration" example:"12"`
-	TotalPayment float64 `json:"total_payment" example:"12000000"`
+	TotalPayment float64 `json:"-"`
 }
 
 func (a Agreements) TableName() string {
@@ -49,28 +50,31 @@ type UpdatingAgreementStatus struct {
 type MyAgreementRequests struct {
 	UserId uuid.UUID `json:"-"`
 	Order string `json:"-"`
+	Role enums.ActorRoles `json:"-"`
 }
 
 type MyAgreementResponses struct {
+	OwnerTotal        int64            `json:"owner_total" example:"2"`
 	OwnerAgreements   []AgreementLists `json:"owner_agreements"`
+	DwellerTotal      int64            `json:"dweller_total" example:"2"`
 	DwellerAgreements []AgreementLists `json:"dweller_agreements"`
 }
 
 // Data Structure for Agreement Lists
 type AgreementLists struct {
 	AgreementId     uuid.UUID `json:"agreement_id" example:"00000000-0000-0000-0000-000000000000"`
-	AgreementType   enums.AgreementTypes `json:"agreement_type" example:"SELLING"`
+	AgreementType   enums.AgreementTypes `json:"agreement_type" example:"SELLING" filtermapper:"a.agreement_type::TEXT"`
 	Property        PropertyAgreementLists `json:"property" gorm:"foreignKey:AgreementId; references:AgreementId; embedded"`
 	Owner           OwnerAgreementLists `json:"owner" gorm:"foreignKey:AgreementId; references:AgreementId; embedded"`
-	AgreementDate   time.Time `json:"agreement_date" example:"2021-01-01T00:00:00Z"`
-	Status          enums.AgreementStatus `json:"status" example:"AWAITING_DEPOSIT"`
+	AgreementDate   time.Time `json:"agreement_date" example:"2021-01-01T00:00:00Z" sortmapper:"a.agreement_date" filtermapper:"a.agreement_date"`
+	Status          enums.AgreementStatus `json:"status" example:"AWAITING_DEPOSIT" filtermapper:"a.status::TEXT"`
 	CancelledMessage string `json:"cancelled_message" example:"This is cancelled message."`
 	CommonModels
 }
 
 type PropertyAgreementLists struct {
 	AgreementId     uuid.UUID `json:"-"`
-	PropertyId      uuid.UUID `json:"property_id" example:"00000000-0000-0000-0000-000000000000"`
+	PropertyId      uuid.UUID `json:"property_id" example:"00000000-0000-0000-0000-000000000000" filtermapper:"a.property_id"`
 	PropertyName    string `json:"property_name" example:"The Base Sukhumvit 77"`
 	PropertyType    enums.PropertyTypes `json:"property_type" example:"CONDO"`
 	PropertyImages  []PropertyImageAgreements `json:"property_images" gorm:"foreignKey:AgreementId; references:AgreementId"`
@@ -104,6 +108,7 @@ type AgreementDetails struct {
 	PaymentDuration int `json:"payment_duration" example:"12"`
 	TotalPayment    float64 `json:"total_payment" example:"12000000"`
 	CancelledMessage string `json:"cancelled_message" example:"This is cancelled message."`
+	StatusHistories []AgreementStatusHistories `json:"status_histories" gorm:"-"`
 	CommonModels
 }
 
@@ -141,4 +146,24 @@ type DwellerAgreementDetails struct {
 	DwellerLastName    string `json:"dweller_last_name" example:"Doe"`
 	DwellerProfileImageUrl string `json:"dweller_profile_image_url" example:"https://www.example.com/image.jpg"`
 	DwellerPhoneNumber string `json:"dweller_phone_number" example:"0812345678"`
-}
\ No newline at end of file
+}
+
+// AgreementProperties is what an agreement needs to know about its property.
+// The selling and renting fields are nil when the property is not for sale or
+// not for rent.
+type AgreementProperties struct {
+	PropertyId    uuid.UUID
+	OwnerId       uuid.UUID
+	SellingPrice  *float64
+	IsSold        *bool
+	PricePerMonth *float64
+	IsOccupied    *bool
+}
+
+// AgreementPropertyFlags is how a change of an agreement status changes its
+// property. Nil flags are left as they are.
+type AgreementPropertyFlags struct {
+	PropertyId uuid.UUID
+	IsSold     *bool
+	IsOccupied *bool
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+// AppointmentAttendances records whether a party of an appointment showed up,
+// either because they checked in themselves or because the other party
+// reported them as a no-show. Each party has at most one record.
+type AppointmentAttendances struct {
+	AppointmentId    uuid.UUID              `json:"appointment_id"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserId           uuid.UUID              `json:"user_id"             example:"123e4567-e89b-12d3-a456-426614174000"`
+	Role             enums.ActorRoles       `json:"role"                example:"DWELLER"`
+	Status           enums.AttendanceStatus `json:"status"              example:"CHECKED_IN"`
+	ReportedByUserId uuid.UUID              `json:"reported_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt        time.Time              `json:"created_at"          example:"2024-02-18T11:05:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AppointmentAttendances) TableName() string {
+	return "appointment_attendances"
+}
+
+type AttendanceStatistics struct {
+	UserId     uuid.UUID `json:"user_id"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	CheckIns   int64     `json:"check_ins"    example:"9"`
+	NoShows    int64     `json:"no_shows"     example:"1"`
+	NoShowRate float64   `json:"no_show_rate" example:"0.1"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type AppointmentProposals struct {
+	ProposalId        uuid.UUID            `json:"proposal_id"          example:"123e4567-e89b-12d3-a456-426614174000"`
+	AppointmentId     uuid.UUID            `json:"-"`
+	ProposedByUserId  uuid.UUID            `json:"proposed_by_user_id"  example:"123e4567-e89b-12d3-a456-426614174000"`
+	ProposerRole      enums.ActorRoles     `json:"proposer_role"        example:"OWNER"`
+	ProposedDate      time.Time            `json:"proposed_date"        example:"2024-02-18T17:00:00+07:00"`
+	DurationMinutes   int64                `json:"duration_minutes"     example:"30"`
+	Status            enums.ProposalStatus `json:"status"               example:"PENDING"`
+	Message           string               `json:"message"              example:"Can we do 5pm instead?" gorm:"default:null"`
+	RespondedByUserId *uuid.UUID           `json:"responded_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RespondedAt       *time.Time           `json:"responded_at"         example:"2024-02-17T11:00:00Z"`
+	CreatedAt         time.Time            `json:"created_at"           example:"2024-02-17T10:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AppointmentProposals) TableName() string {
+	return "appointment_proposals"
+}
+
+type CreatingAppointmentProposals struct {
+	ProposedDate time.Time `json:"proposed_date" example:"2024-02-18T17:00:00+07:00"`
+	Message      string    `json:"message"       example:"Can we do 5pm instead?"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type AppointmentStatusHistories struct {
+	HistoryId     uuid.UUID               `json:"history_id"     example:"123e4567-e89b-12d3-a456-426614174000"`
+	AppointmentId uuid.UUID               `json:"-"`
+	FromStatus    enums.AppointmentStatus `json:"from_status"    example:"PENDING"`
+	ToStatus      enums.AppointmentStatus `json:"to_status"      example:"CONFIRMED"`
+	ActorUserId   *uuid.UUID              `json:"actor_user_id"  example:"123e4567-e89b-12d3-a456-426614174000"`
+	ActorRole     enums.ActorRoles        `json:"actor_role"     example:"OWNER"`
+	Message       string                  `json:"message"        example:"This is a cancelled message" gorm:"default:null"`
+	CreatedAt     time.Time               `json:"created_at"     example:"2024-02-18T11:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (a AppointmentStatusHistories) TableName() string {
+	return "appointment_status_histories"
+}
@@ -13,6 +13,7 @@ type Appointments struct {
 	OwnerUserId      uuid.UUID               `json:"owner_user_id"    example:"123e4567-e89b-12d3-a456-426614174000"`
 	DwellerUserId    uuid.UUID               `json:"dweller_user_id"  example:"123e4567-e89b-12d3-a456-426614174000"`
 	AppointmentDate  time.Time               `json:"appointment_date" example:"2024-02-18T11:00:00Z"`
+	DurationMinutes  int64                   `json:"duration_minutes" example:"30"`
 	Status           enums.AppointmentStatus `json:"status"           example:"PENDING"`
 	Note             string                  `json:"note"             example:"This is a note"`
 	CancelledMessage string                  `json:"cancelled_message" example:"This is a cancelled message"`
@@ -21,9 +22,10 @@ type Appointments struct {
 
 type CreatingAppointments struct {
 	PropertyId      uuid.UUID `json:"property_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
-	OwnerUserId     uuid.UUID `json:"owner_user_id"     example:"123e4567-e89b-12d3-a456-426614174000"`
-	DwellerUserId   uuid.UUID `json:"-"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	OwnerUserId     uuid.UUID `json:"-"`
+	DwellerUserId   uuid.UUID `json:"-"`
 	AppointmentDate time.Time `json:"appointment_dates" example:"2024-02-18T11:00:00Z"`
+	DurationMinutes int64     `json:"-"`
 	Note            string    `json:"note"             example:"This is a note"`
 }
 
@@ -41,10 +43,13 @@ type UpdatingAppointmentStatus struct {
 type MyAppointmentRequests struct {
 	UserId uuid.UUID `json:"-"`
 	Order string `json:"-"`
+	Role enums.ActorRoles `json:"-"`
 }
 
 type MyAppointmentResponses struct {
+	OwnerTotal          int64              `json:"owner_total"   example:"2"`
 	OwnerAppointments   []AppointmentLists `json:"owner_appointments"`
+	DwellerTotal        int64              `json:"dweller_total" example:"2"`
 	DwellerAppointments []AppointmentLists `json:"dweller_appointments"`
 }
 
@@ -53,8 +58,9 @@ type AppointmentLists struct {
 	AppointmentId    uuid.UUID                `json:"appointment_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
 	Property         PropertyAppointmentLists `json:"property" gorm:"foreignKey:AppointmentId; references:AppointmentId; embedded"`
 	Owner            OwnerAppointmentLists    `json:"owner" gorm:"foreignKey:AppointmentId; references:AppointmentId; embedded"`
-	AppointmentDate  time.Time                `json:"appointment_date" example:"2024-02-18T11:00:00Z"`
-	Status           enums.AppointmentStatus  `json:"status"           example:"PENDING"`
+	AppointmentDate  time.Time                `json:"appointment_date" example:"2024-02-18T11:00:00Z" sortmapper:"a.appointment_date" filtermapper:"a.appointment_date"`
+	DurationMinutes  int64                    `json:"duration_minutes" example:"30"`
+	Status           enums.AppointmentStatus  `json:"status"           example:"PENDING" filtermapper:"a.status::TEXT"`
 	Note             string                   `json:"note"             example:"This is a note"`
 	CancelledMessage string                   `json:"cancelled_message" example:"This is a cancelled message"`
 	CommonModels
@@ -62,7 +68,7 @@ type AppointmentLists struct {
 
 type PropertyAppointmentLists struct {
 	AppointmentId  uuid.UUID                   `json:"-"`
-	PropertyId     uuid.UUID                   `json:"property_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyId     uuid.UUID                   `json:"property_id" example:"123e4567-e89b-12d3-a456-426614174000" filtermapper:"a.property_id"`
 	PropertyName   string                      `json:"property_name" example:"The Base Sukhumvit 77"`
 	PropertyType   enums.PropertyTypes         `json:"property_type" example:"CONDO"`
 	PropertyImages []PropertyImageAppointments `json:"property_images" gorm:"foreignKey:AppointmentId; references:AppointmentId"`
@@ -89,9 +95,11 @@ type AppointmentDetails struct {
 	Owner            OwnerAppointmentDetails    `json:"owner" gorm:"foreignKey:AppointmentId; references:AppointmentId; embedded"`
 	Dweller          DwellerAppointmentDetails  `json:"dweller" gorm:"foreignKey:AppointmentId; references:AppointmentId; embedded"`
 	AppointmentDate  time.Time                  `json:"appointment_date" example:"2024-02-18T11:00:00Z"`
+	DurationMinutes  int64                      `json:"duration_minutes" example:"30"`
 	Status           enums.AppointmentStatus    `json:"status"           example:"PENDING"`
 	Note             string                     `json:"note"             example:"This is a note"`
 	CancelledMessage string                     `json:"cancelled_message" example:"This is a cancelled message"`
+	DwellerAttendance *AttendanceStatistics     `json:"dweller_attendance,omitempty" gorm:"-"`
 	CommonModels
 }
 
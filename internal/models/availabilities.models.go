@@ -0,0 +1,131 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClockTimes is a time of day stored as minutes since midnight. It is written
+// as "HH:MM" in json and as a TIME column in the database.
+type ClockTimes int64
+
+func ParseClockTime(value string) (ClockTimes, error) {
+	for _, layout := range []string{"15:04", "15:04:05", "15:04:05.999999"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return ClockTimes(t.Hour()*60 + t.Minute()), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid time of day: %v", value)
+}
+
+func (c ClockTimes) String() string {
+	return fmt.Sprintf("%02d:%02d", c/60, c%60)
+}
+
+func (c ClockTimes) Minutes() time.Duration {
+	return time.Duration(c) * time.Minute
+}
+
+func (c ClockTimes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+func (c *ClockTimes) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := ParseClockTime(value)
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+	return nil
+}
+
+func (c ClockTimes) Value() (driver.Value, error) {
+	return c.String() + ":00", nil
+}
+
+func (c *ClockTimes) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		*c = ClockTimes(v.Hour()*60 + v.Minute())
+		return nil
+
+	case []byte:
+		return c.Scan(string(v))
+
+	case string:
+		parsed, err := ParseClockTime(v)
+		if err != nil {
+			return err
+		}
+
+		*c = parsed
+		return nil
+	}
+
+	return fmt.Errorf("could not scan %T into ClockTimes", value)
+}
+
+type PropertyAvailabilities struct {
+	AvailabilityId uuid.UUID  `json:"availability_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyId     uuid.UUID  `json:"-"`
+	DayOfWeek      int64      `json:"day_of_week"     example:"1"`
+	StartTime      ClockTimes `json:"start_time"      example:"09:00" swaggertype:"string"`
+	EndTime        ClockTimes `json:"end_time"        example:"17:00" swaggertype:"string"`
+	SlotDuration   int64      `json:"slot_duration"   example:"30"`
+	CreatedAt      *time.Time `json:"-" gorm:"autoCreateTime"`
+	UpdatedAt      *time.Time `json:"-" gorm:"autoUpdateTime"`
+}
+
+func (p PropertyAvailabilities) TableName() string {
+	return "property_availabilities"
+}
+
+type PropertyAvailabilityExceptions struct {
+	ExceptionId   uuid.UUID   `json:"exception_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyId    uuid.UUID   `json:"-"`
+	ExceptionDate time.Time   `json:"exception_date" example:"2024-02-18T00:00:00Z"`
+	StartTime     *ClockTimes `json:"start_time"     example:"12:00" swaggertype:"string"`
+	EndTime       *ClockTimes `json:"end_time"       example:"13:00" swaggertype:"string"`
+	IsAvailable   bool        `json:"is_available"   example:"false"`
+	SlotDuration  *int64      `json:"slot_duration"  example:"30"`
+	Note          string      `json:"note"           example:"Lunch break" gorm:"default:null"`
+	CreatedAt     *time.Time  `json:"-" gorm:"autoCreateTime"`
+}
+
+func (p PropertyAvailabilityExceptions) TableName() string {
+	return "property_availability_exceptions"
+}
+
+type UpdatingPropertyAvailabilities struct {
+	Availabilities []PropertyAvailabilities `json:"availabilities"`
+}
+
+type CreatingAvailabilityExceptions struct {
+	ExceptionDate string      `json:"exception_date" example:"2024-02-18"`
+	StartTime     *ClockTimes `json:"start_time"     example:"12:00" swaggertype:"string"`
+	EndTime       *ClockTimes `json:"end_time"       example:"13:00" swaggertype:"string"`
+	IsAvailable   bool        `json:"is_available"   example:"false"`
+	SlotDuration  *int64      `json:"slot_duration"  example:"30"`
+	Note          string      `json:"note"           example:"Lunch break"`
+}
+
+type PropertyAvailabilityResponses struct {
+	Availabilities []PropertyAvailabilities         `json:"availabilities"`
+	Exceptions     []PropertyAvailabilityExceptions `json:"exceptions"`
+}
+
+type AppointmentSlots struct {
+	StartTime time.Time `json:"start_time" example:"2024-02-18T09:00:00+07:00"`
+	EndTime   time.Time `json:"end_time"   example:"2024-02-18T09:30:00+07:00"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CalendarFeeds struct {
+	UserId    uuid.UUID  `json:"-"`
+	Token     string     `json:"-"`
+	FeedUrl   string     `json:"feed_url"   example:"http://localhost:8000/api/v1/calendar/0123456789abcdef.ics" gorm:"-"`
+	CreatedAt *time.Time `json:"created_at" example:"2024-02-18T11:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (c CalendarFeeds) TableName() string {
+	return "calendar_feeds"
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type CreatingCheckouts struct {
+	PaymentId uuid.UUID
+	Name      string
+	Amount    float64
+}
+
+type CheckoutSessions struct {
+	SessionId string
+	Url       string
+	Status    enums.CheckoutStatus
+}
+
+// CheckoutEvents are what a payment provider tells through its webhook about a
+// checkout session. Events the platform does not act on have no status.
+type CheckoutEvents struct {
+	SessionId string               `json:"session_id" example:"fake_cs_123e4567-e89b-12d3-a456-426614174000"`
+	Status    enums.CheckoutStatus `json:"status"     example:"PAID"`
+}
+
+type CreatedPayments struct {
+	PaymentId   uuid.UUID `json:"payment_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	CheckoutUrl string    `json:"checkout_url" example:"https://checkout.stripe.com/c/pay/cs_test_a1b2c3"`
+}
@@ -15,3 +15,51 @@ type VerificationEmails struct {
 func (v VerificationEmails) Path() string {
 	return "internal/templates/VerificationEmail.html"
 }
+
+type AppointmentReminderEmails struct {
+	FirstName              string
+	PropertyName           string
+	AppointmentDate        string
+	Location               string
+	CounterpartRole        string
+	CounterpartName        string
+	CounterpartPhoneNumber string
+}
+
+func (a AppointmentReminderEmails) Path() string {
+	return "internal/templates/AppointmentReminderEmail.html"
+}
+
+type AppointmentExpiredEmails struct {
+	FirstName       string
+	PropertyName    string
+	AppointmentDate string
+}
+
+func (a AppointmentExpiredEmails) Path() string {
+	return "internal/templates/AppointmentExpiredEmail.html"
+}
+
+type AppointmentCancelledEmails struct {
+	FirstName       string
+	PropertyName    string
+	AppointmentDate string
+	Reason          string
+}
+
+func (a AppointmentCancelledEmails) Path() string {
+	return "internal/templates/AppointmentCancelledEmail.html"
+}
+
+type AgreementOverdueEmails struct {
+	FirstName       string
+	PropertyName    string
+	IsOwner         bool
+	CounterpartName string
+	DueDate         string
+	OverdueAmount   string
+}
+
+func (a AgreementOverdueEmails) Path() string {
+	return "internal/templates/AgreementOverdueEmail.html"
+}
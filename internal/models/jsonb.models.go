@@ -0,0 +1,19 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func scanJSONB(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return fmt.Errorf("could not scan %T into jsonb column", value)
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+// MaintenanceTickets are repairs the dweller of a renting agreement asks the
+// owner for. Every change of status is kept as an update.
+type MaintenanceTickets struct {
+	TicketId        uuid.UUID                   `json:"ticket_id"          example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementId     uuid.UUID                   `json:"agreement_id"       example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedByUserId uuid.UUID                   `json:"created_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Title           string                      `json:"title"              example:"Bedroom air-con is leaking"`
+	Description     string                      `json:"description"        example:"Water drips from the indoor unit whenever it runs"`
+	Category        enums.MaintenanceCategories `json:"category"           example:"AIR_CONDITIONING"`
+	Priority        enums.MaintenancePriorities `json:"priority"           example:"HIGH"`
+	Status          enums.MaintenanceStatus     `json:"status"             example:"SCHEDULED"`
+	ScheduledAt     *time.Time                  `json:"scheduled_at"       example:"2024-02-20T09:00:00Z"`
+	ResolvedAt      *time.Time                  `json:"resolved_at"        example:"2024-02-20T11:00:00Z"`
+	CreatedAt       time.Time                   `json:"created_at"         example:"2024-02-17T10:00:00Z" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time                   `json:"updated_at"         example:"2024-02-18T10:00:00Z" gorm:"autoUpdateTime"`
+	Photos          []MaintenanceTicketPhotos   `json:"photos"             gorm:"foreignKey:TicketId; references:TicketId"`
+	Updates         []MaintenanceTicketUpdates  `json:"updates"            gorm:"foreignKey:TicketId; references:TicketId"`
+}
+
+func (m MaintenanceTickets) TableName() string {
+	return "maintenance_tickets"
+}
+
+func (m *MaintenanceTickets) ToOutBound() *OutBoundMessages {
+	tmp := *m
+	return &OutBoundMessages{
+		Event:   enums.OUTBOUND_TICKET,
+		Payload: tmp,
+	}
+}
+
+type MaintenanceTicketPhotos struct {
+	TicketId  uuid.UUID `json:"-"`
+	ImageUrl  string    `json:"image_url" example:"https://image_url.com/abcd"`
+	CreatedAt time.Time `json:"-"         gorm:"autoCreateTime"`
+}
+
+func (m MaintenanceTicketPhotos) TableName() string {
+	return "maintenance_ticket_photos"
+}
+
+type MaintenanceTicketUpdates struct {
+	UpdateId        uuid.UUID               `json:"update_id"          example:"123e4567-e89b-12d3-a456-426614174000"`
+	TicketId        uuid.UUID               `json:"-"`
+	FromStatus      enums.MaintenanceStatus `json:"from_status"        example:"ACKNOWLEDGED"`
+	ToStatus        enums.MaintenanceStatus `json:"to_status"          example:"SCHEDULED"`
+	ScheduledAt     *time.Time              `json:"scheduled_at"       example:"2024-02-20T09:00:00Z"`
+	Note            string                  `json:"note"               example:"The technician will come on Tuesday morning" gorm:"default:null"`
+	UpdatedByUserId *uuid.UUID              `json:"updated_by_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CreatedAt       time.Time               `json:"created_at"         example:"2024-02-18T10:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (m MaintenanceTicketUpdates) TableName() string {
+	return "maintenance_ticket_updates"
+}
+
+type CreatingMaintenanceTickets struct {
+	Title       string                      `form:"title"       example:"Bedroom air-con is leaking"`
+	Description string                      `form:"description" example:"Water drips from the indoor unit whenever it runs"`
+	Category    enums.MaintenanceCategories `form:"category"    example:"AIR_CONDITIONING"`
+	Priority    enums.MaintenancePriorities `form:"priority"    example:"HIGH"`
+}
+
+type UpdatingMaintenanceTickets struct {
+	Status      enums.MaintenanceStatus `json:"status"       example:"SCHEDULED"`
+	ScheduledAt *time.Time              `json:"scheduled_at" example:"2024-02-20T09:00:00Z"`
+	Note        string                  `json:"note"         example:"The technician will come on Tuesday morning"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserNotificationPreferences struct {
+	UserId               uuid.UUID  `json:"-"`
+	AppointmentReminders bool       `json:"appointment_reminders" example:"true"`
+	AppointmentUpdates   bool       `json:"appointment_updates"   example:"true"`
+	UpdatedAt            *time.Time `json:"-"                     gorm:"autoUpdateTime"`
+}
+
+func (n UserNotificationPreferences) TableName() string {
+	return "user_notification_preferences"
+}
+
+// UpdatingUserNotificationPreferences leaves the preferences that are not in
+// the body unchanged.
+type UpdatingUserNotificationPreferences struct {
+	AppointmentReminders *bool `json:"appointment_reminders" example:"false"`
+	AppointmentUpdates   *bool `json:"appointment_updates"   example:"true"`
+}
+
+// NotificationRecipients is a user together with the notifications they agreed
+// to receive.
+type NotificationRecipients struct {
+	UserId             uuid.UUID
+	Email              string
+	FirstName          string
+	AppointmentUpdates bool
+}
+
+// AppointmentReminders records a reminder that has been sent, so that the same
+// reminder is never sent twice. Rescheduling an appointment changes its date
+// which makes its reminders due again.
+type AppointmentReminders struct {
+	AppointmentId   uuid.UUID
+	UserId          uuid.UUID
+	OffsetSeconds   int64
+	AppointmentDate time.Time
+	SentAt          *time.Time `gorm:"autoCreateTime"`
+}
+
+func (a AppointmentReminders) TableName() string {
+	return "appointment_reminders"
+}
+
+// DueAppointmentReminders is a reminder that should be sent to one party of an
+// appointment, together with what the email needs about the other party.
+type DueAppointmentReminders struct {
+	AppointmentId          uuid.UUID
+	AppointmentDate        time.Time
+	DurationMinutes        int64
+	PropertyName           string
+	Location               string
+	RecipientUserId        uuid.UUID
+	RecipientEmail         string
+	RecipientFirstName     string
+	RecipientIsOwner       bool
+	CounterpartFirstName   string
+	CounterpartLastName    string
+	CounterpartPhoneNumber string
+}
@@ -1,21 +1,46 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
 
 // payment_id UUID PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
 // user_id    UUID REFERENCES users(user_id)              NOT NULL,
 // price     DOUBLE PRECISION                           NOT NULL,
 // IsSuccess BOOLEAN                                    NOT NULL,
+// agreement_id UUID REFERENCES agreements(agreement_id)  DEFAULT NULL,
+// payment_type payment_types                            DEFAULT NULL,
+// installment_id UUID REFERENCES agreement_installments(installment_id) DEFAULT NULL,
+// recipient_user_id UUID REFERENCES users(user_id)       DEFAULT NULL,
+// checkout_session_id VARCHAR(255) UNIQUE                DEFAULT NULL,
+// refunded_at TIMESTAMP(0) WITH TIME ZONE               DEFAULT NULL,
+// paid_at TIMESTAMP(0) WITH TIME ZONE                   DEFAULT NULL,
+// rent_amount DOUBLE PRECISION                          DEFAULT NULL,
+// late_fee_amount DOUBLE PRECISION                      DEFAULT NULL,
+// utility_amount DOUBLE PRECISION                       DEFAULT NULL,
 // created_at TIMESTAMP(0) WITH TIME ZONE                DEFAULT CURRENT_TIMESTAMP,
 // updated_at TIMESTAMP(0) WITH TIME ZONE                DEFAULT CURRENT_TIMESTAMP,
 // deleted_at TIMESTAMP(0) WITH TIME ZONE                DEFAULT NULL
 
 type Payments struct {
-	PaymentId uuid.UUID `json:"payment_id" `
-	UserId    uuid.UUID `json:"user_id" `
-	Price     float64   `json:"price" `
-	IsSuccess bool      `json:"is_success"`
-	Name      string    `json:"name"`
+	PaymentId         uuid.UUID          `json:"payment_id" `
+	UserId            uuid.UUID          `json:"user_id" `
+	Price             float64            `json:"price" `
+	IsSuccess         bool               `json:"is_success"`
+	Name              string             `json:"name"`
+	AgreementId       *uuid.UUID         `json:"agreement_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PaymentType       enums.PaymentTypes `json:"payment_type" example:"DEPOSIT"`
+	InstallmentId     *uuid.UUID         `json:"installment_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	RecipientUserId   *uuid.UUID         `json:"recipient_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	CheckoutSessionId *string            `json:"-"`
+	RefundedAt        *time.Time         `json:"refunded_at" example:"2024-02-22T03:06:53.313735Z"`
+	PaidAt            *time.Time         `json:"paid_at" example:"2024-02-22T03:06:53.313735Z"`
+	RentAmount        *float64           `json:"-"`
+	LateFeeAmount     *float64           `json:"-"`
+	UtilityAmount     *float64           `json:"-"`
 	CommonModels
 }
 
@@ -6,30 +6,31 @@ import (
 )
 
 type Properties struct {
-	PropertyId          uuid.UUID            `json:"property_id" gorm:"type:uuid;unique;primaryKey;default:uuid_generate_v4()" example:"123e4567-e89b-12d3-a456-426614174000"`
-	OwnerId             uuid.UUID            `json:"owner_id"                  example:"123e4567-e89b-12d3-a456-426614174000"`
-	PropertyName        string               `json:"property_name"             example:"Supalai"`
-	PropertyDescription string               `json:"property_description"      example:"Et sequi dolor praes"`
-	PropertyType        enums.PropertyTypes  `json:"property_type"             example:"CONDOMINIUM"`
-	Address             string               `json:"address"                   example:"123/4"`
-	Alley               string               `json:"alley" gorm:"default:null" example:"Pattaya Nua 78"`
-	Street              string               `json:"street"                    example:"Pattaya"`
-	SubDistrict         string               `json:"sub_district"              example:"Bang Bon"`
-	District            string               `json:"district"                  example:"Bang Phli"`
-	Province            string               `json:"province"                  example:"Pattaya"`
-	Country             string               `json:"country"                   example:"Thailand"`
-	PostalCode          string               `json:"postal_code"               example:"69096"`
-	Bedrooms            int64                `json:"bedrooms"                  example:"3"      filtermapper:"bedrooms"`
-	Bathrooms           int64                `json:"bathrooms"                 example:"2"      filtermapper:"bathrooms"`
-	Furnishing          enums.Furnishing     `json:"furnishing"                example:"UNFURNISHED"`
-	Floor               int64                `json:"floor"                     example:"5"      sortmapper:"floor"`
-	FloorSize           float64              `json:"floor_size"                example:"123.45" filtermapper:"floor_size"`
-	FloorSizeUnit       enums.FloorSizeUnits `json:"floor_size_unit"           gorm:"default:SQM" example:"SQM"`
-	UnitNumber          int64                `json:"unit_number"               example:"123"`
-	PropertyImages      []PropertyImages     `gorm:"foreignKey:PropertyId; references:PropertyId" json:"property_images"`
-	SellingProperty     SellingProperties    `gorm:"foreignKey:PropertyId; references:PropertyId; embedded" json:"selling_property"`
-	RentingProperty     RentingProperties    `gorm:"foreignKey:PropertyId; references:PropertyId; embedded" json:"renting_property"`
-	IsFavorite          bool                 `json:"is_favorite" gorm:"default:false" example:"true"`
+	PropertyId          uuid.UUID             `json:"property_id" gorm:"type:uuid;unique;primaryKey;default:uuid_generate_v4()" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OwnerId             uuid.UUID             `json:"owner_id"                  example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyName        string                `json:"property_name"             example:"Supalai"`
+	PropertyDescription string                `json:"property_description"      example:"Et sequi dolor praes"`
+	PropertyType        enums.PropertyTypes   `json:"property_type"             example:"CONDOMINIUM"`
+	Address             string                `json:"address"                   example:"123/4"`
+	Alley               string                `json:"alley" gorm:"default:null" example:"Pattaya Nua 78"`
+	Street              string                `json:"street"                    example:"Pattaya"`
+	SubDistrict         string                `json:"sub_district"              example:"Bang Bon"`
+	District            string                `json:"district"                  example:"Bang Phli"`
+	Province            string                `json:"province"                  example:"Pattaya"`
+	Country             string                `json:"country"                   example:"Thailand"`
+	PostalCode          string                `json:"postal_code"               example:"69096"`
+	Bedrooms            int64                 `json:"bedrooms"                  example:"3"      filtermapper:"bedrooms"`
+	Bathrooms           int64                 `json:"bathrooms"                 example:"2"      filtermapper:"bathrooms"`
+	Furnishing          enums.Furnishing      `json:"furnishing"                example:"UNFURNISHED"`
+	Floor               int64                 `json:"floor"                     example:"5"      sortmapper:"floor"`
+	FloorSize           float64               `json:"floor_size"                example:"123.45" filtermapper:"floor_size"`
+	FloorSizeUnit       enums.FloorSizeUnits  `json:"floor_size_unit"           gorm:"default:SQM" example:"SQM"`
+	UnitNumber          int64                 `json:"unit_number"               example:"123"`
+	PropertyImages      []PropertyImages      `gorm:"foreignKey:PropertyId; references:PropertyId" json:"property_images"`
+	Attachments         []PropertyAttachments `gorm:"foreignKey:PropertyId; references:PropertyId" json:"attachments,omitempty"`
+	SellingProperty     SellingProperties     `gorm:"foreignKey:PropertyId; references:PropertyId; embedded" json:"selling_property"`
+	RentingProperty     RentingProperties     `gorm:"foreignKey:PropertyId; references:PropertyId; embedded" json:"renting_property"`
+	IsFavorite          bool                  `json:"is_favorite" gorm:"default:false" example:"true"`
 	CommonModels
 }
 
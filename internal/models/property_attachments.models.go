@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type PropertyAttachments struct {
+	AttachmentId   uuid.UUID                     `json:"attachment_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyId     uuid.UUID                     `json:"-"`
+	AttachmentType enums.PropertyAttachmentTypes `json:"attachment_type" example:"FLOOR_PLAN"`
+	Title          string                        `json:"title"           example:"Floor plan"`
+	Url            string                        `json:"url"             example:"https://attachment_url.com/abcd.pdf"`
+	ContentType    string                        `json:"content_type"    gorm:"default:null" example:"application/pdf"`
+	DisplayOrder   int64                         `json:"display_order"   example:"1"`
+	CommonModels   `sortmapper:"-"`
+}
+
+func (p PropertyAttachments) TableName() string {
+	return "property_attachments"
+}
+
+type CreatingPropertyAttachments struct {
+	AttachmentType enums.PropertyAttachmentTypes `form:"attachment_type" example:"TOUR_URL"`
+	Title          string                        `form:"title"           example:"360 tour"`
+	Url            string                        `form:"url"             example:"https://tour_url.com/abcd"`
+}
+
+type ReorderingPropertyAttachments struct {
+	AttachmentIds []uuid.UUID `json:"attachment_ids" example:"123e4567-e89b-12d3-a456-426614174000,123e4567-e89b-12d3-a456-426614174001"`
+}
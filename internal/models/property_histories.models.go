@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type PropertyHistories struct {
+	HistoryId           uuid.UUID                    `json:"history_id"              example:"123e4567-e89b-12d3-a456-426614174000" gorm:"default:gen_random_uuid()"`
+	PropertyId          uuid.UUID                    `json:"property_id"             example:"123e4567-e89b-12d3-a456-426614174000"`
+	ActorUserId         *uuid.UUID                   `json:"actor_user_id"           example:"123e4567-e89b-12d3-a456-426614174000"`
+	ActorFirstName      string                       `json:"actor_first_name"        example:"John" gorm:"->"`
+	ActorLastName       string                       `json:"actor_last_name"         example:"Doe"  gorm:"->"`
+	Action              enums.PropertyHistoryActions `json:"action"                  example:"UPDATE"`
+	Version             int64                        `json:"version"                 example:"2"`
+	RevertedFromVersion *int64                       `json:"reverted_from_version"   example:"1" gorm:"default:null"`
+	Changes             FieldChanges                 `json:"changes"`
+	Snapshot            PropertySnapshots            `json:"snapshot"`
+	CreatedAt           *time.Time                   `json:"created_at"              example:"2024-02-18T11:00:00Z" gorm:"autoCreateTime"`
+}
+
+func (p PropertyHistories) TableName() string {
+	return "property_histories"
+}
+
+// FieldChanges maps a json field name to its value before and after a change.
+type FieldChanges map[string]FieldChangeItems
+
+type FieldChangeItems struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+func (f FieldChanges) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+func (f *FieldChanges) Scan(value interface{}) error {
+	return scanJSONB(value, f)
+}
+
+// PropertySnapshots is the full state of a property, including its selling,
+// renting and image rows, right after a change was applied.
+type PropertySnapshots PropertyInfos
+
+func (p PropertySnapshots) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *PropertySnapshots) Scan(value interface{}) error {
+	return scanJSONB(value, p)
+}
+
+type PropertyHistoriesResponses struct {
+	Total     int64               `json:"total" example:"2"`
+	Histories []PropertyHistories `json:"histories"`
+}
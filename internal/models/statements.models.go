@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+// StatementPayments are the payments the owner of renting agreements received,
+// along with how a rent payment was split when it was paid. A payment refunded
+// since comes back once more as a refund, dated when it was refunded.
+type StatementPayments struct {
+	PaymentId     uuid.UUID
+	PaymentType   enums.PaymentTypes
+	Price         float64
+	PaidAt        time.Time
+	RefundedAt    *time.Time
+	Refunded      bool
+	AgreementId   uuid.UUID
+	PropertyId    uuid.UUID
+	PropertyName  string
+	RentAmount    *float64
+	LateFeeAmount *float64
+	UtilityAmount *float64
+}
+
+// StatementHeldDeposits is what an owner holds in deposits for a property at
+// some point in time.
+type StatementHeldDeposits struct {
+	PropertyId   uuid.UUID
+	PropertyName string
+	Amount       float64
+}
+
+// StatementAmounts splits what an owner received. Rental income is the rent,
+// late fees and utilities collected, less what was refunded. Deposits are held
+// for the dweller, so they are kept apart from the income, and deposits held is
+// the balance at the end of the period.
+type StatementAmounts struct {
+	RentCollected    float64 `json:"rent_collected"    example:"45000"`
+	LateFees         float64 `json:"late_fees"         example:"750"`
+	Utilities        float64 `json:"utilities"         example:"3120.5"`
+	RentalIncome     float64 `json:"rental_income"     example:"48870.5"`
+	DepositsReceived float64 `json:"deposits_received" example:"30000"`
+	DepositsRefunded float64 `json:"deposits_refunded" example:"25500"`
+	DepositsHeld     float64 `json:"deposits_held"     example:"4500"`
+}
+
+type PropertyStatements struct {
+	PropertyId   uuid.UUID `json:"property_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyName string    `json:"property_name" example:"Supalai Place"`
+	StatementAmounts
+}
+
+type MonthlyStatements struct {
+	Year       int                  `json:"year"       example:"2024"`
+	Month      int                  `json:"month"      example:"2"`
+	Properties []PropertyStatements `json:"properties"`
+	Total      StatementAmounts     `json:"total"`
+}
+
+type AnnualStatements struct {
+	Year       int                  `json:"year"       example:"2024"`
+	Months     []MonthlyStatements  `json:"months"`
+	Properties []PropertyStatements `json:"properties"`
+	Total      StatementAmounts     `json:"total"`
+}
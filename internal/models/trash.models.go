@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
+)
+
+type TrashedProperties struct {
+	PropertyId   uuid.UUID           `json:"property_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	OwnerId      uuid.UUID           `json:"owner_id"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyName string              `json:"property_name" example:"Supalai"`
+	PropertyType enums.PropertyTypes `json:"property_type" example:"CONDOMINIUM"`
+	Restorable   bool                `json:"restorable"    example:"true"`
+	DeletedAt    time.Time           `json:"deleted_at"    example:"2024-02-18T11:00:00Z"`
+	PurgeAt      time.Time           `json:"purge_at"      example:"2024-03-19T11:00:00Z" gorm:"-"`
+}
+
+type TrashedAppointments struct {
+	AppointmentId   uuid.UUID               `json:"appointment_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyId      uuid.UUID               `json:"property_id"      example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyName    string                  `json:"property_name"    example:"Supalai"`
+	OwnerUserId     uuid.UUID               `json:"owner_user_id"    example:"123e4567-e89b-12d3-a456-426614174000"`
+	DwellerUserId   uuid.UUID               `json:"dweller_user_id"  example:"123e4567-e89b-12d3-a456-426614174000"`
+	AppointmentDate time.Time               `json:"appointment_date" example:"2024-02-18T11:00:00Z"`
+	Status          enums.AppointmentStatus `json:"status"           example:"PENDING"`
+	Restorable      bool                    `json:"restorable"       example:"true"`
+	DeletedAt       time.Time               `json:"deleted_at"       example:"2024-02-18T11:00:00Z"`
+	PurgeAt         time.Time               `json:"purge_at"         example:"2024-03-19T11:00:00Z" gorm:"-"`
+}
+
+type TrashedAgreements struct {
+	AgreementId   uuid.UUID             `json:"agreement_id"    example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementType enums.AgreementTypes  `json:"agreement_type"  example:"SELLING"`
+	PropertyId    uuid.UUID             `json:"property_id"     example:"123e4567-e89b-12d3-a456-426614174000"`
+	PropertyName  string                `json:"property_name"   example:"Supalai"`
+	OwnerUserId   uuid.UUID             `json:"owner_user_id"   example:"123e4567-e89b-12d3-a456-426614174000"`
+	DwellerUserId uuid.UUID             `json:"dweller_user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	AgreementDate time.Time             `json:"agreement_date"  example:"2024-02-18T11:00:00Z"`
+	Status        enums.AgreementStatus `json:"status"          example:"AWAITING_DEPOSIT"`
+	Restorable    bool                  `json:"restorable"      example:"true"`
+	DeletedAt     time.Time             `json:"deleted_at"      example:"2024-02-18T11:00:00Z"`
+	PurgeAt       time.Time             `json:"purge_at"        example:"2024-03-19T11:00:00Z" gorm:"-"`
+}
+
+type TrashedUsers struct {
+	UserId     uuid.UUID `json:"user_id"    example:"123e4567-e89b-12d3-a456-426614174000"`
+	Email      string    `json:"email"      example:"admin@email.com"`
+	FirstName  string    `json:"first_name" example:"John"`
+	LastName   string    `json:"last_name"  example:"Doe"`
+	Restorable bool      `json:"restorable" example:"true"`
+	DeletedAt  time.Time `json:"deleted_at" example:"2024-02-18T11:00:00Z"`
+	PurgeAt    time.Time `json:"purge_at"   example:"2024-03-19T11:00:00Z" gorm:"-"`
+}
+
+type TrashResponses struct {
+	Properties   []TrashedProperties   `json:"properties"`
+	Appointments []TrashedAppointments `json:"appointments"`
+	Agreements   []TrashedAgreements   `json:"agreements"`
+	Users        []TrashedUsers        `json:"users,omitempty"`
+}
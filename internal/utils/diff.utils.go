@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+)
+
+// DiffFields compares two values of the same struct type field by field and
+// returns the changed fields keyed by their json name. A nil old or new value
+// is treated as the zero value of the other one's type.
+func DiffFields(old interface{}, new interface{}) models.FieldChanges {
+	changes := models.FieldChanges{}
+
+	oldValue, newValue := indirectValue(old), indirectValue(new)
+	if !oldValue.IsValid() && !newValue.IsValid() {
+		return changes
+	} else if !oldValue.IsValid() {
+		oldValue = reflect.Zero(newValue.Type())
+	} else if !newValue.IsValid() {
+		newValue = reflect.Zero(oldValue.Type())
+	}
+
+	t := oldValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, _ := SplitByFirstString(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" || len(name) == 0 {
+			continue
+		}
+
+		oldField, newField := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if isEmptyValue(oldValue.Field(i)) && isEmptyValue(newValue.Field(i)) {
+			continue
+		}
+
+		if !reflect.DeepEqual(oldField, newField) {
+			changes[strings.TrimSpace(name)] = models.FieldChangeItems{
+				Old: oldField,
+				New: newField,
+			}
+		}
+	}
+
+	return changes
+}
+
+func indirectValue(v interface{}) reflect.Value {
+	value := reflect.ValueOf(v)
+	for value.IsValid() && value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brain-flowing-company/pprp-backend/internal/models"
+)
+
+type diffedFields struct {
+	Name     string   `json:"name"`
+	Price    float64  `json:"price,omitempty"`
+	Tags     []string `json:"tags"`
+	Hidden   string   `json:"-"`
+	Untagged string
+	internal string
+}
+
+func TestDiffFields(t *testing.T) {
+	tests := []struct {
+		name string
+		old  interface{}
+		new  interface{}
+		want models.FieldChanges
+	}{
+		{
+			name: "unchanged",
+			old:  diffedFields{Name: "Condo", Price: 10},
+			new:  &diffedFields{Name: "Condo", Price: 10},
+			want: models.FieldChanges{},
+		},
+		{
+			name: "changed fields keyed by json name",
+			old:  &diffedFields{Name: "Condo", Price: 10},
+			new:  &diffedFields{Name: "House", Price: 12.5},
+			want: models.FieldChanges{
+				"name":  {Old: "Condo", New: "House"},
+				"price": {Old: 10.0, New: 12.5},
+			},
+		},
+		{
+			name: "ignores hidden, untagged and unexported fields",
+			old:  diffedFields{Hidden: "a", Untagged: "a", internal: "a"},
+			new:  diffedFields{Hidden: "b", Untagged: "b", internal: "b"},
+			want: models.FieldChanges{},
+		},
+		{
+			name: "nil and empty slices are the same",
+			old:  diffedFields{Tags: nil},
+			new:  diffedFields{Tags: []string{}},
+			want: models.FieldChanges{},
+		},
+		{
+			name: "nil old is the zero value",
+			old:  (*diffedFields)(nil),
+			new:  &diffedFields{Name: "Condo"},
+			want: models.FieldChanges{
+				"name": {Old: "", New: "Condo"},
+			},
+		},
+		{
+			name: "nil new is the zero value",
+			old:  &diffedFields{Tags: []string{"pool"}},
+			new:  nil,
+			want: models.FieldChanges{
+				"tags": {Old: []string{"pool"}, New: []string(nil)},
+			},
+		},
+		{
+			name: "both nil",
+			old:  nil,
+			new:  nil,
+			want: models.FieldChanges{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := DiffFields(test.old, test.new)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("DiffFields() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,21 +1,35 @@
 package utils
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/brain-flowing-company/pprp-backend/internal/enums"
+	"github.com/google/uuid"
 )
 
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+// FilteredQuery turns `<field>[<opt>]:<value>` filters into SQL conditions.
+// Numbers are written into the SQL as they are, while text, uuid and time
+// values are passed as named arguments which must be given to the query along
+// with FilteredArgs.
 type FilteredQuery struct {
 	items  []string
+	args   []interface{}
 	mapper map[string]string
+	types  map[string]reflect.Type
 }
 
 func NewFilteredQuery(model interface{}) *FilteredQuery {
-	s := &FilteredQuery{mapper: map[string]string{}}
+	s := &FilteredQuery{mapper: map[string]string{}, types: map[string]reflect.Type{}}
 	t := reflect.TypeOf(model)
 
 	parents := NewStack[string]()
@@ -54,6 +68,7 @@ func (s *FilteredQuery) assignMapper(parents *Stack[string], t reflect.Type) {
 		parents.Push(json)
 		key := strings.Join(parents.Seek(), ".")
 		s.mapper[key] = sortmap
+		s.types[key] = f.Type
 		parents.Pop()
 	}
 }
@@ -74,7 +89,10 @@ func (s *FilteredQuery) ParseQuery(query string) error {
 			case ']':
 				cb = i
 			case ':':
-				cl = i
+				// values such as times may contain colons themselves
+				if cl == -1 && cb != -1 {
+					cl = i
+				}
 			}
 		}
 
@@ -94,22 +112,67 @@ func (s *FilteredQuery) ParseQuery(query string) error {
 			return errors.New("sort direction can only be 'lte' or 'gte'")
 		}
 
-		var value float32
-		_, err := fmt.Sscanf(filter[cl+1:], "%f", &value)
+		value, err := s.parseValue(fld, filter[cl+1:])
 		if err != nil {
 			return err
 		}
 
-		s.items = append(s.items, fmt.Sprintf("%s %s %f", field, operation, value))
+		s.items = append(s.items, fmt.Sprintf("%s %s %s", field, operation, value))
 	}
 
 	return nil
 }
 
+func (s *FilteredQuery) parseValue(key string, raw string) (string, error) {
+	t := s.types[key]
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			value, err = ParseLocalDate(raw)
+		}
+		if err != nil {
+			return "", fmt.Errorf("'%s' must be a RFC 3339 time or a YYYY-MM-DD date", key)
+		}
+		return s.bind(value), nil
+
+	case t == uuidType:
+		if !IsValidUUID(raw) {
+			return "", fmt.Errorf("'%s' must be a valid uuid", key)
+		}
+		return s.bind(raw), nil
+
+	case t != nil && t.Kind() == reflect.String:
+		return s.bind(raw), nil
+	}
+
+	var value float32
+	_, err := fmt.Sscanf(raw, "%f", &value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%f", value), nil
+}
+
+func (s *FilteredQuery) bind(value interface{}) string {
+	name := fmt.Sprintf("filter_%d", len(s.args))
+	s.args = append(s.args, sql.Named(name, value))
+	return "@" + name
+}
+
 func (s *FilteredQuery) Map(key string, value string) {
 	s.mapper[key] = value
 }
 
+func (s *FilteredQuery) FilteredArgs() []interface{} {
+	return s.args
+}
+
 func (s *FilteredQuery) FilteredSQL() string {
 	if len(s.items) > 0 {
 		return strings.Join(s.items, " AND ")
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type filteredOwners struct {
+	OwnerId uuid.UUID `json:"owner_id" filtermapper:"o.owner_id"`
+}
+
+type filteredLists struct {
+	Price     float64        `json:"price"      filtermapper:"l.price"`
+	Status    string         `json:"status"     filtermapper:"l.status"`
+	CreatedAt time.Time      `json:"created_at" filtermapper:"l.created_at"`
+	DeletedAt *time.Time     `json:"deleted_at" filtermapper:"l.deleted_at"`
+	Owner     filteredOwners `json:"owner"`
+	Hidden    string         `json:"-"          filtermapper:"l.hidden"`
+}
+
+func TestFilteredQueryParseValue(t *testing.T) {
+	ownerId := uuid.New()
+
+	tests := []struct {
+		name    string
+		key     string
+		raw     string
+		want    string
+		wantArg interface{}
+		wantErr bool
+	}{
+		{name: "number is written as is", key: "price", raw: "1500", want: "1500.000000"},
+		{name: "decimal number", key: "price", raw: "12.5", want: "12.500000"},
+		{name: "invalid number", key: "price", raw: "cheap", wantErr: true},
+		{name: "text is bound", key: "status", raw: "PENDING; DROP TABLE", want: "@filter_0", wantArg: "PENDING; DROP TABLE"},
+		{name: "rfc 3339 time is bound", key: "created_at", raw: "2024-02-18T11:00:00Z", want: "@filter_0", wantArg: time.Date(2024, 2, 18, 11, 0, 0, 0, time.UTC)},
+		{name: "date is a local midnight", key: "created_at", raw: "2024-02-18", want: "@filter_0", wantArg: time.Date(2024, 2, 18, 0, 0, 0, 0, LocalTimezone)},
+		{name: "pointer to time", key: "deleted_at", raw: "2024-02-18", want: "@filter_0", wantArg: time.Date(2024, 2, 18, 0, 0, 0, 0, LocalTimezone)},
+		{name: "invalid time", key: "created_at", raw: "yesterday", wantErr: true},
+		{name: "nested uuid is bound", key: "owner.owner_id", raw: ownerId.String(), want: "@filter_0", wantArg: ownerId.String()},
+		{name: "invalid uuid", key: "owner.owner_id", raw: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := NewFilteredQuery(filteredLists{})
+
+			got, err := filtered.parseValue(tt.key, tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			var wantArgs []interface{}
+			if tt.wantArg != nil {
+				wantArgs = []interface{}{sql.Named("filter_0", tt.wantArg)}
+			}
+			if !reflect.DeepEqual(filtered.FilteredArgs(), wantArgs) {
+				t.Errorf("got args %v, want %v", filtered.FilteredArgs(), wantArgs)
+			}
+		})
+	}
+}
+
+func TestFilteredQueryParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{name: "no filters", query: "", want: "TRUE"},
+		{name: "single filter", query: "price[gte]:1000", want: "l.price >= 1000.000000"},
+		{name: "filters are joined", query: "price[gte]:1000,status[eql]:PENDING", want: "l.price >= 1000.000000 AND l.status = @filter_0"},
+		{name: "time with colons", query: "created_at[lte]:2024-02-18T11:00:00Z", want: "l.created_at <= @filter_0"},
+		{name: "unknown field", query: "owner_name[eql]:x", wantErr: true},
+		{name: "hidden field", query: "hidden[eql]:x", wantErr: true},
+		{name: "unknown operation", query: "price[lt]:1000", wantErr: true},
+		{name: "missing operation", query: "price:1000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := NewFilteredQuery(filteredLists{})
+
+			err := filtered.ParseQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && filtered.FilteredSQL() != tt.want {
+				t.Errorf("got %q, want %q", filtered.FilteredSQL(), tt.want)
+			}
+		})
+	}
+}
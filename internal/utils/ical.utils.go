@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ICalendars is a minimal RFC 5545 calendar holding only events.
+type ICalendars struct {
+	Name   string
+	Events []ICalEvents
+}
+
+type ICalEvents struct {
+	Uid          string
+	Sequence     int64
+	Status       string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	CreatedAt    time.Time
+	LastModified time.Time
+}
+
+func (cal *ICalendars) String() string {
+	var b strings.Builder
+
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//Brain Flowing Company//Suechaokhai//EN")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	if cal.Name != "" {
+		writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(cal.Name))
+	}
+
+	now := formatICalTime(time.Now())
+	for _, event := range cal.Events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+event.Uid)
+		writeICalLine(&b, "DTSTAMP:"+now)
+		writeICalLine(&b, "DTSTART:"+formatICalTime(event.Start))
+		writeICalLine(&b, "DTEND:"+formatICalTime(event.End))
+		writeICalLine(&b, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		writeICalLine(&b, "STATUS:"+event.Status)
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(event.Summary))
+		if event.Description != "" {
+			writeICalLine(&b, "DESCRIPTION:"+escapeICalText(event.Description))
+		}
+		if event.Location != "" {
+			writeICalLine(&b, "LOCATION:"+escapeICalText(event.Location))
+		}
+		if !event.CreatedAt.IsZero() {
+			writeICalLine(&b, "CREATED:"+formatICalTime(event.CreatedAt))
+		}
+		if !event.LastModified.IsZero() {
+			writeICalLine(&b, "LAST-MODIFIED:"+formatICalTime(event.LastModified))
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func escapeICalText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeICalLine folds content lines longer than 75 octets as required by the
+// spec, taking care not to split multi-byte characters.
+func writeICalLine(b *strings.Builder, line string) {
+	limit := 75
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+
+		// continuation lines start with a space which counts towards the limit
+		limit = 74
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
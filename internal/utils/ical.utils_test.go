@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteICalLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{
+			name: "short line is kept",
+			line: "SUMMARY:Viewing",
+			want: []string{"SUMMARY:Viewing"},
+		},
+		{
+			name: "exactly 75 octets is kept",
+			line: strings.Repeat("a", 75),
+			want: []string{strings.Repeat("a", 75)},
+		},
+		{
+			name: "76 octets is folded",
+			line: strings.Repeat("a", 76),
+			want: []string{strings.Repeat("a", 75), " a"},
+		},
+		{
+			name: "continuation lines count the leading space",
+			line: strings.Repeat("a", 75+74+1),
+			want: []string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " a"},
+		},
+		{
+			name: "multi-byte characters are not split",
+			line: strings.Repeat("a", 74) + "บ้าน",
+			want: []string{strings.Repeat("a", 74), " บ้าน"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeICalLine(&b, tt.line)
+
+			got := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+
+			for _, line := range got {
+				if len(line) > 75 {
+					t.Errorf("line %q is longer than 75 octets", line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %q splits a character", line)
+				}
+			}
+
+			unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+			if unfolded != tt.line+"\r\n" {
+				t.Errorf("unfolds to %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestEscapeICalText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain", "plain"},
+		{"a, b; c", `a\, b\; c`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines", `two\nlines`},
+		{"two\r\nlines", `two\nlines`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := escapeICalText(tt.text); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,546 @@
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A4 in points, with the same margin all around
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 56.0
+)
+
+const (
+	pdfTitleSize     = 16.0
+	pdfHeadingSize   = 12.5
+	pdfParagraphSize = 10.5
+	pdfLineSpacing   = 1.45
+)
+
+// PDFDocuments lays text out from top to bottom on A4 pages, starting a new page
+// whenever the current one is full. Text is set in Helvetica, which only covers
+// Latin-1, unless a TrueType font is given. That font is embedded whole, so any
+// script it has glyphs for, such as Thai, can be written.
+type PDFDocuments struct {
+	font  pdfFont
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func NewPDFDocument(trueTypeFont []byte) (*PDFDocuments, error) {
+	var font pdfFont = helveticaFont{}
+	if trueTypeFont != nil {
+		ttf, err := parseTrueTypeFont(trueTypeFont)
+		if err != nil {
+			return nil, err
+		}
+
+		font = ttf
+	}
+
+	doc := &PDFDocuments{font: font}
+	doc.AddPage()
+
+	return doc, nil
+}
+
+func (doc *PDFDocuments) AddPage() {
+	doc.pages = append(doc.pages, &bytes.Buffer{})
+	doc.y = pdfPageHeight - pdfMargin
+}
+
+// WriteText writes text with a minimal markup: a line starting with "# " is a
+// centered title, one starting with "## " is a section heading, blank lines
+// separate paragraphs and every other line is a paragraph wrapped to the page.
+func (doc *PDFDocuments) WriteText(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			doc.Space(pdfParagraphSize * 0.6)
+		case strings.HasPrefix(line, "# "):
+			doc.Title(strings.TrimPrefix(line, "# "))
+		case strings.HasPrefix(line, "## "):
+			doc.Heading(strings.TrimPrefix(line, "## "))
+		default:
+			doc.Paragraph(line)
+		}
+	}
+}
+
+func (doc *PDFDocuments) Title(text string) {
+	for _, line := range doc.wrap(text, pdfTitleSize) {
+		x := (pdfPageWidth - doc.textWidth(line, pdfTitleSize)) / 2
+		doc.writeLine(line, x, pdfTitleSize)
+	}
+	doc.Space(pdfTitleSize * 0.5)
+}
+
+func (doc *PDFDocuments) Heading(text string) {
+	doc.Space(pdfHeadingSize * 0.5)
+	for _, line := range doc.wrap(text, pdfHeadingSize) {
+		doc.writeLine(line, pdfMargin, pdfHeadingSize)
+	}
+}
+
+func (doc *PDFDocuments) Paragraph(text string) {
+	for _, line := range doc.wrap(text, pdfParagraphSize) {
+		doc.writeLine(line, pdfMargin, pdfParagraphSize)
+	}
+}
+
+func (doc *PDFDocuments) Space(height float64) {
+	doc.y -= height
+}
+
+func (doc *PDFDocuments) writeLine(text string, x float64, size float64) {
+	lineHeight := size * pdfLineSpacing
+	if doc.y-lineHeight < pdfMargin {
+		doc.AddPage()
+	}
+
+	doc.y -= lineHeight
+	page := doc.pages[len(doc.pages)-1]
+	fmt.Fprintf(page, "BT /F1 %.2f Tf %.2f %.2f Td %v Tj ET\n", size, x, doc.y, doc.font.encode(text))
+}
+
+func (doc *PDFDocuments) textWidth(text string, size float64) float64 {
+	var width float64
+	for _, r := range text {
+		width += doc.font.width(r)
+	}
+
+	return width * size / 1000
+}
+
+// wrap breaks text into lines that fit between the margins. Lines are broken
+// at spaces where possible and anywhere else when a single word is too long,
+// which is also how Thai, written without spaces between words, gets broken.
+func (doc *PDFDocuments) wrap(text string, size float64) []string {
+	maxWidth := pdfPageWidth - 2*pdfMargin
+	spaceWidth := doc.textWidth(" ", size)
+
+	lines := []string{}
+	var line strings.Builder
+	var lineWidth float64
+
+	flush := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		lineWidth = 0
+	}
+
+	for _, word := range strings.Fields(text) {
+		wordWidth := doc.textWidth(word, size)
+		if line.Len() > 0 && lineWidth+spaceWidth+wordWidth <= maxWidth {
+			line.WriteString(" ")
+			line.WriteString(word)
+			lineWidth += spaceWidth + wordWidth
+			continue
+		}
+
+		if line.Len() > 0 {
+			flush()
+		}
+
+		for wordWidth > maxWidth {
+			// never split a combining mark, which has no width, from its base
+			cut, cutWidth := 0, 0.0
+			for i, r := range word {
+				runeWidth := doc.textWidth(string(r), size)
+				if cutWidth+runeWidth > maxWidth && runeWidth > 0 && i > 0 {
+					break
+				}
+				cut, cutWidth = i+utf8.RuneLen(r), cutWidth+runeWidth
+			}
+
+			line.WriteString(word[:cut])
+			flush()
+			word, wordWidth = word[cut:], wordWidth-cutWidth
+		}
+
+		line.WriteString(word)
+		lineWidth = wordWidth
+	}
+
+	if line.Len() > 0 || len(lines) == 0 {
+		flush()
+	}
+
+	return lines
+}
+
+// Bytes renders the document. Objects are numbered in the order they are
+// written: the catalog, the page tree, the font objects and then a page and its
+// content for every page.
+func (doc *PDFDocuments) Bytes() ([]byte, error) {
+	var objects [][]byte
+
+	add := func(object []byte) int {
+		objects = append(objects, object)
+		return len(objects)
+	}
+
+	add([]byte("<< /Type /Catalog /Pages 2 0 R >>"))
+	pagesId := add(nil)
+
+	fontId, err := doc.font.objects(add)
+	if err != nil {
+		return nil, err
+	}
+
+	kids := make([]string, 0, len(doc.pages))
+	for _, page := range doc.pages {
+		content, err := pdfStream("", page.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		contentId := add(content)
+		pageId := add([]byte(fmt.Sprintf(
+			"<< /Type /Page /Parent %v 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 %v 0 R >> >> /Contents %v 0 R >>",
+			pagesId, pdfPageWidth, pdfPageHeight, fontId, contentId)))
+		kids = append(kids, fmt.Sprintf("%v 0 R", pageId))
+	}
+
+	objects[pagesId-1] = []byte(fmt.Sprintf("<< /Type /Pages /Kids [%v] /Count %v >>", strings.Join(kids, " "), len(kids)))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%v 0 obj\n", i+1)
+		out.Write(object)
+		out.WriteString("\nendobj\n")
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %v\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %v /Root 1 0 R >>\nstartxref\n%v\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes(), nil
+}
+
+// pdfStream compresses data into a stream object, with extra entries for its
+// dictionary if any.
+func pdfStream(extra string, data []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var stream bytes.Buffer
+	fmt.Fprintf(&stream, "<< /Length %v /Filter /FlateDecode %v>>\nstream\n", compressed.Len(), extra)
+	stream.Write(compressed.Bytes())
+	stream.WriteString("\nendstream")
+
+	return stream.Bytes(), nil
+}
+
+type pdfFont interface {
+	// width is the advance of r in thousandths of the font size
+	width(r rune) float64
+	// encode turns text into a string operand of the Tj operator
+	encode(text string) string
+	// objects adds the objects of the font and returns the number of the font
+	objects(add func([]byte) int) (int, error)
+}
+
+// helveticaFont is one of the standard fonts every PDF reader has, so nothing
+// needs to be embedded. Runes outside of Latin-1 are written as '?'.
+type helveticaFont struct{}
+
+var helveticaWidths = [95]float64{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+func (f helveticaFont) width(r rune) float64 {
+	if r >= ' ' && r <= '~' {
+		return helveticaWidths[r-' ']
+	}
+
+	// close enough for the accented letters of Latin-1
+	return 556
+}
+
+func (f helveticaFont) encode(text string) string {
+	var encoded strings.Builder
+	encoded.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			encoded.WriteByte('\\')
+			encoded.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			encoded.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&encoded, "\\%03o", r)
+		default:
+			encoded.WriteByte('?')
+		}
+	}
+	encoded.WriteByte(')')
+
+	return encoded.String()
+}
+
+func (f helveticaFont) objects(add func([]byte) int) (int, error) {
+	return add([]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")), nil
+}
+
+// trueTypeFont is embedded as a composite font whose character codes are glyph
+// ids, so it can address every glyph of the font. Only the glyphs that have
+// been written get a width and a unicode mapping.
+type trueTypeFont struct {
+	file       []byte
+	unitsPerEm float64
+	advances   []uint16
+	bbox       [4]int16
+	ascent     int16
+	descent    int16
+	cmap       map[rune]uint16
+	used       map[uint16]rune
+}
+
+var errInvalidTrueTypeFont = errors.New("invalid or unsupported TrueType font")
+
+func parseTrueTypeFont(file []byte) (*trueTypeFont, error) {
+	if len(file) < 12 {
+		return nil, errInvalidTrueTypeFont
+	}
+
+	tables := map[string][]byte{}
+	numTables := int(binary.BigEndian.Uint16(file[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + i*16
+		if record+16 > len(file) {
+			return nil, errInvalidTrueTypeFont
+		}
+
+		offset := binary.BigEndian.Uint32(file[record+8:])
+		length := binary.BigEndian.Uint32(file[record+12:])
+		if uint64(offset)+uint64(length) > uint64(len(file)) {
+			return nil, errInvalidTrueTypeFont
+		}
+
+		tables[string(file[record:record+4])] = file[offset : offset+length]
+	}
+
+	head, hhea, hmtx, cmap := tables["head"], tables["hhea"], tables["hmtx"], tables["cmap"]
+	if len(head) < 54 || len(hhea) < 36 || hmtx == nil || cmap == nil || tables["glyf"] == nil {
+		return nil, errInvalidTrueTypeFont
+	}
+
+	font := &trueTypeFont{
+		file:       file,
+		unitsPerEm: float64(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int16(binary.BigEndian.Uint16(hhea[4:])),
+		descent:    int16(binary.BigEndian.Uint16(hhea[6:])),
+		used:       map[uint16]rune{},
+	}
+	for i := range font.bbox {
+		font.bbox[i] = int16(binary.BigEndian.Uint16(head[36+2*i:]))
+	}
+
+	numberOfHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if font.unitsPerEm == 0 || numberOfHMetrics == 0 || len(hmtx) < 4*numberOfHMetrics {
+		return nil, errInvalidTrueTypeFont
+	}
+	for i := 0; i < numberOfHMetrics; i++ {
+		font.advances = append(font.advances, binary.BigEndian.Uint16(hmtx[4*i:]))
+	}
+
+	var err error
+	font.cmap, err = parseUnicodeCmap(cmap)
+	if err != nil {
+		return nil, err
+	}
+
+	return font, nil
+}
+
+// parseUnicodeCmap reads the Windows Unicode BMP subtable (format 4), which
+// every TrueType font meant for Windows has.
+func parseUnicodeCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errInvalidTrueTypeFont
+	}
+
+	var subtable []byte
+	numSubtables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numSubtables; i++ {
+		record := 4 + i*8
+		if record+8 > len(cmap) {
+			return nil, errInvalidTrueTypeFont
+		}
+
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := binary.BigEndian.Uint32(cmap[record+4:])
+		if platform == 3 && encoding == 1 && uint64(offset)+14 <= uint64(len(cmap)) {
+			subtable = cmap[offset:]
+			break
+		}
+	}
+
+	if subtable == nil || binary.BigEndian.Uint16(subtable) != 4 {
+		return nil, errInvalidTrueTypeFont
+	}
+
+	segCount := int(binary.BigEndian.Uint16(subtable[6:])) / 2
+	if len(subtable) < 16+8*segCount {
+		return nil, errInvalidTrueTypeFont
+	}
+
+	endCodes := subtable[14:]
+	startCodes := subtable[16+2*segCount:]
+	idDeltas := subtable[16+4*segCount:]
+	idRangeOffsets := subtable[16+6*segCount:]
+
+	glyphs := map[rune]uint16{}
+	for i := 0; i < segCount; i++ {
+		start := binary.BigEndian.Uint16(startCodes[2*i:])
+		end := binary.BigEndian.Uint16(endCodes[2*i:])
+		delta := binary.BigEndian.Uint16(idDeltas[2*i:])
+		rangeOffset := int(binary.BigEndian.Uint16(idRangeOffsets[2*i:]))
+
+		for code := int(start); code <= int(end) && code != 0xffff; code++ {
+			var glyph uint16
+			if rangeOffset == 0 {
+				glyph = uint16(code) + delta
+			} else {
+				// the offset is relative to where it is stored itself
+				at := 16 + 6*segCount + 2*i + rangeOffset + 2*(code-int(start))
+				if at+2 > len(subtable) {
+					return nil, errInvalidTrueTypeFont
+				}
+
+				glyph = binary.BigEndian.Uint16(subtable[at:])
+				if glyph != 0 {
+					glyph += delta
+				}
+			}
+
+			if glyph != 0 {
+				glyphs[rune(code)] = glyph
+			}
+		}
+	}
+
+	return glyphs, nil
+}
+
+func (f *trueTypeFont) advance(glyph uint16) float64 {
+	if int(glyph) >= len(f.advances) {
+		glyph = uint16(len(f.advances) - 1)
+	}
+
+	return float64(f.advances[glyph]) * 1000 / f.unitsPerEm
+}
+
+func (f *trueTypeFont) width(r rune) float64 {
+	return f.advance(f.cmap[r])
+}
+
+func (f *trueTypeFont) encode(text string) string {
+	var encoded strings.Builder
+	encoded.WriteByte('<')
+	for _, r := range text {
+		glyph := f.cmap[r]
+		if glyph != 0 {
+			f.used[glyph] = r
+		}
+		fmt.Fprintf(&encoded, "%04X", glyph)
+	}
+	encoded.WriteByte('>')
+
+	return encoded.String()
+}
+
+func (f *trueTypeFont) objects(add func([]byte) int) (int, error) {
+	fontFile, err := pdfStream(fmt.Sprintf("/Length1 %v ", len(f.file)), f.file)
+	if err != nil {
+		return 0, err
+	}
+	fontFileId := add(fontFile)
+
+	scale := func(v int16) int {
+		return int(float64(v) * 1000 / f.unitsPerEm)
+	}
+
+	descriptorId := add([]byte(fmt.Sprintf(
+		"<< /Type /FontDescriptor /FontName /EmbeddedFont /Flags 32 /FontBBox [%v %v %v %v] /ItalicAngle 0 /Ascent %v /Descent %v /CapHeight %v /StemV 80 /FontFile2 %v 0 R >>",
+		scale(f.bbox[0]), scale(f.bbox[1]), scale(f.bbox[2]), scale(f.bbox[3]), scale(f.ascent), scale(f.descent), scale(f.ascent), fontFileId)))
+
+	glyphs := make([]int, 0, len(f.used))
+	for glyph := range f.used {
+		glyphs = append(glyphs, int(glyph))
+	}
+	sort.Ints(glyphs)
+
+	var widths, toUnicode strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%v [%.0f] ", glyph, f.advance(uint16(glyph)))
+	}
+
+	toUnicode.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	toUnicode.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	toUnicode.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	toUnicode.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// bfchar sections hold at most 100 mappings each
+	for i := 0; i < len(glyphs); i += 100 {
+		chunk := glyphs[i:min(i+100, len(glyphs))]
+		fmt.Fprintf(&toUnicode, "%v beginbfchar\n", len(chunk))
+		for _, glyph := range chunk {
+			fmt.Fprintf(&toUnicode, "<%04X> <%v>\n", glyph, utf16Hex(f.used[uint16(glyph)]))
+		}
+		toUnicode.WriteString("endbfchar\n")
+	}
+	toUnicode.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+
+	toUnicodeStream, err := pdfStream("", []byte(toUnicode.String()))
+	if err != nil {
+		return 0, err
+	}
+	toUnicodeId := add(toUnicodeStream)
+
+	cidFontId := add([]byte(fmt.Sprintf(
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /EmbeddedFont /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %v 0 R /CIDToGIDMap /Identity /DW %.0f /W [%v] >>",
+		descriptorId, f.advance(0), widths.String())))
+
+	return add([]byte(fmt.Sprintf(
+		"<< /Type /Font /Subtype /Type0 /BaseFont /EmbeddedFont /Encoding /Identity-H /DescendantFonts [%v 0 R] /ToUnicode %v 0 R >>",
+		cidFontId, toUnicodeId))), nil
+}
+
+func utf16Hex(r rune) string {
+	if r < 0x10000 {
+		return fmt.Sprintf("%04X", r)
+	}
+
+	r -= 0x10000
+	return fmt.Sprintf("%04X%04X", 0xd800+(r>>10), 0xdc00+(r&0x3ff))
+}
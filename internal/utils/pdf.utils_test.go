@@ -0,0 +1,429 @@
+package utils
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// cmapSegments are written into a format 4 subtable, mapping their codes
+// through idDelta unless they list glyphs for the glyph id array.
+type cmapSegments struct {
+	start  uint16
+	end    uint16
+	delta  int16
+	glyphs []uint16
+}
+
+type cmapRecords struct {
+	platform uint16
+	encoding uint16
+	subtable []byte
+}
+
+func format4Subtable(segments []cmapSegments) []byte {
+	segments = append(segments, cmapSegments{start: 0xffff, end: 0xffff, delta: 1})
+	segCount := len(segments)
+
+	var glyphIds []uint16
+	rangeOffsets := make([]uint16, segCount)
+	for i, segment := range segments {
+		if segment.glyphs != nil {
+			rangeOffsets[i] = uint16(2*(segCount-i) + 2*len(glyphIds))
+			glyphIds = append(glyphIds, segment.glyphs...)
+		}
+	}
+
+	var b bytes.Buffer
+	write := func(values ...uint16) {
+		for _, value := range values {
+			binary.Write(&b, binary.BigEndian, value)
+		}
+	}
+
+	write(4, uint16(16+8*segCount+2*len(glyphIds)), 0, uint16(2*segCount), 0, 0, 0)
+	for _, segment := range segments {
+		write(segment.end)
+	}
+	write(0)
+	for _, segment := range segments {
+		write(segment.start)
+	}
+	for _, segment := range segments {
+		write(uint16(segment.delta))
+	}
+	write(rangeOffsets...)
+	write(glyphIds...)
+
+	return b.Bytes()
+}
+
+func cmapTable(records []cmapRecords) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, []uint16{0, uint16(len(records))})
+
+	offset := 4 + 8*len(records)
+	for _, record := range records {
+		binary.Write(&b, binary.BigEndian, []uint16{record.platform, record.encoding})
+		binary.Write(&b, binary.BigEndian, uint32(offset))
+		offset += len(record.subtable)
+	}
+	for _, record := range records {
+		b.Write(record.subtable)
+	}
+
+	return b.Bytes()
+}
+
+// trueTypeFile puts together the tables parseTrueTypeFont reads, with a 1000
+// units per em so that advances are widths in thousandths of the font size.
+func trueTypeFile(advances []uint16, cmap []byte) []byte {
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 1000)
+	for i, v := range []int16{-100, -200, 1000, 900} {
+		binary.BigEndian.PutUint16(head[36+2*i:], uint16(v))
+	}
+
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[4:], 800)
+	binary.BigEndian.PutUint16(hhea[6:], uint16(0xffff-199))
+	binary.BigEndian.PutUint16(hhea[34:], uint16(len(advances)))
+
+	hmtx := make([]byte, 4*len(advances))
+	for i, advance := range advances {
+		binary.BigEndian.PutUint16(hmtx[4*i:], advance)
+	}
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"cmap", cmap}, {"glyf", []byte{0, 0, 0, 0}}, {"head", head}, {"hhea", hhea}, {"hmtx", hmtx}}
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(0x00010000))
+	binary.Write(&b, binary.BigEndian, []uint16{uint16(len(tables)), 0, 0, 0})
+
+	offset := 12 + 16*len(tables)
+	for _, table := range tables {
+		b.WriteString(table.tag)
+		binary.Write(&b, binary.BigEndian, []uint32{0, uint32(offset), uint32(len(table.data))})
+		offset += len(table.data)
+	}
+	for _, table := range tables {
+		b.Write(table.data)
+	}
+
+	return b.Bytes()
+}
+
+// thaiFont has glyphs for ก (1) and ข (2), and for the zero width vowel sign
+// above ิ (3), besides the space (4).
+func thaiFont() []byte {
+	return trueTypeFile([]uint16{500, 600, 600, 0, 250}, cmapTable([]cmapRecords{{3, 1, format4Subtable([]cmapSegments{
+		{start: ' ', end: ' ', delta: 4 - ' '},
+		{start: 0x0e01, end: 0x0e02, delta: 1 - 0x0e01},
+		{start: 0x0e34, end: 0x0e34, glyphs: []uint16{3}},
+	})}}))
+}
+
+func TestParseUnicodeCmap(t *testing.T) {
+	latin := format4Subtable([]cmapSegments{
+		{start: 'A', end: 'C', delta: 1 - 'A'},
+		{start: 'a', end: 'c', glyphs: []uint16{5, 0, 7}, delta: 10},
+		{start: 0x0e01, end: 0x0e02, delta: -0x0e01 + 20},
+	})
+	latinGlyphs := map[rune]uint16{'A': 1, 'B': 2, 'C': 3, 'a': 15, 'c': 17, 0x0e01: 20, 0x0e02: 21}
+
+	format6 := []byte{0, 6, 0, 10, 0, 0, 0, 'A', 0, 0}
+
+	tests := []struct {
+		name    string
+		cmap    []byte
+		want    map[rune]uint16
+		wantErr bool
+	}{
+		{
+			name: "delta and glyph id array segments",
+			cmap: cmapTable([]cmapRecords{{3, 1, latin}}),
+			want: latinGlyphs,
+		},
+		{
+			name: "windows unicode bmp is picked among others",
+			cmap: cmapTable([]cmapRecords{{0, 3, format6}, {3, 0, format6}, {3, 1, latin}, {3, 10, format6}}),
+			want: latinGlyphs,
+		},
+		{
+			name:    "no windows unicode bmp subtable",
+			cmap:    cmapTable([]cmapRecords{{0, 3, latin}, {3, 0, latin}}),
+			wantErr: true,
+		},
+		{
+			name:    "windows unicode bmp subtable is not format 4",
+			cmap:    cmapTable([]cmapRecords{{3, 1, format6}}),
+			wantErr: true,
+		},
+		{
+			name:    "truncated subtable",
+			cmap:    cmapTable([]cmapRecords{{3, 1, latin[:20]}}),
+			wantErr: true,
+		},
+		{
+			name:    "truncated records",
+			cmap:    []byte{0, 0, 0, 2, 0, 3, 0, 1},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			cmap:    []byte{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUnicodeCmap(tt.cmap)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrueTypeFont(t *testing.T) {
+	font := thaiFont()
+
+	tests := []struct {
+		name    string
+		file    []byte
+		wantErr bool
+	}{
+		{name: "valid", file: font},
+		{name: "too short", file: font[:8], wantErr: true},
+		{name: "table out of the file", file: font[:len(font)-1], wantErr: true},
+		{name: "missing tables", file: trueTypeFile([]uint16{500}, nil), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTrueTypeFont(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.width('ก') != 600 || got.width('ิ') != 0 || got.width(' ') != 250 {
+				t.Errorf("got widths %v %v %v", got.width('ก'), got.width('ิ'), got.width(' '))
+			}
+			if got.width('x') != 500 {
+				t.Errorf("missing glyphs should take the width of glyph 0, got %v", got.width('x'))
+			}
+		})
+	}
+}
+
+func TestPDFWrap(t *testing.T) {
+	maxWidth := pdfPageWidth - 2*pdfMargin
+
+	tests := []struct {
+		name      string
+		font      []byte
+		text      string
+		wantLines int
+	}{
+		{name: "empty", text: "", wantLines: 1},
+		{name: "short", text: "The dweller pays the rent monthly.", wantLines: 1},
+		{name: "extra spaces are collapsed", text: "  two   words  ", wantLines: 1},
+		{name: "broken at spaces", text: strings.Repeat("deposit ", 60), wantLines: 5},
+		{name: "long word is broken anywhere", text: strings.Repeat("W", 100), wantLines: 3},
+		{name: "thai without spaces", font: thaiFont(), text: strings.Repeat("กข", 100), wantLines: 3},
+		{name: "combining marks stay with their base", font: thaiFont(), text: strings.Repeat("กิ", 150), wantLines: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewPDFDocument(tt.font)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			lines := doc.wrap(tt.text, pdfParagraphSize)
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %v lines, want %v: %q", len(lines), tt.wantLines, lines)
+			}
+
+			for _, line := range lines {
+				if width := doc.textWidth(line, pdfParagraphSize); width > maxWidth {
+					t.Errorf("line %q is %v wide, more than %v", line, width, maxWidth)
+				}
+				if r, _ := utf8.DecodeRuneInString(line); r == 'ิ' {
+					t.Errorf("line %q starts with a combining mark", line)
+				}
+			}
+
+			// joining the lines back gives the text, whose spaces were only
+			// ever turned into line breaks
+			joined := strings.Join(lines, " ")
+			if !strings.Contains(tt.text, " ") {
+				joined = strings.Join(lines, "")
+			}
+			if joined != strings.Join(strings.Fields(tt.text), " ") {
+				t.Errorf("got %q back", joined)
+			}
+		})
+	}
+}
+
+var (
+	pdfStartXref = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+	pdfStreams   = regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode [^>]*>>\nstream\n`)
+	pdfPageCount = regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`)
+)
+
+// readPDF checks the cross-reference table of a document and returns its
+// objects by number, with every stream inflated.
+func readPDF(t *testing.T, document []byte) map[int]string {
+	t.Helper()
+
+	if !bytes.HasPrefix(document, []byte("%PDF-1.4\n")) {
+		t.Fatalf("missing header: %q", document[:min(16, len(document))])
+	}
+
+	match := pdfStartXref.FindSubmatch(document)
+	if match == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+
+	var size int
+	table := string(document[xref:])
+	if _, err := fmt.Sscanf(table, "xref\n0 %d\n", &size); err != nil {
+		t.Fatalf("invalid xref table: %v", err)
+	}
+	if !strings.Contains(table, fmt.Sprintf("trailer\n<< /Size %v /Root 1 0 R >>", size)) {
+		t.Errorf("trailer does not match a size of %v", size)
+	}
+
+	entries := strings.Split(table, "\n")[3 : 3+size-1]
+	offsets := make([]int, len(entries))
+	for i, entry := range entries {
+		if _, err := fmt.Sscanf(entry, "%010d 00000 n ", &offsets[i]); err != nil {
+			t.Fatalf("invalid xref entry %q: %v", entry, err)
+		}
+	}
+
+	objects := map[int]string{}
+	for i, offset := range offsets {
+		header := fmt.Sprintf("%v 0 obj\n", i+1)
+		if !bytes.HasPrefix(document[offset:], []byte(header)) {
+			t.Fatalf("xref entry %v points at %q", i+1, document[offset:offset+10])
+		}
+
+		end := bytes.Index(document[offset:], []byte("\nendobj\n"))
+		object := document[offset+len(header) : offset+end]
+
+		if match := pdfStreams.FindSubmatchIndex(object); match != nil {
+			length, _ := strconv.Atoi(string(object[match[2]:match[3]]))
+			r, err := zlib.NewReader(bytes.NewReader(object[match[1] : match[1]+length]))
+			if err != nil {
+				t.Fatalf("object %v: %v", i+1, err)
+			}
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("object %v: %v", i+1, err)
+			}
+			object = data
+		}
+
+		objects[i+1] = string(object)
+	}
+
+	return objects
+}
+
+func TestPDFDocumentBytes(t *testing.T) {
+	tests := []struct {
+		name      string
+		font      []byte
+		text      string
+		wantPages int
+		want      []string
+	}{
+		{
+			name:      "helvetica",
+			text:      "# Rental (Agreement)\n\n## Parties\nThe owner \\ the dweller pay ฿100.",
+			wantPages: 1,
+			want:      []string{`(Rental \(Agreement\)) Tj`, `(Parties) Tj`, `(The owner \\ the dweller pay ?100.) Tj`, "/BaseFont /Helvetica"},
+		},
+		{
+			name:      "new pages once full",
+			text:      strings.Repeat("A paragraph of the contract.\n", 80),
+			wantPages: 2,
+			want:      []string{"(A paragraph of the contract.) Tj"},
+		},
+		{
+			name:      "embedded font",
+			font:      thaiFont(),
+			text:      "# กข\nกิ ข",
+			wantPages: 1,
+			want:      []string{"<00010002> Tj", "<0001000300040002> Tj", "/FontFile2", "<0001> <0E01>", "<0003> <0E34>", "/W [1 [600] 2 [600] 3 [0] 4 [250] ]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewPDFDocument(tt.font)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc.WriteText(tt.text)
+
+			document, err := doc.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			objects := readPDF(t, document)
+			if objects[1] != "<< /Type /Catalog /Pages 2 0 R >>" {
+				t.Errorf("got catalog %q", objects[1])
+			}
+
+			match := pdfPageCount.FindStringSubmatch(objects[2])
+			if match == nil || match[1] != strconv.Itoa(tt.wantPages) {
+				t.Errorf("got page tree %q, want %v pages", objects[2], tt.wantPages)
+			}
+
+			var pages int
+			var all strings.Builder
+			for i := 1; i <= len(objects); i++ {
+				if strings.Contains(objects[i], "/Type /Page ") {
+					pages++
+				}
+				all.WriteString(objects[i])
+				all.WriteString("\n")
+			}
+
+			if pages != tt.wantPages {
+				t.Errorf("got %v page objects, want %v", pages, tt.wantPages)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(all.String(), want) {
+					t.Errorf("document is missing %q", want)
+				}
+			}
+		})
+	}
+}
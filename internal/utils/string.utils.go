@@ -1,6 +1,10 @@
 package utils
 
-import "strings"
+import (
+	"fmt"
+	"math"
+	"strings"
+)
 
 func SplitByFirstString(str string, splitter string) (string, string) {
 	spt := strings.Split(str, splitter)
@@ -11,3 +15,23 @@ func SplitByFirstString(str string, splitter string) (string, string) {
 		return spt[0], ""
 	}
 }
+
+// FormatAmount formats an amount of baht with thousands separators, e.g. 12,000.00.
+func FormatAmount(amount float64) string {
+	whole, fraction, _ := strings.Cut(fmt.Sprintf("%.2f", math.Abs(amount)), ".")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	sign := ""
+	if amount < 0 && strings.Trim(whole+fraction, "0") != "" {
+		sign = "-"
+	}
+
+	return sign + grouped.String() + "." + fraction
+}
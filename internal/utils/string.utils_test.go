@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		want   string
+	}{
+		{name: "zero", amount: 0, want: "0.00"},
+		{name: "below a thousand", amount: 999.5, want: "999.50"},
+		{name: "a thousand", amount: 1000, want: "1,000.00"},
+		{name: "millions", amount: 12345678.9, want: "12,345,678.90"},
+		{name: "rounded to satang", amount: 1234.567, want: "1,234.57"},
+		{name: "rounded up to the next thousand", amount: 999.999, want: "1,000.00"},
+		{name: "negative", amount: -4500, want: "-4,500.00"},
+		{name: "negative rounded to zero", amount: -0.001, want: "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatAmount(tt.amount); got != tt.want {
+				t.Errorf("FormatAmount(%v) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
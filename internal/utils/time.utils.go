@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// LocalTimezone is the timezone the platform operates in. Thailand does not
+// observe daylight saving time so a fixed offset is enough.
+var LocalTimezone = time.FixedZone("Asia/Bangkok", 7*60*60)
+
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.In(LocalTimezone).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, LocalTimezone)
+}
+
+func ParseLocalDate(date string) (time.Time, error) {
+	return time.ParseInLocation(time.DateOnly, date, LocalTimezone)
+}
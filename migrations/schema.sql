@@ -4,7 +4,7 @@ CREATE TYPE registered_types AS ENUM('EMAIL', 'GOOGLE');
 
 CREATE TYPE agreement_types AS ENUM('SELLING', 'RENTING');
 
-CREATE TYPE appointment_status AS ENUM('PENDING', 'CONFIRMED', 'REJECTED', 'CANCELLED', 'ARCHIVED');
+CREATE TYPE appointment_status AS ENUM('PENDING', 'CONFIRMED', 'REJECTED', 'CANCELLED', 'ARCHIVED', 'EXPIRED');
 
 CREATE TYPE agreement_status AS ENUM('AWAITING_DEPOSIT', 'AWAITING_PAYMENT', 'RENTING', 'CANCELLED', 'OVERDUE', 'ARCHIVED');
 
@@ -16,6 +16,40 @@ CREATE TYPE furnishing AS ENUM('UNFURNISHED', 'PARTIALLY_FURNISHED', 'FULLY_FURN
 
 CREATE TYPE floor_size_units AS ENUM('SQM', 'SQFT');
 
+CREATE TYPE property_history_actions AS ENUM('CREATE', 'UPDATE', 'DELETE', 'REVERT');
+
+CREATE TYPE actor_roles AS ENUM('OWNER', 'DWELLER', 'SYSTEM', 'ADMIN');
+
+CREATE TYPE proposal_status AS ENUM('PENDING', 'ACCEPTED', 'DECLINED', 'SUPERSEDED');
+
+CREATE TYPE attendance_status AS ENUM('CHECKED_IN', 'NO_SHOW');
+
+CREATE TYPE payment_types AS ENUM('DEPOSIT', 'RENT', 'PURCHASE', 'DEPOSIT_REFUND');
+
+CREATE TYPE installment_status AS ENUM('UNPAID', 'PAID', 'OVERDUE');
+
+CREATE TYPE contract_languages AS ENUM('EN', 'TH');
+
+CREATE TYPE signature_types AS ENUM('TYPED', 'DRAWN');
+
+CREATE TYPE amendment_types AS ENUM('RENEWAL', 'AMENDMENT');
+
+CREATE TYPE deduction_status AS ENUM('PENDING', 'ACCEPTED', 'DISPUTED', 'ESCALATED', 'WITHDRAWN');
+
+CREATE TYPE inspection_types AS ENUM('MOVE_IN', 'MOVE_OUT');
+
+CREATE TYPE item_conditions AS ENUM('EXCELLENT', 'GOOD', 'FAIR', 'POOR', 'DAMAGED');
+
+CREATE TYPE maintenance_categories AS ENUM('AIR_CONDITIONING', 'PLUMBING', 'ELECTRICAL', 'APPLIANCE', 'STRUCTURAL', 'PEST_CONTROL', 'OTHER');
+
+CREATE TYPE maintenance_priorities AS ENUM('LOW', 'MEDIUM', 'HIGH', 'URGENT');
+
+CREATE TYPE maintenance_status AS ENUM('OPEN', 'ACKNOWLEDGED', 'SCHEDULED', 'RESOLVED');
+
+CREATE TYPE utility_types AS ENUM('WATER', 'ELECTRICITY');
+
+CREATE TYPE property_attachment_types AS ENUM('DOCUMENT', 'FLOOR_PLAN', 'VIDEO_URL', 'TOUR_URL');
+
 CREATE TABLE email_verification_codes
 (
     email                     VARCHAR(50) PRIMARY KEY           NOT NULL,
@@ -116,6 +150,20 @@ CREATE TABLE property_images
     PRIMARY KEY (property_id, image_url)
 );
 
+CREATE TABLE property_attachments
+(
+    attachment_id            UUID PRIMARY KEY DEFAULT gen_random_uuid()             NOT NULL,
+    property_id              UUID REFERENCES properties (property_id) ON DELETE CASCADE NOT NULL,
+    attachment_type          property_attachment_types                              NOT NULL,
+    title                    VARCHAR(200)                                           NOT NULL,
+    url                      VARCHAR(2000)                                          NOT NULL,
+    content_type             VARCHAR(100)                                           DEFAULT NULL,
+    display_order            INTEGER                                                NOT NULL,
+    created_at               TIMESTAMP(0) WITH TIME ZONE                            DEFAULT CURRENT_TIMESTAMP,
+    updated_at               TIMESTAMP(0) WITH TIME ZONE                            DEFAULT CURRENT_TIMESTAMP,
+    deleted_at               TIMESTAMP(0) WITH TIME ZONE                            DEFAULT NULL
+);
+
 CREATE TABLE selling_properties
 (
     property_id UUID REFERENCES properties (property_id) ON DELETE CASCADE          NOT NULL,
@@ -150,13 +198,103 @@ CREATE TABLE appointments
     owner_user_id       UUID REFERENCES users (user_id)            ON DELETE CASCADE    NOT NULL,
     dweller_user_id     UUID REFERENCES users (user_id)            ON DELETE CASCADE    NOT NULL,
     appointment_date    TIMESTAMP(0) WITH TIME ZONE                NOT NULL,
+    duration_minutes    INTEGER DEFAULT 30                         NOT NULL,
     status              appointment_status DEFAULT 'PENDING'       NOT NULL,
     note                TEXT                                       DEFAULT NULL,
     cancelled_message   TEXT                                       DEFAULT NULL,
     created_at          TIMESTAMP(0) WITH TIME ZONE                DEFAULT CURRENT_TIMESTAMP,
     updated_at          TIMESTAMP(0) WITH TIME ZONE                DEFAULT CURRENT_TIMESTAMP,
-    deleted_at          TIMESTAMP(0) WITH TIME ZONE                DEFAULT NULL,
-    UNIQUE (property_id, appointment_date, deleted_at)
+    deleted_at          TIMESTAMP(0) WITH TIME ZONE                DEFAULT NULL
+);
+
+CREATE TABLE appointment_status_histories
+(
+    history_id          UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    appointment_id      UUID REFERENCES appointments (appointment_id) ON DELETE CASCADE NOT NULL,
+    from_status         appointment_status                                              NOT NULL,
+    to_status           appointment_status                                              NOT NULL,
+    actor_user_id       UUID REFERENCES users (user_id) ON DELETE SET NULL              DEFAULT NULL,
+    actor_role          actor_roles                                                     NOT NULL,
+    message             TEXT                                                            DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE appointment_proposals
+(
+    proposal_id             UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    appointment_id          UUID REFERENCES appointments (appointment_id) ON DELETE CASCADE NOT NULL,
+    proposed_by_user_id     UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    proposer_role           actor_roles                                                     NOT NULL,
+    proposed_date           TIMESTAMP(0) WITH TIME ZONE                                     NOT NULL,
+    duration_minutes        INTEGER                                                         NOT NULL,
+    status                  proposal_status DEFAULT 'PENDING'                               NOT NULL,
+    message                 TEXT                                                            DEFAULT NULL,
+    responded_by_user_id    UUID REFERENCES users (user_id) ON DELETE SET NULL              DEFAULT NULL,
+    responded_at            TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    created_at              TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE appointment_attendances
+(
+    appointment_id          UUID REFERENCES appointments (appointment_id) ON DELETE CASCADE NOT NULL,
+    user_id                 UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    role                    actor_roles                                                     NOT NULL,
+    status                  attendance_status                                               NOT NULL,
+    reported_by_user_id     UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    created_at              TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (appointment_id, user_id)
+);
+
+CREATE TABLE calendar_feeds
+(
+    user_id             UUID PRIMARY KEY REFERENCES users (user_id) ON DELETE CASCADE  NOT NULL,
+    token               VARCHAR(64) UNIQUE                                          NOT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                 DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_notification_preferences
+(
+    user_id                 UUID PRIMARY KEY REFERENCES users (user_id) ON DELETE CASCADE  NOT NULL,
+    appointment_reminders   BOOLEAN                                                     DEFAULT TRUE NOT NULL,
+    appointment_updates     BOOLEAN                                                     DEFAULT TRUE NOT NULL,
+    updated_at              TIMESTAMP(0) WITH TIME ZONE                                 DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE appointment_reminders
+(
+    appointment_id      UUID REFERENCES appointments (appointment_id) ON DELETE CASCADE NOT NULL,
+    user_id             UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    offset_seconds      INTEGER                                                         NOT NULL,
+    appointment_date    TIMESTAMP(0) WITH TIME ZONE                                     NOT NULL,
+    sent_at             TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (appointment_id, user_id, offset_seconds, appointment_date)
+);
+
+CREATE TABLE property_availabilities
+(
+    availability_id     UUID PRIMARY KEY DEFAULT gen_random_uuid()                  NOT NULL,
+    property_id         UUID REFERENCES properties (property_id) ON DELETE CASCADE  NOT NULL,
+    day_of_week         SMALLINT                                                    NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time          TIME(0)                                                     NOT NULL,
+    end_time            TIME(0)                                                     NOT NULL,
+    slot_duration       INTEGER                                                     NOT NULL CHECK (slot_duration > 0),
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                 DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP(0) WITH TIME ZONE                                 DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE property_availability_exceptions
+(
+    exception_id        UUID PRIMARY KEY DEFAULT gen_random_uuid()                  NOT NULL,
+    property_id         UUID REFERENCES properties (property_id) ON DELETE CASCADE  NOT NULL,
+    exception_date      DATE                                                        NOT NULL,
+    start_time          TIME(0)                                                     DEFAULT NULL,
+    end_time            TIME(0)                                                     DEFAULT NULL,
+    is_available        BOOLEAN                                                     NOT NULL DEFAULT FALSE,
+    slot_duration       INTEGER                                                     DEFAULT NULL CHECK (slot_duration > 0),
+    note                TEXT                                                        DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                 DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time)
 );
 
 CREATE TABLE agreements
@@ -179,6 +317,204 @@ CREATE TABLE agreements
     UNIQUE (property_id, agreement_date)
 );
 
+CREATE TABLE agreement_status_histories
+(
+    history_id          UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    from_status         agreement_status                                                NOT NULL,
+    to_status           agreement_status                                                NOT NULL,
+    actor_user_id       UUID REFERENCES users (user_id) ON DELETE SET NULL              DEFAULT NULL,
+    actor_role          actor_roles                                                     NOT NULL,
+    message             TEXT                                                            DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE agreement_installments
+(
+    installment_id      UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    installment_number  INTEGER                                                         NOT NULL,
+    due_date            DATE                                                            NOT NULL,
+    amount              DOUBLE PRECISION                                                NOT NULL,
+    status              installment_status DEFAULT 'UNPAID'                             NOT NULL,
+    late_fee            DOUBLE PRECISION DEFAULT 0                                      NOT NULL,
+    utility_amount      DOUBLE PRECISION DEFAULT 0                                      NOT NULL,
+    paid_at             TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (agreement_id, installment_number)
+);
+
+CREATE TABLE agreement_contracts
+(
+    contract_id         UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    language            contract_languages                                              NOT NULL,
+    version             INTEGER                                                         NOT NULL,
+    agreement_version   INTEGER DEFAULT 1                                               NOT NULL,
+    file_key            TEXT                                                            NOT NULL,
+    document_hash       VARCHAR(64)                                                     NOT NULL,
+    created_by_user_id  UUID REFERENCES users (user_id) ON DELETE SET NULL              DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (agreement_id, language, version)
+);
+
+CREATE TABLE agreement_signatures
+(
+    signature_id        UUID PRIMARY KEY DEFAULT gen_random_uuid()                              NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE             NOT NULL,
+    contract_id         UUID REFERENCES agreement_contracts (contract_id) ON DELETE CASCADE     NOT NULL,
+    user_id             UUID REFERENCES users (user_id) ON DELETE CASCADE                       NOT NULL,
+    role                actor_roles                                                             NOT NULL,
+    signature_type      signature_types                                                         NOT NULL,
+    signed_name         VARCHAR(100)                                                            NOT NULL,
+    signature_image_key TEXT                                                                    DEFAULT NULL,
+    document_hash       VARCHAR(64)                                                             NOT NULL,
+    ip_address          VARCHAR(45)                                                             NOT NULL,
+    user_agent          TEXT                                                                    DEFAULT NULL,
+    signed_at           TIMESTAMP(0) WITH TIME ZONE                                             DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (contract_id, user_id)
+);
+
+CREATE TABLE agreement_amendments
+(
+    amendment_id                UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    agreement_id                UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    amendment_type              amendment_types                                                 NOT NULL,
+    version                     INTEGER                                                         DEFAULT NULL,
+    proposed_by_user_id         UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    proposer_role               actor_roles                                                     NOT NULL,
+    previous_payment_duration   INTEGER                                                         NOT NULL,
+    previous_payment_per_month  DOUBLE PRECISION                                                NOT NULL,
+    payment_duration            INTEGER                                                         NOT NULL,
+    payment_per_month           DOUBLE PRECISION                                                NOT NULL,
+    end_date                    TIMESTAMP(0) WITH TIME ZONE                                     NOT NULL,
+    status                      proposal_status DEFAULT 'PENDING'                               NOT NULL,
+    message                     TEXT                                                            DEFAULT NULL,
+    responded_by_user_id        UUID REFERENCES users (user_id) ON DELETE SET NULL              DEFAULT NULL,
+    responded_at                TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    created_at                  TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (agreement_id, version)
+);
+
+CREATE TABLE agreement_deposit_deductions
+(
+    deduction_id        UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    description         TEXT                                                            NOT NULL,
+    amount              DOUBLE PRECISION                                                NOT NULL,
+    status              deduction_status DEFAULT 'PENDING'                              NOT NULL,
+    dispute_reason      TEXT                                                            DEFAULT NULL,
+    created_by_user_id  UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    responded_at        TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    resolved_at         TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE agreement_deduction_evidences
+(
+    deduction_id        UUID REFERENCES agreement_deposit_deductions (deduction_id) ON DELETE CASCADE   NOT NULL,
+    image_url           TEXT                                                                            NOT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                                     DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (deduction_id, image_url)
+);
+
+CREATE TABLE agreement_inspections
+(
+    inspection_id       UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    inspection_type     inspection_types                                                NOT NULL,
+    created_by_user_id  UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    owner_signed_at     TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    dweller_signed_at   TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    superseded_at       TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE agreement_inspection_items
+(
+    item_id             UUID PRIMARY KEY DEFAULT gen_random_uuid()                                  NOT NULL,
+    inspection_id       UUID REFERENCES agreement_inspections (inspection_id) ON DELETE CASCADE     NOT NULL,
+    room                VARCHAR(50)                                                                 NOT NULL,
+    item                VARCHAR(50)                                                                 NOT NULL,
+    condition           item_conditions                                                             NOT NULL,
+    notes               TEXT                                                                        DEFAULT NULL,
+    item_order          INTEGER                                                                     NOT NULL,
+    UNIQUE (inspection_id, room, item)
+);
+
+CREATE TABLE agreement_inspection_photos
+(
+    item_id             UUID REFERENCES agreement_inspection_items (item_id) ON DELETE CASCADE  NOT NULL,
+    image_url           TEXT                                                                    NOT NULL,
+    photo_order         INTEGER                                                                 NOT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                             DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, image_url)
+);
+
+CREATE TABLE agreement_utility_rates
+(
+    agreement_id        UUID PRIMARY KEY REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    water_rate          DOUBLE PRECISION                                                            NOT NULL,
+    electricity_rate    DOUBLE PRECISION                                                            NOT NULL,
+    updated_at          TIMESTAMP(0) WITH TIME ZONE                                                 DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE agreement_meter_readings
+(
+    reading_id          UUID PRIMARY KEY DEFAULT gen_random_uuid()                                      NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE                     NOT NULL,
+    installment_id      UUID REFERENCES agreement_installments (installment_id) ON DELETE CASCADE       NOT NULL,
+    installment_number  INTEGER                                                                         NOT NULL,
+    utility_type        utility_types                                                                   NOT NULL,
+    previous_reading    DOUBLE PRECISION                                                                NOT NULL,
+    current_reading     DOUBLE PRECISION                                                                NOT NULL,
+    units               DOUBLE PRECISION                                                                NOT NULL,
+    unit_rate           DOUBLE PRECISION                                                                NOT NULL,
+    amount              DOUBLE PRECISION                                                                NOT NULL,
+    image_url           TEXT                                                                            NOT NULL,
+    recorded_by_user_id UUID REFERENCES users (user_id) ON DELETE CASCADE                               NOT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                                     DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (agreement_id, installment_number, utility_type)
+);
+
+CREATE TABLE maintenance_tickets
+(
+    ticket_id           UUID PRIMARY KEY DEFAULT gen_random_uuid()                      NOT NULL,
+    agreement_id        UUID REFERENCES agreements (agreement_id) ON DELETE CASCADE     NOT NULL,
+    created_by_user_id  UUID REFERENCES users (user_id) ON DELETE CASCADE               NOT NULL,
+    title               VARCHAR(100)                                                    NOT NULL,
+    description         TEXT                                                            NOT NULL,
+    category            maintenance_categories                                          NOT NULL,
+    priority            maintenance_priorities                                          NOT NULL,
+    status              maintenance_status DEFAULT 'OPEN'                               NOT NULL,
+    scheduled_at        TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    resolved_at         TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP(0) WITH TIME ZONE                                     DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE maintenance_ticket_photos
+(
+    ticket_id           UUID REFERENCES maintenance_tickets (ticket_id) ON DELETE CASCADE   NOT NULL,
+    image_url           TEXT                                                                NOT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                         DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (ticket_id, image_url)
+);
+
+CREATE TABLE maintenance_ticket_updates
+(
+    update_id           UUID PRIMARY KEY DEFAULT gen_random_uuid()                          NOT NULL,
+    ticket_id           UUID REFERENCES maintenance_tickets (ticket_id) ON DELETE CASCADE   NOT NULL,
+    from_status         maintenance_status                                                  NOT NULL,
+    to_status           maintenance_status                                                  NOT NULL,
+    scheduled_at        TIMESTAMP(0) WITH TIME ZONE                                         DEFAULT NULL,
+    note                TEXT                                                                DEFAULT NULL,
+    updated_by_user_id  UUID REFERENCES users (user_id) ON DELETE SET NULL                  DEFAULT NULL,
+    created_at          TIMESTAMP(0) WITH TIME ZONE                                         DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE messages (
     message_id  UUID PRIMARY KEY         NOT NULL,
     sender_id   UUID                     NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
@@ -194,42 +530,71 @@ CREATE TABLE payments(
     price     DOUBLE PRECISION                           NOT NULL,
     IsSuccess BOOLEAN                                    NOT NULL, 
     Name       VARCHAR(50)                               NOT NULL,
+    agreement_id UUID REFERENCES agreements(agreement_id) ON DELETE SET NULL DEFAULT NULL,
+    payment_type payment_types                            DEFAULT NULL,
+    installment_id UUID REFERENCES agreement_installments(installment_id) ON DELETE SET NULL DEFAULT NULL,
+    recipient_user_id UUID REFERENCES users(user_id)       ON DELETE SET NULL DEFAULT NULL,
+    checkout_session_id VARCHAR(255) UNIQUE                DEFAULT NULL,
+    refunded_at TIMESTAMP(0) WITH TIME ZONE               DEFAULT NULL,
+    paid_at TIMESTAMP(0) WITH TIME ZONE                   DEFAULT NULL,
+    rent_amount DOUBLE PRECISION                          DEFAULT NULL,
+    late_fee_amount DOUBLE PRECISION                      DEFAULT NULL,
+    utility_amount DOUBLE PRECISION                       DEFAULT NULL,
     created_at TIMESTAMP(0) WITH TIME ZONE                DEFAULT CURRENT_TIMESTAMP, 
     updated_at TIMESTAMP(0) WITH TIME ZONE                DEFAULT CURRENT_TIMESTAMP, 
     deleted_at TIMESTAMP(0) WITH TIME ZONE                DEFAULT NULL
 );
 
+CREATE TABLE property_histories
+(
+    history_id              UUID PRIMARY KEY DEFAULT gen_random_uuid()                  NOT NULL,
+    property_id             UUID REFERENCES properties (property_id) ON DELETE CASCADE  NOT NULL,
+    actor_user_id           UUID REFERENCES users (user_id) ON DELETE SET NULL          DEFAULT NULL,
+    action                  property_history_actions                                    NOT NULL,
+    version                 INTEGER                                                     NOT NULL,
+    reverted_from_version   INTEGER                                                     DEFAULT NULL,
+    changes                 JSONB                                                       NOT NULL DEFAULT '{}',
+    snapshot                JSONB                                                       NOT NULL,
+    created_at              TIMESTAMP(0) WITH TIME ZONE                                 DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (property_id, version)
+);
+
 -------------------- RULES --------------------
 
-CREATE RULE soft_deletion AS ON DELETE TO users DO INSTEAD (
+-- Deleting a row that is already soft-deleted falls through to a hard delete, which is how the trash purge job removes expired rows.
+CREATE RULE soft_deletion AS ON DELETE TO users WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE user_id = old.user_id and deleted_at IS NULL
 );
 
-CREATE RULE soft_deletion AS ON DELETE TO user_financial_informations DO INSTEAD (
+CREATE RULE soft_deletion AS ON DELETE TO user_financial_informations WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE user_financial_informations SET deleted_at = CURRENT_TIMESTAMP WHERE user_id = old.user_id and deleted_at IS NULL
 );
 
-CREATE RULE soft_deletion AS ON DELETE TO properties DO INSTEAD (
+CREATE RULE soft_deletion AS ON DELETE TO properties WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE properties SET deleted_at = CURRENT_TIMESTAMP WHERE property_id = old.property_id and deleted_at IS NULL
 );
 
-CREATE RULE soft_deletion AS ON DELETE TO property_images DO INSTEAD (
+CREATE RULE soft_deletion AS ON DELETE TO property_images WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE property_images SET deleted_at = CURRENT_TIMESTAMP WHERE property_id = old.property_id and deleted_at IS NULL
 );
 
-CREATE RULE soft_deletion AS ON DELETE TO selling_properties DO INSTEAD (
+CREATE RULE soft_deletion AS ON DELETE TO property_attachments WHERE old.deleted_at IS NULL DO INSTEAD (
+    UPDATE property_attachments SET deleted_at = CURRENT_TIMESTAMP WHERE attachment_id = old.attachment_id and deleted_at IS NULL
+);
+
+CREATE RULE soft_deletion AS ON DELETE TO selling_properties WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE selling_properties SET deleted_at = CURRENT_TIMESTAMP WHERE property_id = old.property_id and deleted_at IS NULL
 );
 
-CREATE RULE soft_deletion AS ON DELETE TO renting_properties DO INSTEAD (
+CREATE RULE soft_deletion AS ON DELETE TO renting_properties WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE renting_properties SET deleted_at = CURRENT_TIMESTAMP WHERE property_id = old.property_id and deleted_at IS NULL
 );
 
-CREATE RULE soft_deletion AS ON DELETE TO appointments DO INSTEAD (
+CREATE RULE soft_deletion AS ON DELETE TO appointments WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE appointments SET deleted_at = CURRENT_TIMESTAMP WHERE appointment_id = old.appointment_id and deleted_at IS NULL
 );
 
-CREATE RULE soft_deletion AS ON DELETE TO agreements DO INSTEAD (
+CREATE RULE soft_deletion AS ON DELETE TO agreements WHERE old.deleted_at IS NULL DO INSTEAD (
     UPDATE agreements SET deleted_at = CURRENT_TIMESTAMP WHERE agreement_id = old.agreement_id and deleted_at IS NULL
 );
 
@@ -247,12 +612,31 @@ CREATE RULE delete_properties AS ON UPDATE TO properties
     WHERE old.deleted_at IS NULL AND new.deleted_at IS NOT NULL
     DO ALSO (
         DELETE FROM property_images WHERE property_id = old.property_id;
+        DELETE FROM property_attachments WHERE property_id = old.property_id;
         UPDATE selling_properties SET deleted_at = new.deleted_at WHERE property_id = old.property_id;
         UPDATE renting_properties SET deleted_at = new.deleted_at WHERE property_id = old.property_id;
         DELETE FROM favorite_properties WHERE property_id = old.property_id;
         UPDATE appointments SET deleted_at = new.deleted_at WHERE property_id = old.property_id;
     );
 
+CREATE RULE restore_users AS ON UPDATE TO users
+    WHERE old.deleted_at IS NOT NULL AND new.deleted_at IS NULL
+    DO ALSO (
+        UPDATE properties SET deleted_at = NULL WHERE owner_id = old.user_id AND deleted_at = old.deleted_at;
+        UPDATE appointments SET deleted_at = NULL WHERE (owner_user_id = old.user_id OR dweller_user_id = old.user_id) AND deleted_at = old.deleted_at;
+        UPDATE user_financial_informations SET deleted_at = NULL WHERE user_id = old.user_id AND deleted_at = old.deleted_at;
+    );
+
+CREATE RULE restore_properties AS ON UPDATE TO properties
+    WHERE old.deleted_at IS NOT NULL AND new.deleted_at IS NULL
+    DO ALSO (
+        UPDATE property_images SET deleted_at = NULL WHERE property_id = old.property_id AND deleted_at = old.deleted_at;
+        UPDATE property_attachments SET deleted_at = NULL WHERE property_id = old.property_id AND deleted_at = old.deleted_at;
+        UPDATE selling_properties SET deleted_at = NULL WHERE property_id = old.property_id AND deleted_at = old.deleted_at;
+        UPDATE renting_properties SET deleted_at = NULL WHERE property_id = old.property_id AND deleted_at = old.deleted_at;
+        UPDATE appointments SET deleted_at = NULL WHERE property_id = old.property_id AND deleted_at = old.deleted_at;
+    );
+
 CREATE RULE create_email_verification_codes AS ON INSERT TO email_verification_codes
     WHERE new.email = (SELECT email FROM email_verification_codes WHERE email = new.email) DO INSTEAD(
         UPDATE email_verification_codes SET code = new.code, expired_at = new.expired_at WHERE email = new.email
@@ -341,6 +725,9 @@ INSERT INTO messages (message_id, sender_id, receiver_id, content, read_at, sent
 ('f48c2f66-3450-41f1-8307-db6386187472', '62dd40da-f326-4825-9afc-2d68e06e0282', 'bc5891ce-d6f2-d6f2-d6f2-ebc331626555', 'Hi' , NULL, '2024-02-25 19:05:10.519+07'),
 ('8d7a913b-0bd4-4554-8286-bc8ad2b8817e', '62dd40da-f326-4825-9afc-2d68e06e0282', 'bc5891ce-d6f2-d6f2-d6f2-ebc331626555', '?' , NULL, '2024-02-25 19:05:12.953+07');
 
+INSERT INTO property_availabilities (property_id, day_of_week, start_time, end_time, slot_duration)
+SELECT property_id, day_of_week, '09:00', '17:00', 30 FROM properties, generate_series(1, 5) AS day_of_week;
+
 INSERT INTO appointments (property_id, owner_user_id, dweller_user_id, status, appointment_date, note) VALUES
 ('0bd03187-91ac-457d-957c-3ba2f6c0d24b', 'f38f80b3-f326-4825-9afc-ebc331626555', 'bc5891ce-d6f2-d6f2-d6f2-ebc331626555', 'PENDING', '2024-02-21 15:50:00.000+07', NULL),
 ('21b492b6-8d4f-45a6-af25-2fa9c1eb2042', 'f38f80b3-f326-4825-9afc-ebc331626555', 'bc5891ce-d6f2-d6f2-d6f2-ebc331626555', 'PENDING', '2024-02-21 15:51:00.000+07', 'Good morning');
@@ -375,6 +762,9 @@ CREATE VIEW properties AS SELECT *
 ALTER TABLE property_images RENAME TO _property_images;
 CREATE VIEW property_images AS SELECT * FROM _property_images WHERE property_id IN (SELECT property_id FROM properties WHERE deleted_at IS NULL);
 
+ALTER TABLE property_attachments RENAME TO _property_attachments;
+CREATE VIEW property_attachments AS SELECT * FROM _property_attachments WHERE deleted_at IS NULL AND property_id IN (SELECT property_id FROM properties WHERE deleted_at IS NULL);
+
 ALTER TABLE selling_properties RENAME TO _selling_properties;
 CREATE VIEW selling_properties AS SELECT * FROM _selling_properties WHERE property_id IN (SELECT property_id FROM properties WHERE deleted_at IS NULL);
 
@@ -407,6 +797,33 @@ CREATE INDEX idx_users_deleted_at                       ON _users (deleted_at);
 CREATE INDEX idx_user_financial_information_deleted_at  ON _user_financial_informations (deleted_at);
 CREATE INDEX idx_properties_deleted_at                  ON _properties (deleted_at);
 CREATE INDEX idx_property_images_deleted_at             ON _property_images (deleted_at);
+CREATE INDEX idx_property_attachments_deleted_at        ON _property_attachments (deleted_at);
 CREATE INDEX idx_selling_properties_deleted_at          ON _selling_properties (deleted_at);
 CREATE INDEX idx_renting_properties_deleted_at          ON _renting_properties (deleted_at);
-CREATE INDEX idx_appointments_deleted_at                ON _appointments (deleted_at);
\ No newline at end of file
+CREATE INDEX idx_appointments_deleted_at                ON _appointments (deleted_at);
+CREATE INDEX idx_agreements_deleted_at                  ON _agreements (deleted_at);
+CREATE INDEX idx_property_histories_property_id         ON property_histories (property_id);
+CREATE INDEX idx_property_attachments_property_id       ON _property_attachments (property_id, display_order);
+CREATE INDEX idx_property_availabilities_property_id    ON property_availabilities (property_id, day_of_week);
+CREATE INDEX idx_availability_exceptions_property_id    ON property_availability_exceptions (property_id, exception_date);
+CREATE INDEX idx_appointment_status_histories_id        ON appointment_status_histories (appointment_id, created_at);
+CREATE INDEX idx_appointment_proposals_appointment_id   ON appointment_proposals (appointment_id, created_at);
+CREATE INDEX idx_appointment_attendances_user_id        ON appointment_attendances (user_id, role);
+CREATE INDEX idx_appointments_property_id_date          ON _appointments (property_id, appointment_date);
+CREATE INDEX idx_agreement_status_histories_id          ON agreement_status_histories (agreement_id, created_at);
+CREATE INDEX idx_payments_agreement_id                  ON payments (agreement_id);
+CREATE INDEX idx_payments_installment_id                ON payments (installment_id);
+CREATE INDEX idx_agreement_installments_due_date        ON agreement_installments (status, due_date);
+CREATE INDEX idx_agreement_signatures_agreement_id       ON agreement_signatures (agreement_id, signed_at);
+CREATE INDEX idx_agreement_amendments_agreement_id       ON agreement_amendments (agreement_id, created_at);
+CREATE INDEX idx_agreement_deductions_agreement_id       ON agreement_deposit_deductions (agreement_id, created_at);
+CREATE INDEX idx_payments_recipient_user_id              ON payments (recipient_user_id);
+CREATE INDEX idx_payments_paid_at                        ON payments (paid_at);
+CREATE INDEX idx_agreement_inspection_items_id           ON agreement_inspection_items (inspection_id, item_order);
+CREATE INDEX idx_meter_readings_installment_id           ON agreement_meter_readings (installment_id);
+CREATE INDEX idx_maintenance_tickets_agreement_id         ON maintenance_tickets (agreement_id, created_at);
+CREATE INDEX idx_maintenance_ticket_updates_ticket_id     ON maintenance_ticket_updates (ticket_id, created_at);
+CREATE UNIQUE INDEX idx_payments_deposit_refund          ON payments (agreement_id) WHERE payment_type = 'DEPOSIT_REFUND';
+CREATE UNIQUE INDEX idx_appointments_active_slot         ON _appointments (property_id, appointment_date) WHERE status IN ('PENDING', 'CONFIRMED') AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_agreement_inspections_current    ON agreement_inspections (agreement_id, inspection_type) WHERE superseded_at IS NULL;
+CREATE UNIQUE INDEX idx_agreements_active_property      ON _agreements (property_id) WHERE status IN ('AWAITING_DEPOSIT', 'AWAITING_PAYMENT', 'RENTING', 'OVERDUE') AND deleted_at IS NULL;
\ No newline at end of file
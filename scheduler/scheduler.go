@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Scheduler struct {
+	logger *zap.Logger
+	jobs   []job
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func()
+}
+
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Every registers a job that runs once when the scheduler starts and then
+// once per interval until the scheduler is stopped.
+func (s *Scheduler) Every(name string, interval time.Duration, run func()) {
+	s.jobs = append(s.jobs, job{name, interval, run})
+}
+
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+func (s *Scheduler) Stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runJob(j)
+
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runJob(j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Scheduled job panicked", zap.String("job", j.name), zap.Any("panic", r))
+		}
+	}()
+
+	start := time.Now()
+	j.run()
+	s.logger.Debug("Scheduled job finished", zap.String("job", j.name), zap.Duration("took", time.Since(start)))
+}
@@ -14,6 +14,7 @@ import (
 
 type Storage interface {
 	Upload(string, io.Reader, types.ObjectCannedACL) (string, error)
+	Download(string) (io.ReadCloser, error)
 }
 
 type storageImpl struct {
@@ -52,3 +53,18 @@ func (s *storageImpl) Upload(filename string, file io.Reader, acl types.ObjectCa
 
 	return result.Location, nil
 }
+
+// Download reads back a file, including private ones that cannot be fetched
+// through their url.
+func (s *storageImpl) Download(filename string) (io.ReadCloser, error) {
+	result, err := s.client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(filename),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Body, nil
+}